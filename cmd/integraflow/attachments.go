@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/repository/filestore"
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// newAttachmentService configures attachment storage from the environment:
+//
+//	ATTACHMENT_DIR      directory the file contents are stored in (default "attachments")
+//	ATTACHMENT_MAX_MB   largest file accepted, in MiB (default 20)
+func newAttachmentService(
+	repo repository.AttachmentRepository,
+	risks repository.RiskRepository,
+	incidents repository.IncidentRepository,
+	audits repository.AuditRepository,
+	actions repository.ActionRepository,
+) (*service.AttachmentService, error) {
+	dir := os.Getenv("ATTACHMENT_DIR")
+	if dir == "" {
+		dir = "attachments"
+	}
+	store, err := filestore.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	var maxSize int64
+	if v := os.Getenv("ATTACHMENT_MAX_MB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("ATTACHMENT_MAX_MB: invalid number %q", v)
+		}
+		maxSize = int64(n) << 20
+	}
+	return service.NewAttachmentService(repo, store, risks, incidents, audits, actions, maxSize), nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// runVerifyHistory implements "integraflow verify-history". It fails when
+// the hash chain is broken so it can run from cron or CI.
+func runVerifyHistory(historySvc *service.HistoryService) error {
+	v, err := historySvc.VerifyHistory(auth.SystemContext(context.Background()))
+	if err != nil {
+		return err
+	}
+	if !v.Valid {
+		return fmt.Errorf("history chain broken at entry %d after %d valid entries: %s", v.BrokenID, v.Checked, v.Reason)
+	}
+	fmt.Printf("history chain ok: %d entries, head %s\n", v.Checked, v.HeadHash)
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/notify"
+	"github.com/xenakil/integraflow-ims/internal/scheduler"
+	"github.com/xenakil/integraflow-ims/internal/service"
+	"github.com/xenakil/integraflow-ims/internal/webhook"
+)
+
+// Default job intervals, overridable through the environment.
+const (
+	defaultRelayInterval   = 10 * time.Second
+	defaultOverdueInterval = time.Hour
+	defaultNotifyInterval  = 30 * time.Second
+	defaultWebhookInterval = 10 * time.Second
+)
+
+// newScheduler registers the background jobs. EVENT_RELAY_INTERVAL,
+// OVERDUE_CHECK_INTERVAL (also used for due date reminders), NOTIFY_INTERVAL
+// and WEBHOOK_INTERVAL take a Go duration such as "15m"; "0" disables the
+// job.
+func newScheduler(events *service.EventRelay, actionSvc *service.ActionService, notifier *notify.Notifier, webhooks *webhook.Dispatcher) (*scheduler.Scheduler, error) {
+	relay, err := envDuration("EVENT_RELAY_INTERVAL", defaultRelayInterval)
+	if err != nil {
+		return nil, err
+	}
+	overdue, err := envDuration("OVERDUE_CHECK_INTERVAL", defaultOverdueInterval)
+	if err != nil {
+		return nil, err
+	}
+	deliver, err := envDuration("NOTIFY_INTERVAL", defaultNotifyInterval)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := envDuration("WEBHOOK_INTERVAL", defaultWebhookInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	s := scheduler.New()
+	s.Add(scheduler.Job{
+		Name:     "event-relay",
+		Interval: relay,
+		Run: func(ctx context.Context) (string, error) {
+			n, err := events.Relay(auth.SystemContext(ctx))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d event(s) relayed", n), nil
+		},
+	})
+	s.Add(scheduler.Job{
+		Name:     "overdue-actions",
+		Interval: overdue,
+		Run: func(ctx context.Context) (string, error) {
+			n, err := actionSvc.MarkOverdue(auth.SystemContext(ctx), time.Now())
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d action(s) marked overdue", n), nil
+		},
+	})
+	if len(notifier.Channels()) > 0 && notifier.DueSoonDays() > 0 {
+		days := notifier.DueSoonDays()
+		s.Add(scheduler.Job{
+			Name:     "due-soon-reminders",
+			Interval: overdue,
+			Run: func(ctx context.Context) (string, error) {
+				n, err := actionSvc.RemindDueSoon(auth.SystemContext(ctx), time.Now(), days, notifier)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d due date reminder(s) queued", n), nil
+			},
+		})
+	}
+	if len(notifier.Channels()) > 0 {
+		s.Add(scheduler.Job{
+			Name:     "notifications",
+			Interval: deliver,
+			Run: func(ctx context.Context) (string, error) {
+				sent, failed, err := notifier.Deliver(ctx)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d notification(s) sent, %d failed", sent, failed), nil
+			},
+		})
+	}
+	s.Add(scheduler.Job{
+		Name:     "webhook-deliveries",
+		Interval: hooks,
+		Run: func(ctx context.Context) (string, error) {
+			delivered, failed, err := webhooks.Deliver(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d webhook delivery(ies) sent, %d failed", delivered, failed), nil
+		},
+	})
+	return s, nil
+}
+
+func envDuration(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: invalid duration %q", name, v)
+	}
+	return d, nil
+}
@@ -8,9 +8,16 @@
 // @contact.email ims@example.com
 //
 // @BasePath /
+//
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                "Bearer <token>" from POST /api/auth/login or an API token.
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -27,21 +34,101 @@ func main() {
 		log.Fatalf("failed to open database: %v", err)
 	}
 
+	// Migrations run before the schema check so they work on any database
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Check the schema version before serving
+	if err := ensureSchema(db); err != nil {
+		log.Fatalf("database schema check failed: %v", err)
+	}
+
 	// Initialize repositories backed by SQLite
+	userRepo := repoSqlite.NewUserRepository(db)
+	tokenRepo := repoSqlite.NewTokenRepository(db)
 	riskRepo := repoSqlite.NewRiskRepository(db)
 	incidentRepo := repoSqlite.NewIncidentRepository(db)
 	auditRepo := repoSqlite.NewAuditRepository(db)
 	actionRepo := repoSqlite.NewActionRepository(db)
+	historyRepo := repoSqlite.NewHistoryRepository(db)
+	notificationRepo := repoSqlite.NewNotificationRepository(db)
+	webhookRepo := repoSqlite.NewWebhookRepository(db)
+	riskMatrixRepo := repoSqlite.NewRiskMatrixRepository(db)
+	processRepo := repoSqlite.NewProcessRepository(db)
+	attachmentRepo := repoSqlite.NewAttachmentRepository(db)
+	hoursRepo := repoSqlite.NewHoursWorkedRepository(db)
+	aspectRepo := repoSqlite.NewAspectRepository(db)
+	assetRepo := repoSqlite.NewAssetRepository(db)
+	ismsControlRepo := repoSqlite.NewISMSControlRepository(db)
+	eventOutboxRepo := repoSqlite.NewEventOutboxRepository(db)
+
+	// Record change events are staged with each change and relayed to the
+	// notification outbox and the webhook queue
+	notifier, err := newNotifier(notificationRepo, userRepo, riskMatrixRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhooks, err := newWebhookDispatcher(webhookRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	events := service.NewEventRelay(eventOutboxRepo, service.Publishers{notifier, webhooks})
 
 	// Initialize services
-	riskSvc := service.NewRiskService(riskRepo)
-	incidentSvc := service.NewIncidentService(incidentRepo, riskRepo)
-	auditSvc := service.NewAuditService(auditRepo)
-	actionSvc := service.NewActionService(actionRepo, riskRepo, incidentRepo, auditRepo)
-	dashboardSvc := service.NewDashboardService(riskRepo, incidentRepo, actionRepo)
+	authSvc := service.NewAuthService(userRepo, tokenRepo)
+	riskSvc := service.NewRiskService(riskRepo, processRepo, assetRepo, ismsControlRepo, riskMatrixRepo, actionRepo, historyRepo, events)
+	incidentSvc := service.NewIncidentService(incidentRepo, riskRepo, aspectRepo, riskMatrixRepo, historyRepo, events)
+	auditSvc := service.NewAuditService(auditRepo, processRepo, historyRepo, events)
+	actionSvc := service.NewActionService(actionRepo, riskRepo, incidentRepo, auditRepo, historyRepo, events)
+	dashboardSvc := service.NewDashboardService(riskRepo, incidentRepo, actionRepo, riskMatrixRepo, hoursRepo, aspectRepo)
+	historySvc := service.NewHistoryService(historyRepo)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	riskMatrixSvc := service.NewRiskMatrixService(riskMatrixRepo, riskRepo, incidentRepo, events)
+	processSvc := service.NewProcessService(processRepo, riskRepo, incidentRepo, auditRepo, actionRepo, riskMatrixRepo)
+	ohsSvc := service.NewOHSService(hoursRepo, incidentRepo)
+	aspectSvc := service.NewAspectService(aspectRepo, processRepo)
+	ismsSvc := service.NewISMSService(assetRepo, ismsControlRepo, riskRepo)
+
+	// Subcommands
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "user":
+			err = runUser(authSvc, os.Args[2:])
+		case "token":
+			err = runToken(authSvc, os.Args[2:])
+		case "verify-history":
+			err = runVerifyHistory(historySvc)
+		case "notify":
+			err = runNotify(notifier, notificationRepo, os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q (available: migrate, user, token, verify-history, notify)", os.Args[1])
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Background jobs
+	jobs, err := newScheduler(events, actionSvc, notifier, webhooks)
+	if err != nil {
+		log.Fatal(err)
+	}
+	jobs.Start(context.Background())
+
+	// Attachment storage is only needed by the API
+	attachmentSvc, err := newAttachmentService(attachmentRepo, riskRepo, incidentRepo, auditRepo, actionRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// HTTP API server
-	server := httpapi.NewServer(riskSvc, incidentSvc, auditSvc, actionSvc, dashboardSvc)
+	server := httpapi.NewServer(authSvc, riskSvc, incidentSvc, auditSvc, actionSvc, dashboardSvc, historySvc, webhookSvc, riskMatrixSvc, processSvc, attachmentSvc, ohsSvc, aspectSvc, ismsSvc, jobs)
 
 	port := ":8080"
 	if p := os.Getenv("PORT"); p != "" {
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	repoSqlite "github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+const migrateUsage = "usage: integraflow migrate up|down [-force] [steps]|status"
+
+// runMigrate implements the "integraflow migrate" subcommand.
+func runMigrate(db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := repoSqlite.Migrate(db)
+		for _, m := range applied {
+			fmt.Printf("applied %03d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return nil
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		force := fs.Bool("force", false, "also revert the baseline migration, dropping all records")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() > 1 {
+			return errors.New(migrateUsage)
+		}
+		steps := 1
+		if fs.NArg() > 0 {
+			n, err := strconv.Atoi(fs.Arg(0))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", fs.Arg(0))
+			}
+			steps = n
+		}
+		reverted, err := repoSqlite.MigrateDown(db, steps, *force)
+		for _, m := range reverted {
+			fmt.Printf("reverted %03d %s\n", m.Version, m.Name)
+		}
+		if errors.Is(err, repoSqlite.ErrBaselineRevert) {
+			return fmt.Errorf("%w; nothing was reverted. Back up integraflow.db and run \"integraflow migrate down -force %d\" to revert it anyway", err, steps)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("nothing to revert")
+		}
+		return nil
+
+	case "status":
+		all, err := repoSqlite.Migrations(db)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			state := "pending"
+			if m.Applied {
+				state = "applied " + m.AppliedAt
+			}
+			fmt.Printf("%03d %-40s %s\n", m.Version, m.Name, state)
+		}
+		return nil
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// ensureSchema is the startup check run before serving requests. Pending
+// migrations are applied automatically unless AUTO_MIGRATE=false, in which
+// case the server refuses to start until "integraflow migrate up" is run.
+func ensureSchema(db *sql.DB) error {
+	err := repoSqlite.CheckMigrations(db)
+	if err == nil || !errors.Is(err, repoSqlite.ErrPendingMigrations) {
+		return err
+	}
+	if os.Getenv("AUTO_MIGRATE") == "false" {
+		return fmt.Errorf("%w; run \"integraflow migrate up\"", err)
+	}
+
+	applied, err := repoSqlite.Migrate(db)
+	for _, m := range applied {
+		log.Printf("applied migration %03d %s", m.Version, m.Name)
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/notify"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+const defaultDueSoonDays = 3
+
+const notifyUsage = "usage: integraflow notify test [-to ADDR] | notify list [-status pending|sent|failed] [-limit N]"
+
+// newNotifier configures notifications from the environment:
+//
+//	NOTIFY_SMTP_ADDR        mail relay host:port; enables email
+//	NOTIFY_SMTP_FROM        sender address (required with NOTIFY_SMTP_ADDR)
+//	NOTIFY_SMTP_USERNAME    optional relay login, with NOTIFY_SMTP_PASSWORD
+//	NOTIFY_WEBHOOK_URL      enables posting every notification to this URL
+//	NOTIFY_ESCALATION_TO    comma-separated addresses for escalations
+//	NOTIFY_TEMPLATE_DIR     directory with <name>.tmpl template overrides
+//	NOTIFY_MAX_ATTEMPTS     delivery attempts before giving up (default 5)
+//	NOTIFY_RETRY_BACKOFF    delay after the first failure (default 1m)
+//	NOTIFY_DUE_SOON_DAYS    days before a due date to remind action owners (default 3, 0 turns it off)
+func newNotifier(
+	outbox repository.NotificationRepository,
+	users repository.UserRepository,
+	matrix repository.RiskMatrixRepository,
+) (*notify.Notifier, error) {
+	cfg := notify.Config{
+		WebhookURL:  os.Getenv("NOTIFY_WEBHOOK_URL"),
+		TemplateDir: os.Getenv("NOTIFY_TEMPLATE_DIR"),
+	}
+	if addr := os.Getenv("NOTIFY_SMTP_ADDR"); addr != "" {
+		cfg.SMTP = &notify.SMTPConfig{
+			Addr:     addr,
+			From:     os.Getenv("NOTIFY_SMTP_FROM"),
+			Username: os.Getenv("NOTIFY_SMTP_USERNAME"),
+			Password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+		}
+	}
+	if v := os.Getenv("NOTIFY_ESCALATION_TO"); v != "" {
+		cfg.EscalationEmails = strings.Split(v, ",")
+	}
+	if v := os.Getenv("NOTIFY_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("NOTIFY_MAX_ATTEMPTS: invalid number %q", v)
+		}
+		cfg.MaxAttempts = n
+	}
+	cfg.DueSoonDays = defaultDueSoonDays
+	if v := os.Getenv("NOTIFY_DUE_SOON_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("NOTIFY_DUE_SOON_DAYS: invalid number %q", v)
+		}
+		cfg.DueSoonDays = n
+	}
+	backoff, err := envDuration("NOTIFY_RETRY_BACKOFF", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RetryBackoff = backoff
+
+	n, err := notify.New(outbox, users, matrix, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+	return n, nil
+}
+
+// runNotify implements "integraflow notify". "test" queues a test message
+// and delivers it right away, which is the quickest way to check a relay or
+// webhook; "list" shows the outbox.
+func runNotify(n *notify.Notifier, outbox repository.NotificationRepository, args []string) error {
+	if len(args) == 0 {
+		return errors.New(notifyUsage)
+	}
+	fs := flag.NewFlagSet("notify "+args[0], flag.ContinueOnError)
+	to := fs.String("to", "", "email address for the test message")
+	status := fs.String("status", "", "only list notifications in this state")
+	limit := fs.Int("limit", 20, "number of notifications to list")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "test":
+		if len(n.Channels()) == 0 {
+			return errors.New("no notification channel configured; set NOTIFY_SMTP_ADDR or NOTIFY_WEBHOOK_URL")
+		}
+		if err := n.EnqueueTest("system", *to); err != nil {
+			return err
+		}
+		sent, failed, err := n.Deliver(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("channels: %s; sent %d, failed %d\n", strings.Join(n.Channels(), ", "), sent, failed)
+		if failed > 0 {
+			return errors.New("some notifications failed; see \"integraflow notify list -status pending\"")
+		}
+		return nil
+
+	case "list":
+		items, err := outbox.List(*status, *limit)
+		if err != nil {
+			return err
+		}
+		for _, m := range items {
+			fmt.Printf("%4d %-8s %-8s %-30s %-24s attempts=%d %s\n", m.ID, m.Status, m.Channel, m.Recipient, m.EventType, m.Attempts, m.LastError)
+		}
+		return nil
+
+	default:
+		return errors.New(notifyUsage)
+	}
+}
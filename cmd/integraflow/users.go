@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+const (
+	userUsage  = "usage: integraflow user add -username NAME [-display NAME] [-email ADDR] [-password PASS] [-role ROLE[@DOMAIN]]... | user passwd -username NAME -password PASS | user roles -username NAME [-role ROLE[@DOMAIN]]... | user list"
+	tokenUsage = "usage: integraflow token create -username NAME -name LABEL [-days N]"
+)
+
+// runUser implements the "integraflow user" subcommand used to bootstrap and
+// administer accounts from the server host. It acts as the system user, so
+// it is how the first IMS manager gets created.
+func runUser(authSvc *service.AuthService, args []string) error {
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+	ctx := auth.SystemContext(context.Background())
+
+	fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	username := fs.String("username", "", "login name")
+	display := fs.String("display", "", "display name")
+	email := fs.String("email", "", "email address for notifications")
+	password := fs.String("password", os.Getenv("INTEGRAFLOW_PASSWORD"), "password (or set INTEGRAFLOW_PASSWORD)")
+	var roles []domain.RoleAssignment
+	fs.Func("role", "role, optionally scoped as role@domain (repeatable)", func(v string) error {
+		ra, err := domain.ParseRoleAssignment(v)
+		if err != nil {
+			return fmt.Errorf("%w %q", err, v)
+		}
+		roles = append(roles, ra)
+		return nil
+	})
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		u, err := authSvc.CreateUser(ctx, service.CreateUserInput{
+			Username:    *username,
+			DisplayName: *display,
+			Email:       *email,
+			Password:    *password,
+			Roles:       roles,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created user %d %s\n", u.ID, u.Username)
+		return nil
+
+	case "passwd":
+		if err := authSvc.SetPassword(ctx, *username, *password); err != nil {
+			return err
+		}
+		fmt.Printf("password updated for %s\n", *username)
+		return nil
+
+	case "roles":
+		u, err := authSvc.GetUserByUsername(ctx, *username)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", *username, err)
+		}
+		if u, err = authSvc.SetRoles(ctx, u.ID, roles); err != nil {
+			return err
+		}
+		fmt.Printf("roles for %s: %s\n", u.Username, formatRoles(u.Roles))
+		return nil
+
+	case "list":
+		users, err := authSvc.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			state := "active"
+			if !u.Active {
+				state = "inactive"
+			}
+			fmt.Printf("%4d %-20s %-30s %-8s %s\n", u.ID, u.Username, u.DisplayName, state, formatRoles(u.Roles))
+		}
+		return nil
+
+	default:
+		return errors.New(userUsage)
+	}
+}
+
+// runToken implements the "integraflow token" subcommand, which issues an API
+// token for an existing user without needing to log in over HTTP.
+func runToken(authSvc *service.AuthService, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New(tokenUsage)
+	}
+
+	fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+	username := fs.String("username", "", "user the token belongs to")
+	name := fs.String("name", "", "what the token is used for")
+	days := fs.Int("days", 0, "lifetime in days (0 = never expires)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	u, err := authSvc.GetUserByUsername(auth.SystemContext(ctx), *username)
+	if err != nil {
+		return fmt.Errorf("user %q: %w", *username, err)
+	}
+
+	token, _, err := authSvc.CreateToken(auth.WithUser(ctx, u), *name, time.Duration(*days)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+func formatRoles(roles []domain.RoleAssignment) string {
+	if len(roles) == 0 {
+		return "(no roles)"
+	}
+	parts := make([]string, len(roles))
+	for i, ra := range roles {
+		parts[i] = string(ra.Role)
+		if ra.Domain != "" {
+			parts[i] += "@" + string(ra.Domain)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/webhook"
+)
+
+// newWebhookDispatcher configures webhook delivery from the environment:
+//
+//	WEBHOOK_MAX_ATTEMPTS    delivery attempts before giving up (default 8)
+//	WEBHOOK_RETRY_BACKOFF   delay after the first failure, doubled per attempt (default 30s)
+func newWebhookDispatcher(repo repository.WebhookRepository) (*webhook.Dispatcher, error) {
+	attempts := 0
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: invalid number %q", v)
+		}
+		attempts = n
+	}
+	backoff, err := envDuration("WEBHOOK_RETRY_BACKOFF", 0)
+	if err != nil {
+		return nil, err
+	}
+	return webhook.New(repo, attempts, backoff), nil
+}
@@ -20,7 +20,12 @@ const docTemplate = `{
     "paths": {
         "/api/actions": {
             "get": {
-                "description": "Returns actions, optionally filtered by status or source type.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns actions filtered, sorted and paged in the database.",
                 "produces": [
                     "application/json"
                 ],
@@ -40,6 +45,66 @@ const docTemplate = `{
                         "description": "Source type filter (Risk|Incident|Audit)",
                         "name": "sourceType",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source record ID filter",
+                        "name": "sourceId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Process filter: actions from its risks and audits and from incidents linked to its risks",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Due on or after (YYYY-MM-DD)",
+                        "name": "dueFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Due on or before (YYYY-MM-DD)",
+                        "name": "dueTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted actions",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. dueDate:asc",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -50,6 +115,24 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/domain.Action"
                             }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -61,6 +144,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Creates a corrective/preventive action linked to a risk, incident or audit.",
                 "consumes": [
                     "application/json"
@@ -96,6 +184,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -107,7 +201,12 @@ const docTemplate = `{
         },
         "/api/actions/{id}": {
             "put": {
-                "description": "Updates the status and/or due date of an action.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the status and/or due date (YYYY-MM-DD) of an action. Moving an Overdue action's due date to today or later reopens it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,47 +248,28 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.TransitionError"
                         }
-                    }
-                }
-            }
-        },
-        "/api/audits": {
-            "get": {
-                "description": "Returns internal audits, optionally filtered by status.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "audits"
-                ],
-                "summary": "List audits",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Status filter (Planned|In Progress|Completed)",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Audit"
-                            }
+                            "$ref": "#/definitions/domain.TransitionError"
                         }
                     },
                     "500": {
@@ -200,38 +280,38 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Creates a new IMS internal audit record.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Soft-deletes an action.",
                 "tags": [
-                    "audits"
+                    "actions"
                 ],
-                "summary": "Create internal audit",
+                "summary": "Delete action",
                 "parameters": [
                     {
-                        "description": "Audit payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpapi.CreateAuditRequest"
-                        }
+                        "type": "integer",
+                        "description": "Action ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/domain.Audit"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -245,46 +325,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/audits/{id}": {
-            "put": {
-                "description": "Updates audit status and/or findings.",
-                "consumes": [
-                    "application/json"
+        "/api/actions/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns every change to an action (including while deleted), oldest first, with actor, timestamp and field-level before/after values.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "audits"
+                    "actions"
                 ],
-                "summary": "Update audit",
+                "summary": "Action change history",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Audit ID",
+                        "description": "Action ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpapi.UpdateAuditRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Audit"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HistoryEntry"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -304,21 +380,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/dashboard": {
-            "get": {
-                "description": "Returns aggregated IMS KPIs (risks, incidents, actions).",
+        "/api/actions/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted action.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "dashboard"
+                    "actions"
+                ],
+                "summary": "Restore action",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Action ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get IMS dashboard",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Dashboard"
+                            "$ref": "#/definitions/domain.Action"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -330,27 +432,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/incidents": {
+        "/api/aspects": {
             "get": {
-                "description": "Returns incidents, optionally filtered by domain and status.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the aspects and impacts register, filtered, sorted and paged in the database.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "aspects"
                 ],
-                "summary": "List incidents",
+                "summary": "List environmental aspects",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only aspects of this process",
+                        "name": "processId",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Domain filter",
-                        "name": "domain",
+                        "description": "Condition filter (Normal|Abnormal|Emergency)",
+                        "name": "condition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only significant (true) or non-significant (false) aspects",
+                        "name": "significant",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Status filter (Open|Investigation|Closed)",
-                        "name": "status",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in activity, aspect, impact and owner",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. score:desc",
+                        "name": "sort",
                         "in": "query"
                     }
                 ],
@@ -360,7 +503,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Incident"
+                                "$ref": "#/definitions/domain.EnvironmentalAspect"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
                             }
                         }
                     },
@@ -370,6 +519,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -379,7 +534,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Records an incident/nonconformity in the IMS.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an aspect to the register. Its score is severity x frequency; it is significant when the score is 12 or more or a legal requirement applies. Requires contributing to the Environment domain.",
                 "consumes": [
                     "application/json"
                 ],
@@ -387,17 +547,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "aspects"
                 ],
-                "summary": "Create a new incident",
+                "summary": "Register environmental aspect",
                 "parameters": [
                     {
-                        "description": "Incident payload",
+                        "description": "Aspect",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpapi.CreateIncidentRequest"
+                            "$ref": "#/definitions/httpapi.CreateAspectRequest"
                         }
                     }
                 ],
@@ -405,7 +565,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Incident"
+                            "$ref": "#/definitions/domain.EnvironmentalAspect"
                         }
                     },
                     "400": {
@@ -414,6 +574,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -423,20 +589,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/incidents/{id}": {
+        "/api/aspects/{id}": {
             "get": {
-                "description": "Returns a single incident by ID.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an aspect of the register.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "aspects"
                 ],
-                "summary": "Get incident",
+                "summary": "Get environmental aspect",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Incident ID",
+                        "description": "Aspect ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -446,7 +617,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Incident"
+                            "$ref": "#/definitions/domain.EnvironmentalAspect"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "404": {
@@ -463,33 +640,88 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Updates root cause and/or status of an incident.",
-                "consumes": [
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an aspect from the register. Aspects linked to incidents (including deleted ones) are refused with 409.",
+                "tags": [
+                    "aspects"
+                ],
+                "summary": "Delete environmental aspect",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Aspect ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes an aspect and re-scores it. Omitted fields are left unchanged.",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "aspects"
                 ],
-                "summary": "Update incident",
+                "summary": "Update environmental aspect",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Incident ID",
+                        "description": "Aspect ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update payload",
+                        "description": "Fields to change",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpapi.UpdateIncidentRequest"
+                            "$ref": "#/definitions/httpapi.UpdateAspectRequest"
                         }
                     }
                 ],
@@ -497,7 +729,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Incident"
+                            "$ref": "#/definitions/domain.EnvironmentalAspect"
                         }
                     },
                     "400": {
@@ -506,6 +738,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -521,28 +759,234 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/risks": {
+        "/api/attachments/{id}": {
             "get": {
-                "description": "Returns all risks, optionally filtered by IMS domain and status.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "risks"
+                    "attachments"
                 ],
-                "summary": "List risks",
+                "summary": "Get attachment metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Attachment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an attachment. Requires the process_owner or ims_manager role for the record's domain.",
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Delete attachment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/attachments/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the file as uploaded, with its sniffed content type and its SHA-256 checksum as ETag. Range requests are supported.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Download attachment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns internal audits filtered, sorted and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audits"
+                ],
+                "summary": "List audits",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "description": "Domain filter",
                         "name": "domain",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Status filter (Open|Accepted|Mitigated)",
+                        "description": "Status filter (Planned|In Progress|Completed)",
                         "name": "status",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Auditor filter",
+                        "name": "auditor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Audited process filter",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Planned on or after (YYYY-MM-DD)",
+                        "name": "plannedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Planned on or before (YYYY-MM-DD)",
+                        "name": "plannedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in title, scope and findings",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted audits",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. plannedDate:asc",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -551,7 +995,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Risk"
+                                "$ref": "#/definitions/domain.Audit"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
                             }
                         }
                     },
@@ -561,6 +1011,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -570,7 +1026,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates a new IMS risk and calculates its risk score and level.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new IMS internal audit record.",
                 "consumes": [
                     "application/json"
                 ],
@@ -578,17 +1039,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "risks"
+                    "audits"
                 ],
-                "summary": "Create a new risk",
+                "summary": "Create internal audit",
                 "parameters": [
                     {
-                        "description": "Risk payload",
+                        "description": "Audit payload",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpapi.CreateRiskRequest"
+                            "$ref": "#/definitions/httpapi.CreateAuditRequest"
                         }
                     }
                 ],
@@ -596,7 +1057,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Risk"
+                            "$ref": "#/definitions/domain.Audit"
                         }
                     },
                     "400": {
@@ -605,6 +1066,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -614,9 +1081,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/risks/{id}": {
+        "/api/audits/{id}": {
             "put": {
-                "description": "Updates the status of an existing risk.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates audit status and/or findings, or links the audit to a process.",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,24 +1096,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "risks"
+                    "audits"
                 ],
-                "summary": "Update risk status",
+                "summary": "Update audit",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Risk ID",
+                        "description": "Audit ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New status",
+                        "description": "Update payload",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httpapi.UpdateRiskStatusRequest"
+                            "$ref": "#/definitions/httpapi.UpdateAuditRequest"
                         }
                     }
                 ],
@@ -649,7 +1121,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Risk"
+                            "$ref": "#/definitions/domain.Audit"
                         }
                     },
                     "400": {
@@ -658,6 +1130,181 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-deletes an audit. Audits still linked to active actions are refused with 409.",
+                "tags": [
+                    "audits"
+                ],
+                "summary": "Delete audit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Audit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audits/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every change to an audit (including while deleted), oldest first, with actor, timestamp and field-level before/after values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audits"
+                ],
+                "summary": "Audit change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Audit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HistoryEntry"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audits/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted audit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audits"
+                ],
+                "summary": "Restore audit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Audit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Audit"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -672,404 +1319,7295 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Action": {
+        },
+        "/api/auth/login": {
+            "post": {
+                "description": "Exchanges a username and password for a session token valid for 12 hours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "Credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the token used for this request.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log out",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the current user's API and session tokens. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List API tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.APIToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a long-lived API token for the current user. The token is only shown in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Create API token",
+                "parameters": [
+                    {
+                        "description": "Token name and lifetime",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes one of the current user's tokens.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Revoke API token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns aggregated IMS KPIs (risks, incidents, actions).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Get IMS dashboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dashboard"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/history/verify": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Walks the hash chain over all history entries and reports the first broken link. A broken chain is still a 200 response with valid=false. Requires the auditor or ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Verify change history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HistoryVerification"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns incidents filtered, sorted and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List incidents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain filter",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (Open|Investigation|Closed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Risk level filter, one of the risk matrix levels (default Low|Medium|High)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only incidents linked to this risk",
+                        "name": "relatedRiskId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only incidents linked to a risk of this process",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only incidents linked to this environmental aspect",
+                        "name": "aspectId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "OHS incident type, e.g. Lost Time",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reportable (true) or non-reportable (false) incidents",
+                        "name": "reportable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after (YYYY-MM-DD)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before (YYYY-MM-DD)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurred on or after (YYYY-MM-DD)",
+                        "name": "occurredFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurred on or before (YYYY-MM-DD)",
+                        "name": "occurredTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in title, description and root cause",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted incidents",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. createdAt:desc",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Incident"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records an incident/nonconformity in the IMS. Environment incidents can be linked to an environmental aspect. Only OHS incidents carry a type and, for injuries, the injured person, body part and injury nature codes (GET /api/ohs/codes) and days lost; reportable incidents need a reporting deadline.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Create a new incident",
+                "parameters": [
+                    {
+                        "description": "Incident payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/incidents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a single incident by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return the incident if it is soft-deleted",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Incident"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the root cause, status, occurrence date, environmental aspect or OHS classification of an incident. Omitted fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Update incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-deletes an incident. Incidents still linked to active actions are refused with 409.",
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Delete incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/incidents/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every change to an incident (including while deleted), oldest first, with actor, timestamp and field-level before/after values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Incident change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HistoryEntry"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/incidents/{id}/investigation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the incident's root-cause investigation: its 5-Whys chain and Ishikawa (fishbone) diagram. 404 until an investigation is saved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get incident investigation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Investigation"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the incident's investigation. It needs whys, a fishbone diagram or both; fishbone categories are man, machine, method, material, measurement and environment, each at most once, and causes may have sub-causes. At most one why or cause may be flagged with rootCause; its answer or cause becomes the incident's rootCause. Without a flagged root cause the incident's rootCause is left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Save incident investigation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Investigation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.SaveInvestigationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Investigation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the incident's investigation. The incident keeps its rootCause.",
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Delete incident investigation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/incidents/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted incident.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Restore incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Incident"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/assets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the information asset inventory, filtered, sorted and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "List information assets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset type filter (Information|Software|Hardware|Service|People|Site)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Classification filter (Public|Internal|Confidential|Restricted)",
+                        "name": "classification",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in name, description, owner and location",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. criticality:desc",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.InformationAsset"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an asset to the inventory. Its criticality is the highest of its confidentiality, integrity and availability ratings. Requires contributing to the Information Security domain.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Register information asset",
+                "parameters": [
+                    {
+                        "description": "Asset",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateAssetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InformationAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/assets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an asset of the inventory. GET /api/risks?assetId={id} lists its risks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Get information asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Asset ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InformationAsset"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an asset from the inventory. Assets linked to risks (including deleted ones) are refused with 409.",
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Delete information asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Asset ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes an asset. Omitted fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Update information asset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Asset ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateAssetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.InformationAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/controls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the controls catalogue in ID order (5.9 before 5.10), filtered and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "List ISMS controls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Theme filter (Organizational|People|Physical|Technological)",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only applicable (true) or excluded (false) controls",
+                        "name": "applicable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Implementation status filter",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in ID, title, description, justification and owner",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. implementationStatus:asc",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ISMSControl"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a control of the organisation's own to the catalogue. Excluding it requires a justification. Requires process_owner or ims_manager for Information Security.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Add ISMS control",
+                "parameters": [
+                    {
+                        "description": "Control",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateControlRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ISMSControl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/controls/seed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the 93 ISO 27001:2022 Annex A controls missing from the catalogue as applicable and not implemented. Controls already in the catalogue keep their decisions, so seeding again is safe. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Seed Annex A controls",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.SeedControlsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/controls/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a control of the catalogue. GET /api/risks?ismsControl={id} lists its risks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Get ISMS control",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Control ID, e.g. 5.15",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ISMSControl"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a control of the organisation's own. Annex A controls can't be deleted (400; mark them not applicable instead), and controls linked to risks (including deleted ones) are refused with 409.",
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Delete ISMS control",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Control ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the applicability decision, justification, implementation status or owner of a control. The theme, title and description of Annex A controls are fixed. Requires process_owner or ims_manager for Information Security.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Update ISMS control",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Control ID, e.g. 5.15",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateControlRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ISMSControl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/isms/soa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every control of the catalogue in ID order with its applicability, justification, implementation status and the active Information Security risks linked to it, and counts the applicable and excluded controls and the applicable ones by implementation status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "isms"
+                ],
+                "summary": "Statement of Applicability",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StatementOfApplicability"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ohs/codes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the incident types, employment types, body part codes and injury nature codes incidents accept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ohs"
+                ],
+                "summary": "OHS classification codes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OHSCodes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ohs/hours-worked": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the hours worked recorded per month, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ohs"
+                ],
+                "summary": "List hours worked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First month (YYYY-MM)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last month (YYYY-MM)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HoursWorked"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ohs/hours-worked/{month}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the hours worked by all workers in a month, replacing an earlier figure. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ohs"
+                ],
+                "summary": "Record hours worked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hours worked",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.SetHoursWorkedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HoursWorked"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the hours worked recorded for a month. Requires the ims_manager role for all domains.",
+                "tags": [
+                    "ohs"
+                ],
+                "summary": "Delete hours worked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ohs/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the OHS incidents that occurred in a period by type, with lost-time and recordable injuries, days lost and reportable incidents, and computes the LTIFR (per 1,000,000 hours) and TRIR (per 200,000 hours) from the recorded hours worked. The rates only count injuries in months with hours worked and are null without any. overdueReports counts reportable incidents of any period not reported by their deadline. Requires read access to OHS.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ohs"
+                ],
+                "summary": "OHS statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First month (YYYY-MM); with to, default the last 12 months",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last month (YYYY-MM)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OHSStatistics"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/processes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the process register, filtered, sorted and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processes"
+                ],
+                "summary": "List processes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only processes in this domain (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only sub-processes of this process",
+                        "name": "parentId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in name, description and owner",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. name:asc",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Process"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a process to the process register. Names are unique regardless of letter case; surrounding and repeated spaces are removed. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processes"
+                ],
+                "summary": "Register process",
+                "parameters": [
+                    {
+                        "description": "Process",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateProcessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Process"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/processes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a process of the register.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processes"
+                ],
+                "summary": "Get process",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Process ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Process"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a process from the register. Processes that risks (including deleted ones), audits, environmental aspects or sub-processes refer to are refused with 409. Requires the ims_manager role for all domains.",
+                "tags": [
+                    "processes"
+                ],
+                "summary": "Delete process",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Process ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes a process. A new name shows on every risk and audit of the process. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processes"
+                ],
+                "summary": "Update process",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Process ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateProcessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Process"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/processes/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the risks (by status and level), incidents, audits and actions (by status) of a process in the domains the caller can read, and lists its sub-processes. Incidents count through their related risk; actions through the risk, incident or audit they were raised from.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processes"
+                ],
+                "summary": "Process summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Process ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProcessSummary"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risk-matrix": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the likelihood and impact scales, the level bands and any per-cell overrides used to score risks and incidents. The default is a 5x5 matrix with Low (1-7), Medium (8-15) and High (16-25).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk-matrix"
+                ],
+                "summary": "Get risk matrix",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskMatrix"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the risk matrix and re-scores every active risk and incident. A record's level comes from its cell override, or else from the highest band whose minScore its likelihood x impact reaches. Refused with 400 while active records are rated outside the new scales. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk-matrix"
+                ],
+                "summary": "Replace risk matrix",
+                "parameters": [
+                    {
+                        "description": "New matrix",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskMatrix"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.RiskMatrixUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns risks filtered, sorted and paged in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "List risks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Process filter",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Process name filter",
+                        "name": "process",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (Open|Accepted|Mitigated)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Level filter, one of the risk matrix levels (default Low|Medium|High)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Residual level filter",
+                        "name": "residualLevel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only risks linked to this information asset",
+                        "name": "assetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only risks linked to this catalogue control, e.g. 5.15",
+                        "name": "ismsControl",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after (YYYY-MM-DD)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before (YYYY-MM-DD)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search in title, description, process and owner",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted risks",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc, e.g. createdAt:desc",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Risk"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new IMS risk with its controls and calculates the inherent and residual risk scores and levels. Residual ratings default to the inherent ones and can't exceed them. Information Security risks can be linked to information assets and catalogue controls. The process must be registered (processId, or process with its name); unknown process names are refused with 400 and are not registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Create a new risk",
+                "parameters": [
+                    {
+                        "description": "Risk payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateRiskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Risk"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/heatmap": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the risks in each likelihood x impact cell of the risk matrix, with their IDs, by inherent or residual ratings. Every cell of the matrix is returned, by likelihood then impact.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Risk heat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ratings to place risks by (inherent|residual, default inherent)",
+                        "name": "basis",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Process filter",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Process name filter",
+                        "name": "process",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (Open|Accepted|Mitigated)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskHeatmap"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/heatmap.svg": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders the risk heat map as an SVG image for management review reports: impact across, likelihood up, cells coloured by level from green (lowest) to red (highest) and labelled with their risk counts. Takes the same filters as /api/risks/heatmap.",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Risk heat map image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ratings to place risks by (inherent|residual, default inherent)",
+                        "name": "basis",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Process filter",
+                        "name": "processId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Process name filter",
+                        "name": "process",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (Open|Accepted|Mitigated)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/reviews/due": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists risks whose nextReviewAt is today or earlier, or within the given number of days, soonest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Risks due for review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Also include risks due in the next N days (default 0)",
+                        "name": "within",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner filter",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order as field:asc|desc (default nextReviewAt:asc)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Risk"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching records"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/trend": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the number of risks per level, overall and per domain, and their average inherent score at the end of each week (Monday to Sunday, UTC), oldest first. The current week shows the risks as they are now. Deleted risks count in the weeks before their deletion.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Risk trend",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of weeks including the current one (default 12, max 104)",
+                        "name": "weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain filter (quality|environment|ohs|isms)",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.RiskTrendPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a single risk by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Get risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return the risk if it is soft-deleted",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Risk"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the status of an existing risk. Risks in an escalating level of the risk matrix (default High) can only be Accepted under an approved treatment plan with option Accept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Update risk status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateRiskStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Risk"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-deletes a risk. Risks still linked to active incidents or actions are refused with 409.",
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Delete risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates any subset of risk fields. Inherent and residual scores and levels are recalculated from the ratings; a residual rating equal to the inherent one follows it unless given. controls, assetIds and ismsControls replace the whole list. A process name must be registered; unknown names are refused with 400.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Patch risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.PatchRiskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Risk"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransitionError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every change to a risk (including while deleted), oldest first, with actor, timestamp and field-level before/after values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Risk change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.HistoryEntry"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted risk.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Restore risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Risk"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the reviews of a risk, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "List risk reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.RiskReview"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a periodic review of a risk by the caller. Ratings in the body re-score the risk; omitted ones are confirmed unchanged. The next review is scheduled from today using the reviewDays of the risk's level in the risk matrix.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Review risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/score-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every rating of a risk, oldest first: one point when it was created and one whenever an edit, review or risk matrix change altered its inherent or residual likelihood, impact, score or level. Also available for deleted risks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Get risk score history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.RiskScore"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/treatment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the risk's treatment plan (null until set) with the actions raised from the risk to carry it out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Get risk treatment plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the risk's treatment plan. Changing the option or justification withdraws an earlier approval. Raise the plan's actions with POST /api/actions (sourceType risk); when all of them are Done, a risk treated by avoid, reduce or transfer moves to Mitigated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Set risk treatment plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment plan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.SetTreatmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/risks/{id}/treatment/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the caller as approver of the risk's treatment plan. Requires the process_owner or ims_manager role for the risk's domain; accepting a risk in an escalating level of the risk matrix (default High) requires the ims_manager role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risks"
+                ],
+                "summary": "Approve risk treatment plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Risk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TreatmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists all user accounts with their roles. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a user account with optional password and roles. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a user account with its roles. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes a user's display name or email address, or deactivates/reactivates the account. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/roles": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the user's role assignments. Roles: viewer, contributor, process_owner, auditor, ims_manager. A role without a domain applies to every domain. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set user roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role assignments",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.SetRolesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists webhook subscriptions. Secrets are never returned. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes a URL to record events. Event types: risk.created, risk.status_changed, risk.level_changed, incident.created, incident.status_changed, audit.created, audit.status_changed, action.created, action.status_changed, or * for all. Deliveries are signed with HMAC-SHA256 in the X-IMS-Signature header. The secret is only shown in this response. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.WebhookSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a webhook subscription. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a webhook subscription together with its pending deliveries and delivery log. Requires the ims_manager role for all domains.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the URL, event filter or description, pauses/resumes deliveries, or rotates the secret. The new secret is only shown in this response. Requires the ims_manager role for all domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.WebhookSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the webhook's deliveries, newest first, each with the posted payload and the status code, response time and error of every attempt. Requires the ims_manager role for all domains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of deliveries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.WebhookDelivery"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of deliveries"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/{records}/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the files attached to a risk, incident, audit or action, oldest first. The path segment is risks, incidents, audits or actions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "List attachments of a record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "risks, incidents, audits or actions",
+                        "name": "records",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Record ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attachment"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches a file to a risk, incident, audit or action as multipart/form-data with the file in the \"file\" field. The content type is sniffed from the content, and the SHA-256 checksum is recorded; identical content is stored once. Files over the configured limit (ATTACHMENT_MAX_MB, default 20) are refused with 413.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Upload an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "risks, incidents, audits or actions",
+                        "name": "records",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Record ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "What the file shows",
+                        "name": "description",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Reports that the server is up, with the interval and last run of each background job.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.HealthResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.APIToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "RFC3339, empty = never",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "api, session",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Action": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "sourceId": {
+                    "type": "integer"
+                },
+                "sourceType": {
+                    "description": "Risk, Incident, Audit",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Open, In Progress, Done, Overdue",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Attachment": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "description": "Sniffed from the content",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recordId": {
+                    "type": "integer"
+                },
+                "recordType": {
+                    "description": "risk, incident, audit, action",
+                    "type": "string"
+                },
+                "sha256": {
+                    "description": "Hex checksum of the content",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Bytes",
+                    "type": "integer"
+                },
+                "uploadedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "uploadedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Audit": {
+            "type": "object",
+            "properties": {
+                "auditor": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "Main focus area",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Domain"
+                        }
+                    ]
+                },
+                "findings": {
+                    "description": "Text field",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "plannedDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "process": {
+                    "description": "Name of the process",
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Audited process, if the audit covers one",
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Planned, In Progress, Completed",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CauseNode": {
+            "type": "object",
+            "properties": {
+                "cause": {
+                    "type": "string"
+                },
+                "causes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CauseNode"
+                    }
+                },
+                "rootCause": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.Code": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Dashboard": {
+            "type": "object",
+            "properties": {
+                "actionsByStatus": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "highRisks": {
+                    "description": "Risks in escalating bands of the risk matrix",
+                    "type": "integer"
+                },
+                "incidentsByDomain": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "ohs": {
+                    "description": "OHS injuries over the last 12 months; null without read access to OHS",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OHSStatistics"
+                        }
+                    ]
+                },
+                "openIncidents": {
+                    "type": "integer"
+                },
+                "overdueReviews": {
+                    "description": "Risks whose nextReviewAt has passed",
+                    "type": "integer"
+                },
+                "riskReduction": {
+                    "description": "Inherent vs residual risk per domain",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.RiskReduction"
+                    }
+                },
+                "significantAspects": {
+                    "description": "If the caller can read Environment",
+                    "type": "integer"
+                },
+                "significantAspectsByCondition": {
+                    "description": "Normal, Abnormal, Emergency",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "totalIncidents": {
+                    "type": "integer"
+                },
+                "totalRisks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Domain": {
+            "type": "string",
+            "enum": [
+                "Quality",
+                "Environment",
+                "OHS",
+                "Information Security"
+            ],
+            "x-enum-comments": {
+                "DomainEnv": "ISO 14001",
+                "DomainISMS": "ISMS / ISO 27001",
+                "DomainOHS": "ISO 45001",
+                "DomainQuality": "ISO 9001"
+            },
+            "x-enum-descriptions": [
+                "ISO 9001",
+                "ISO 14001",
+                "ISO 45001",
+                "ISMS / ISO 27001"
+            ],
+            "x-enum-varnames": [
+                "DomainQuality",
+                "DomainEnv",
+                "DomainOHS",
+                "DomainISMS"
+            ]
+        },
+        "domain.EnvironmentalAspect": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "description": "e.g. Tank cleaning",
+                    "type": "string"
+                },
+                "aspect": {
+                    "description": "e.g. Discharge of wash water",
+                    "type": "string"
+                },
+                "condition": {
+                    "description": "Normal, Abnormal, Emergency",
+                    "type": "string"
+                },
+                "controls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
+                "createdAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "description": "1 to 5",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impact": {
+                    "description": "e.g. Contamination of surface water",
+                    "type": "string"
+                },
+                "legalRequirements": {
+                    "description": "Compliance obligations, e.g. permit or regulation references",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "process": {
+                    "description": "Name of the process",
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Process the activity belongs to",
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "severity x frequency",
+                    "type": "integer"
+                },
+                "severity": {
+                    "description": "1 to 5",
+                    "type": "integer"
+                },
+                "significant": {
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FishboneCategory": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Man, Machine, Method, Material, Measurement, Environment",
+                    "type": "string"
+                },
+                "causes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CauseNode"
+                    }
+                }
+            }
+        },
+        "domain.HeatmapCell": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "impact": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "likelihood": {
+                    "type": "integer"
+                },
+                "riskIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "created, updated, deleted, restored",
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "changedAt": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "hash": {
+                    "description": "SHA-256 over this entry's content and PrevHash",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "prevHash": {
+                    "description": "Hash of the previous entry in the whole history (\"\" for the first)",
+                    "type": "string"
+                },
+                "recordId": {
+                    "type": "integer"
+                },
+                "recordType": {
+                    "description": "risk, incident, audit, action",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.HistoryVerification": {
+            "type": "object",
+            "properties": {
+                "brokenId": {
+                    "description": "First entry whose link does not verify",
+                    "type": "integer"
+                },
+                "checked": {
+                    "description": "Entries verified before stopping",
+                    "type": "integer"
+                },
+                "headHash": {
+                    "description": "Hash of the newest entry when valid; keep it elsewhere to detect truncation",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.HoursWorked": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "number"
+                },
+                "month": {
+                    "description": "YYYY-MM",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ISMSControl": {
+            "type": "object",
+            "properties": {
+                "annexA": {
+                    "description": "Seeded from Annex A; these can't be deleted",
+                    "type": "boolean"
+                },
+                "applicable": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "e.g. 5.15",
+                    "type": "string"
+                },
+                "implementationStatus": {
+                    "description": "Not Implemented, Planned, Partially Implemented, Implemented",
+                    "type": "string"
+                },
+                "justification": {
+                    "description": "Why the control is included or excluded",
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "theme": {
+                    "description": "Organizational, People, Physical, Technological",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Incident": {
+            "type": "object",
+            "properties": {
+                "aspectId": {
+                    "description": "Environmental aspect, for Environment incidents",
+                    "type": "integer"
+                },
+                "bodyPart": {
+                    "description": "Code from BodyParts",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "daysLost": {
+                    "description": "Lost Time injuries only",
+                    "type": "integer"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "$ref": "#/definitions/domain.Domain"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "injuredPerson": {
+                    "$ref": "#/definitions/domain.InjuredPerson"
+                },
+                "injuryNature": {
+                    "description": "Code from InjuryNatures",
+                    "type": "string"
+                },
+                "likelihood": {
+                    "description": "1 to the risk matrix's likelihoodScale",
+                    "type": "integer"
+                },
+                "occurredOn": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "relatedRiskId": {
+                    "type": "integer"
+                },
+                "reportable": {
+                    "description": "Must be reported to the regulator",
+                    "type": "boolean"
+                },
+                "reportedOn": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "reportingDeadline": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "riskLevel": {
+                    "description": "Risk matrix level",
+                    "type": "string"
+                },
+                "riskScore": {
+                    "type": "integer"
+                },
+                "rootCause": {
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "1 to the risk matrix's impactScale",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Open, Investigation, Closed",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "OHS classification (ISO 45001); Type is empty for other incidents.",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.InformationAsset": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "1 to 3",
+                    "type": "integer"
+                },
+                "classification": {
+                    "description": "Public, Internal, Confidential, Restricted",
+                    "type": "string"
+                },
+                "confidentiality": {
+                    "description": "1 to 3",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "criticality": {
+                    "description": "Highest of the three ratings",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "integrity": {
+                    "description": "1 to 3",
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Information, Software, Hardware, Service, People, Site",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.InjuredPerson": {
+            "type": "object",
+            "properties": {
+                "employment": {
+                    "description": "Employee, Contractor, Agency Worker, Visitor, Member of the Public",
+                    "type": "string"
+                },
+                "jobTitle": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Investigation": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "fishbone": {
+                    "description": "One entry per cause category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FishboneCategory"
+                    }
+                },
+                "incidentId": {
+                    "type": "integer"
+                },
+                "problem": {
+                    "description": "Problem statement; the effect of the fishbone diagram",
+                    "type": "string"
+                },
+                "rootCause": {
+                    "description": "Text of the flagged why or cause, empty if none is flagged",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "whys": {
+                    "description": "In order, each answering the previous one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WhyStep"
+                    }
+                }
+            }
+        },
+        "domain.OHSCodes": {
+            "type": "object",
+            "properties": {
+                "bodyParts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Code"
+                    }
+                },
+                "employmentTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "incidentTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "injuryNatures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Code"
+                    }
+                }
+            }
+        },
+        "domain.OHSStatistics": {
+            "type": "object",
+            "properties": {
+                "byType": {
+                    "description": "Incidents that occurred in the period, by type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "daysLost": {
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "YYYY-MM",
+                    "type": "string"
+                },
+                "hoursWorked": {
+                    "type": "number"
+                },
+                "lostTimeInjuries": {
+                    "type": "integer"
+                },
+                "ltifr": {
+                    "description": "Lost-time injuries per 1,000,000 hours worked",
+                    "type": "number"
+                },
+                "monthsCovered": {
+                    "type": "integer"
+                },
+                "overdueReports": {
+                    "description": "Reportable incidents not reported by their deadline, of any period",
+                    "type": "integer"
+                },
+                "recordableInjuries": {
+                    "type": "integer"
+                },
+                "reportableIncidents": {
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "YYYY-MM",
+                    "type": "string"
+                },
+                "trir": {
+                    "description": "Recordable injuries per 200,000 hours worked",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Process": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domains": {
+                    "description": "IMS domains the process falls under",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Domain"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kpis": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ProcessKPI"
+                    }
+                },
+                "name": {
+                    "description": "Unique regardless of letter case",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Process owner (username or role)",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "Parent process, for sub-processes",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ProcessKPI": {
+            "type": "object",
+            "properties": {
+                "frequency": {
+                    "description": "e.g. Monthly",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target": {
+                    "description": "e.g. \"\u003e= 98%\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ProcessSummary": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "$ref": "#/definitions/domain.RecordCounts"
+                },
+                "audits": {
+                    "$ref": "#/definitions/domain.RecordCounts"
+                },
+                "highRisks": {
+                    "description": "Risks in escalating levels of the risk matrix",
+                    "type": "integer"
+                },
+                "incidents": {
+                    "$ref": "#/definitions/domain.RecordCounts"
+                },
+                "process": {
+                    "$ref": "#/definitions/domain.Process"
+                },
+                "risks": {
+                    "$ref": "#/definitions/domain.RecordCounts"
+                },
+                "risksByLevel": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "subprocesses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Process"
+                    }
+                }
+            }
+        },
+        "domain.RecordCounts": {
+            "type": "object",
+            "properties": {
+                "byStatus": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Risk": {
+            "type": "object",
+            "properties": {
+                "assetIds": {
+                    "description": "Information assets at risk; Information Security risks only",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "controls": {
+                    "description": "Controls applied to the risk",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
+                "createdAt": {
+                    "description": "RFC3339 timestamp",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "description": "Username of the creator",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "RFC3339, set when soft-deleted",
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "description": "Who soft-deleted the risk",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Detailed description",
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "IMS Domain (Quality/Environment/OHS/Information Security)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Domain"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Auto-generated risk ID",
+                    "type": "integer"
+                },
+                "impact": {
+                    "description": "1 to the risk matrix's impactScale",
+                    "type": "integer"
+                },
+                "ismsControls": {
+                    "description": "IDs of catalogue controls treating the risk, e.g. 5.15",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lastReviewedAt": {
+                    "description": "RFC3339 timestamp of the last review",
+                    "type": "string"
+                },
+                "level": {
+                    "description": "Risk matrix level, e.g. Low/Medium/High",
+                    "type": "string"
+                },
+                "likelihood": {
+                    "description": "1 to the risk matrix's likelihoodScale",
+                    "type": "integer"
+                },
+                "nextReviewAt": {
+                    "description": "YYYY-MM-DD; from the risk matrix's reviewDays for the level",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Responsible person / role",
+                    "type": "string"
+                },
+                "process": {
+                    "description": "Name of the process",
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Process where risk occurs; see /api/processes",
+                    "type": "integer"
+                },
+                "residualImpact": {
+                    "description": "After controls; at most Impact",
+                    "type": "integer"
+                },
+                "residualLevel": {
+                    "description": "Risk matrix level of the residual score",
+                    "type": "string"
+                },
+                "residualLikelihood": {
+                    "description": "After controls; at most Likelihood",
+                    "type": "integer"
+                },
+                "residualScore": {
+                    "description": "ResidualLikelihood * ResidualImpact",
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Likelihood * Impact",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Open, Accepted, Mitigated",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Short risk title",
+                    "type": "string"
+                },
+                "treatment": {
+                    "description": "How the risk is treated; see /api/risks/{id}/treatment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RiskTreatment"
+                        }
+                    ]
+                },
+                "updatedBy": {
+                    "description": "Username of the last editor",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RiskBand": {
+            "type": "object",
+            "properties": {
+                "escalate": {
+                    "description": "Counts as high risk: dashboard highRisks and incident escalation emails",
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "minScore": {
+                    "type": "integer"
+                },
+                "reviewDays": {
+                    "description": "Days between reviews of risks in this level; 0 for no scheduled review",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskCell": {
+            "type": "object",
+            "properties": {
+                "impact": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "likelihood": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskControl": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "effectiveness": {
+                    "description": "Effective, Partially Effective, Ineffective, Not Tested",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Preventive, Detective, Corrective",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RiskHeatmap": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "description": "inherent or residual",
+                    "type": "string"
+                },
+                "cells": {
+                    "description": "Every cell, by likelihood then impact",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.HeatmapCell"
+                    }
+                },
+                "impactScale": {
+                    "type": "integer"
+                },
+                "levels": {
+                    "description": "Band levels, lowest first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "likelihoodScale": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskMatrix": {
+            "type": "object",
+            "properties": {
+                "bands": {
+                    "description": "Lowest first; the first starts at score 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskBand"
+                    }
+                },
+                "cells": {
+                    "description": "Optional per-cell overrides",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskCell"
+                    }
+                },
+                "impactScale": {
+                    "description": "Also the incident severity scale",
+                    "type": "integer"
+                },
+                "likelihoodScale": {
+                    "description": "Ratings run from 1 to this",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RiskReduction": {
+            "type": "object",
+            "properties": {
+                "inherentScore": {
+                    "type": "integer"
+                },
+                "reductionPercent": {
+                    "description": "Share of the inherent score removed by controls, one decimal",
+                    "type": "number"
+                },
+                "residualScore": {
+                    "type": "integer"
+                },
+                "risks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskReview": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impact": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "likelihood": {
+                    "type": "integer"
+                },
+                "nextReviewAt": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "previousLevel": {
+                    "type": "string"
+                },
+                "previousScore": {
+                    "type": "integer"
+                },
+                "rescored": {
+                    "description": "Whether the review changed any rating",
+                    "type": "boolean"
+                },
+                "residualImpact": {
+                    "type": "integer"
+                },
+                "residualLevel": {
+                    "type": "string"
+                },
+                "residualLikelihood": {
+                    "type": "integer"
+                },
+                "residualScore": {
+                    "type": "integer"
+                },
+                "reviewedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "reviewer": {
+                    "description": "Username",
+                    "type": "string"
+                },
+                "riskId": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskScore": {
+            "type": "object",
+            "properties": {
+                "changedBy": {
+                    "type": "string"
+                },
+                "impact": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "likelihood": {
+                    "type": "integer"
+                },
+                "recordedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "residualImpact": {
+                    "type": "integer"
+                },
+                "residualLevel": {
+                    "type": "string"
+                },
+                "residualLikelihood": {
+                    "type": "integer"
+                },
+                "residualScore": {
+                    "type": "integer"
+                },
+                "riskId": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskTreatment": {
+            "type": "object",
+            "properties": {
+                "approvedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "approvedBy": {
+                    "description": "Username of the approver",
+                    "type": "string"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "option": {
+                    "description": "Avoid, Reduce, Transfer, Accept",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RiskTrendPoint": {
+            "type": "object",
+            "properties": {
+                "averageScore": {
+                    "description": "One decimal",
+                    "type": "number"
+                },
+                "byDomain": {
+                    "description": "Counts per level within each domain",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "byLevel": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "weekStart": {
+                    "description": "Monday, YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Role": {
+            "type": "string",
+            "enum": [
+                "viewer",
+                "contributor",
+                "process_owner",
+                "auditor",
+                "ims_manager"
+            ],
+            "x-enum-comments": {
+                "RoleAuditor": "Plan audits and record findings",
+                "RoleContributor": "Raise and edit risks, incidents and actions",
+                "RoleIMSManager": "Everything, including user administration",
+                "RoleProcessOwner": "Contributor + accept risks, close incidents, delete records",
+                "RoleViewer": "Read records"
+            },
+            "x-enum-descriptions": [
+                "Read records",
+                "Raise and edit risks, incidents and actions",
+                "Contributor + accept risks, close incidents, delete records",
+                "Plan audits and record findings",
+                "Everything, including user administration"
+            ],
+            "x-enum-varnames": [
+                "RoleViewer",
+                "RoleContributor",
+                "RoleProcessOwner",
+                "RoleAuditor",
+                "RoleIMSManager"
+            ]
+        },
+        "domain.RoleAssignment": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "$ref": "#/definitions/domain.Domain"
+                },
+                "role": {
+                    "$ref": "#/definitions/domain.Role"
+                }
+            }
+        },
+        "domain.SoAEntry": {
+            "type": "object",
+            "properties": {
+                "annexA": {
+                    "description": "Seeded from Annex A; these can't be deleted",
+                    "type": "boolean"
+                },
+                "applicable": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "e.g. 5.15",
+                    "type": "string"
+                },
+                "implementationStatus": {
+                    "description": "Not Implemented, Planned, Partially Implemented, Implemented",
+                    "type": "string"
+                },
+                "justification": {
+                    "description": "Why the control is included or excluded",
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "risks": {
+                    "description": "Active risks linked to the control",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SoARisk"
+                    }
+                },
+                "theme": {
+                    "description": "Organizational, People, Physical, Technological",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SoARisk": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "residualLevel": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StatementOfApplicability": {
+            "type": "object",
+            "properties": {
+                "applicable": {
+                    "type": "integer"
+                },
+                "byStatus": {
+                    "description": "Implementation status of the applicable controls",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "controls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SoAEntry"
+                    }
+                },
+                "excluded": {
+                    "type": "integer"
+                },
+                "generatedAt": {
+                    "description": "RFC3339",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TransitionError": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "Statuses reachable from From",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "description": "invalid_transition or guard_failed",
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "guard": {
+                    "description": "Failed guard name",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TreatmentPlan": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "Actions with sourceType Risk and this risk's ID",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Action"
+                    }
+                },
+                "actionsDone": {
+                    "type": "integer"
+                },
+                "riskId": {
+                    "type": "integer"
+                },
+                "riskLevel": {
+                    "type": "string"
+                },
+                "riskStatus": {
+                    "type": "string"
+                },
+                "treatment": {
+                    "description": "null until a plan is set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RiskTreatment"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.User": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Where notifications for this user are sent",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RoleAssignment"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Event types, or \"*\" for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "attemptedAt": {
+                    "type": "string"
+                },
+                "durationMs": {
+                    "description": "Response time",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "description": "0 when no response was received",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attemptLog": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebhookAttempt"
+                    }
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nextAttemptAt": {
+                    "description": "Pending deliveries only",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Body as posted",
+                    "type": "object"
+                },
+                "status": {
+                    "description": "pending, delivered, failed",
+                    "type": "string"
+                },
+                "webhookId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WhyStep": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "question": {
+                    "description": "e.g. \"Why did the operator slip?\"",
+                    "type": "string"
+                },
+                "rootCause": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "httpapi.CreateActionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "sourceId": {
+                    "type": "integer"
+                },
+                "sourceType": {
+                    "description": "risk|incident|audit",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateAspectRequest": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "type": "string"
+                },
+                "aspect": {
+                    "type": "string"
+                },
+                "condition": {
+                    "description": "Normal (default), Abnormal, Emergency",
+                    "type": "string"
+                },
+                "controls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
+                "frequency": {
+                    "description": "1 to 5",
+                    "type": "integer"
+                },
+                "impact": {
+                    "type": "string"
+                },
+                "legalRequirements": {
+                    "description": "Compliance obligations; any makes the aspect significant",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Optional process the activity belongs to",
+                    "type": "integer"
+                },
+                "severity": {
+                    "description": "1 to 5",
+                    "type": "integer"
+                }
+            }
+        },
+        "httpapi.CreateAssetRequest": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "1 (Low) to 3 (High)",
+                    "type": "integer"
+                },
+                "classification": {
+                    "description": "Public, Internal (default), Confidential, Restricted",
+                    "type": "string"
+                },
+                "confidentiality": {
+                    "description": "1 (Low) to 3 (High)",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "integrity": {
+                    "description": "1 (Low) to 3 (High)",
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Information, Software, Hardware, Service, People, Site",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateAuditRequest": {
+            "type": "object",
+            "properties": {
+                "auditor": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "quality|environment|ohs|isms",
+                    "type": "string"
+                },
+                "plannedDate": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Optional audited process",
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateControlRequest": {
+            "type": "object",
+            "properties": {
+                "applicable": {
+                    "description": "Default true",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "e.g. ORG-1; Annex A numbers are taken",
+                    "type": "string"
+                },
+                "implementationStatus": {
+                    "description": "Not Implemented (default), Planned, Partially Implemented, Implemented",
+                    "type": "string"
+                },
+                "justification": {
+                    "description": "Required when not applicable",
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "theme": {
+                    "description": "Organizational, People, Physical, Technological",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateIncidentRequest": {
+            "type": "object",
+            "properties": {
+                "aspectId": {
+                    "description": "Optional environmental aspect, Environment incidents only",
+                    "type": "integer"
+                },
+                "bodyPart": {
+                    "description": "Code, see GET /api/ohs/codes",
+                    "type": "string"
+                },
+                "daysLost": {
+                    "description": "Lost Time injuries only",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "quality|environment|ohs|isms",
+                    "type": "string"
+                },
+                "injuredPerson": {
+                    "$ref": "#/definitions/domain.InjuredPerson"
+                },
+                "injuryNature": {
+                    "description": "Code, see GET /api/ohs/codes",
+                    "type": "string"
+                },
+                "likelihood": {
+                    "description": "1 to the risk matrix's likelihoodScale",
+                    "type": "integer"
+                },
+                "occurredOn": {
+                    "description": "YYYY-MM-DD, default today",
+                    "type": "string"
+                },
+                "relatedRiskId": {
+                    "description": "Optional link to risk",
+                    "type": "integer"
+                },
+                "reportable": {
+                    "description": "Must be reported to the regulator",
+                    "type": "boolean"
+                },
+                "reportedOn": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "reportingDeadline": {
+                    "description": "YYYY-MM-DD, required when reportable",
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "1 to the risk matrix's impactScale",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Near Miss, Dangerous Occurrence, First Aid, Medical Treatment, Restricted Work, Lost Time, Fatality",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateProcessRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "domains": {
+                    "description": "quality|environment|ohs|isms",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kpis": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ProcessKPI"
+                    }
+                },
+                "name": {
+                    "description": "Unique regardless of letter case",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Process owner",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "Optional parent process",
+                    "type": "integer"
+                }
+            }
+        },
+        "httpapi.CreateReviewRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "comments": {
                     "type": "string"
                 },
+                "impact": {
+                    "type": "integer"
+                },
+                "likelihood": {
+                    "type": "integer"
+                },
+                "residualImpact": {
+                    "type": "integer"
+                },
+                "residualLikelihood": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpapi.CreateRiskRequest": {
+            "type": "object",
+            "properties": {
+                "assetIds": {
+                    "description": "Information assets at risk, isms risks only",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "controls": {
+                    "description": "Controls applied to the risk",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
                 "description": {
+                    "description": "Detailed risk description",
                     "type": "string"
                 },
-                "dueDate": {
-                    "description": "YYYY-MM-DD",
+                "domain": {
+                    "description": "Domain: quality|environment|ohs|isms",
                     "type": "string"
                 },
-                "id": {
+                "impact": {
+                    "description": "Inherent impact, 1 (minor) up to the risk matrix's impactScale (default 5)",
+                    "type": "integer"
+                },
+                "ismsControls": {
+                    "description": "Catalogue control IDs treating the risk, e.g. [\"5.15\"]; isms risks only",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "likelihood": {
+                    "description": "Inherent likelihood, 1 (rare) up to the risk matrix's likelihoodScale (default 5)",
                     "type": "integer"
                 },
                 "owner": {
+                    "description": "Responsible person or role",
                     "type": "string"
                 },
-                "sourceId": {
+                "process": {
+                    "description": "Or the name of a registered process; unknown names are refused",
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "Process where the risk occurs, from /api/processes",
                     "type": "integer"
                 },
-                "sourceType": {
-                    "description": "Risk, Incident, Audit",
+                "residualImpact": {
+                    "description": "Impact with controls in place; defaults to impact",
+                    "type": "integer"
+                },
+                "residualLikelihood": {
+                    "description": "Likelihood with controls in place; defaults to likelihood",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Short name of the risk",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresInDays": {
+                    "description": "0 = never expires",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "What the token is used for, e.g. \"BI export\"",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Optional; used for notifications",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Optional; at least 10 characters",
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RoleAssignment"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
                 },
+                "events": {
+                    "description": "e.g. [\"incident.created\", \"action.status_changed\"], or [\"*\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Optional signing key (min. 16 characters); generated when empty",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scheduler.JobStatus"
+                    }
+                },
                 "status": {
-                    "description": "Open, In Progress, Done, Overdue",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.PatchRiskRequest": {
+            "type": "object",
+            "properties": {
+                "assetIds": {
+                    "description": "Replaces the linked assets when present",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "controls": {
+                    "description": "Replaces the controls when present",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "quality|environment|ohs|isms",
+                    "type": "string"
+                },
+                "impact": {
+                    "description": "1 to the risk matrix's impactScale",
+                    "type": "integer"
+                },
+                "ismsControls": {
+                    "description": "Replaces the linked catalogue controls when present",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "likelihood": {
+                    "description": "1 to the risk matrix's likelihoodScale",
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "process": {
+                    "description": "Name of a registered process, if processId is omitted",
+                    "type": "string"
+                },
+                "processId": {
+                    "type": "integer"
+                },
+                "residualImpact": {
+                    "description": "At most impact",
+                    "type": "integer"
+                },
+                "residualLikelihood": {
+                    "description": "At most likelihood",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Open, Accepted, Mitigated",
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                }
+            }
+        },
+        "httpapi.RiskMatrixUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "bands": {
+                    "description": "Lowest first; the first starts at score 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskBand"
+                    }
+                },
+                "cells": {
+                    "description": "Optional per-cell overrides",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskCell"
+                    }
+                },
+                "impactScale": {
+                    "description": "Also the incident severity scale",
+                    "type": "integer"
+                },
+                "likelihoodScale": {
+                    "description": "Ratings run from 1 to this",
+                    "type": "integer"
+                },
+                "rescoredIncidents": {
+                    "type": "integer"
+                },
+                "rescoredRisks": {
+                    "type": "integer"
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.SaveInvestigationRequest": {
+            "type": "object",
+            "properties": {
+                "fishbone": {
+                    "description": "Ishikawa diagram, one entry per category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FishboneCategory"
+                    }
+                },
+                "problem": {
+                    "type": "string"
+                },
+                "whys": {
+                    "description": "5-Whys chain, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WhyStep"
+                    }
+                }
+            }
+        },
+        "httpapi.SeedControlsResponse": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpapi.SetHoursWorkedRequest": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "httpapi.SetRolesRequest": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "description": "Omit domain for a role in every domain",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RoleAssignment"
+                    }
+                }
+            }
+        },
+        "httpapi.SetTreatmentRequest": {
+            "type": "object",
+            "properties": {
+                "justification": {
+                    "description": "Why this option was chosen",
+                    "type": "string"
+                },
+                "option": {
+                    "description": "avoid|reduce|transfer|accept",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "RFC3339, empty = never",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "api, session",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpapi.UpdateActionRequest": {
+            "type": "object",
+            "properties": {
+                "dueDate": {
+                    "description": "Optional new due date",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Open, In Progress, Done, Overdue",
+                    "type": "string"
+                }
+            }
+        },
+        "httpapi.UpdateAspectRequest": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "type": "string"
+                },
+                "aspect": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "controls": {
+                    "description": "Replaces the controls when present",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskControl"
+                    }
+                },
+                "frequency": {
+                    "type": "integer"
+                },
+                "impact": {
+                    "type": "string"
+                },
+                "legalRequirements": {
+                    "description": "Replaces the legal requirements when present",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "processId": {
+                    "description": "0 unlinks the process",
+                    "type": "integer"
+                },
+                "severity": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.Audit": {
+        "httpapi.UpdateAssetRequest": {
             "type": "object",
             "properties": {
-                "auditor": {
-                    "type": "string"
+                "availability": {
+                    "type": "integer"
                 },
-                "createdAt": {
+                "classification": {
                     "type": "string"
                 },
-                "domain": {
-                    "description": "Main focus area",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.Domain"
-                        }
-                    ]
+                "confidentiality": {
+                    "type": "integer"
                 },
-                "findings": {
-                    "description": "Text field",
+                "description": {
                     "type": "string"
                 },
-                "id": {
+                "integrity": {
                     "type": "integer"
                 },
-                "plannedDate": {
-                    "description": "YYYY-MM-DD",
+                "location": {
                     "type": "string"
                 },
-                "scope": {
+                "name": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Planned, In Progress, Completed",
+                "owner": {
                     "type": "string"
                 },
-                "title": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "domain.Dashboard": {
+        "httpapi.UpdateAuditRequest": {
             "type": "object",
             "properties": {
-                "actionsByStatus": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
-                },
-                "highRisks": {
-                    "type": "integer"
-                },
-                "incidentsByDomain": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
-                },
-                "openIncidents": {
-                    "type": "integer"
+                "findings": {
+                    "description": "Summary of audit findings",
+                    "type": "string"
                 },
-                "totalIncidents": {
+                "processId": {
+                    "description": "Audited process; 0 unlinks it",
                     "type": "integer"
                 },
-                "totalRisks": {
-                    "type": "integer"
+                "status": {
+                    "description": "Planned, In Progress, Completed",
+                    "type": "string"
                 }
             }
         },
-        "domain.Domain": {
-            "type": "string",
-            "enum": [
-                "Quality",
-                "Environment",
-                "OHS",
-                "Information Security"
-            ],
-            "x-enum-comments": {
-                "DomainEnv": "ISO 14001",
-                "DomainISMS": "ISMS / ISO 27001",
-                "DomainOHS": "ISO 45001",
-                "DomainQuality": "ISO 9001"
-            },
-            "x-enum-descriptions": [
-                "ISO 9001",
-                "ISO 14001",
-                "ISO 45001",
-                "ISMS / ISO 27001"
-            ],
-            "x-enum-varnames": [
-                "DomainQuality",
-                "DomainEnv",
-                "DomainOHS",
-                "DomainISMS"
-            ]
-        },
-        "domain.Incident": {
+        "httpapi.UpdateControlRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "RFC3339",
-                    "type": "string"
+                "applicable": {
+                    "type": "boolean"
                 },
                 "description": {
+                    "description": "Not for Annex A controls",
                     "type": "string"
                 },
-                "domain": {
-                    "$ref": "#/definitions/domain.Domain"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "likelihood": {
-                    "description": "1-5",
-                    "type": "integer"
-                },
-                "relatedRiskId": {
-                    "type": "integer"
-                },
-                "riskLevel": {
-                    "description": "Low/Medium/High",
+                "implementationStatus": {
                     "type": "string"
                 },
-                "riskScore": {
-                    "type": "integer"
-                },
-                "rootCause": {
+                "justification": {
+                    "description": "Required when not applicable",
                     "type": "string"
                 },
-                "severity": {
-                    "description": "1-5",
-                    "type": "integer"
-                },
-                "status": {
-                    "description": "Open, Investigation, Closed",
+                "owner": {
                     "type": "string"
                 },
-                "title": {
+                "theme": {
+                    "description": "Not for Annex A controls",
                     "type": "string"
                 },
-                "updatedAt": {
-                    "description": "RFC3339",
+                "title": {
+                    "description": "Not for Annex A controls",
                     "type": "string"
                 }
             }
         },
-        "domain.Risk": {
+        "httpapi.UpdateIncidentRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "RFC3339 timestamp",
-                    "type": "string"
+                "aspectId": {
+                    "description": "Environmental aspect; 0 unlinks it",
+                    "type": "integer"
                 },
-                "description": {
-                    "description": "Detailed description",
+                "bodyPart": {
                     "type": "string"
                 },
-                "domain": {
-                    "description": "IMS Domain (Quality/Environment/OHS/Information Security)",
+                "daysLost": {
+                    "type": "integer"
+                },
+                "injuredPerson": {
+                    "description": "Replaces the injured person; without a name removes it",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.Domain"
+                            "$ref": "#/definitions/domain.InjuredPerson"
                         }
                     ]
                 },
-                "id": {
-                    "description": "Auto-generated risk ID",
-                    "type": "integer"
-                },
-                "impact": {
-                    "description": "1-5",
-                    "type": "integer"
+                "injuryNature": {
+                    "type": "string"
                 },
-                "level": {
-                    "description": "Low/Medium/High",
+                "occurredOn": {
                     "type": "string"
                 },
-                "likelihood": {
-                    "description": "1-5",
-                    "type": "integer"
+                "reportable": {
+                    "description": "false clears reportingDeadline and reportedOn",
+                    "type": "boolean"
                 },
-                "owner": {
-                    "description": "Responsible person / role",
+                "reportedOn": {
                     "type": "string"
                 },
-                "process": {
-                    "description": "Process where risk occurs",
+                "reportingDeadline": {
                     "type": "string"
                 },
-                "score": {
-                    "description": "Likelihood * Impact",
-                    "type": "integer"
+                "rootCause": {
+                    "type": "string"
                 },
                 "status": {
-                    "description": "Open, Accepted, Mitigated",
+                    "description": "Open, Investigation, Closed",
                     "type": "string"
                 },
-                "title": {
-                    "description": "Short risk title",
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "httpapi.CreateActionRequest": {
+        "httpapi.UpdateProcessRequest": {
             "type": "object",
             "properties": {
                 "description": {
                     "type": "string"
                 },
-                "dueDate": {
-                    "description": "YYYY-MM-DD",
+                "domains": {
+                    "description": "Replaces the domains when present",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kpis": {
+                    "description": "Replaces the KPIs when present",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ProcessKPI"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
                 "owner": {
                     "type": "string"
                 },
-                "sourceId": {
+                "parentId": {
+                    "description": "0 makes the process top-level",
                     "type": "integer"
-                },
-                "sourceType": {
-                    "description": "risk|incident|audit",
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "httpapi.CreateAuditRequest": {
+        "httpapi.UpdateRiskStatusRequest": {
             "type": "object",
             "properties": {
-                "auditor": {
-                    "type": "string"
-                },
-                "domain": {
-                    "description": "quality|environment|ohs|isms",
-                    "type": "string"
-                },
-                "plannedDate": {
-                    "description": "YYYY-MM-DD",
+                "status": {
+                    "description": "Open, Accepted, Mitigated",
                     "type": "string"
+                }
+            }
+        },
+        "httpapi.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "false blocks login and all of the user's tokens",
+                    "type": "boolean"
                 },
-                "scope": {
+                "displayName": {
                     "type": "string"
                 },
-                "title": {
+                "email": {
+                    "description": "Empty string clears the address",
                     "type": "string"
                 }
             }
         },
-        "httpapi.CreateIncidentRequest": {
+        "httpapi.UpdateWebhookRequest": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "active": {
+                    "description": "false pauses deliveries; they are kept and sent on reactivation",
+                    "type": "boolean"
                 },
-                "domain": {
-                    "description": "quality|environment|ohs|isms",
+                "description": {
                     "type": "string"
                 },
-                "likelihood": {
-                    "description": "1-5",
-                    "type": "integer"
-                },
-                "relatedRiskId": {
-                    "description": "Optional link to risk",
-                    "type": "integer"
+                "events": {
+                    "description": "Replaces the filter when present",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "severity": {
-                    "description": "1-5",
-                    "type": "integer"
+                "rotateSecret": {
+                    "description": "Issue a new signing secret",
+                    "type": "boolean"
                 },
-                "title": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "httpapi.CreateRiskRequest": {
+        "httpapi.WebhookSecretResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "description": "Detailed risk description",
+                "active": {
+                    "type": "boolean"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "domain": {
-                    "description": "Domain: quality|environment|ohs|isms",
+                "createdBy": {
                     "type": "string"
                 },
-                "impact": {
-                    "description": "1 (minor) - 5 (catastrophic)",
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "likelihood": {
-                    "description": "1 (rare) - 5 (almost certain)",
+                "events": {
+                    "description": "Event types, or \"*\" for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
                     "type": "integer"
                 },
-                "owner": {
-                    "description": "Responsible person or role",
+                "secret": {
                     "type": "string"
                 },
-                "process": {
-                    "description": "Process where the risk occurs",
+                "updatedAt": {
                     "type": "string"
                 },
-                "title": {
-                    "description": "Short name of the risk",
-                    "type": "string"
-                }
-            }
-        },
-        "httpapi.UpdateActionRequest": {
-            "type": "object",
-            "properties": {
-                "dueDate": {
-                    "description": "Optional new due date",
+                "updatedBy": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Open, In Progress, Done, Overdue",
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "httpapi.UpdateAuditRequest": {
+        "scheduler.JobStatus": {
             "type": "object",
             "properties": {
-                "findings": {
-                    "description": "Summary of audit findings",
+                "interval": {
+                    "description": "Go duration, e.g. \"1h0m0s\"",
                     "type": "string"
                 },
-                "status": {
-                    "description": "Planned, In Progress, Completed",
+                "lastError": {
+                    "description": "Error of the last run, if it failed",
                     "type": "string"
-                }
-            }
-        },
-        "httpapi.UpdateIncidentRequest": {
-            "type": "object",
-            "properties": {
-                "rootCause": {
+                },
+                "lastResult": {
+                    "description": "Summary returned by the last successful run",
                     "type": "string"
                 },
-                "status": {
-                    "description": "Open, Investigation, Closed",
+                "lastRun": {
+                    "description": "Start of the last run (RFC3339)",
                     "type": "string"
-                }
-            }
-        },
-        "httpapi.UpdateRiskStatusRequest": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "description": "Open, Accepted, Mitigated",
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nextRun": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "\"Bearer \u003ctoken\u003e\" from POST /api/auth/login or an API token.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,40 @@
+// Package auth holds the request identity and the credential primitives
+// shared by the service and repository layers.
+package auth
+
+import (
+	"context"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+type ctxKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, u *domain.User) context.Context {
+	return context.WithValue(ctx, ctxKey{}, u)
+}
+
+// UserFromContext returns the authenticated user, or nil if there is none.
+func UserFromContext(ctx context.Context) *domain.User {
+	u, _ := ctx.Value(ctxKey{}).(*domain.User)
+	return u
+}
+
+// Actor returns the username to stamp on records changed within ctx, or ""
+// when the change is not made on behalf of a user.
+func Actor(ctx context.Context) string {
+	if u := UserFromContext(ctx); u != nil {
+		return u.Username
+	}
+	return ""
+}
+
+// System is the actor used for changes made by the server itself, such as
+// scheduled jobs and command-line administration on the server host.
+var System = &domain.User{Username: "system", DisplayName: "System", Active: true, System: true}
+
+// SystemContext returns a copy of ctx acting as System.
+func SystemContext(ctx context.Context) context.Context {
+	return WithUser(ctx, System)
+}
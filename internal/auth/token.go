@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// tokenPrefix makes IntegraFlow tokens recognisable in logs and secret scanners.
+const tokenPrefix = "ifl_"
+
+// NewToken generates a random bearer token and returns it together with the
+// hash that is stored. The plaintext is shown to the user once and never
+// persisted.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = tokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the stored form of a bearer token. Tokens carry 256 bits
+// of entropy, so a fast unsalted hash is sufficient for lookups.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package domain
+
+// Annex A control themes of ISO 27001:2022.
+const (
+	ThemeOrganizational = "Organizational"
+	ThemePeople         = "People"
+	ThemePhysical       = "Physical"
+	ThemeTechnological  = "Technological"
+)
+
+var ControlThemes = []string{ThemeOrganizational, ThemePeople, ThemePhysical, ThemeTechnological}
+
+// AnnexA lists the 93 controls of ISO 27001:2022 Annex A by number and title.
+var AnnexA = []ISMSControl{
+	{ID: "5.1", Theme: ThemeOrganizational, Title: "Policies for information security"},
+	{ID: "5.2", Theme: ThemeOrganizational, Title: "Information security roles and responsibilities"},
+	{ID: "5.3", Theme: ThemeOrganizational, Title: "Segregation of duties"},
+	{ID: "5.4", Theme: ThemeOrganizational, Title: "Management responsibilities"},
+	{ID: "5.5", Theme: ThemeOrganizational, Title: "Contact with authorities"},
+	{ID: "5.6", Theme: ThemeOrganizational, Title: "Contact with special interest groups"},
+	{ID: "5.7", Theme: ThemeOrganizational, Title: "Threat intelligence"},
+	{ID: "5.8", Theme: ThemeOrganizational, Title: "Information security in project management"},
+	{ID: "5.9", Theme: ThemeOrganizational, Title: "Inventory of information and other associated assets"},
+	{ID: "5.10", Theme: ThemeOrganizational, Title: "Acceptable use of information and other associated assets"},
+	{ID: "5.11", Theme: ThemeOrganizational, Title: "Return of assets"},
+	{ID: "5.12", Theme: ThemeOrganizational, Title: "Classification of information"},
+	{ID: "5.13", Theme: ThemeOrganizational, Title: "Labelling of information"},
+	{ID: "5.14", Theme: ThemeOrganizational, Title: "Information transfer"},
+	{ID: "5.15", Theme: ThemeOrganizational, Title: "Access control"},
+	{ID: "5.16", Theme: ThemeOrganizational, Title: "Identity management"},
+	{ID: "5.17", Theme: ThemeOrganizational, Title: "Authentication information"},
+	{ID: "5.18", Theme: ThemeOrganizational, Title: "Access rights"},
+	{ID: "5.19", Theme: ThemeOrganizational, Title: "Information security in supplier relationships"},
+	{ID: "5.20", Theme: ThemeOrganizational, Title: "Addressing information security within supplier agreements"},
+	{ID: "5.21", Theme: ThemeOrganizational, Title: "Managing information security in the ICT supply chain"},
+	{ID: "5.22", Theme: ThemeOrganizational, Title: "Monitoring, review and change management of supplier services"},
+	{ID: "5.23", Theme: ThemeOrganizational, Title: "Information security for use of cloud services"},
+	{ID: "5.24", Theme: ThemeOrganizational, Title: "Information security incident management planning and preparation"},
+	{ID: "5.25", Theme: ThemeOrganizational, Title: "Assessment and decision on information security events"},
+	{ID: "5.26", Theme: ThemeOrganizational, Title: "Response to information security incidents"},
+	{ID: "5.27", Theme: ThemeOrganizational, Title: "Learning from information security incidents"},
+	{ID: "5.28", Theme: ThemeOrganizational, Title: "Collection of evidence"},
+	{ID: "5.29", Theme: ThemeOrganizational, Title: "Information security during disruption"},
+	{ID: "5.30", Theme: ThemeOrganizational, Title: "ICT readiness for business continuity"},
+	{ID: "5.31", Theme: ThemeOrganizational, Title: "Legal, statutory, regulatory and contractual requirements"},
+	{ID: "5.32", Theme: ThemeOrganizational, Title: "Intellectual property rights"},
+	{ID: "5.33", Theme: ThemeOrganizational, Title: "Protection of records"},
+	{ID: "5.34", Theme: ThemeOrganizational, Title: "Privacy and protection of PII"},
+	{ID: "5.35", Theme: ThemeOrganizational, Title: "Independent review of information security"},
+	{ID: "5.36", Theme: ThemeOrganizational, Title: "Compliance with policies, rules and standards for information security"},
+	{ID: "5.37", Theme: ThemeOrganizational, Title: "Documented operating procedures"},
+
+	{ID: "6.1", Theme: ThemePeople, Title: "Screening"},
+	{ID: "6.2", Theme: ThemePeople, Title: "Terms and conditions of employment"},
+	{ID: "6.3", Theme: ThemePeople, Title: "Information security awareness, education and training"},
+	{ID: "6.4", Theme: ThemePeople, Title: "Disciplinary process"},
+	{ID: "6.5", Theme: ThemePeople, Title: "Responsibilities after termination or change of employment"},
+	{ID: "6.6", Theme: ThemePeople, Title: "Confidentiality or non-disclosure agreements"},
+	{ID: "6.7", Theme: ThemePeople, Title: "Remote working"},
+	{ID: "6.8", Theme: ThemePeople, Title: "Information security event reporting"},
+
+	{ID: "7.1", Theme: ThemePhysical, Title: "Physical security perimeters"},
+	{ID: "7.2", Theme: ThemePhysical, Title: "Physical entry"},
+	{ID: "7.3", Theme: ThemePhysical, Title: "Securing offices, rooms and facilities"},
+	{ID: "7.4", Theme: ThemePhysical, Title: "Physical security monitoring"},
+	{ID: "7.5", Theme: ThemePhysical, Title: "Protecting against physical and environmental threats"},
+	{ID: "7.6", Theme: ThemePhysical, Title: "Working in secure areas"},
+	{ID: "7.7", Theme: ThemePhysical, Title: "Clear desk and clear screen"},
+	{ID: "7.8", Theme: ThemePhysical, Title: "Equipment siting and protection"},
+	{ID: "7.9", Theme: ThemePhysical, Title: "Security of assets off-premises"},
+	{ID: "7.10", Theme: ThemePhysical, Title: "Storage media"},
+	{ID: "7.11", Theme: ThemePhysical, Title: "Supporting utilities"},
+	{ID: "7.12", Theme: ThemePhysical, Title: "Cabling security"},
+	{ID: "7.13", Theme: ThemePhysical, Title: "Equipment maintenance"},
+	{ID: "7.14", Theme: ThemePhysical, Title: "Secure disposal or re-use of equipment"},
+
+	{ID: "8.1", Theme: ThemeTechnological, Title: "User endpoint devices"},
+	{ID: "8.2", Theme: ThemeTechnological, Title: "Privileged access rights"},
+	{ID: "8.3", Theme: ThemeTechnological, Title: "Information access restriction"},
+	{ID: "8.4", Theme: ThemeTechnological, Title: "Access to source code"},
+	{ID: "8.5", Theme: ThemeTechnological, Title: "Secure authentication"},
+	{ID: "8.6", Theme: ThemeTechnological, Title: "Capacity management"},
+	{ID: "8.7", Theme: ThemeTechnological, Title: "Protection against malware"},
+	{ID: "8.8", Theme: ThemeTechnological, Title: "Management of technical vulnerabilities"},
+	{ID: "8.9", Theme: ThemeTechnological, Title: "Configuration management"},
+	{ID: "8.10", Theme: ThemeTechnological, Title: "Information deletion"},
+	{ID: "8.11", Theme: ThemeTechnological, Title: "Data masking"},
+	{ID: "8.12", Theme: ThemeTechnological, Title: "Data leakage prevention"},
+	{ID: "8.13", Theme: ThemeTechnological, Title: "Information backup"},
+	{ID: "8.14", Theme: ThemeTechnological, Title: "Redundancy of information processing facilities"},
+	{ID: "8.15", Theme: ThemeTechnological, Title: "Logging"},
+	{ID: "8.16", Theme: ThemeTechnological, Title: "Monitoring activities"},
+	{ID: "8.17", Theme: ThemeTechnological, Title: "Clock synchronization"},
+	{ID: "8.18", Theme: ThemeTechnological, Title: "Use of privileged utility programs"},
+	{ID: "8.19", Theme: ThemeTechnological, Title: "Installation of software on operational systems"},
+	{ID: "8.20", Theme: ThemeTechnological, Title: "Networks security"},
+	{ID: "8.21", Theme: ThemeTechnological, Title: "Security of network services"},
+	{ID: "8.22", Theme: ThemeTechnological, Title: "Segregation of networks"},
+	{ID: "8.23", Theme: ThemeTechnological, Title: "Web filtering"},
+	{ID: "8.24", Theme: ThemeTechnological, Title: "Use of cryptography"},
+	{ID: "8.25", Theme: ThemeTechnological, Title: "Secure development life cycle"},
+	{ID: "8.26", Theme: ThemeTechnological, Title: "Application security requirements"},
+	{ID: "8.27", Theme: ThemeTechnological, Title: "Secure system architecture and engineering principles"},
+	{ID: "8.28", Theme: ThemeTechnological, Title: "Secure coding"},
+	{ID: "8.29", Theme: ThemeTechnological, Title: "Security testing in development and acceptance"},
+	{ID: "8.30", Theme: ThemeTechnological, Title: "Outsourced development"},
+	{ID: "8.31", Theme: ThemeTechnological, Title: "Separation of development, test and production environments"},
+	{ID: "8.32", Theme: ThemeTechnological, Title: "Change management"},
+	{ID: "8.33", Theme: ThemeTechnological, Title: "Test information"},
+	{ID: "8.34", Theme: ThemeTechnological, Title: "Protection of information systems during audit testing"},
+}
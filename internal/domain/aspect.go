@@ -0,0 +1,50 @@
+package domain
+
+// Operating conditions an environmental aspect arises under (ISO 14001
+// 6.1.2).
+const (
+	ConditionNormal    = "Normal"
+	ConditionAbnormal  = "Abnormal"
+	ConditionEmergency = "Emergency"
+)
+
+var AspectConditions = []string{ConditionNormal, ConditionAbnormal, ConditionEmergency}
+
+// Significance scoring of environmental aspects: severity and frequency are
+// rated 1 to AspectScale, and an aspect is significant when their product
+// reaches AspectSignificanceThreshold or a legal requirement applies to it.
+const (
+	AspectScale                 = 5
+	AspectSignificanceThreshold = 12
+)
+
+// AspectSignificance scores an aspect and tells whether it is significant.
+func AspectSignificance(severity, frequency int, legalRequirements []string) (score int, significant bool) {
+	score = severity * frequency
+	return score, score >= AspectSignificanceThreshold || len(legalRequirements) > 0
+}
+
+// EnvironmentalAspect is an entry of the aspects and impacts register: an
+// element of an activity that interacts with the environment and the change
+// to the environment it causes.
+// swagger:model EnvironmentalAspect
+type EnvironmentalAspect struct {
+	ID                int           `json:"id"`
+	ProcessID         *int          `json:"processId,omitempty"` // Process the activity belongs to
+	Process           string        `json:"process,omitempty"`   // Name of the process
+	Activity          string        `json:"activity"`            // e.g. Tank cleaning
+	Aspect            string        `json:"aspect"`              // e.g. Discharge of wash water
+	Impact            string        `json:"impact"`              // e.g. Contamination of surface water
+	Condition         string        `json:"condition"`           // Normal, Abnormal, Emergency
+	Severity          int           `json:"severity"`            // 1 to 5
+	Frequency         int           `json:"frequency"`           // 1 to 5
+	Score             int           `json:"score"`               // severity x frequency
+	Significant       bool          `json:"significant"`
+	LegalRequirements []string      `json:"legalRequirements"` // Compliance obligations, e.g. permit or regulation references
+	Controls          []RiskControl `json:"controls"`
+	Owner             string        `json:"owner"`
+	CreatedAt         string        `json:"createdAt"` // RFC3339
+	CreatedBy         string        `json:"createdBy"`
+	UpdatedAt         string        `json:"updatedAt"` // RFC3339
+	UpdatedBy         string        `json:"updatedBy"`
+}
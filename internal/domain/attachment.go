@@ -0,0 +1,18 @@
+package domain
+
+// Attachment is a file kept as evidence on a risk, incident, audit or
+// action. The content is stored once per SHA-256 checksum; the attachment
+// row links it to the record under the name it was uploaded with.
+// swagger:model Attachment
+type Attachment struct {
+	ID          int    `json:"id"`
+	RecordType  string `json:"recordType"` // risk, incident, audit, action
+	RecordID    int    `json:"recordId"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"` // Sniffed from the content
+	Size        int64  `json:"size"`        // Bytes
+	SHA256      string `json:"sha256"`      // Hex checksum of the content
+	Description string `json:"description,omitempty"`
+	UploadedAt  string `json:"uploadedAt"` // RFC3339
+	UploadedBy  string `json:"uploadedBy"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+// Event types published when records change.
+const (
+	EventRiskCreated           = "risk.created"
+	EventRiskStatusChanged     = "risk.status_changed"
+	EventRiskLevelChanged      = "risk.level_changed"
+	EventIncidentCreated       = "incident.created"
+	EventIncidentStatusChanged = "incident.status_changed"
+	EventAuditCreated          = "audit.created"
+	EventAuditStatusChanged    = "audit.status_changed"
+	EventActionCreated         = "action.created"
+	EventActionStatusChanged   = "action.status_changed"
+)
+
+// EventActionDueSoon is the type of the reminders sent before an action's
+// due date. The reminders are only notified, never published to webhooks.
+const EventActionDueSoon = "action.due_soon"
+
+// EventTypes lists every event type, for validating subscriptions.
+var EventTypes = []string{
+	EventRiskCreated, EventRiskStatusChanged, EventRiskLevelChanged,
+	EventIncidentCreated, EventIncidentStatusChanged,
+	EventAuditCreated, EventAuditStatusChanged,
+	EventActionCreated, EventActionStatusChanged,
+}
+
+// Event describes a change to a record after it was saved.
+// swagger:model Event
+type Event struct {
+	ID         string `json:"id"` // Unique per event; lets receivers drop duplicates
+	Type       string `json:"type"`
+	RecordType string `json:"recordType"` // risk, incident, audit, action
+	RecordID   int    `json:"recordId"`
+	Domain     Domain `json:"domain,omitempty"`
+	Actor      string `json:"actor"`
+	OccurredAt string `json:"occurredAt"`
+	From       string `json:"from,omitempty"` // Previous status or level for *_changed events
+	To         string `json:"to,omitempty"`
+	Record     any    `json:"record"` // The record as saved
+}
+
+// Notification channels.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Notification delivery states.
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // Gave up after the maximum number of attempts
+)
+
+// Notification is a rendered message in the outbox.
+type Notification struct {
+	ID            int    `json:"id"`
+	Channel       string `json:"channel"`
+	Recipient     string `json:"recipient"` // Email address or webhook URL
+	Subject       string `json:"subject"`
+	Body          string `json:"body"`
+	EventType     string `json:"eventType"`
+	RecordType    string `json:"recordType"`
+	RecordID      int    `json:"recordId"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt string `json:"nextAttemptAt"`
+	LastError     string `json:"lastError,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+	SentAt        string `json:"sentAt,omitempty"`
+	DedupKey      string `json:"dedupKey,omitempty"` // Queued once per key, channel and recipient
+}
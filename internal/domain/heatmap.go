@@ -0,0 +1,31 @@
+package domain
+
+// Heat map bases: which ratings place a risk in the matrix.
+const (
+	HeatmapInherent = "inherent"
+	HeatmapResidual = "residual"
+)
+
+var HeatmapBases = []string{HeatmapInherent, HeatmapResidual}
+
+// HeatmapCell is one likelihood/impact combination of the risk matrix with
+// the risks rated in it.
+type HeatmapCell struct {
+	Likelihood int    `json:"likelihood"`
+	Impact     int    `json:"impact"`
+	Score      int    `json:"score"`
+	Level      string `json:"level"`
+	Count      int    `json:"count"`
+	RiskIDs    []int  `json:"riskIds"`
+}
+
+// RiskHeatmap counts risks per cell of the risk matrix.
+// swagger:model RiskHeatmap
+type RiskHeatmap struct {
+	Basis           string        `json:"basis"` // inherent or residual
+	LikelihoodScale int           `json:"likelihoodScale"`
+	ImpactScale     int           `json:"impactScale"`
+	Levels          []string      `json:"levels"` // Band levels, lowest first
+	Total           int           `json:"total"`
+	Cells           []HeatmapCell `json:"cells"` // Every cell, by likelihood then impact
+}
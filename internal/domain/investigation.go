@@ -0,0 +1,50 @@
+package domain
+
+// Ishikawa cause categories (the six Ms).
+const (
+	CauseMan         = "Man"
+	CauseMachine     = "Machine"
+	CauseMethod      = "Method"
+	CauseMaterial    = "Material"
+	CauseMeasurement = "Measurement"
+	CauseEnvironment = "Environment"
+)
+
+var CauseCategories = []string{CauseMan, CauseMachine, CauseMethod, CauseMaterial, CauseMeasurement, CauseEnvironment}
+
+// Investigation is the root-cause analysis of an incident: a 5-Whys chain,
+// an Ishikawa (fishbone) diagram, or both. At most one why or cause is
+// flagged as the root cause; its text becomes the incident's RootCause.
+// swagger:model Investigation
+type Investigation struct {
+	IncidentID int                `json:"incidentId"`
+	Problem    string             `json:"problem"`   // Problem statement; the effect of the fishbone diagram
+	Whys       []WhyStep          `json:"whys"`      // In order, each answering the previous one
+	Fishbone   []FishboneCategory `json:"fishbone"`  // One entry per cause category
+	RootCause  string             `json:"rootCause"` // Text of the flagged why or cause, empty if none is flagged
+	CreatedAt  string             `json:"createdAt"` // RFC3339
+	CreatedBy  string             `json:"createdBy"`
+	UpdatedAt  string             `json:"updatedAt"` // RFC3339
+	UpdatedBy  string             `json:"updatedBy"`
+}
+
+// WhyStep is one step of a 5-Whys chain.
+type WhyStep struct {
+	Question  string `json:"question"` // e.g. "Why did the operator slip?"
+	Answer    string `json:"answer"`
+	RootCause bool   `json:"rootCause,omitempty"`
+}
+
+// FishboneCategory is a bone of an Ishikawa diagram.
+type FishboneCategory struct {
+	Category string      `json:"category"` // Man, Machine, Method, Material, Measurement, Environment
+	Causes   []CauseNode `json:"causes"`
+}
+
+// CauseNode is a cause in an Ishikawa diagram, with the causes that
+// contribute to it.
+type CauseNode struct {
+	Cause     string      `json:"cause"`
+	RootCause bool        `json:"rootCause,omitempty"`
+	Causes    []CauseNode `json:"causes,omitempty"`
+}
@@ -0,0 +1,100 @@
+package domain
+
+// Information asset types.
+const (
+	AssetInformation = "Information"
+	AssetSoftware    = "Software"
+	AssetHardware    = "Hardware"
+	AssetService     = "Service"
+	AssetPeople      = "People"
+	AssetSite        = "Site"
+)
+
+var AssetTypes = []string{AssetInformation, AssetSoftware, AssetHardware, AssetService, AssetPeople, AssetSite}
+
+// Information classification levels, from least to most sensitive.
+const (
+	ClassificationPublic       = "Public"
+	ClassificationInternal     = "Internal"
+	ClassificationConfidential = "Confidential"
+	ClassificationRestricted   = "Restricted"
+)
+
+var Classifications = []string{ClassificationPublic, ClassificationInternal, ClassificationConfidential, ClassificationRestricted}
+
+// AssetRatingScale is the highest confidentiality, integrity and
+// availability rating: 1 Low, 2 Medium, 3 High.
+const AssetRatingScale = 3
+
+// InformationAsset is an entry of the ISMS asset inventory (ISO 27001
+// Annex A 5.9).
+// swagger:model InformationAsset
+type InformationAsset struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Type            string `json:"type"` // Information, Software, Hardware, Service, People, Site
+	Owner           string `json:"owner"`
+	Location        string `json:"location"`
+	Classification  string `json:"classification"`  // Public, Internal, Confidential, Restricted
+	Confidentiality int    `json:"confidentiality"` // 1 to 3
+	Integrity       int    `json:"integrity"`       // 1 to 3
+	Availability    int    `json:"availability"`    // 1 to 3
+	Criticality     int    `json:"criticality"`     // Highest of the three ratings
+	CreatedAt       string `json:"createdAt"`       // RFC3339
+	CreatedBy       string `json:"createdBy"`
+	UpdatedAt       string `json:"updatedAt"` // RFC3339
+	UpdatedBy       string `json:"updatedBy"`
+}
+
+// Implementation statuses of ISMS controls.
+const (
+	ImplementationNotImplemented = "Not Implemented"
+	ImplementationPlanned        = "Planned"
+	ImplementationPartial        = "Partially Implemented"
+	ImplementationImplemented    = "Implemented"
+)
+
+var ImplementationStatuses = []string{ImplementationNotImplemented, ImplementationPlanned, ImplementationPartial, ImplementationImplemented}
+
+// ISMSControl is an entry of the controls catalogue: an ISO 27001:2022
+// Annex A control or an additional control of the organisation, with the
+// applicability decision the Statement of Applicability reports.
+// swagger:model ISMSControl
+type ISMSControl struct {
+	ID                   string `json:"id"`    // e.g. 5.15
+	Theme                string `json:"theme"` // Organizational, People, Physical, Technological
+	Title                string `json:"title"`
+	Description          string `json:"description"`
+	AnnexA               bool   `json:"annexA"` // Seeded from Annex A; these can't be deleted
+	Applicable           bool   `json:"applicable"`
+	Justification        string `json:"justification"`        // Why the control is included or excluded
+	ImplementationStatus string `json:"implementationStatus"` // Not Implemented, Planned, Partially Implemented, Implemented
+	Owner                string `json:"owner"`
+	UpdatedAt            string `json:"updatedAt"` // RFC3339
+	UpdatedBy            string `json:"updatedBy"`
+}
+
+// StatementOfApplicability lists every control of the catalogue with its
+// applicability, implementation status and the risks it treats.
+// swagger:model StatementOfApplicability
+type StatementOfApplicability struct {
+	GeneratedAt string         `json:"generatedAt"` // RFC3339
+	Controls    []SoAEntry     `json:"controls"`
+	Applicable  int            `json:"applicable"`
+	Excluded    int            `json:"excluded"`
+	ByStatus    map[string]int `json:"byStatus"` // Implementation status of the applicable controls
+}
+
+// SoAEntry is a control in the Statement of Applicability.
+type SoAEntry struct {
+	ISMSControl
+	Risks []SoARisk `json:"risks"` // Active risks linked to the control
+}
+
+type SoARisk struct {
+	ID            int    `json:"id"`
+	Title         string `json:"title"`
+	ResidualLevel string `json:"residualLevel"`
+	Status        string `json:"status"`
+}
@@ -39,35 +39,70 @@ func ParseDomain(s string) (Domain, error) {
 	}
 }
 
-// RiskLevelFromScore returns Low/Medium/High from numeric score.
-func RiskLevelFromScore(score int) string {
-	switch {
-	case score >= 16:
-		return "High"
-	case score >= 8:
-		return "Medium"
-	default:
-		return "Low"
-	}
-}
-
 // --------- Core IMS models ---------
 
-// Risk represents a risk in the integrated management system.
+// Risk represents a risk in the integrated management system. Likelihood,
+// impact, score and level rate the inherent risk before controls; the
+// residual fields rate what remains with the controls in place.
 // swagger:model Risk
 type Risk struct {
-	ID          int    `json:"id"`          // Auto-generated risk ID
-	Title       string `json:"title"`       // Short risk title
-	Process     string `json:"process"`     // Process where risk occurs
-	Domain      Domain `json:"domain"`      // IMS Domain (Quality/Environment/OHS/Information Security)
-	Description string `json:"description"` // Detailed description
-	Likelihood  int    `json:"likelihood"`  // 1-5
-	Impact      int    `json:"impact"`      // 1-5
-	Score       int    `json:"score"`       // Likelihood * Impact
-	Level       string `json:"level"`       // Low/Medium/High
-	Owner       string `json:"owner"`       // Responsible person / role
-	Status      string `json:"status"`      // Open, Accepted, Mitigated
-	CreatedAt   string `json:"createdAt"`   // RFC3339 timestamp
+	ID                 int            `json:"id"`                       // Auto-generated risk ID
+	Title              string         `json:"title"`                    // Short risk title
+	ProcessID          int            `json:"processId"`                // Process where risk occurs; see /api/processes
+	Process            string         `json:"process"`                  // Name of the process
+	Domain             Domain         `json:"domain"`                   // IMS Domain (Quality/Environment/OHS/Information Security)
+	Description        string         `json:"description"`              // Detailed description
+	Likelihood         int            `json:"likelihood"`               // 1 to the risk matrix's likelihoodScale
+	Impact             int            `json:"impact"`                   // 1 to the risk matrix's impactScale
+	Score              int            `json:"score"`                    // Likelihood * Impact
+	Level              string         `json:"level"`                    // Risk matrix level, e.g. Low/Medium/High
+	ResidualLikelihood int            `json:"residualLikelihood"`       // After controls; at most Likelihood
+	ResidualImpact     int            `json:"residualImpact"`           // After controls; at most Impact
+	ResidualScore      int            `json:"residualScore"`            // ResidualLikelihood * ResidualImpact
+	ResidualLevel      string         `json:"residualLevel"`            // Risk matrix level of the residual score
+	Controls           []RiskControl  `json:"controls"`                 // Controls applied to the risk
+	AssetIDs           []int          `json:"assetIds"`                 // Information assets at risk; Information Security risks only
+	ISMSControls       []string       `json:"ismsControls"`             // IDs of catalogue controls treating the risk, e.g. 5.15
+	Treatment          *RiskTreatment `json:"treatment,omitempty"`      // How the risk is treated; see /api/risks/{id}/treatment
+	LastReviewedAt     string         `json:"lastReviewedAt,omitempty"` // RFC3339 timestamp of the last review
+	NextReviewAt       string         `json:"nextReviewAt,omitempty"`   // YYYY-MM-DD; from the risk matrix's reviewDays for the level
+	Owner              string         `json:"owner"`                    // Responsible person / role
+	Status             string         `json:"status"`                   // Open, Accepted, Mitigated
+	CreatedAt          string         `json:"createdAt"`                // RFC3339 timestamp
+	CreatedBy          string         `json:"createdBy"`                // Username of the creator
+	UpdatedBy          string         `json:"updatedBy"`                // Username of the last editor
+	DeletedAt          string         `json:"deletedAt,omitempty"`      // RFC3339, set when soft-deleted
+	DeletedBy          string         `json:"deletedBy,omitempty"`      // Who soft-deleted the risk
+}
+
+// Control types.
+const (
+	ControlPreventive = "Preventive"
+	ControlDetective  = "Detective"
+	ControlCorrective = "Corrective"
+)
+
+// Control effectiveness ratings.
+const (
+	EffectivenessEffective   = "Effective"
+	EffectivenessPartial     = "Partially Effective"
+	EffectivenessIneffective = "Ineffective"
+	EffectivenessNotTested   = "Not Tested"
+)
+
+var (
+	ControlTypes           = []string{ControlPreventive, ControlDetective, ControlCorrective}
+	ControlEffectivenesses = []string{EffectivenessEffective, EffectivenessPartial, EffectivenessIneffective, EffectivenessNotTested}
+)
+
+// RiskControl is a control applied to a risk, such as a procedure, guard or
+// backup, with how well it works.
+// swagger:model RiskControl
+type RiskControl struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Type          string `json:"type,omitempty"` // Preventive, Detective, Corrective
+	Effectiveness string `json:"effectiveness"`  // Effective, Partially Effective, Ineffective, Not Tested
 }
 
 // Incident represents an incident / nonconformity.
@@ -78,14 +113,31 @@ type Incident struct {
 	Description   string `json:"description"`
 	Domain        Domain `json:"domain"`
 	RelatedRiskID *int   `json:"relatedRiskId,omitempty"`
-	Severity      int    `json:"severity"`   // 1-5
-	Likelihood    int    `json:"likelihood"` // 1-5
+	AspectID      *int   `json:"aspectId,omitempty"` // Environmental aspect, for Environment incidents
+	Severity      int    `json:"severity"`           // 1 to the risk matrix's impactScale
+	Likelihood    int    `json:"likelihood"`         // 1 to the risk matrix's likelihoodScale
 	RiskScore     int    `json:"riskScore"`
-	RiskLevel     string `json:"riskLevel"` // Low/Medium/High
+	RiskLevel     string `json:"riskLevel"` // Risk matrix level
 	RootCause     string `json:"rootCause"`
-	Status        string `json:"status"`    // Open, Investigation, Closed
-	CreatedAt     string `json:"createdAt"` // RFC3339
-	UpdatedAt     string `json:"updatedAt"` // RFC3339
+	Status        string `json:"status"`     // Open, Investigation, Closed
+	OccurredOn    string `json:"occurredOn"` // YYYY-MM-DD
+
+	// OHS classification (ISO 45001); Type is empty for other incidents.
+	Type              string         `json:"type,omitempty"` // Near Miss, Dangerous Occurrence, First Aid, Medical Treatment, Restricted Work, Lost Time, Fatality
+	InjuredPerson     *InjuredPerson `json:"injuredPerson,omitempty"`
+	BodyPart          string         `json:"bodyPart,omitempty"`          // Code from BodyParts
+	InjuryNature      string         `json:"injuryNature,omitempty"`      // Code from InjuryNatures
+	DaysLost          int            `json:"daysLost"`                    // Lost Time injuries only
+	Reportable        bool           `json:"reportable"`                  // Must be reported to the regulator
+	ReportingDeadline string         `json:"reportingDeadline,omitempty"` // YYYY-MM-DD
+	ReportedOn        string         `json:"reportedOn,omitempty"`        // YYYY-MM-DD
+
+	CreatedAt string `json:"createdAt"` // RFC3339
+	UpdatedAt string `json:"updatedAt"` // RFC3339
+	CreatedBy string `json:"createdBy"`
+	UpdatedBy string `json:"updatedBy"`
+	DeletedAt string `json:"deletedAt,omitempty"`
+	DeletedBy string `json:"deletedBy,omitempty"`
 }
 
 // Audit represents an internal IMS audit.
@@ -94,12 +146,18 @@ type Audit struct {
 	ID          int    `json:"id"`
 	Title       string `json:"title"`
 	Scope       string `json:"scope"`
-	Domain      Domain `json:"domain"`      // Main focus area
-	PlannedDate string `json:"plannedDate"` // YYYY-MM-DD
+	Domain      Domain `json:"domain"`              // Main focus area
+	ProcessID   *int   `json:"processId,omitempty"` // Audited process, if the audit covers one
+	Process     string `json:"process,omitempty"`   // Name of the process
+	PlannedDate string `json:"plannedDate"`         // YYYY-MM-DD
 	Auditor     string `json:"auditor"`
 	Status      string `json:"status"`   // Planned, In Progress, Completed
 	Findings    string `json:"findings"` // Text field
 	CreatedAt   string `json:"createdAt"`
+	CreatedBy   string `json:"createdBy"`
+	UpdatedBy   string `json:"updatedBy"`
+	DeletedAt   string `json:"deletedAt,omitempty"`
+	DeletedBy   string `json:"deletedBy,omitempty"`
 }
 
 // Action represents a corrective / preventive action (CAPA).
@@ -115,15 +173,120 @@ type Action struct {
 	Status      string `json:"status"`  // Open, In Progress, Done, Overdue
 	CreatedAt   string `json:"createdAt"`
 	UpdatedAt   string `json:"updatedAt"`
+	CreatedBy   string `json:"createdBy"`
+	UpdatedBy   string `json:"updatedBy"`
+	DeletedAt   string `json:"deletedAt,omitempty"`
+	DeletedBy   string `json:"deletedBy,omitempty"`
+}
+
+// --------- Accounts ---------
+
+// User is an account that can call the API.
+// swagger:model User
+type User struct {
+	ID           int              `json:"id"`
+	Username     string           `json:"username"`
+	DisplayName  string           `json:"displayName"`
+	Email        string           `json:"email,omitempty"` // Where notifications for this user are sent
+	PasswordHash string           `json:"-"`
+	Active       bool             `json:"active"`
+	Roles        []RoleAssignment `json:"roles"`
+	CreatedAt    string           `json:"createdAt"`
+	System       bool             `json:"-"` // Internal actor for background jobs; bypasses authorization
+}
+
+// Token kinds.
+const (
+	TokenKindAPI     = "api"     // Long-lived token created explicitly by a user
+	TokenKindSession = "session" // Short-lived token issued by password login
+)
+
+// APIToken is a bearer token issued to a user. Only its hash is stored.
+// swagger:model APIToken
+type APIToken struct {
+	ID         int    `json:"id"`
+	UserID     int    `json:"userId"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"` // api, session
+	TokenHash  string `json:"-"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt,omitempty"` // RFC3339, empty = never
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
+}
+
+// --------- Change history ---------
+
+// Record kinds tracked in the change history.
+const (
+	KindRisk     = "risk"
+	KindIncident = "incident"
+	KindAudit    = "audit"
+	KindAction   = "action"
+)
+
+// History entry actions.
+const (
+	HistoryCreated  = "created"
+	HistoryUpdated  = "updated"
+	HistoryDeleted  = "deleted"
+	HistoryRestored = "restored"
+)
+
+// FieldChange is the before/after value of one field. Before is null for
+// created records.
+// swagger:model FieldChange
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// HistoryEntry is one append-only change to a risk, incident, audit or action.
+// swagger:model HistoryEntry
+type HistoryEntry struct {
+	ID         int           `json:"id"`
+	RecordType string        `json:"recordType"` // risk, incident, audit, action
+	RecordID   int           `json:"recordId"`
+	Action     string        `json:"action"` // created, updated, deleted, restored
+	Actor      string        `json:"actor"`
+	ChangedAt  string        `json:"changedAt"`
+	Changes    []FieldChange `json:"changes"`
+	PrevHash   string        `json:"prevHash"` // Hash of the previous entry in the whole history ("" for the first)
+	Hash       string        `json:"hash"`     // SHA-256 over this entry's content and PrevHash
+}
+
+// HistoryVerification is the result of walking the history hash chain.
+// swagger:model HistoryVerification
+type HistoryVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`            // Entries verified before stopping
+	HeadHash string `json:"headHash,omitempty"` // Hash of the newest entry when valid; keep it elsewhere to detect truncation
+	BrokenID int    `json:"brokenId,omitempty"` // First entry whose link does not verify
+	Reason   string `json:"reason,omitempty"`
 }
 
 // Dashboard aggregates KPIs for IMS.
 // swagger:model Dashboard
 type Dashboard struct {
-	TotalRisks        int            `json:"totalRisks"`
-	HighRisks         int            `json:"highRisks"`
-	TotalIncidents    int            `json:"totalIncidents"`
-	OpenIncidents     int            `json:"openIncidents"`
-	ActionsByStatus   map[string]int `json:"actionsByStatus"`
-	IncidentsByDomain map[Domain]int `json:"incidentsByDomain"`
+	TotalRisks                    int                      `json:"totalRisks"`
+	HighRisks                     int                      `json:"highRisks"` // Risks in escalating bands of the risk matrix
+	TotalIncidents                int                      `json:"totalIncidents"`
+	OpenIncidents                 int                      `json:"openIncidents"`
+	ActionsByStatus               map[string]int           `json:"actionsByStatus"`
+	IncidentsByDomain             map[Domain]int           `json:"incidentsByDomain"`
+	RiskReduction                 map[Domain]RiskReduction `json:"riskReduction"`                 // Inherent vs residual risk per domain
+	OverdueReviews                int                      `json:"overdueReviews"`                // Risks whose nextReviewAt has passed
+	OHS                           *OHSStatistics           `json:"ohs"`                           // OHS injuries over the last 12 months; null without read access to OHS
+	SignificantAspects            int                      `json:"significantAspects"`            // If the caller can read Environment
+	SignificantAspectsByCondition map[string]int           `json:"significantAspectsByCondition"` // Normal, Abnormal, Emergency
+}
+
+// RiskReduction sums the inherent and residual scores of a domain's active
+// risks.
+// swagger:model RiskReduction
+type RiskReduction struct {
+	Risks            int     `json:"risks"`
+	InherentScore    int     `json:"inherentScore"`
+	ResidualScore    int     `json:"residualScore"`
+	ReductionPercent float64 `json:"reductionPercent"` // Share of the inherent score removed by controls, one decimal
 }
@@ -0,0 +1,114 @@
+package domain
+
+// Incident types (ISO 45001). Near misses and dangerous occurrences hurt
+// nobody; the others are injuries, in increasing order of severity.
+const (
+	IncidentNearMiss            = "Near Miss"
+	IncidentDangerousOccurrence = "Dangerous Occurrence"
+	IncidentFirstAid            = "First Aid"
+	IncidentMedicalTreatment    = "Medical Treatment"
+	IncidentRestrictedWork      = "Restricted Work"
+	IncidentLostTime            = "Lost Time"
+	IncidentFatality            = "Fatality"
+)
+
+var IncidentTypes = []string{
+	IncidentNearMiss, IncidentDangerousOccurrence, IncidentFirstAid,
+	IncidentMedicalTreatment, IncidentRestrictedWork, IncidentLostTime, IncidentFatality,
+}
+
+// IsInjury reports whether an incident type involves an injured person.
+func IsInjury(incidentType string) bool {
+	switch incidentType {
+	case IncidentFirstAid, IncidentMedicalTreatment, IncidentRestrictedWork, IncidentLostTime, IncidentFatality:
+		return true
+	}
+	return false
+}
+
+// IsRecordable reports whether an injury counts towards the TRIR: anything
+// beyond first aid.
+func IsRecordable(incidentType string) bool {
+	return IsInjury(incidentType) && incidentType != IncidentFirstAid
+}
+
+// IsLostTime reports whether an injury counts towards the LTIFR.
+func IsLostTime(incidentType string) bool {
+	return incidentType == IncidentLostTime || incidentType == IncidentFatality
+}
+
+// Employment relations of an injured person.
+var EmploymentTypes = []string{"Employee", "Contractor", "Agency Worker", "Visitor", "Member of the Public"}
+
+// InjuredPerson identifies who was hurt in an incident.
+// swagger:model InjuredPerson
+type InjuredPerson struct {
+	Name       string `json:"name"`
+	JobTitle   string `json:"jobTitle,omitempty"`
+	Employment string `json:"employment,omitempty"` // Employee, Contractor, Agency Worker, Visitor, Member of the Public
+}
+
+// Code is an entry of a classification code list.
+// swagger:model Code
+type Code struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+// BodyParts codes the part of the body injured.
+var BodyParts = []Code{
+	{"HEAD", "Head"}, {"EYE", "Eye"}, {"FACE", "Face"}, {"NECK", "Neck"},
+	{"BACK", "Back"}, {"TRUNK", "Chest or abdomen"}, {"SHOULDER", "Shoulder"},
+	{"ARM", "Arm or elbow"}, {"WRIST", "Wrist"}, {"HAND", "Hand"}, {"FINGER", "Finger or thumb"},
+	{"HIP", "Hip or pelvis"}, {"LEG", "Leg"}, {"KNEE", "Knee"}, {"ANKLE", "Ankle"},
+	{"FOOT", "Foot"}, {"TOE", "Toe"}, {"INTERNAL", "Internal organs"}, {"MULTIPLE", "Several parts"},
+}
+
+// InjuryNatures codes the nature of an injury.
+var InjuryNatures = []Code{
+	{"CUT", "Cut or laceration"}, {"BRUISE", "Bruise or contusion"}, {"FRACTURE", "Fracture"},
+	{"SPRAIN", "Sprain or strain"}, {"DISLOCATION", "Dislocation"}, {"BURN", "Burn or scald"},
+	{"CHEMICAL", "Chemical burn or exposure"}, {"ELECTRIC", "Electric shock"}, {"CRUSH", "Crushing"},
+	{"AMPUTATION", "Amputation"}, {"FOREIGN_BODY", "Foreign body"}, {"CONCUSSION", "Concussion"},
+	{"HEARING", "Hearing loss"}, {"RESPIRATORY", "Respiratory condition"},
+	{"MSD", "Musculoskeletal disorder"}, {"POISONING", "Poisoning"}, {"MULTIPLE", "Several injuries"},
+	{"OTHER", "Other"},
+}
+
+// OHSCodes lists the classification values incidents accept.
+// swagger:model OHSCodes
+type OHSCodes struct {
+	IncidentTypes   []string `json:"incidentTypes"`
+	EmploymentTypes []string `json:"employmentTypes"`
+	BodyParts       []Code   `json:"bodyParts"`
+	InjuryNatures   []Code   `json:"injuryNatures"`
+}
+
+// HoursWorked is the number of hours worked by all workers in a month, the
+// exposure the injury rates are computed against.
+// swagger:model HoursWorked
+type HoursWorked struct {
+	Month     string  `json:"month"` // YYYY-MM
+	Hours     float64 `json:"hours"`
+	UpdatedAt string  `json:"updatedAt"` // RFC3339
+	UpdatedBy string  `json:"updatedBy"`
+}
+
+// OHSStatistics summarises injuries over a period. The rates only cover
+// the months hours worked were recorded for; they are null when there are
+// none.
+// swagger:model OHSStatistics
+type OHSStatistics struct {
+	From                string         `json:"from"` // YYYY-MM
+	To                  string         `json:"to"`   // YYYY-MM
+	MonthsCovered       int            `json:"monthsCovered"`
+	HoursWorked         float64        `json:"hoursWorked"`
+	ByType              map[string]int `json:"byType"` // Incidents that occurred in the period, by type
+	RecordableInjuries  int            `json:"recordableInjuries"`
+	LostTimeInjuries    int            `json:"lostTimeInjuries"`
+	DaysLost            int            `json:"daysLost"`
+	LTIFR               *float64       `json:"ltifr"` // Lost-time injuries per 1,000,000 hours worked
+	TRIR                *float64       `json:"trir"`  // Recordable injuries per 200,000 hours worked
+	ReportableIncidents int            `json:"reportableIncidents"`
+	OverdueReports      int            `json:"overdueReports"` // Reportable incidents not reported by their deadline, of any period
+}
@@ -0,0 +1,48 @@
+package domain
+
+// Process is an entry of the process register (ISO 9001 4.4). Every risk
+// belongs to a process; audits may cover one.
+// swagger:model Process
+type Process struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"` // Unique regardless of letter case
+	Description string       `json:"description"`
+	Owner       string       `json:"owner"`              // Process owner (username or role)
+	ParentID    *int         `json:"parentId,omitempty"` // Parent process, for sub-processes
+	Domains     []Domain     `json:"domains"`            // IMS domains the process falls under
+	KPIs        []ProcessKPI `json:"kpis"`
+	CreatedAt   string       `json:"createdAt"` // RFC3339
+	CreatedBy   string       `json:"createdBy"`
+	UpdatedAt   string       `json:"updatedAt"` // RFC3339
+	UpdatedBy   string       `json:"updatedBy"`
+}
+
+// ProcessKPI is a performance indicator monitored for a process (ISO 9001
+// 9.1).
+// swagger:model ProcessKPI
+type ProcessKPI struct {
+	Name      string `json:"name"`
+	Target    string `json:"target"`              // e.g. ">= 98%"
+	Frequency string `json:"frequency,omitempty"` // e.g. Monthly
+}
+
+// RecordCounts counts records by status.
+type RecordCounts struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"byStatus"`
+}
+
+// ProcessSummary aggregates the records of a process in the domains the
+// caller can read. Incidents count through their related risk, actions
+// through the risk, incident or audit they were raised from.
+// swagger:model ProcessSummary
+type ProcessSummary struct {
+	Process      *Process       `json:"process"`
+	Subprocesses []*Process     `json:"subprocesses"`
+	Risks        RecordCounts   `json:"risks"`
+	RisksByLevel map[string]int `json:"risksByLevel"`
+	HighRisks    int            `json:"highRisks"` // Risks in escalating levels of the risk matrix
+	Incidents    RecordCounts   `json:"incidents"`
+	Audits       RecordCounts   `json:"audits"`
+	Actions      RecordCounts   `json:"actions"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+// RiskReview records a periodic review of a risk: who reviewed it, the
+// ratings it was left with and when it is due again.
+// swagger:model RiskReview
+type RiskReview struct {
+	ID                 int    `json:"id"`
+	RiskID             int    `json:"riskId"`
+	Reviewer           string `json:"reviewer"`   // Username
+	ReviewedAt         string `json:"reviewedAt"` // RFC3339
+	Comments           string `json:"comments"`
+	Rescored           bool   `json:"rescored"` // Whether the review changed any rating
+	PreviousScore      int    `json:"previousScore"`
+	PreviousLevel      string `json:"previousLevel"`
+	Likelihood         int    `json:"likelihood"`
+	Impact             int    `json:"impact"`
+	Score              int    `json:"score"`
+	Level              string `json:"level"`
+	ResidualLikelihood int    `json:"residualLikelihood"`
+	ResidualImpact     int    `json:"residualImpact"`
+	ResidualScore      int    `json:"residualScore"`
+	ResidualLevel      string `json:"residualLevel"`
+	NextReviewAt       string `json:"nextReviewAt,omitempty"` // YYYY-MM-DD
+}
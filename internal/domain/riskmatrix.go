@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMatrix = errors.New("invalid risk matrix")
+
+// Limits on matrix size and review frequency.
+const (
+	MinMatrixScale = 2
+	MaxMatrixScale = 10
+	MaxReviewDays  = 3650
+)
+
+// RiskBand maps a range of scores to a level. A band covers scores from
+// MinScore up to the next band's MinScore.
+type RiskBand struct {
+	Level      string `json:"level"`
+	MinScore   int    `json:"minScore"`
+	Escalate   bool   `json:"escalate"`   // Counts as high risk: dashboard highRisks and incident escalation emails
+	ReviewDays int    `json:"reviewDays"` // Days between reviews of risks in this level; 0 for no scheduled review
+}
+
+// RiskCell assigns a level to one likelihood/impact combination regardless of
+// the score bands.
+type RiskCell struct {
+	Likelihood int    `json:"likelihood"`
+	Impact     int    `json:"impact"`
+	Level      string `json:"level"`
+}
+
+// RiskMatrix configures how ratings are scored and banded into levels. Risks
+// are rated by likelihood and impact, incidents by likelihood and severity
+// on the impact scale. The score is likelihood × impact.
+// swagger:model RiskMatrix
+type RiskMatrix struct {
+	LikelihoodScale int        `json:"likelihoodScale"` // Ratings run from 1 to this
+	ImpactScale     int        `json:"impactScale"`     // Also the incident severity scale
+	Bands           []RiskBand `json:"bands"`           // Lowest first; the first starts at score 1
+	Cells           []RiskCell `json:"cells"`           // Optional per-cell overrides
+	UpdatedAt       string     `json:"updatedAt,omitempty"`
+	UpdatedBy       string     `json:"updatedBy,omitempty"`
+}
+
+// DefaultRiskMatrix is the 5×5 matrix used until another one is saved.
+func DefaultRiskMatrix() *RiskMatrix {
+	return &RiskMatrix{
+		LikelihoodScale: 5,
+		ImpactScale:     5,
+		Bands: []RiskBand{
+			{Level: "Low", MinScore: 1, ReviewDays: 365},
+			{Level: "Medium", MinScore: 8, ReviewDays: 180},
+			{Level: "High", MinScore: 16, Escalate: true, ReviewDays: 90},
+		},
+		Cells: []RiskCell{},
+	}
+}
+
+// Validate checks the matrix and normalises cell levels to the band labels'
+// spelling. Errors wrap ErrInvalidMatrix.
+func (m *RiskMatrix) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidMatrix, fmt.Sprintf(format, args...))
+	}
+	for _, s := range []struct {
+		name  string
+		value int
+	}{{"likelihoodScale", m.LikelihoodScale}, {"impactScale", m.ImpactScale}} {
+		if s.value < MinMatrixScale || s.value > MaxMatrixScale {
+			return invalid("%s must be between %d and %d", s.name, MinMatrixScale, MaxMatrixScale)
+		}
+	}
+
+	if len(m.Bands) == 0 {
+		return invalid("at least one band is required")
+	}
+	maxScore := m.LikelihoodScale * m.ImpactScale
+	seen := make(map[string]bool)
+	for i := range m.Bands {
+		b := &m.Bands[i]
+		b.Level = strings.TrimSpace(b.Level)
+		if b.Level == "" {
+			return invalid("band %d has no level", i+1)
+		}
+		if seen[strings.ToLower(b.Level)] {
+			return invalid("level %q is used by more than one band", b.Level)
+		}
+		seen[strings.ToLower(b.Level)] = true
+		switch {
+		case i == 0 && b.MinScore != 1:
+			return invalid("the first band must start at score 1")
+		case i > 0 && b.MinScore <= m.Bands[i-1].MinScore:
+			return invalid("band minScores must increase; %q starts at %d", b.Level, b.MinScore)
+		case b.MinScore > maxScore:
+			return invalid("band %q starts at %d, above the highest score %d", b.Level, b.MinScore, maxScore)
+		case b.ReviewDays < 0 || b.ReviewDays > MaxReviewDays:
+			return invalid("band %q reviewDays must be between 0 and %d", b.Level, MaxReviewDays)
+		}
+	}
+
+	cells := make(map[[2]int]bool)
+	for i := range m.Cells {
+		c := &m.Cells[i]
+		if c.Likelihood < 1 || c.Likelihood > m.LikelihoodScale || c.Impact < 1 || c.Impact > m.ImpactScale {
+			return invalid("cell %d×%d is outside the matrix", c.Likelihood, c.Impact)
+		}
+		key := [2]int{c.Likelihood, c.Impact}
+		if cells[key] {
+			return invalid("cell %d×%d is mapped more than once", c.Likelihood, c.Impact)
+		}
+		cells[key] = true
+		level, ok := m.NormalizeLevel(c.Level)
+		if !ok {
+			return invalid("cell %d×%d maps to unknown level %q", c.Likelihood, c.Impact, c.Level)
+		}
+		c.Level = level
+	}
+	if m.Cells == nil {
+		m.Cells = []RiskCell{}
+	}
+	return nil
+}
+
+// InRange reports whether likelihood and impact are valid ratings.
+func (m *RiskMatrix) InRange(likelihood, impact int) bool {
+	return likelihood >= 1 && likelihood <= m.LikelihoodScale && impact >= 1 && impact <= m.ImpactScale
+}
+
+// Assess returns the score and level of a rating. Ratings must be in range.
+func (m *RiskMatrix) Assess(likelihood, impact int) (score int, level string) {
+	score = likelihood * impact
+	for _, c := range m.Cells {
+		if c.Likelihood == likelihood && c.Impact == impact {
+			return score, c.Level
+		}
+	}
+	for _, b := range m.Bands {
+		if score >= b.MinScore {
+			level = b.Level
+		}
+	}
+	return score, level
+}
+
+// Escalates reports whether level belongs to a band marked for escalation.
+func (m *RiskMatrix) Escalates(level string) bool {
+	for _, b := range m.Bands {
+		if strings.EqualFold(b.Level, level) {
+			return b.Escalate
+		}
+	}
+	return false
+}
+
+// NextReview returns the date (YYYY-MM-DD) a risk in level is next due for
+// review when last reviewed (or raised) at from, or "" when the level has no
+// scheduled review.
+func (m *RiskMatrix) NextReview(level string, from time.Time) string {
+	for _, b := range m.Bands {
+		if strings.EqualFold(b.Level, level) && b.ReviewDays > 0 {
+			return from.AddDate(0, 0, b.ReviewDays).Format(time.DateOnly)
+		}
+	}
+	return ""
+}
+
+// NormalizeLevel maps a level in any letter case to its band label.
+func (m *RiskMatrix) NormalizeLevel(level string) (string, bool) {
+	for _, b := range m.Bands {
+		if strings.EqualFold(b.Level, strings.TrimSpace(level)) {
+			return b.Level, true
+		}
+	}
+	return "", false
+}
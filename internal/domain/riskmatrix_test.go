@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultRiskMatrixIsValid(t *testing.T) {
+	if err := DefaultRiskMatrix().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRiskMatrixValidate(t *testing.T) {
+	bands := func(bs ...RiskBand) func(m *RiskMatrix) {
+		return func(m *RiskMatrix) { m.Bands = bs }
+	}
+	cells := func(cs ...RiskCell) func(m *RiskMatrix) {
+		return func(m *RiskMatrix) { m.Cells = cs }
+	}
+	tests := []struct {
+		name   string
+		change func(m *RiskMatrix)
+		reason string // Empty when the matrix is valid
+	}{
+		{"likelihood scale too small", func(m *RiskMatrix) { m.LikelihoodScale = 1 }, "likelihoodScale must be between 2 and 10"},
+		{"impact scale too large", func(m *RiskMatrix) { m.ImpactScale = 11 }, "impactScale must be between 2 and 10"},
+		{"smallest scales", func(m *RiskMatrix) { m.LikelihoodScale, m.ImpactScale = 2, 2; m.Bands = m.Bands[:1] }, ""},
+		{"no bands", bands(), "at least one band is required"},
+		{"band without level", bands(RiskBand{Level: " ", MinScore: 1}), "band 1 has no level"},
+		{"first band above 1", bands(RiskBand{Level: "Low", MinScore: 2}), "the first band must start at score 1"},
+		{"bands out of order", bands(RiskBand{Level: "Low", MinScore: 1}, RiskBand{Level: "High", MinScore: 9}, RiskBand{Level: "Medium", MinScore: 9}),
+			`band minScores must increase; "Medium" starts at 9`},
+		{"band above the highest score", bands(RiskBand{Level: "Low", MinScore: 1}, RiskBand{Level: "High", MinScore: 26}),
+			`band "High" starts at 26, above the highest score 25`},
+		{"band at the highest score", bands(RiskBand{Level: "Low", MinScore: 1}, RiskBand{Level: "Extreme", MinScore: 25}), ""},
+		{"duplicate level", bands(RiskBand{Level: "Low", MinScore: 1}, RiskBand{Level: "low", MinScore: 5}), `level "low" is used by more than one band`},
+		{"negative review days", bands(RiskBand{Level: "Low", MinScore: 1, ReviewDays: -1}), `band "Low" reviewDays must be between 0 and 3650`},
+		{"review days too long", bands(RiskBand{Level: "Low", MinScore: 1, ReviewDays: MaxReviewDays + 1}), "reviewDays must be between"},
+		{"cell outside the matrix", cells(RiskCell{Likelihood: 6, Impact: 1, Level: "High"}), "cell 6×1 is outside the matrix"},
+		{"cell below the matrix", cells(RiskCell{Likelihood: 1, Impact: 0, Level: "High"}), "cell 1×0 is outside the matrix"},
+		{"cell mapped twice", cells(RiskCell{Likelihood: 1, Impact: 5, Level: "High"}, RiskCell{Likelihood: 1, Impact: 5, Level: "Low"}),
+			"cell 1×5 is mapped more than once"},
+		{"cell with unknown level", cells(RiskCell{Likelihood: 1, Impact: 5, Level: "Severe"}), `cell 1×5 maps to unknown level "Severe"`},
+		{"cell override", cells(RiskCell{Likelihood: 1, Impact: 5, Level: "high"}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := DefaultRiskMatrix()
+			tt.change(m)
+			err := m.Validate()
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidMatrix) || !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("Validate = %v, want %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRiskMatrixValidateNormalizes(t *testing.T) {
+	m := DefaultRiskMatrix()
+	m.Bands[2].Level = "  High "
+	m.Cells = nil
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Bands[2].Level != "High" || m.Cells == nil {
+		t.Errorf("band level %q, cells %v", m.Bands[2].Level, m.Cells)
+	}
+
+	m.Cells = []RiskCell{{Likelihood: 1, Impact: 5, Level: "HIGH"}}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Cells[0].Level != "High" {
+		t.Errorf("cell level %q, want the band's spelling", m.Cells[0].Level)
+	}
+}
+
+func TestRiskMatrixAssess(t *testing.T) {
+	m := DefaultRiskMatrix()
+	m.Cells = []RiskCell{{Likelihood: 1, Impact: 5, Level: "High"}}
+	for _, tt := range []struct {
+		likelihood, impact int
+		score              int
+		level              string
+	}{
+		{1, 1, 1, "Low"},
+		{2, 3, 6, "Low"},
+		{2, 4, 8, "Medium"},
+		{3, 5, 15, "Medium"},
+		{4, 4, 16, "High"},
+		{5, 5, 25, "High"},
+		{1, 5, 5, "High"}, // Cell override
+	} {
+		score, level := m.Assess(tt.likelihood, tt.impact)
+		if score != tt.score || level != tt.level {
+			t.Errorf("Assess(%d, %d) = %d, %s; want %d, %s", tt.likelihood, tt.impact, score, level, tt.score, tt.level)
+		}
+	}
+}
+
+func TestRiskMatrixInRange(t *testing.T) {
+	m := &RiskMatrix{LikelihoodScale: 4, ImpactScale: 3}
+	for _, tt := range []struct {
+		likelihood, impact int
+		want               bool
+	}{{1, 1, true}, {4, 3, true}, {0, 1, false}, {5, 1, false}, {1, 4, false}, {1, 0, false}} {
+		if got := m.InRange(tt.likelihood, tt.impact); got != tt.want {
+			t.Errorf("InRange(%d, %d) = %v, want %v", tt.likelihood, tt.impact, got, tt.want)
+		}
+	}
+}
+
+func TestRiskMatrixLevels(t *testing.T) {
+	m := DefaultRiskMatrix()
+	m.Bands[0].ReviewDays = 0
+	if !m.Escalates("high") || m.Escalates("Medium") || m.Escalates("Unknown") {
+		t.Error("only High escalates")
+	}
+	from := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	if got := m.NextReview("HIGH", from); got != "2026-05-01" {
+		t.Errorf("NextReview(High) = %q, want 90 days later", got)
+	}
+	if got := m.NextReview("Low", from); got != "" {
+		t.Errorf("NextReview(Low) = %q for a band without reviews", got)
+	}
+	if got, ok := m.NormalizeLevel(" medium "); !ok || got != "Medium" {
+		t.Errorf("NormalizeLevel = %q, %v", got, ok)
+	}
+	if _, ok := m.NormalizeLevel("Severe"); ok {
+		t.Error("NormalizeLevel accepted an unknown level")
+	}
+}
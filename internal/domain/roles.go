@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidRole = errors.New("invalid role")
+
+// Role is a set of permissions a user holds within an IMS domain.
+type Role string
+
+const (
+	RoleViewer       Role = "viewer"        // Read records
+	RoleContributor  Role = "contributor"   // Raise and edit risks, incidents and actions
+	RoleProcessOwner Role = "process_owner" // Contributor + accept risks, close incidents, delete records
+	RoleAuditor      Role = "auditor"       // Plan audits and record findings
+	RoleIMSManager   Role = "ims_manager"   // Everything, including user administration
+)
+
+// ParseRole maps role names such as "process owner" or "IMS-Manager" to Role constants.
+func ParseRole(s string) (Role, error) {
+	r := strings.NewReplacer(" ", "_", "-", "_").Replace(normalize(s))
+	switch Role(r) {
+	case RoleViewer, RoleContributor, RoleProcessOwner, RoleAuditor, RoleIMSManager:
+		return Role(r), nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// RoleAssignment grants a role in one IMS domain, or in every domain when
+// Domain is empty.
+type RoleAssignment struct {
+	Role   Role   `json:"role"`
+	Domain Domain `json:"domain,omitempty"`
+}
+
+// ParseRoleAssignment parses "role" or "role@domain", e.g. "auditor@ohs".
+func ParseRoleAssignment(s string) (RoleAssignment, error) {
+	roleStr, domStr, scoped := strings.Cut(strings.TrimSpace(s), "@")
+	role, err := ParseRole(roleStr)
+	if err != nil {
+		return RoleAssignment{}, err
+	}
+	ra := RoleAssignment{Role: role}
+	if scoped {
+		dom, err := ParseDomain(domStr)
+		if err != nil {
+			return RoleAssignment{}, err
+		}
+		ra.Domain = dom
+	}
+	return ra, nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+// Risk treatment options (ISO 31000).
+const (
+	TreatmentAvoid    = "Avoid"
+	TreatmentReduce   = "Reduce"
+	TreatmentTransfer = "Transfer"
+	TreatmentAccept   = "Accept"
+)
+
+var TreatmentOptions = []string{TreatmentAvoid, TreatmentReduce, TreatmentTransfer, TreatmentAccept}
+
+// RiskTreatment records how a risk is treated. Changing the option or
+// justification, or a change of the risk's level, withdraws the approval.
+// swagger:model RiskTreatment
+type RiskTreatment struct {
+	Option        string `json:"option"` // Avoid, Reduce, Transfer, Accept
+	Justification string `json:"justification"`
+	ApprovedBy    string `json:"approvedBy,omitempty"` // Username of the approver
+	ApprovedAt    string `json:"approvedAt,omitempty"` // RFC3339
+}
+
+// Approved reports whether the plan has been approved.
+func (t *RiskTreatment) Approved() bool {
+	return t != nil && t.ApprovedAt != ""
+}
+
+// TreatmentPlan is a risk's treatment together with the actions raised from
+// the risk to carry it out.
+// swagger:model TreatmentPlan
+type TreatmentPlan struct {
+	RiskID      int            `json:"riskId"`
+	RiskStatus  string         `json:"riskStatus"`
+	RiskLevel   string         `json:"riskLevel"`
+	Treatment   *RiskTreatment `json:"treatment"` // null until a plan is set
+	Actions     []*Action      `json:"actions"`   // Actions with sourceType Risk and this risk's ID
+	ActionsDone int            `json:"actionsDone"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+// RiskScore is one point in a risk's score history, written whenever its
+// ratings, scores or levels change.
+// swagger:model RiskScore
+type RiskScore struct {
+	RiskID             int    `json:"riskId"`
+	RecordedAt         string `json:"recordedAt"` // RFC3339
+	ChangedBy          string `json:"changedBy"`
+	Likelihood         int    `json:"likelihood"`
+	Impact             int    `json:"impact"`
+	Score              int    `json:"score"`
+	Level              string `json:"level"`
+	ResidualLikelihood int    `json:"residualLikelihood"`
+	ResidualImpact     int    `json:"residualImpact"`
+	ResidualScore      int    `json:"residualScore"`
+	ResidualLevel      string `json:"residualLevel"`
+}
+
+// RiskTrendPoint is the risk profile at the end of a week (or now, for the
+// current week).
+// swagger:model RiskTrendPoint
+type RiskTrendPoint struct {
+	WeekStart    string                    `json:"weekStart"` // Monday, YYYY-MM-DD
+	Total        int                       `json:"total"`
+	AverageScore float64                   `json:"averageScore"` // One decimal
+	ByLevel      map[string]int            `json:"byLevel"`
+	ByDomain     map[Domain]map[string]int `json:"byDomain"` // Counts per level within each domain
+}
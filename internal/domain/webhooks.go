@@ -0,0 +1,64 @@
+package domain
+
+import "encoding/json"
+
+// WebhookAllEvents subscribes a webhook to every event type.
+const WebhookAllEvents = "*"
+
+// Webhook is a subscription that receives record events as signed HTTP POSTs.
+// swagger:model Webhook
+type Webhook struct {
+	ID          int      `json:"id"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"` // Event types, or "*" for all
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	Secret      string   `json:"-"` // HMAC-SHA256 signing key
+	CreatedAt   string   `json:"createdAt"`
+	CreatedBy   string   `json:"createdBy"`
+	UpdatedAt   string   `json:"updatedAt"`
+	UpdatedBy   string   `json:"updatedBy"`
+}
+
+// Matches reports whether the webhook is subscribed to eventType.
+func (w *Webhook) Matches(eventType string) bool {
+	for _, e := range w.Events {
+		if e == WebhookAllEvents || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook delivery states.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // Gave up after the maximum number of attempts
+)
+
+// WebhookDelivery is one event queued for one webhook.
+// swagger:model WebhookDelivery
+type WebhookDelivery struct {
+	ID            int              `json:"id"`
+	WebhookID     int              `json:"webhookId"`
+	EventID       string           `json:"eventId"`
+	EventType     string           `json:"eventType"`
+	Payload       json.RawMessage  `json:"payload" swaggertype:"object"` // Body as posted
+	Status        string           `json:"status"`                       // pending, delivered, failed
+	Attempts      int              `json:"attempts"`
+	NextAttemptAt string           `json:"nextAttemptAt,omitempty"` // Pending deliveries only
+	CreatedAt     string           `json:"createdAt"`
+	DeliveredAt   string           `json:"deliveredAt,omitempty"`
+	AttemptLog    []WebhookAttempt `json:"attemptLog"`
+}
+
+// WebhookAttempt records one POST of a delivery.
+// swagger:model WebhookAttempt
+type WebhookAttempt struct {
+	Attempt     int    `json:"attempt"` // 1-based
+	AttemptedAt string `json:"attemptedAt"`
+	StatusCode  int    `json:"statusCode"` // 0 when no response was received
+	DurationMs  int64  `json:"durationMs"` // Response time
+	Error       string `json:"error,omitempty"`
+}
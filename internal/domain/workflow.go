@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidTransition = errors.New("status transition not allowed")
+	ErrGuardFailed       = errors.New("status transition guard failed")
+)
+
+// Guard is a condition a record must meet to enter a status and to stay in
+// it while it is edited.
+type Guard[T any] struct {
+	Name    string // Stable identifier returned to clients, e.g. "root_cause_required"
+	Message string // Human-readable reason shown when the guard fails
+	Allow   func(T) bool
+}
+
+// Workflow is a declarative state machine over a record's Status. Moving to
+// the current status is not a transition and is always allowed, but the
+// status's guards still apply.
+type Workflow[T any] struct {
+	Entity      string
+	States      []string
+	Transitions map[string][]string   // from -> allowed targets
+	Guards      map[string][]Guard[T] // target -> conditions to enter it
+}
+
+// Normalize maps a status in any letter case to one of the workflow's states.
+func (w *Workflow[T]) Normalize(status string) (string, bool) {
+	for _, s := range w.States {
+		if strings.EqualFold(s, strings.TrimSpace(status)) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Check reports whether rec, already carrying its new field values, may move
+// from status from to status to, or stay in it when from and to are the same.
+// It returns a *TransitionError.
+func (w *Workflow[T]) Check(from, to string, rec T) error {
+	allowed := w.Transitions[from]
+	ok := from == to
+	for _, t := range allowed {
+		if t == to {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		msg := fmt.Sprintf("%s cannot move from %s to %s", w.Entity, from, to)
+		if len(allowed) == 0 {
+			msg += fmt.Sprintf("; %s is final", from)
+		}
+		return &TransitionError{
+			Code:    "invalid_transition",
+			Entity:  w.Entity,
+			From:    from,
+			To:      to,
+			Allowed: append([]string{}, allowed...),
+			Message: msg,
+		}
+	}
+	for _, g := range w.Guards[to] {
+		if !g.Allow(rec) {
+			return &TransitionError{
+				Code:    "guard_failed",
+				Entity:  w.Entity,
+				From:    from,
+				To:      to,
+				Guard:   g.Name,
+				Message: g.Message,
+			}
+		}
+	}
+	return nil
+}
+
+// TransitionError describes a rejected status change. Disallowed transitions
+// unwrap to ErrInvalidTransition, failed guards to ErrGuardFailed.
+// swagger:model TransitionError
+type TransitionError struct {
+	Code    string   `json:"code"` // invalid_transition or guard_failed
+	Entity  string   `json:"entity"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Allowed []string `json:"allowed,omitempty"` // Statuses reachable from From
+	Guard   string   `json:"guard,omitempty"`   // Failed guard name
+	Message string   `json:"message"`
+}
+
+func (e *TransitionError) Error() string { return e.Message }
+
+func (e *TransitionError) Unwrap() error {
+	if e.Guard != "" {
+		return ErrGuardFailed
+	}
+	return ErrInvalidTransition
+}
+
+// --------- Workflows ---------
+
+var RiskWorkflow = &Workflow[*Risk]{
+	Entity: "risk",
+	States: []string{"Open", "Accepted", "Mitigated"},
+	Transitions: map[string][]string{
+		"Open":      {"Accepted", "Mitigated"},
+		"Accepted":  {"Open", "Mitigated"},
+		"Mitigated": {"Open"},
+	},
+	Guards: map[string][]Guard[*Risk]{
+		"Accepted": {{
+			Name:    "owner_required",
+			Message: "a risk can't be accepted without an owner",
+			Allow:   func(r *Risk) bool { return strings.TrimSpace(r.Owner) != "" },
+		}},
+	},
+}
+
+var IncidentWorkflow = &Workflow[*Incident]{
+	Entity: "incident",
+	States: []string{"Open", "Investigation", "Closed"},
+	Transitions: map[string][]string{
+		"Open":          {"Investigation"},
+		"Investigation": {"Open", "Closed"},
+		"Closed":        {"Investigation"},
+	},
+	Guards: map[string][]Guard[*Incident]{
+		"Closed": {{
+			Name:    "root_cause_required",
+			Message: "an incident can't be closed without a root cause",
+			Allow:   func(i *Incident) bool { return strings.TrimSpace(i.RootCause) != "" },
+		}},
+	},
+}
+
+var AuditWorkflow = &Workflow[*Audit]{
+	Entity: "audit",
+	States: []string{"Planned", "In Progress", "Completed"},
+	Transitions: map[string][]string{
+		"Planned":     {"In Progress", "Completed"},
+		"In Progress": {"Planned", "Completed"},
+		"Completed":   {},
+	},
+	Guards: map[string][]Guard[*Audit]{
+		"Completed": {{
+			Name:    "findings_required",
+			Message: "an audit can't be completed without findings",
+			Allow:   func(a *Audit) bool { return strings.TrimSpace(a.Findings) != "" },
+		}},
+	},
+}
+
+var ActionWorkflow = &Workflow[*Action]{
+	Entity: "action",
+	States: []string{"Open", "In Progress", "Done", "Overdue"},
+	Transitions: map[string][]string{
+		"Open":        {"In Progress", "Done", "Overdue"},
+		"In Progress": {"Open", "Done", "Overdue"},
+		"Overdue":     {"Open", "In Progress", "Done"},
+		"Done":        {},
+	},
+}
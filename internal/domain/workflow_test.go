@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorkflowNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"in progress":   "In Progress",
+		" IN PROGRESS ": "In Progress",
+		"Done":          "Done",
+		"overdue":       "Overdue",
+	} {
+		if got, ok := ActionWorkflow.Normalize(in); !ok || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if got, ok := ActionWorkflow.Normalize("Closed"); ok {
+		t.Errorf("Normalize(Closed) = %q for actions", got)
+	}
+}
+
+func TestWorkflowTransitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    func(from, to string) error
+		from, to string
+		ok       bool
+	}{
+		{"risk open to mitigated", func(f, to string) error { return RiskWorkflow.Check(f, to, &Risk{}) }, "Open", "Mitigated", true},
+		{"risk mitigated to accepted", func(f, to string) error { return RiskWorkflow.Check(f, to, &Risk{Owner: "bob"}) }, "Mitigated", "Accepted", false},
+		{"incident open to closed", func(f, to string) error { return IncidentWorkflow.Check(f, to, &Incident{RootCause: "x"}) }, "Open", "Closed", false},
+		{"incident closed to investigation", func(f, to string) error { return IncidentWorkflow.Check(f, to, &Incident{}) }, "Closed", "Investigation", true},
+		{"audit completed to planned", func(f, to string) error { return AuditWorkflow.Check(f, to, &Audit{}) }, "Completed", "Planned", false},
+		{"action done to open", func(f, to string) error { return ActionWorkflow.Check(f, to, &Action{}) }, "Done", "Open", false},
+		{"action overdue to open", func(f, to string) error { return ActionWorkflow.Check(f, to, &Action{}) }, "Overdue", "Open", true},
+		{"same status", func(f, to string) error { return ActionWorkflow.Check(f, to, &Action{}) }, "Done", "Done", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.from, tt.to)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Check(%s, %s) = %v, want allowed", tt.from, tt.to, err)
+				}
+				return
+			}
+			var te *TransitionError
+			if !errors.As(err, &te) || !errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrGuardFailed) {
+				t.Fatalf("Check(%s, %s) = %v, want an invalid transition", tt.from, tt.to, err)
+			}
+			if te.Code != "invalid_transition" || te.From != tt.from || te.To != tt.to {
+				t.Errorf("TransitionError = %+v", te)
+			}
+		})
+	}
+}
+
+func TestWorkflowReportsAllowedAndFinal(t *testing.T) {
+	var te *TransitionError
+	if err := IncidentWorkflow.Check("Open", "Closed", &Incident{}); !errors.As(err, &te) {
+		t.Fatalf("Check = %v", err)
+	}
+	if len(te.Allowed) != 1 || te.Allowed[0] != "Investigation" {
+		t.Errorf("Allowed = %v, want [Investigation]", te.Allowed)
+	}
+	te.Allowed[0] = "Closed"
+	if IncidentWorkflow.Transitions["Open"][0] != "Investigation" {
+		t.Fatal("the error shares its Allowed slice with the workflow")
+	}
+
+	if err := AuditWorkflow.Check("Completed", "In Progress", &Audit{}); !errors.As(err, &te) {
+		t.Fatalf("Check = %v", err)
+	}
+	if want := "audit cannot move from Completed to In Progress; Completed is final"; te.Message != want {
+		t.Errorf("Message = %q, want %q", te.Message, want)
+	}
+}
+
+func TestWorkflowGuards(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func() error
+		guard string // empty when the transition is allowed
+	}{
+		{"risk accepted without owner", func() error { return RiskWorkflow.Check("Open", "Accepted", &Risk{Owner: "  "}) }, "owner_required"},
+		{"risk accepted with owner", func() error { return RiskWorkflow.Check("Open", "Accepted", &Risk{Owner: "bob"}) }, ""},
+		{"incident closed without root cause", func() error {
+			return IncidentWorkflow.Check("Investigation", "Closed", &Incident{RootCause: " "})
+		}, "root_cause_required"},
+		{"incident closed with root cause", func() error {
+			return IncidentWorkflow.Check("Investigation", "Closed", &Incident{RootCause: "Worn rung"})
+		}, ""},
+		{"audit completed without findings", func() error { return AuditWorkflow.Check("In Progress", "Completed", &Audit{}) }, "findings_required"},
+		{"audit completed with findings", func() error {
+			return AuditWorkflow.Check("Planned", "Completed", &Audit{Findings: "Two minor nonconformities"})
+		}, ""},
+		// Guards also apply to edits that keep the status.
+		{"closed incident loses root cause", func() error {
+			return IncidentWorkflow.Check("Closed", "Closed", &Incident{RootCause: "  "})
+		}, "root_cause_required"},
+		{"closed incident keeps root cause", func() error {
+			return IncidentWorkflow.Check("Closed", "Closed", &Incident{RootCause: "Worn rung"})
+		}, ""},
+		{"accepted risk loses owner", func() error { return RiskWorkflow.Check("Accepted", "Accepted", &Risk{}) }, "owner_required"},
+		{"completed audit loses findings", func() error { return AuditWorkflow.Check("Completed", "Completed", &Audit{}) }, "findings_required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check()
+			if tt.guard == "" {
+				if err != nil {
+					t.Fatalf("Check = %v, want allowed", err)
+				}
+				return
+			}
+			var te *TransitionError
+			if !errors.As(err, &te) || !errors.Is(err, ErrGuardFailed) || errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("Check = %v, want a failed guard", err)
+			}
+			if te.Code != "guard_failed" || te.Guard != tt.guard || te.Message == "" {
+				t.Errorf("TransitionError = %+v, want guard %s", te, tt.guard)
+			}
+		})
+	}
+}
+
+func TestWorkflowsAreConsistent(t *testing.T) {
+	checkWorkflow(t, RiskWorkflow)
+	checkWorkflow(t, IncidentWorkflow)
+	checkWorkflow(t, AuditWorkflow)
+	checkWorkflow(t, ActionWorkflow)
+}
+
+// checkWorkflow reports transitions and guards that refer to states the
+// workflow does not have.
+func checkWorkflow[T any](t *testing.T, w *Workflow[T]) {
+	t.Helper()
+	known := make(map[string]bool)
+	for _, s := range w.States {
+		known[s] = true
+		if _, ok := w.Transitions[s]; !ok {
+			t.Errorf("%s: state %s has no transitions entry", w.Entity, s)
+		}
+	}
+	for from, tos := range w.Transitions {
+		if !known[from] {
+			t.Errorf("%s: transition from unknown state %s", w.Entity, from)
+		}
+		for _, to := range tos {
+			if !known[to] || to == from {
+				t.Errorf("%s: invalid transition %s -> %s", w.Entity, from, to)
+			}
+		}
+	}
+	for to := range w.Guards {
+		if !known[to] {
+			t.Errorf("%s: guard on unknown state %s", w.Entity, to)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// Channel delivers one rendered notification. A returned error means the
+// delivery should be retried.
+type Channel interface {
+	Send(ctx context.Context, n *domain.Notification) error
+}
+
+// sendTimeout bounds a single delivery attempt.
+const sendTimeout = 30 * time.Second
+
+// SMTPConfig points at a mail relay. Username is optional; without it no
+// AUTH is attempted.
+type SMTPConfig struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPChannel sends plain-text mail through a relay. STARTTLS is used when
+// the relay offers it.
+type SMTPChannel struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPChannel(cfg SMTPConfig) *SMTPChannel {
+	return &SMTPChannel{cfg: cfg}
+}
+
+func (c *SMTPChannel) Send(ctx context.Context, n *domain.Notification) error {
+	host, _, err := net.SplitHostPort(c.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp address %q: %w", c.cfg.Addr, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", c.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if c.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(n.Recipient); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(c.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (c *SMTPChannel) message(n *domain.Notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", n.Recipient)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(n.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// WebhookChannel posts notifications as JSON to the URL in the
+// notification's recipient. The "text" field makes the payload usable with
+// chat incoming webhooks as is.
+type WebhookChannel struct {
+	client *http.Client
+}
+
+func NewWebhookChannel() *WebhookChannel {
+	return &WebhookChannel{client: &http.Client{Timeout: sendTimeout}}
+}
+
+// webhookPayload is the body posted to notification webhooks.
+type webhookPayload struct {
+	Event      string `json:"event"`
+	RecordType string `json:"recordType"`
+	RecordID   int    `json:"recordId"`
+	Subject    string `json:"subject"`
+	Text       string `json:"text"`
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, n *domain.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:      n.EventType,
+		RecordType: n.RecordType,
+		RecordID:   n.RecordID,
+		Subject:    n.Subject,
+		Text:       n.Subject + "\n\n" + n.Body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Recipient, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "IntegraFlow-IMS")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
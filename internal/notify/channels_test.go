@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func testNotification(channel, recipient string) *domain.Notification {
+	return &domain.Notification{
+		Channel:    channel,
+		Recipient:  recipient,
+		Subject:    "[IMS] Action #7 assigned to you: Replace ladder",
+		Body:       "alice assigned you a corrective/preventive action.\n\nDue date: 2025-12-01\n",
+		EventType:  domain.EventActionCreated,
+		RecordType: domain.KindAction,
+		RecordID:   7,
+	}
+}
+
+func TestSMTPChannelSend(t *testing.T) {
+	relay := startSMTP(t)
+	ch := NewSMTPChannel(SMTPConfig{Addr: relay.Addr, From: "ims@example.com"})
+
+	if err := ch.Send(context.Background(), testNotification(domain.ChannelEmail, "bob@example.com")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msgs := relay.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("relay got %d messages, want 1", len(msgs))
+	}
+	m := msgs[0]
+	if m.From != "ims@example.com" || m.To != "bob@example.com" {
+		t.Errorf("envelope = %s -> %s, want ims@example.com -> bob@example.com", m.From, m.To)
+	}
+	for _, want := range []string{
+		"From: ims@example.com\r\n",
+		"To: bob@example.com\r\n",
+		"Subject: [IMS] Action #7 assigned to you: Replace ladder\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nalice assigned you a corrective/preventive action.\r\n\r\nDue date: 2025-12-01\r\n",
+	} {
+		if !strings.Contains(m.Data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, m.Data)
+		}
+	}
+}
+
+func TestSMTPChannelRejectedRecipient(t *testing.T) {
+	relay := startSMTP(t)
+	relay.RejectRecipients(true)
+	ch := NewSMTPChannel(SMTPConfig{Addr: relay.Addr, From: "ims@example.com"})
+
+	if err := ch.Send(context.Background(), testNotification(domain.ChannelEmail, "nobody@example.com")); err == nil {
+		t.Fatal("Send succeeded for a rejected recipient")
+	}
+	if n := len(relay.Messages()); n != 0 {
+		t.Errorf("relay got %d messages, want 0", n)
+	}
+}
+
+func TestSMTPChannelUnreachableRelay(t *testing.T) {
+	ch := NewSMTPChannel(SMTPConfig{Addr: "127.0.0.1:1", From: "ims@example.com"})
+	if err := ch.Send(context.Background(), testNotification(domain.ChannelEmail, "bob@example.com")); err == nil {
+		t.Fatal("Send succeeded without a relay")
+	}
+}
+
+func TestWebhookChannelSend(t *testing.T) {
+	var got webhookPayload
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := testNotification(domain.ChannelWebhook, srv.URL)
+	if err := NewWebhookChannel().Send(context.Background(), n); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	want := webhookPayload{
+		Event:      domain.EventActionCreated,
+		RecordType: domain.KindAction,
+		RecordID:   7,
+		Subject:    n.Subject,
+		Text:       n.Subject + "\n\n" + n.Body,
+	}
+	if got != want {
+		t.Errorf("payload = %+v, want %+v", got, want)
+	}
+}
+
+func TestWebhookChannelErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookChannel().Send(context.Background(), testNotification(domain.ChannelWebhook, srv.URL))
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("Send error = %v, want a 503 failure", err)
+	}
+}
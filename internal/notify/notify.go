@@ -0,0 +1,339 @@
+// Package notify turns record change events into email and webhook
+// notifications. Messages are rendered when the event is published and
+// stored in a persisted outbox; Deliver, run by the scheduler, sends them
+// and retries failures with exponential backoff, so pending notifications
+// survive restarts.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+const (
+	defaultMaxAttempts  = 5
+	defaultRetryBackoff = time.Minute
+	deliverBatch        = 50
+)
+
+// Config selects the channels and recipients. Channels without
+// configuration are off; with neither configured, Publish does nothing.
+type Config struct {
+	SMTP             *SMTPConfig
+	WebhookURL       string
+	EscalationEmails []string      // Receive overdue actions and incidents in escalating risk levels
+	TemplateDir      string        // Optional overrides, see LoadTemplates
+	MaxAttempts      int           // Attempts before a notification is marked failed; default 5
+	RetryBackoff     time.Duration // Delay after the first failure, doubled per attempt; default 1m
+	DueSoonDays      int           // Days before an action's due date to remind its owner; 0 turns reminders off
+}
+
+type Notifier struct {
+	outbox      repository.NotificationRepository
+	users       repository.UserRepository
+	matrix      repository.RiskMatrixRepository
+	channels    map[string]Channel
+	webhookURL  string
+	escalation  []string
+	templates   *Templates
+	maxAttempts int
+	backoff     time.Duration
+	dueSoonDays int
+}
+
+func New(
+	outbox repository.NotificationRepository,
+	users repository.UserRepository,
+	matrix repository.RiskMatrixRepository,
+	cfg Config,
+) (*Notifier, error) {
+	tmpl, err := LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	n := &Notifier{
+		outbox:      outbox,
+		users:       users,
+		matrix:      matrix,
+		channels:    make(map[string]Channel),
+		webhookURL:  cfg.WebhookURL,
+		templates:   tmpl,
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     cfg.RetryBackoff,
+		dueSoonDays: max(cfg.DueSoonDays, 0),
+	}
+	if n.maxAttempts <= 0 {
+		n.maxAttempts = defaultMaxAttempts
+	}
+	if n.backoff <= 0 {
+		n.backoff = defaultRetryBackoff
+	}
+	if cfg.SMTP != nil {
+		if _, err := mail.ParseAddress(cfg.SMTP.From); err != nil {
+			return nil, fmt.Errorf("smtp sender %q: %w", cfg.SMTP.From, err)
+		}
+		n.channels[domain.ChannelEmail] = NewSMTPChannel(*cfg.SMTP)
+	}
+	if cfg.WebhookURL != "" {
+		n.channels[domain.ChannelWebhook] = NewWebhookChannel()
+	}
+	for _, addr := range cfg.EscalationEmails {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return nil, fmt.Errorf("escalation address %q: %w", addr, err)
+		}
+		n.escalation = append(n.escalation, addr)
+	}
+	return n, nil
+}
+
+// Channels returns the names of the enabled channels.
+func (n *Notifier) Channels() []string {
+	var out []string
+	for _, name := range []string{domain.ChannelEmail, domain.ChannelWebhook} {
+		if n.channels[name] != nil {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// DueSoonDays returns how many days before an action's due date its owner
+// is reminded, or 0 when reminders are off.
+func (n *Notifier) DueSoonDays() int {
+	return n.dueSoonDays
+}
+
+// templateData is what notification templates see.
+type templateData struct {
+	Event  domain.Event
+	Record any
+}
+
+// Publish renders the notifications for e into the outbox. The event ID is
+// the dedup key, so publishing an event again queues nothing.
+func (n *Notifier) Publish(ctx context.Context, e domain.Event) error {
+	if len(n.channels) == 0 {
+		return nil
+	}
+	name, emails := n.route(e)
+	if name == "" {
+		return nil
+	}
+	var key string
+	if e.ID != "" {
+		key = "event:" + e.ID
+	}
+	if _, err := n.enqueue(name, e, emails, key); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	return nil
+}
+
+// Remind queues the notifications for a reminder event and reports whether
+// any were new. A reminder is queued once per record and due date, so
+// running the reminder job again is harmless and moving the due date
+// reminds again.
+func (n *Notifier) Remind(ctx context.Context, e domain.Event) (bool, error) {
+	if len(n.channels) == 0 {
+		return false, nil
+	}
+	name, emails := n.route(e)
+	if name == "" {
+		return false, nil
+	}
+	key := fmt.Sprintf("%s:%s:%d", e.Type, e.RecordType, e.RecordID)
+	if a, ok := e.Record.(*domain.Action); ok {
+		key += ":" + a.DueDate
+	}
+	added, err := n.enqueue(name, e, emails, key)
+	return added > 0, err
+}
+
+// route picks the template and email recipients for an event. An empty
+// name means the event does not notify anyone.
+func (n *Notifier) route(e domain.Event) (name string, emails []string) {
+	switch rec := e.Record.(type) {
+	case *domain.Action:
+		switch {
+		case e.Type == domain.EventActionCreated && rec.Owner != "":
+			return tmplActionAssigned, n.resolve(e.Actor, rec.Owner)
+		case e.Type == domain.EventActionStatusChanged && e.To == "Overdue":
+			return tmplActionOverdue, append(n.resolve(e.Actor, rec.Owner), n.escalation...)
+		case e.Type == domain.EventActionStatusChanged:
+			return tmplActionStatusChanged, n.resolve(e.Actor, rec.Owner)
+		case e.Type == domain.EventActionDueSoon:
+			return tmplActionDueSoon, n.resolve(e.Actor, rec.Owner)
+		}
+	case *domain.Incident:
+		switch {
+		case e.Type == domain.EventIncidentCreated && n.escalates(rec.RiskLevel):
+			return tmplIncidentHigh, n.escalation
+		case e.Type == domain.EventIncidentStatusChanged:
+			return tmplIncidentStatusChanged, n.resolve(e.Actor, rec.CreatedBy)
+		}
+	}
+	return "", nil
+}
+
+// escalates reports whether an incident level is in an escalating band of
+// the risk matrix.
+func (n *Notifier) escalates(level string) bool {
+	m, err := n.matrix.Get()
+	if err != nil {
+		log.Printf("notify: reading risk matrix: %v", err)
+		return false
+	}
+	return m.Escalates(level)
+}
+
+// resolve maps a free-text owner to email addresses: an address is used as
+// is, anything else is looked up as a username. The actor is not notified
+// of their own changes.
+func (n *Notifier) resolve(actor, who string) []string {
+	who = strings.TrimSpace(who)
+	if who == "" || strings.EqualFold(who, actor) {
+		return nil
+	}
+	if strings.Contains(who, "@") {
+		if addr, err := mail.ParseAddress(who); err == nil {
+			return []string{addr.Address}
+		}
+		return nil
+	}
+	u, err := n.users.GetByUsername(who)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("notify: looking up %q: %v", who, err)
+		}
+		return nil
+	}
+	if !u.Active || u.Email == "" || strings.EqualFold(u.Username, actor) {
+		return nil
+	}
+	return []string{u.Email}
+}
+
+// enqueue renders template name once and stores a notification per email
+// recipient, plus one for the webhook, and returns how many were queued.
+// With a dedupKey, notifications queued before under the same key are not
+// repeated.
+func (n *Notifier) enqueue(name string, e domain.Event, emails []string, dedupKey string) (int, error) {
+	subject, body, err := n.templates.Render(name, templateData{Event: e, Record: e.Record})
+	if err != nil {
+		return 0, err
+	}
+
+	var out []*domain.Notification
+	now := time.Now().UTC().Format(time.RFC3339)
+	add := func(channel, recipient string) {
+		out = append(out, &domain.Notification{
+			Channel:    channel,
+			Recipient:  recipient,
+			Subject:    subject,
+			Body:       body,
+			EventType:  e.Type,
+			RecordType: e.RecordType,
+			RecordID:   e.RecordID,
+			Status:     domain.NotificationPending,
+			CreatedAt:  now,
+			DedupKey:   dedupKey,
+		})
+	}
+	if n.channels[domain.ChannelEmail] != nil {
+		seen := make(map[string]bool)
+		for _, addr := range emails {
+			if key := strings.ToLower(addr); !seen[key] {
+				seen[key] = true
+				add(domain.ChannelEmail, addr)
+			}
+		}
+	}
+	if n.channels[domain.ChannelWebhook] != nil {
+		add(domain.ChannelWebhook, n.webhookURL)
+	}
+
+	added := 0
+	for _, m := range out {
+		if err := n.outbox.Enqueue(m); err != nil {
+			return added, err
+		}
+		if m.ID != 0 {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// EnqueueTest queues a test message to email address to (if set and email is
+// enabled) and to the webhook (if configured).
+func (n *Notifier) EnqueueTest(actor, to string) error {
+	var emails []string
+	if to != "" {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid email address %q: %w", to, err)
+		}
+		emails = append(emails, addr.Address)
+	}
+	e := domain.Event{
+		Type:       "notification.test",
+		RecordType: "test",
+		Actor:      actor,
+		OccurredAt: time.Now().Format(time.RFC3339),
+	}
+	_, err := n.enqueue(tmplTest, e, emails, "")
+	return err
+}
+
+// Deliver sends the notifications that are due and returns how many were
+// sent and how many attempts failed. A failed notification is retried after
+// RetryBackoff, doubling each time, until MaxAttempts is reached.
+func (n *Notifier) Deliver(ctx context.Context) (sent, failed int, err error) {
+	now := time.Now().UTC()
+	due, err := n.outbox.Due(now.Format(time.RFC3339), deliverBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, m := range due {
+		if ctx.Err() != nil {
+			return sent, failed, ctx.Err()
+		}
+		ch := n.channels[m.Channel]
+		if ch == nil {
+			failed++
+			if err := n.outbox.MarkFailed(m.ID, fmt.Sprintf("channel %s is not configured", m.Channel), m.NextAttemptAt, true); err != nil {
+				return sent, failed, err
+			}
+			continue
+		}
+
+		if sendErr := ch.Send(ctx, m); sendErr != nil {
+			failed++
+			attempts := m.Attempts + 1
+			next := time.Now().UTC().Add(n.backoff << (attempts - 1))
+			final := attempts >= n.maxAttempts
+			log.Printf("notify: %s to %s (attempt %d/%d): %v", m.Channel, m.Recipient, attempts, n.maxAttempts, sendErr)
+			if err := n.outbox.MarkFailed(m.ID, sendErr.Error(), next.Format(time.RFC3339), final); err != nil {
+				return sent, failed, err
+			}
+			continue
+		}
+		sent++
+		if err := n.outbox.MarkSent(m.ID, time.Now().Format(time.RFC3339)); err != nil {
+			return sent, failed, err
+		}
+	}
+	return sent, failed, nil
+}
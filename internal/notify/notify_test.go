@@ -0,0 +1,357 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+// memOutbox is an in-memory repository.NotificationRepository.
+type memOutbox struct {
+	items []*domain.Notification
+}
+
+func (o *memOutbox) Enqueue(n *domain.Notification) error {
+	n.ID = len(o.items) + 1
+	if n.Status == "" {
+		n.Status = domain.NotificationPending
+	}
+	if n.NextAttemptAt == "" {
+		n.NextAttemptAt = n.CreatedAt
+	}
+	o.items = append(o.items, n)
+	return nil
+}
+
+func (o *memOutbox) Due(now string, limit int) ([]*domain.Notification, error) {
+	var out []*domain.Notification
+	for _, n := range o.items {
+		if n.Status == domain.NotificationPending && n.NextAttemptAt <= now && len(out) < limit {
+			c := *n
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+func (o *memOutbox) MarkSent(id int, sentAt string) error {
+	n := o.items[id-1]
+	n.Status, n.Attempts, n.LastError, n.SentAt = domain.NotificationSent, n.Attempts+1, "", sentAt
+	return nil
+}
+
+func (o *memOutbox) MarkFailed(id int, lastError, nextAttemptAt string, final bool) error {
+	n := o.items[id-1]
+	n.Attempts++
+	n.LastError, n.NextAttemptAt = lastError, nextAttemptAt
+	if final {
+		n.Status = domain.NotificationFailed
+	}
+	return nil
+}
+
+func (o *memOutbox) List(status string, limit int) ([]*domain.Notification, error) {
+	return nil, nil
+}
+
+// makeDue moves every pending notification's next attempt into the past,
+// as if the backoff had elapsed.
+func (o *memOutbox) makeDue() {
+	for _, n := range o.items {
+		n.NextAttemptAt = "2000-01-01T00:00:00Z"
+	}
+}
+
+// hookServer is a webhook stand-in that fails the first failures requests
+// and records the payloads of the rest.
+type hookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures int
+	payloads []webhookPayload
+}
+
+func startHook(t *testing.T, failures int) *hookServer {
+	t.Helper()
+	h := &hookServer{failures: failures}
+	h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.failures != 0 {
+			h.failures--
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		h.payloads = append(h.payloads, p)
+	}))
+	t.Cleanup(h.Close)
+	return h
+}
+
+func (h *hookServer) Payloads() []webhookPayload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]webhookPayload(nil), h.payloads...)
+}
+
+func mustNew(t *testing.T, outbox repository.NotificationRepository, users repository.UserRepository, matrix repository.RiskMatrixRepository, cfg Config) *Notifier {
+	t.Helper()
+	n, err := New(outbox, users, matrix, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDeliverBacksOffAndGivesUp(t *testing.T) {
+	hook := startHook(t, -1) // always fails
+	outbox := &memOutbox{}
+	n := mustNew(t, outbox, nil, nil, Config{WebhookURL: hook.URL, MaxAttempts: 3, RetryBackoff: time.Hour})
+	if err := n.EnqueueTest("alice", ""); err != nil {
+		t.Fatal(err)
+	}
+	m := outbox.items[0]
+
+	for attempt, wantDelay := range []time.Duration{time.Hour, 2 * time.Hour} {
+		start := time.Now().UTC().Truncate(time.Second)
+		sent, failed, err := n.Deliver(context.Background())
+		if err != nil || sent != 0 || failed != 1 {
+			t.Fatalf("attempt %d: Deliver = %d sent, %d failed, %v; want 0, 1, nil", attempt+1, sent, failed, err)
+		}
+		if m.Status != domain.NotificationPending || m.Attempts != attempt+1 || m.LastError == "" {
+			t.Fatalf("attempt %d: notification %+v, want pending with an error", attempt+1, m)
+		}
+		next, err := time.Parse(time.RFC3339, m.NextAttemptAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := next.Sub(start); d < wantDelay || d > wantDelay+2*time.Second {
+			t.Errorf("attempt %d: retried after %v, want %v", attempt+1, d, wantDelay)
+		}
+
+		// Not due again until the backoff elapses.
+		if sent, failed, _ := n.Deliver(context.Background()); sent+failed != 0 {
+			t.Fatalf("attempt %d: delivered again before the backoff elapsed", attempt+1)
+		}
+		outbox.makeDue()
+	}
+
+	if _, failed, err := n.Deliver(context.Background()); err != nil || failed != 1 {
+		t.Fatalf("last attempt: %d failed, %v", failed, err)
+	}
+	if m.Status != domain.NotificationFailed || m.Attempts != 3 {
+		t.Fatalf("after MaxAttempts: status %s, attempts %d; want failed, 3", m.Status, m.Attempts)
+	}
+	outbox.makeDue()
+	if sent, failed, _ := n.Deliver(context.Background()); sent+failed != 0 {
+		t.Fatal("a failed notification was retried")
+	}
+}
+
+func TestDeliverRetriesUntilSent(t *testing.T) {
+	hook := startHook(t, 1)
+	outbox := &memOutbox{}
+	n := mustNew(t, outbox, nil, nil, Config{WebhookURL: hook.URL, RetryBackoff: time.Minute})
+	if err := n.EnqueueTest("alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent, failed, err := n.Deliver(context.Background()); err != nil || sent != 0 || failed != 1 {
+		t.Fatalf("first Deliver = %d sent, %d failed, %v; want 0, 1, nil", sent, failed, err)
+	}
+	outbox.makeDue()
+	if sent, failed, err := n.Deliver(context.Background()); err != nil || sent != 1 || failed != 0 {
+		t.Fatalf("second Deliver = %d sent, %d failed, %v; want 1, 0, nil", sent, failed, err)
+	}
+
+	m := outbox.items[0]
+	if m.Status != domain.NotificationSent || m.Attempts != 2 || m.LastError != "" || m.SentAt == "" {
+		t.Errorf("notification %+v, want sent after 2 attempts", m)
+	}
+	if p := hook.Payloads(); len(p) != 1 || p[0].Subject != "[IMS] Test notification" {
+		t.Errorf("webhook received %+v", p)
+	}
+}
+
+func TestDeliverUnconfiguredChannel(t *testing.T) {
+	hook := startHook(t, 0)
+	outbox := &memOutbox{}
+	n := mustNew(t, outbox, nil, nil, Config{WebhookURL: hook.URL})
+	outbox.Enqueue(&domain.Notification{
+		Channel:   domain.ChannelEmail,
+		Recipient: "bob@example.com",
+		Subject:   "queued while email was configured",
+		CreatedAt: "2025-01-01T00:00:00Z",
+	})
+
+	if _, failed, err := n.Deliver(context.Background()); err != nil || failed != 1 {
+		t.Fatalf("Deliver = %d failed, %v; want 1, nil", failed, err)
+	}
+	if m := outbox.items[0]; m.Status != domain.NotificationFailed {
+		t.Errorf("status = %s, want failed without retries", m.Status)
+	}
+}
+
+// openDB opens (and migrates) the SQLite database at path.
+func openDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sqlite.NewDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlite.Migrate(db); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newSQLiteNotifier(t *testing.T, db *sql.DB, cfg Config) *Notifier {
+	t.Helper()
+	return mustNew(t, sqlite.NewNotificationRepository(db), sqlite.NewUserRepository(db), sqlite.NewRiskMatrixRepository(db), cfg)
+}
+
+func TestPublishRoutesToOwnerAndEscalation(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "ims.db"))
+	defer db.Close()
+	users := sqlite.NewUserRepository(db)
+	if err := users.Create(&domain.User{Username: "bob", Email: "bob@example.com", Active: true, CreatedAt: "2025-01-01T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	relay := startSMTP(t)
+	n := newSQLiteNotifier(t, db, Config{
+		SMTP:             &SMTPConfig{Addr: relay.Addr, From: "ims@example.com"},
+		EscalationEmails: []string{"qhse@example.com"},
+	})
+
+	a := testAction()
+	n.Publish(context.Background(), domain.Event{Type: domain.EventActionCreated, RecordType: domain.KindAction, RecordID: a.ID, Actor: "alice", Record: a})
+	n.Publish(context.Background(), domain.Event{Type: domain.EventActionStatusChanged, RecordType: domain.KindAction, RecordID: a.ID, Actor: "system", From: "Open", To: "Overdue", Record: a})
+	// Nobody is told about their own change.
+	n.Publish(context.Background(), domain.Event{Type: domain.EventActionStatusChanged, RecordType: domain.KindAction, RecordID: a.ID, Actor: "bob", From: "Overdue", To: "Done", Record: a})
+
+	sent, failed, err := n.Deliver(context.Background())
+	if err != nil || sent != 3 || failed != 0 {
+		t.Fatalf("Deliver = %d sent, %d failed, %v; want 3, 0, nil", sent, failed, err)
+	}
+	var got []string
+	for _, m := range relay.Messages() {
+		got = append(got, m.To)
+	}
+	sort.Strings(got)
+	want := []string{"bob@example.com", "bob@example.com", "qhse@example.com"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("mailed %v, want %v", got, want)
+	}
+}
+
+func TestOutboxSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ims.db")
+	hook := startHook(t, 0)
+
+	// Queue a notification and stop before the delivery job runs.
+	db := openDB(t, path)
+	n := newSQLiteNotifier(t, db, Config{WebhookURL: hook.URL})
+	a := testAction()
+	n.Publish(context.Background(), domain.Event{Type: domain.EventActionCreated, RecordType: domain.KindAction, RecordID: a.ID, Actor: "alice", Record: a})
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a restart the pending notification is still there and is sent.
+	db = openDB(t, path)
+	defer db.Close()
+	n = newSQLiteNotifier(t, db, Config{WebhookURL: hook.URL})
+	pending, err := sqlite.NewNotificationRepository(db).List(domain.NotificationPending, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("%d pending notifications after restart, want 1", len(pending))
+	}
+	if p := hook.Payloads(); len(p) != 0 {
+		t.Fatalf("webhook received %d payloads before Deliver", len(p))
+	}
+
+	if sent, failed, err := n.Deliver(context.Background()); err != nil || sent != 1 || failed != 0 {
+		t.Fatalf("Deliver = %d sent, %d failed, %v; want 1, 0, nil", sent, failed, err)
+	}
+	if p := hook.Payloads(); len(p) != 1 || p[0].Event != domain.EventActionCreated || p[0].RecordID != a.ID {
+		t.Errorf("webhook received %+v", p)
+	}
+}
+
+func TestRemindQueuesOncePerDueDate(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "ims.db"))
+	defer db.Close()
+	relay := startSMTP(t)
+	n := newSQLiteNotifier(t, db, Config{SMTP: &SMTPConfig{Addr: relay.Addr, From: "ims@example.com"}, DueSoonDays: 3})
+
+	a := testAction()
+	a.Owner = "bob@example.com"
+	remind := func() bool {
+		t.Helper()
+		added, err := n.Remind(context.Background(), domain.Event{Type: domain.EventActionDueSoon, RecordType: domain.KindAction, RecordID: a.ID, Actor: "system", Record: a})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return added
+	}
+
+	if !remind() {
+		t.Fatal("first reminder was not queued")
+	}
+	if remind() {
+		t.Fatal("reminder for the same due date was queued twice")
+	}
+	if sent, _, err := n.Deliver(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("Deliver = %d sent, %v; want 1", sent, err)
+	}
+	if remind() {
+		t.Fatal("reminder was queued again after it was sent")
+	}
+	a.DueDate = "2025-12-05"
+	if !remind() {
+		t.Fatal("reminder for a new due date was not queued")
+	}
+}
+
+func TestPublishDropsRepeatedEvents(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "ims.db"))
+	defer db.Close()
+	hook := startHook(t, 0)
+	n := newSQLiteNotifier(t, db, Config{WebhookURL: hook.URL})
+
+	// The event relay publishes an event again when it could not drop it
+	// after publishing.
+	a := testAction()
+	e := domain.Event{ID: "0123456789abcdef", Type: domain.EventActionCreated, RecordType: domain.KindAction, RecordID: a.ID, Actor: "alice", Record: a}
+	for i := 0; i < 2; i++ {
+		if err := n.Publish(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending, err := sqlite.NewNotificationRepository(db).List(domain.NotificationPending, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("%d notifications of one event, want 1", len(pending))
+	}
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Template names. Each can be overridden by a file <name>.tmpl in the
+// template directory.
+const (
+	tmplActionAssigned        = "action_assigned"
+	tmplActionStatusChanged   = "action_status_changed"
+	tmplActionOverdue         = "action_overdue"
+	tmplActionDueSoon         = "action_due_soon"
+	tmplIncidentHigh          = "incident_high"
+	tmplIncidentStatusChanged = "incident_status_changed"
+	tmplTest                  = "test"
+)
+
+// defaultTemplates are text/template sources. The first line is the subject
+// and must start with "Subject:"; the body follows after a blank line.
+// Templates see .Event (domain.Event) and .Record (the saved record).
+var defaultTemplates = map[string]string{
+	tmplActionAssigned: `Subject: [IMS] Action #{{.Record.ID}} assigned to you: {{.Record.Title}}
+
+{{.Event.Actor}} assigned you a corrective/preventive action.
+
+Action:   #{{.Record.ID}} {{.Record.Title}}
+Source:   {{.Record.SourceType}} #{{.Record.SourceID}}{{with .Event.Domain}} ({{.}}){{end}}
+Due date: {{or .Record.DueDate "not set"}}
+{{with .Record.Description}}
+{{.}}
+{{end}}`,
+
+	tmplActionStatusChanged: `Subject: [IMS] Action #{{.Record.ID}} is now {{.Event.To}}
+
+{{.Event.Actor}} moved action #{{.Record.ID}} "{{.Record.Title}}" from {{.Event.From}} to {{.Event.To}}.
+
+Owner:    {{.Record.Owner}}
+Due date: {{or .Record.DueDate "not set"}}
+`,
+
+	tmplActionOverdue: `Subject: [IMS] OVERDUE: action #{{.Record.ID}} {{.Record.Title}}
+
+Action #{{.Record.ID}} "{{.Record.Title}}" was due on {{.Record.DueDate}} and is not done.
+
+Owner:  {{or .Record.Owner "unassigned"}}
+Source: {{.Record.SourceType}} #{{.Record.SourceID}}{{with .Event.Domain}} ({{.}}){{end}}
+
+Please complete the action or agree a new due date.
+`,
+
+	tmplActionDueSoon: `Subject: [IMS] Action #{{.Record.ID}} is due on {{.Record.DueDate}}: {{.Record.Title}}
+
+Action #{{.Record.ID}} "{{.Record.Title}}" is due on {{.Record.DueDate}} and is {{.Record.Status}}.
+
+Owner:  {{.Record.Owner}}
+Source: {{.Record.SourceType}} #{{.Record.SourceID}}{{with .Event.Domain}} ({{.}}){{end}}
+
+Please complete the action by then or agree a new due date.
+`,
+
+	tmplIncidentHigh: `Subject: [IMS] {{.Record.RiskLevel}}-risk incident #{{.Record.ID}} reported: {{.Record.Title}}
+
+{{.Event.Actor}} reported an incident rated {{.Record.RiskLevel}} (score {{.Record.RiskScore}}) in {{.Record.Domain}}.
+
+Incident: #{{.Record.ID}} {{.Record.Title}}
+Severity: {{.Record.Severity}}, likelihood: {{.Record.Likelihood}}
+
+{{.Record.Description}}
+`,
+
+	tmplIncidentStatusChanged: `Subject: [IMS] Incident #{{.Record.ID}} is now {{.Event.To}}
+
+{{.Event.Actor}} moved incident #{{.Record.ID}} "{{.Record.Title}}" from {{.Event.From}} to {{.Event.To}}.
+{{with .Record.RootCause}}
+Root cause: {{.}}
+{{end}}`,
+
+	tmplTest: `Subject: [IMS] Test notification
+
+This is a test notification from IntegraFlow IMS sent by {{.Event.Actor}}.
+If you can read this, the channel is configured correctly.
+`,
+}
+
+// Templates renders notification subjects and bodies.
+type Templates struct {
+	set map[string]*template.Template
+}
+
+// LoadTemplates parses the built-in templates, replacing any that have a
+// <name>.tmpl file in dir. An empty dir uses the built-ins only.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{set: make(map[string]*template.Template)}
+	for name, src := range defaultTemplates {
+		if dir != "" {
+			data, err := os.ReadFile(filepath.Join(dir, name+".tmpl"))
+			switch {
+			case err == nil:
+				src = string(data)
+			case !errors.Is(err, os.ErrNotExist):
+				return nil, err
+			}
+		}
+		if !strings.HasPrefix(src, "Subject:") {
+			return nil, fmt.Errorf("template %s: first line must start with \"Subject:\"", name)
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		t.set[name] = tmpl
+	}
+	return t, nil
+}
+
+// Render executes the named template and splits the result into subject and
+// body.
+func (t *Templates) Render(name string, data any) (subject, body string, err error) {
+	tmpl, ok := t.set[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification template %q", name)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	first, rest, _ := strings.Cut(b.String(), "\n")
+	subject = strings.TrimSpace(strings.TrimPrefix(first, "Subject:"))
+	return subject, strings.TrimSpace(rest) + "\n", nil
+}
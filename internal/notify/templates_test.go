@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func testAction() *domain.Action {
+	return &domain.Action{
+		ID:         7,
+		Title:      "Replace ladder",
+		SourceType: "Incident",
+		SourceID:   3,
+		Owner:      "bob",
+		DueDate:    "2025-12-01",
+		Status:     "Open",
+	}
+}
+
+func testIncident() *domain.Incident {
+	return &domain.Incident{
+		ID:          3,
+		Title:       "Fall from ladder",
+		Description: "Rung gave way.",
+		Domain:      domain.DomainOHS,
+		Severity:    4,
+		Likelihood:  4,
+		RiskScore:   16,
+		RiskLevel:   "High",
+		RootCause:   "Worn rung",
+		Status:      "Closed",
+	}
+}
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	tmpl, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := func(typ string, record any) templateData {
+		e := domain.Event{Type: typ, Actor: "alice", Domain: domain.DomainOHS, From: "Open", To: "Overdue", Record: record}
+		return templateData{Event: e, Record: record}
+	}
+	tests := []struct {
+		name    string
+		data    templateData
+		subject string
+	}{
+		{tmplActionAssigned, event(domain.EventActionCreated, testAction()), "[IMS] Action #7 assigned to you: Replace ladder"},
+		{tmplActionStatusChanged, event(domain.EventActionStatusChanged, testAction()), "[IMS] Action #7 is now Overdue"},
+		{tmplActionOverdue, event(domain.EventActionStatusChanged, testAction()), "[IMS] OVERDUE: action #7 Replace ladder"},
+		{tmplActionDueSoon, event(domain.EventActionDueSoon, testAction()), "[IMS] Action #7 is due on 2025-12-01: Replace ladder"},
+		{tmplIncidentHigh, event(domain.EventIncidentCreated, testIncident()), "[IMS] High-risk incident #3 reported: Fall from ladder"},
+		{tmplIncidentStatusChanged, event(domain.EventIncidentStatusChanged, testIncident()), "[IMS] Incident #3 is now Overdue"},
+		{tmplTest, event("notification.test", nil), "[IMS] Test notification"},
+	}
+	if len(tests) != len(defaultTemplates) {
+		t.Fatalf("testing %d templates, but there are %d", len(tests), len(defaultTemplates))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, body, err := tmpl.Render(tt.name, tt.data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if subject != tt.subject {
+				t.Errorf("subject = %q, want %q", subject, tt.subject)
+			}
+			if body == "" || strings.HasPrefix(body, "\n") || !strings.HasSuffix(body, "\n") {
+				t.Errorf("body is not trimmed to end in one newline: %q", body)
+			}
+			if strings.Contains(body, "Subject:") {
+				t.Errorf("body contains the subject line: %q", body)
+			}
+		})
+	}
+}
+
+func TestRenderDueSoonBody(t *testing.T) {
+	tmpl, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := domain.Event{Type: domain.EventActionDueSoon, Actor: "system", Domain: domain.DomainQuality}
+	_, body, err := tmpl.Render(tmplActionDueSoon, templateData{Event: e, Record: testAction()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`Action #7 "Replace ladder" is due on 2025-12-01 and is Open.`,
+		"Owner:  bob",
+		"Source: Incident #3 (Quality)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	tmpl, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tmpl.Render("no_such_template", templateData{}); err == nil {
+		t.Fatal("Render succeeded for an unknown template")
+	}
+}
+
+func TestRenderMissingField(t *testing.T) {
+	dir := t.TempDir()
+	src := "Subject: {{.Record.NoSuchField}}\n\nbody\n"
+	if err := os.WriteFile(filepath.Join(dir, tmplTest+".tmpl"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tmpl.Render(tmplTest, templateData{Record: testAction()}); err == nil {
+		t.Fatal("Render succeeded with a field the record does not have")
+	}
+}
+
+func TestLoadTemplatesOverride(t *testing.T) {
+	dir := t.TempDir()
+	src := "Subject: Action {{.Record.ID}} for you\n\n{{.Record.Title}} is due {{.Record.DueDate}}.\n"
+	if err := os.WriteFile(filepath.Join(dir, tmplActionAssigned+".tmpl"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := templateData{Event: domain.Event{Actor: "alice"}, Record: testAction()}
+	subject, body, err := tmpl.Render(tmplActionAssigned, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Action 7 for you" || body != "Replace ladder is due 2025-12-01.\n" {
+		t.Errorf("override rendered %q / %q", subject, body)
+	}
+
+	// Templates without an override file keep the built-in text.
+	subject, _, err = tmpl.Render(tmplActionOverdue, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "[IMS] OVERDUE: action #7 Replace ladder" {
+		t.Errorf("built-in subject = %q", subject)
+	}
+}
+
+func TestLoadTemplatesRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no subject": "Hello {{.Record.ID}}\n",
+		"bad syntax": "Subject: {{.Record.ID\n\nbody\n",
+	}
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tmplActionAssigned+".tmpl"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadTemplates(dir); err == nil {
+				t.Fatal("LoadTemplates accepted an invalid template")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package filestore keeps attachment contents on local disk, addressed by
+// their SHA-256 checksum.
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// Store implements repository.FileStore. Contents live at
+// <dir>/<first two hex digits>/<checksum>; uploads are written to a
+// temporary file first and renamed into place once complete.
+type Store struct {
+	dir string
+}
+
+// New opens the store in dir, creating the directory if needed.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o750); err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) Put(r io.Reader, limit int64) (string, int64, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed into place
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, limit+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if size > limit {
+		return "", 0, fmt.Errorf("%w: over %d bytes", repository.ErrTooLarge, limit)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	path := s.path(sum)
+	if _, err := os.Stat(path); err == nil {
+		return sum, size, nil // Already stored
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+	return sum, size, nil
+}
+
+func (s *Store) Open(sum string) (io.ReadSeekCloser, error) {
+	if !validSum(sum) {
+		return nil, repository.ErrNotFound
+	}
+	f, err := os.Open(s.path(sum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, repository.ErrNotFound
+	}
+	return f, err
+}
+
+func (s *Store) Remove(sum string) error {
+	if !validSum(sum) {
+		return repository.ErrNotFound
+	}
+	err := os.Remove(s.path(sum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *Store) path(sum string) string {
+	return filepath.Join(s.dir, sum[:2], sum)
+}
+
+// validSum keeps checksums read back from the database from naming paths
+// outside the store.
+func validSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil
+}
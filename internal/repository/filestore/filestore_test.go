@@ -0,0 +1,165 @@
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+func newStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func put(t *testing.T, s *Store, content string) string {
+	t.Helper()
+	sum, size, err := s.Put(strings.NewReader(content), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(content)) {
+		t.Errorf("Put size %d, want %d", size, len(content))
+	}
+	return sum
+}
+
+func read(t *testing.T, s *Store, sum string) string {
+	t.Helper()
+	f, err := s.Open(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// tmpFiles lists the uploads left in the temporary directory.
+func tmpFiles(t *testing.T, s *Store) []os.DirEntry {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(s.dir, "tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestPutOpenRoundTrip(t *testing.T) {
+	s := newStore(t)
+	content := "Calibration certificate 2025"
+	sum := put(t, s, content)
+
+	want := sha256.Sum256([]byte(content))
+	if sum != hex.EncodeToString(want[:]) {
+		t.Errorf("checksum %s, want the SHA-256 of the content", sum)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, sum[:2], sum)); err != nil {
+		t.Errorf("content not stored under its checksum: %v", err)
+	}
+	if got := read(t, s, sum); got != content {
+		t.Errorf("read back %q, want %q", got, content)
+	}
+	if left := tmpFiles(t, s); len(left) != 0 {
+		t.Errorf("%d temporary files left", len(left))
+	}
+}
+
+func TestPutStoresIdenticalContentOnce(t *testing.T) {
+	s := newStore(t)
+	first := put(t, s, "same bytes")
+	second := put(t, s, "same bytes")
+	if first != second {
+		t.Fatalf("identical content stored as %s and %s", first, second)
+	}
+	entries, err := os.ReadDir(filepath.Join(s.dir, first[:2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("%d files stored, want 1", len(entries))
+	}
+	if left := tmpFiles(t, s); len(left) != 0 {
+		t.Errorf("%d temporary files left", len(left))
+	}
+	if other := put(t, s, "other bytes"); other == first {
+		t.Error("different content has the same checksum")
+	}
+}
+
+func TestPutSizeLimit(t *testing.T) {
+	s := newStore(t)
+	if _, size, err := s.Put(strings.NewReader("12345"), 5); err != nil || size != 5 {
+		t.Fatalf("Put at the limit = %d, %v", size, err)
+	}
+	_, _, err := s.Put(strings.NewReader("123456"), 5)
+	if !errors.Is(err, repository.ErrTooLarge) {
+		t.Fatalf("Put over the limit = %v, want ErrTooLarge", err)
+	}
+	if left := tmpFiles(t, s); len(left) != 0 {
+		t.Errorf("%d temporary files left after a rejected upload", len(left))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	s := newStore(t)
+	sum := put(t, s, "obsolete procedure")
+	if err := s.Remove(sum); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(sum); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Open after Remove = %v, want ErrNotFound", err)
+	}
+	if err := s.Remove(sum); err != nil {
+		t.Errorf("removing missing content = %v, want nil", err)
+	}
+}
+
+func TestInvalidSumsAreNotFound(t *testing.T) {
+	s := newStore(t)
+	// A file outside the store that a crafted checksum might try to reach.
+	outside := filepath.Join(filepath.Dir(s.dir), "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	valid := strings.Repeat("ab", sha256.Size)
+
+	for _, sum := range []string{
+		"",
+		"../secret",
+		"../../" + valid[6:],
+		valid[:62] + "/.",
+		strings.Repeat("zz", sha256.Size),
+		strings.ToUpper(valid)[:63],
+		valid + "00",
+	} {
+		if validSum(sum) {
+			t.Errorf("validSum(%q) = true", sum)
+		}
+		if _, err := s.Open(sum); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Open(%q) = %v, want ErrNotFound", sum, err)
+		}
+		if err := s.Remove(sum); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Remove(%q) = %v, want ErrNotFound", sum, err)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the store: %v", err)
+	}
+	if !validSum(valid) {
+		t.Errorf("validSum(%q) = false", valid)
+	}
+}
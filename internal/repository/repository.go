@@ -2,36 +2,369 @@ package repository
 
 import (
 	"errors"
+	"io"
 
 	"github.com/xenakil/integraflow-ims/internal/domain"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInUse        = errors.New("record is still referenced")
+	ErrInvalidQuery = errors.New("invalid query")
+	ErrDuplicate    = errors.New("already exists")
+	ErrTooLarge     = errors.New("file too large")
+)
+
+// Page selects a window of a sorted list. Sort is the API field name
+// (e.g. "createdAt"); an empty Sort orders by ID. Limit 0 returns all rows.
+type Page struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Desc   bool
+}
+
+// DateRange bounds a date or timestamp column. From and To are inclusive
+// YYYY-MM-DD dates; either may be empty.
+type DateRange struct {
+	From string
+	To   string
+}
+
+// Queries select records with AND-ed filters. Nil/empty fields do not
+// filter. String matches are case-insensitive; Search is a substring match
+// over the record's free-text columns. Domains, when non-nil, restricts
+// results to records in those domains; an empty list matches nothing.
+
+type RiskQuery struct {
+	Domain         *domain.Domain
+	Domains        []domain.Domain
+	ProcessID      *int
+	Process        *string // Process name
+	Status         *string
+	Owner          *string
+	Level          *string
+	ResidualLevel  *string
+	AssetID        *int
+	ISMSControl    *string // ID of a catalogue control
+	ReviewDueBy    string  // YYYY-MM-DD; risks with nextReviewAt on or before it
+	Created        DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           Page
+}
+
+type IncidentQuery struct {
+	Domain         *domain.Domain
+	Domains        []domain.Domain
+	ProcessID      *int // Process of the related risk
+	Status         *string
+	Level          *string
+	RelatedRiskID  *int
+	AspectID       *int
+	Type           *string
+	Reportable     *bool
+	Created        DateRange
+	Occurred       DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           Page
+}
+
+type AuditQuery struct {
+	Domain         *domain.Domain
+	Domains        []domain.Domain
+	ProcessID      *int
+	Status         *string
+	Auditor        *string
+	Planned        DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           Page
+}
+
+type ProcessQuery struct {
+	Domain   *domain.Domain
+	Owner    *string
+	ParentID *int
+	Search   string
+	Page     Page
+}
+
+type ActionQuery struct {
+	Domains        []domain.Domain // Domain of the source record
+	ProcessID      *int            // Process of the source record, as for IncidentQuery
+	Status         *string
+	SourceType     *string
+	SourceID       *int
+	Owner          *string
+	Due            DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           Page
+}
+
+// Records are soft-deleted: Delete stamps deleted_at/deleted_by and Restore
+// clears them. Create, Update, Delete and Restore append a HistoryEntry in
+// the same transaction. GetByID and GetAll(false) only return records that are not
+// deleted; GetDeletedByID only returns deleted ones. List applies filters, sorting and paging in the database and
+// also returns the total number of matching records before paging.
 
 type RiskRepository interface {
 	Create(r *domain.Risk) error
 	Update(r *domain.Risk) error
-	GetAll() ([]*domain.Risk, error)
+	GetAll(includeDeleted bool) ([]*domain.Risk, error)
+	List(q RiskQuery) ([]*domain.Risk, int, error)
 	GetByID(id int) (*domain.Risk, error)
+	GetDeletedByID(id int) (*domain.Risk, error)
+	Delete(id int, deletedBy string) error
+	Restore(id int, restoredBy string) error
+
+	// AddReview saves r, already carrying the outcome of the review, and
+	// records rv in the same transaction.
+	AddReview(r *domain.Risk, rv *domain.RiskReview) error
+	Reviews(riskID int) ([]*domain.RiskReview, error) // Newest first
+
+	// Create and Update append to the score history whenever a risk's
+	// ratings, scores or levels change.
+	ScoreHistory(riskID int) ([]*domain.RiskScore, error) // Oldest first
+	AllScores() ([]*domain.RiskScore, error)              // Every risk, oldest first
 }
 
 type IncidentRepository interface {
 	Create(i *domain.Incident) error
 	Update(i *domain.Incident) error
-	GetAll() ([]*domain.Incident, error)
+	GetAll(includeDeleted bool) ([]*domain.Incident, error)
+	List(q IncidentQuery) ([]*domain.Incident, int, error)
 	GetByID(id int) (*domain.Incident, error)
+	GetDeletedByID(id int) (*domain.Incident, error)
+	Delete(id int, deletedBy string) error
+	Restore(id int, restoredBy string) error
+
+	// Investigation returns the investigation of an incident, or ErrNotFound
+	// if none was started.
+	Investigation(incidentID int) (*domain.Investigation, error)
+	// SaveInvestigation saves inc, already carrying the investigation's root
+	// cause, and creates or replaces inv in the same transaction.
+	SaveInvestigation(inc *domain.Incident, inv *domain.Investigation) error
+	DeleteInvestigation(incidentID int) error
 }
 
 type AuditRepository interface {
 	Create(a *domain.Audit) error
 	Update(a *domain.Audit) error
-	GetAll() ([]*domain.Audit, error)
+	GetAll(includeDeleted bool) ([]*domain.Audit, error)
+	List(q AuditQuery) ([]*domain.Audit, int, error)
 	GetByID(id int) (*domain.Audit, error)
+	GetDeletedByID(id int) (*domain.Audit, error)
+	Delete(id int, deletedBy string) error
+	Restore(id int, restoredBy string) error
 }
 
 type ActionRepository interface {
 	Create(a *domain.Action) error
 	Update(a *domain.Action) error
-	GetAll() ([]*domain.Action, error)
+	GetAll(includeDeleted bool) ([]*domain.Action, error)
+	List(q ActionQuery) ([]*domain.Action, int, error)
 	GetByID(id int) (*domain.Action, error)
+	GetDeletedByID(id int) (*domain.Action, error)
+	Delete(id int, deletedBy string) error
+	Restore(id int, restoredBy string) error
+}
+
+// HistoryRepository reads the change history. Entries form a hash chain in
+// insertion order across all records.
+type HistoryRepository interface {
+	// List returns the history of one record, oldest first.
+	List(kind string, id int) ([]*domain.HistoryEntry, error)
+	// Verify checks the whole chain and reports the first broken link.
+	Verify() (*domain.HistoryVerification, error)
+}
+
+type UserRepository interface {
+	Create(u *domain.User) error
+	Update(u *domain.User) error
+	GetAll() ([]*domain.User, error)
+	GetByID(id int) (*domain.User, error)
+	GetByUsername(username string) (*domain.User, error)
+	// SetRoles replaces all role assignments of the user.
+	SetRoles(userID int, roles []domain.RoleAssignment) error
+}
+
+// TokenRepository stores hashed bearer tokens. Lookups are by hash only.
+type TokenRepository interface {
+	Create(t *domain.APIToken) error
+	GetByHash(hash string) (*domain.APIToken, error)
+	ListByUser(userID int) ([]*domain.APIToken, error)
+	Touch(id int, usedAt string) error
+	Delete(id int) error
+}
+
+// EventOutboxRepository holds the record change events that repositories
+// stage in the transaction of each change until they are published.
+type EventOutboxRepository interface {
+	// Pending returns up to limit staged events, oldest first, with their
+	// records decoded to domain records.
+	Pending(limit int) ([]domain.Event, error)
+	// Remove drops a published event.
+	Remove(eventID string) error
+}
+
+// NotificationRepository is the persisted outbox of rendered notifications.
+type NotificationRepository interface {
+	// Enqueue stores n. A notification with a DedupKey already queued for
+	// the same channel and recipient is dropped, leaving n.ID zero.
+	Enqueue(n *domain.Notification) error
+	// Due returns up to limit pending notifications whose next attempt is at
+	// or before now, oldest first.
+	Due(now string, limit int) ([]*domain.Notification, error)
+	MarkSent(id int, sentAt string) error
+	// MarkFailed records a failed attempt. The notification stays pending
+	// and is retried at nextAttemptAt unless final is set.
+	MarkFailed(id int, lastError, nextAttemptAt string, final bool) error
+	// List returns the newest notifications first, optionally by status.
+	List(status string, limit int) ([]*domain.Notification, error)
+}
+
+// WebhookRepository stores webhook subscriptions and their delivery queue.
+type WebhookRepository interface {
+	Create(w *domain.Webhook) error
+	Update(w *domain.Webhook) error
+	GetByID(id int) (*domain.Webhook, error)
+	List() ([]*domain.Webhook, error)
+	// Delete removes the webhook together with its deliveries.
+	Delete(id int) error
+
+	// Enqueue stores d. A delivery of the same event to the same webhook
+	// is dropped, leaving d.ID zero.
+	Enqueue(d *domain.WebhookDelivery) error
+	// Due returns up to limit pending deliveries of active webhooks whose
+	// next attempt is at or before now, oldest first.
+	Due(now string, limit int) ([]*domain.WebhookDelivery, error)
+	// RecordAttempt logs an attempt and sets the delivery's status. For
+	// pending deliveries nextAttemptAt schedules the retry.
+	RecordAttempt(deliveryID int, a domain.WebhookAttempt, status, nextAttemptAt string) error
+	// Deliveries returns a webhook's deliveries, newest first, with their
+	// attempts, and the total count.
+	Deliveries(webhookID int, page Page) ([]*domain.WebhookDelivery, int, error)
+}
+
+// ProcessRepository stores the process register. Processes are deleted
+// outright; Delete returns ErrInUse while risks, audits or sub-processes
+// refer to the process.
+type ProcessRepository interface {
+	// Create returns ErrDuplicate when the name is taken in any letter case.
+	Create(p *domain.Process) error
+	Update(p *domain.Process) error
+	GetByID(id int) (*domain.Process, error)
+	// GetByName matches the name regardless of letter case.
+	GetByName(name string) (*domain.Process, error)
+	List(q ProcessQuery) ([]*domain.Process, int, error)
+	Delete(id int) error
+}
+
+type AspectQuery struct {
+	ProcessID   *int
+	Condition   *string
+	Significant *bool
+	Owner       *string
+	Search      string
+	Page        Page
+}
+
+// AspectRepository stores the environmental aspects register.
+type AspectRepository interface {
+	Create(a *domain.EnvironmentalAspect) error
+	Update(a *domain.EnvironmentalAspect) error
+	GetByID(id int) (*domain.EnvironmentalAspect, error)
+	List(q AspectQuery) ([]*domain.EnvironmentalAspect, int, error)
+	// Delete returns ErrInUse while incidents, including deleted ones, are
+	// linked to the aspect.
+	Delete(id int) error
+}
+
+type AssetQuery struct {
+	Type           *string
+	Classification *string
+	Owner          *string
+	Search         string
+	Page           Page
+}
+
+// AssetRepository stores the ISMS information asset inventory.
+type AssetRepository interface {
+	Create(a *domain.InformationAsset) error
+	Update(a *domain.InformationAsset) error
+	GetByID(id int) (*domain.InformationAsset, error)
+	List(q AssetQuery) ([]*domain.InformationAsset, int, error)
+	// Delete returns ErrInUse while risks, including deleted ones, are
+	// linked to the asset.
+	Delete(id int) error
+}
+
+type ISMSControlQuery struct {
+	Theme      *string
+	Applicable *bool
+	Status     *string // Implementation status
+	Search     string
+	Page       Page
+}
+
+// ISMSControlRepository stores the ISMS controls catalogue. Controls list
+// in the order of their IDs, so 5.9 comes before 5.10.
+type ISMSControlRepository interface {
+	// Create returns ErrDuplicate when the ID is taken.
+	Create(c *domain.ISMSControl) error
+	// Seed adds the controls whose IDs are not taken yet and returns how
+	// many it added.
+	Seed(controls []domain.ISMSControl) (int, error)
+	Update(c *domain.ISMSControl) error
+	GetByID(id string) (*domain.ISMSControl, error)
+	List(q ISMSControlQuery) ([]*domain.ISMSControl, int, error)
+	// Delete returns ErrInUse while risks, including deleted ones, are
+	// linked to the control.
+	Delete(id string) error
+}
+
+// HoursWorkedRepository stores the hours worked per month.
+type HoursWorkedRepository interface {
+	// Set creates or replaces the hours of h.Month.
+	Set(h *domain.HoursWorked) error
+	// List returns the months from from to to (YYYY-MM, inclusive; either may
+	// be empty), oldest first.
+	List(from, to string) ([]*domain.HoursWorked, error)
+	Delete(month string) error
+}
+
+// AttachmentRepository stores attachment metadata; the content is kept in a
+// FileStore under its checksum.
+type AttachmentRepository interface {
+	Create(a *domain.Attachment) error
+	GetByID(id int) (*domain.Attachment, error)
+	// List returns the attachments of one record, oldest first.
+	List(recordType string, recordID int) ([]*domain.Attachment, error)
+	Delete(id int) error
+	// References counts the attachments whose content has the checksum.
+	References(sha256 string) (int, error)
+}
+
+// FileStore keeps file contents addressed by their SHA-256 checksum, so
+// identical uploads are stored once.
+type FileStore interface {
+	// Put stores the content of r and returns its hex checksum and size.
+	// Content over limit bytes is not stored and ErrTooLarge is returned.
+	Put(r io.Reader, limit int64) (sha256 string, size int64, err error)
+	// Open returns the content stored under the checksum, or ErrNotFound.
+	Open(sha256 string) (io.ReadSeekCloser, error)
+	Remove(sha256 string) error
+}
+
+// RiskMatrixRepository stores the single active risk matrix.
+type RiskMatrixRepository interface {
+	// Get returns the saved matrix, or domain.DefaultRiskMatrix if none was
+	// saved.
+	Get() (*domain.RiskMatrix, error)
+	// Save stores m together with the risks and incidents re-scored
+	// against it, with their history, in one transaction.
+	Save(m *domain.RiskMatrix, risks []*domain.Risk, incidents []*domain.Incident) error
 }
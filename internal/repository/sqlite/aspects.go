@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Environmental aspect repository ----------
+
+const aspectColumns = `id, process_id, ` + processName + `, activity, aspect, impact, condition, severity, frequency, score, significant,
+	legal_requirements, controls, owner, created_at, created_by, updated_at, updated_by`
+
+var aspectSortColumns = map[string]string{
+	"id":        "id",
+	"activity":  "activity",
+	"aspect":    "aspect",
+	"process":   processName,
+	"condition": "condition",
+	"score":     "score",
+	"owner":     "owner",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+type AspectRepository struct {
+	db *sql.DB
+}
+
+func NewAspectRepository(db *sql.DB) *AspectRepository {
+	return &AspectRepository{db: db}
+}
+
+func (r *AspectRepository) Create(a *domain.EnvironmentalAspect) error {
+	legal, controls, err := aspectLists(a)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO environmental_aspects (process_id, activity, aspect, impact, condition, severity, frequency, score, significant,
+			legal_requirements, controls, owner, created_at, created_by, updated_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		nullInt(a.ProcessID), a.Activity, a.Aspect, a.Impact, a.Condition, a.Severity, a.Frequency, a.Score, a.Significant,
+		legal, controls, a.Owner, a.CreatedAt, a.CreatedBy, a.UpdatedAt, a.UpdatedBy,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		a.ID = int(id)
+	}
+	return nil
+}
+
+func (r *AspectRepository) Update(a *domain.EnvironmentalAspect) error {
+	legal, controls, err := aspectLists(a)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		UPDATE environmental_aspects SET process_id=?, activity=?, aspect=?, impact=?, condition=?, severity=?, frequency=?, score=?,
+			significant=?, legal_requirements=?, controls=?, owner=?, updated_at=?, updated_by=?
+		WHERE id=?`,
+		nullInt(a.ProcessID), a.Activity, a.Aspect, a.Impact, a.Condition, a.Severity, a.Frequency, a.Score,
+		a.Significant, legal, controls, a.Owner, a.UpdatedAt, a.UpdatedBy, a.ID,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AspectRepository) GetByID(id int) (*domain.EnvironmentalAspect, error) {
+	a, err := scanAspect(r.db.QueryRow(`SELECT `+aspectColumns+` FROM environmental_aspects WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return a, nil
+}
+
+func (r *AspectRepository) List(q repository.AspectQuery) ([]*domain.EnvironmentalAspect, int, error) {
+	var w where
+	if q.ProcessID != nil {
+		w.add("process_id = ?", *q.ProcessID)
+	}
+	if q.Condition != nil {
+		w.add("condition = ? COLLATE NOCASE", *q.Condition)
+	}
+	if q.Significant != nil {
+		w.add("significant = ?", *q.Significant)
+	}
+	if q.Owner != nil {
+		w.add("owner = ? COLLATE NOCASE", *q.Owner)
+	}
+	w.addSearch(q.Search, "activity", "aspect", "impact", "owner")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, aspectSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM environmental_aspects`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+aspectColumns+` FROM environmental_aspects`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.EnvironmentalAspect, 0)
+	for rows.Next() {
+		a, err := scanAspect(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
+}
+
+func (r *AspectRepository) Delete(id int) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var incidents int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM incidents WHERE aspect_id = ?`, id).Scan(&incidents); err != nil {
+			return err
+		}
+		if incidents > 0 {
+			return fmt.Errorf("%w: environmental aspect %d is linked to %d incident(s)", repository.ErrInUse, id, incidents)
+		}
+		res, err := tx.Exec(`DELETE FROM environmental_aspects WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func scanAspect(row rowScanner) (*domain.EnvironmentalAspect, error) {
+	a := &domain.EnvironmentalAspect{}
+	var process sqlNullInt
+	var name sql.NullString
+	var legal, controls string
+	if err := row.Scan(&a.ID, &process, &name, &a.Activity, &a.Aspect, &a.Impact, &a.Condition,
+		&a.Severity, &a.Frequency, &a.Score, &a.Significant, &legal, &controls, &a.Owner,
+		&a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy); err != nil {
+		return nil, err
+	}
+	if process.Valid {
+		id := process.V
+		a.ProcessID = &id
+	}
+	a.Process = name.String
+	if err := json.Unmarshal([]byte(legal), &a.LegalRequirements); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(controls), &a.Controls); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// aspectLists encodes the legal requirements and controls of a for storage.
+func aspectLists(a *domain.EnvironmentalAspect) (legal, controls string, err error) {
+	if a.LegalRequirements == nil {
+		a.LegalRequirements = []string{}
+	}
+	if a.Controls == nil {
+		a.Controls = []domain.RiskControl{}
+	}
+	l, err := json.Marshal(a.LegalRequirements)
+	if err != nil {
+		return "", "", err
+	}
+	c, err := json.Marshal(a.Controls)
+	if err != nil {
+		return "", "", err
+	}
+	return string(l), string(c), nil
+}
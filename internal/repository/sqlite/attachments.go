@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Attachment repository ----------
+
+const attachmentColumns = `id, record_type, record_id, file_name, content_type, size, sha256, description, uploaded_at, uploaded_by`
+
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(a *domain.Attachment) error {
+	res, err := r.db.Exec(`
+		INSERT INTO attachments (record_type, record_id, file_name, content_type, size, sha256, description, uploaded_at, uploaded_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.RecordType, a.RecordID, a.FileName, a.ContentType, a.Size, a.SHA256, a.Description, a.UploadedAt, a.UploadedBy,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+	return nil
+}
+
+func (r *AttachmentRepository) GetByID(id int) (*domain.Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRow(`SELECT `+attachmentColumns+` FROM attachments WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) List(recordType string, recordID int) ([]*domain.Attachment, error) {
+	rows, err := r.db.Query(`SELECT `+attachmentColumns+` FROM attachments WHERE record_type = ? AND record_id = ? ORDER BY id`,
+		recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Attachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM attachments WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) References(sha256 string) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE sha256 = ?`, sha256).Scan(&n)
+	return n, err
+}
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	a := &domain.Attachment{}
+	if err := row.Scan(&a.ID, &a.RecordType, &a.RecordID, &a.FileName, &a.ContentType, &a.Size, &a.SHA256,
+		&a.Description, &a.UploadedAt, &a.UploadedBy); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
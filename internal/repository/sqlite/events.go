@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ---------- Event outbox ----------
+
+// stageEvents stages the events of a change written in tx, so they are
+// committed or rolled back with it: <kind>.created for new records (before
+// is nil), <kind>.status_changed when the status changed and
+// risk.level_changed when a risk's inherent level changed.
+func stageEvents(tx *sql.Tx, actor string, before, after any) error {
+	var events []domain.Event
+	switch rec := after.(type) {
+	case *domain.Risk:
+		prev, ok := before.(*domain.Risk)
+		switch {
+		case !ok:
+			events = append(events, newEvent(domain.EventRiskCreated, domain.KindRisk, rec.ID, rec.Domain, actor, "", "", rec))
+		default:
+			if prev.Status != rec.Status {
+				events = append(events, newEvent(domain.EventRiskStatusChanged, domain.KindRisk, rec.ID, rec.Domain, actor, prev.Status, rec.Status, rec))
+			}
+			if prev.Level != rec.Level {
+				events = append(events, newEvent(domain.EventRiskLevelChanged, domain.KindRisk, rec.ID, rec.Domain, actor, prev.Level, rec.Level, rec))
+			}
+		}
+	case *domain.Incident:
+		prev, ok := before.(*domain.Incident)
+		switch {
+		case !ok:
+			events = append(events, newEvent(domain.EventIncidentCreated, domain.KindIncident, rec.ID, rec.Domain, actor, "", "", rec))
+		case prev.Status != rec.Status:
+			events = append(events, newEvent(domain.EventIncidentStatusChanged, domain.KindIncident, rec.ID, rec.Domain, actor, prev.Status, rec.Status, rec))
+		}
+	case *domain.Audit:
+		prev, ok := before.(*domain.Audit)
+		switch {
+		case !ok:
+			events = append(events, newEvent(domain.EventAuditCreated, domain.KindAudit, rec.ID, rec.Domain, actor, "", "", rec))
+		case prev.Status != rec.Status:
+			events = append(events, newEvent(domain.EventAuditStatusChanged, domain.KindAudit, rec.ID, rec.Domain, actor, prev.Status, rec.Status, rec))
+		}
+	case *domain.Action:
+		prev, ok := before.(*domain.Action)
+		if ok && prev.Status == rec.Status {
+			break
+		}
+		dom, err := actionDomain(tx, rec)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			events = append(events, newEvent(domain.EventActionCreated, domain.KindAction, rec.ID, dom, actor, "", "", rec))
+		} else {
+			events = append(events, newEvent(domain.EventActionStatusChanged, domain.KindAction, rec.ID, dom, actor, prev.Status, rec.Status, rec))
+		}
+	}
+
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO event_outbox (event_id, record_type, event) VALUES (?, ?, ?)`,
+			e.ID, e.RecordType, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newEvent(typ, kind string, id int, dom domain.Domain, actor, from, to string, record any) domain.Event {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return domain.Event{
+		ID:         hex.EncodeToString(b),
+		Type:       typ,
+		RecordType: kind,
+		RecordID:   id,
+		Domain:     dom,
+		Actor:      actor,
+		OccurredAt: time.Now().Format(time.RFC3339),
+		From:       from,
+		To:         to,
+		Record:     record,
+	}
+}
+
+// actionDomain returns the domain of the record an action was raised from,
+// which may be soft-deleted.
+func actionDomain(tx *sql.Tx, a *domain.Action) (domain.Domain, error) {
+	table := map[string]string{"Risk": "risks", "Incident": "incidents", "Audit": "audits"}[a.SourceType]
+	if table == "" {
+		return "", nil
+	}
+	var dom string
+	err := tx.QueryRow(`SELECT domain FROM `+table+` WHERE id = ?`, a.SourceID).Scan(&dom)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return domain.Domain(dom), err
+}
+
+type EventOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewEventOutboxRepository(db *sql.DB) *EventOutboxRepository {
+	return &EventOutboxRepository{db: db}
+}
+
+func (r *EventOutboxRepository) Pending(limit int) ([]domain.Event, error) {
+	rows, err := r.db.Query(`SELECT record_type, event FROM event_outbox ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Event
+	for rows.Next() {
+		var kind, data string
+		if err := rows.Scan(&kind, &data); err != nil {
+			return nil, err
+		}
+		e, err := decodeEvent(kind, []byte(data))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *EventOutboxRepository) Remove(eventID string) error {
+	_, err := r.db.Exec(`DELETE FROM event_outbox WHERE event_id = ?`, eventID)
+	return err
+}
+
+// decodeEvent decodes a staged event with its record as the domain record of
+// kind, as publishers expect.
+func decodeEvent(kind string, data []byte) (domain.Event, error) {
+	var staged struct {
+		domain.Event
+		Record json.RawMessage `json:"record"`
+	}
+	if err := json.Unmarshal(data, &staged); err != nil {
+		return domain.Event{}, err
+	}
+	var rec any
+	switch kind {
+	case domain.KindRisk:
+		rec = &domain.Risk{}
+	case domain.KindIncident:
+		rec = &domain.Incident{}
+	case domain.KindAudit:
+		rec = &domain.Audit{}
+	case domain.KindAction:
+		rec = &domain.Action{}
+	default:
+		return domain.Event{}, fmt.Errorf("event %s: unknown record type %q", staged.ID, kind)
+	}
+	if err := json.Unmarshal(staged.Record, rec); err != nil {
+		return domain.Event{}, fmt.Errorf("event %s: %w", staged.ID, err)
+	}
+	e := staged.Event
+	e.Record = rec
+	return e, nil
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func pending(t *testing.T, db *sql.DB) []domain.Event {
+	t.Helper()
+	events, err := NewEventOutboxRepository(db).Pending(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func eventTypes(events []domain.Event) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Type)
+	}
+	return out
+}
+
+func TestChangesStageEvents(t *testing.T) {
+	db := openTestDB(t)
+	incidents := NewIncidentRepository(db)
+	actions := NewActionRepository(db)
+
+	// seedHistory creates incident 1 and gives it a root cause.
+	seedHistory(t, db, 2)
+	inc, err := incidents.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inc.Status, inc.UpdatedBy = "Investigation", "bob"
+	if err := incidents.Update(inc); err != nil {
+		t.Fatal(err)
+	}
+	a := &domain.Action{Title: "Replace ladder", SourceType: "Incident", SourceID: inc.ID, Status: "Open", CreatedBy: "alice", UpdatedBy: "alice"}
+	if err := actions.Create(a); err != nil {
+		t.Fatal(err)
+	}
+	a.Owner, a.UpdatedBy = "bob", "alice" // No status change, no event
+	if err := actions.Update(a); err != nil {
+		t.Fatal(err)
+	}
+	a.Status, a.UpdatedBy = "Done", "bob"
+	if err := actions.Update(a); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pending(t, db)
+	want := []string{domain.EventIncidentCreated, domain.EventIncidentStatusChanged, domain.EventActionCreated, domain.EventActionStatusChanged}
+	if got := eventTypes(events); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Fatalf("staged %v, want %v", got, want)
+	}
+
+	changed := events[1]
+	rec, ok := changed.Record.(*domain.Incident)
+	if !ok || rec.ID != inc.ID || rec.Status != "Investigation" || rec.RootCause != "Worn rung" {
+		t.Errorf("status change record %#v, want the saved incident", changed.Record)
+	}
+	if changed.From != "Open" || changed.To != "Investigation" || changed.Actor != "bob" || changed.Domain != domain.DomainOHS {
+		t.Errorf("status change event %+v", changed)
+	}
+	// Action events carry the domain of their source.
+	done := events[3]
+	if _, ok := done.Record.(*domain.Action); !ok || done.Domain != domain.DomainOHS || done.To != "Done" {
+		t.Errorf("action event %+v", done)
+	}
+
+	ids := make(map[string]bool)
+	for _, e := range events {
+		ids[e.ID] = true
+	}
+	if len(ids) != len(events) {
+		t.Errorf("event IDs are not unique: %v", ids)
+	}
+
+	if err := NewEventOutboxRepository(db).Remove(events[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if left := pending(t, db); len(left) != len(events)-1 || left[0].ID != events[1].ID {
+		t.Errorf("after removing the oldest event %d are staged", len(left))
+	}
+}
+
+func TestFailedChangeStagesNothing(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 1)
+	if err := NewEventOutboxRepository(db).Remove(pending(t, db)[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	inc, err := NewIncidentRepository(db).GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first incident is saved within the transaction, the second does
+	// not exist, so the whole change is rolled back with its events.
+	m, err := NewRiskMatrixRepository(db).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inc.Status = "Investigation"
+	missing := *inc
+	missing.ID = 99
+	if err := NewRiskMatrixRepository(db).Save(m, nil, []*domain.Incident{inc, &missing}); err == nil {
+		t.Fatal("saving a missing incident succeeded")
+	}
+	if events := pending(t, db); len(events) != 0 {
+		t.Errorf("a rolled back change staged %v", eventTypes(events))
+	}
+}
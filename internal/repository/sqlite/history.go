@@ -0,0 +1,275 @@
+package sqlite
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Change history ----------
+
+// historyIgnored are bookkeeping fields that are recorded on the history
+// entry itself (actor, timestamp) rather than as field changes.
+var historyIgnored = map[string]bool{
+	"id":        true,
+	"createdAt": true,
+	"createdBy": true,
+	"updatedAt": true,
+	"updatedBy": true,
+}
+
+// writeHistory appends a history entry with the field-level differences
+// between before and after, which are domain records (before is nil for
+// created records), and stages the events of the change. Updates that
+// change nothing are not recorded.
+func writeHistory(tx *sql.Tx, kind string, id int, action, actor string, before, after any) error {
+	changes, err := diffFields(before, after)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 && action == domain.HistoryUpdated {
+		return nil
+	}
+	if err := appendHistory(tx, kind, id, action, actor, changes); err != nil {
+		return err
+	}
+	return stageEvents(tx, actor, before, after)
+}
+
+// appendHistory inserts an entry chained to the newest existing one. The
+// single database connection (see NewDB) keeps the read of the previous hash
+// and the insert from interleaving with other writers.
+func appendHistory(tx *sql.Tx, kind string, id int, action, actor string, changes []domain.FieldChange) error {
+	if changes == nil {
+		changes = make([]domain.FieldChange, 0)
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	h := &historyRow{
+		recordType: kind,
+		recordID:   id,
+		action:     action,
+		actor:      actor,
+		changedAt:  time.Now().Format(time.RFC3339),
+		changes:    string(data),
+	}
+	err = tx.QueryRow(`SELECT hash FROM record_history ORDER BY id DESC LIMIT 1`).Scan(&h.prevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	h.hash = h.computeHash()
+
+	_, err = tx.Exec(`
+		INSERT INTO record_history (record_type, record_id, action, actor, changed_at, changes, prev_hash, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		h.recordType, h.recordID, h.action, h.actor, h.changedAt, h.changes, h.prevHash, h.hash,
+	)
+	return err
+}
+
+const historyColumns = `id, record_type, record_id, action, actor, changed_at, changes, prev_hash, hash`
+
+// historyRow is a record_history row as stored, with changes still encoded.
+type historyRow struct {
+	id         int
+	recordType string
+	recordID   int
+	action     string
+	actor      string
+	changedAt  string
+	changes    string
+	prevHash   string
+	hash       string
+}
+
+func scanHistoryRow(row rowScanner) (*historyRow, error) {
+	h := &historyRow{}
+	err := row.Scan(&h.id, &h.recordType, &h.recordID, &h.action, &h.actor, &h.changedAt, &h.changes, &h.prevHash, &h.hash)
+	return h, err
+}
+
+// computeHash returns the hex SHA-256 of the entry's content and prevHash.
+// The fields are encoded as a JSON array so that no two entries share an
+// encoding. The row id is not included; order is covered by prevHash.
+func (h *historyRow) computeHash() string {
+	data, _ := json.Marshal([]any{h.prevHash, h.recordType, h.recordID, h.action, h.actor, h.changedAt, h.changes})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (h *historyRow) entry() (*domain.HistoryEntry, error) {
+	e := &domain.HistoryEntry{
+		ID:         h.id,
+		RecordType: h.recordType,
+		RecordID:   h.recordID,
+		Action:     h.action,
+		Actor:      h.actor,
+		ChangedAt:  h.changedAt,
+		PrevHash:   h.prevHash,
+		Hash:       h.hash,
+	}
+	if err := json.Unmarshal([]byte(h.changes), &e.Changes); err != nil {
+		return nil, fmt.Errorf("history entry %d: %w", h.id, err)
+	}
+	return e, nil
+}
+
+// diffFields compares two records by their JSON fields and returns the
+// changed fields sorted by name. Empty fields and lists of created records
+// are left out.
+func diffFields(before, after any) ([]domain.FieldChange, error) {
+	b, err := fieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for k := range b {
+		names[k] = true
+	}
+	for k := range a {
+		names[k] = true
+	}
+
+	var out []domain.FieldChange
+	for name := range names {
+		if historyIgnored[name] {
+			continue
+		}
+		bv, av := b[name], a[name]
+		if before == nil && isEmpty(av) {
+			continue
+		}
+		bj, _ := json.Marshal(bv)
+		aj, _ := json.Marshal(av)
+		if string(bj) == string(aj) {
+			continue
+		}
+		out = append(out, domain.FieldChange{Field: name, Before: bv, After: av})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out, nil
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func fieldMap(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// noRows maps sql.ErrNoRows to repository.ErrNotFound.
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// HistoryRepository reads the append-only record_history table. Entries are
+// written by the record repositories in the same transaction as the change.
+type HistoryRepository struct {
+	db *sql.DB
+}
+
+func NewHistoryRepository(db *sql.DB) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+func (r *HistoryRepository) List(kind string, id int) ([]*domain.HistoryEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT `+historyColumns+`
+		FROM record_history WHERE record_type = ? AND record_id = ? ORDER BY id`, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.HistoryEntry, 0)
+	for rows.Next() {
+		h, err := scanHistoryRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		e, err := h.entry()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Verify walks the whole history in id order, recomputing each hash and
+// checking that it links to the previous entry. It stops at the first
+// broken link.
+func (r *HistoryRepository) Verify() (*domain.HistoryVerification, error) {
+	rows, err := r.db.Query(`SELECT ` + historyColumns + ` FROM record_history ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	v := &domain.HistoryVerification{}
+	prev, prevID := "", 0
+	for rows.Next() {
+		h, err := scanHistoryRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case h.prevHash != prev && prevID == 0:
+			v.BrokenID, v.Reason = h.id, "first entry does not start the chain; earlier entries were removed"
+		case h.prevHash != prev:
+			v.BrokenID, v.Reason = h.id, fmt.Sprintf("prevHash does not match the hash of entry %d; entries were removed or altered", prevID)
+		case h.computeHash() != h.hash:
+			v.BrokenID, v.Reason = h.id, "content does not match its hash; the entry was altered"
+		}
+		if v.BrokenID != 0 {
+			return v, nil
+		}
+		v.Checked++
+		prev, prevID = h.hash, h.id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	v.Valid = true
+	v.HeadHash = prev
+	return v, nil
+}
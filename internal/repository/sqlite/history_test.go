@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// openTestDB returns a migrated database in a temporary file.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := NewDB(filepath.Join(t.TempDir(), "ims.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedHistory writes n history entries by creating incidents and updating
+// each one once.
+func seedHistory(t *testing.T, db *sql.DB, n int) {
+	t.Helper()
+	repo := NewIncidentRepository(db)
+	for i := 0; i < n; i++ {
+		if i%2 == 1 {
+			inc, err := repo.GetByID(i/2 + 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inc.RootCause = "Worn rung"
+			inc.UpdatedBy = "bob"
+			if err := repo.Update(inc); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		inc := &domain.Incident{
+			Title:      "Fall from ladder",
+			Domain:     domain.DomainOHS,
+			Severity:   3,
+			Likelihood: 2,
+			Status:     "Open",
+			OccurredOn: "2025-11-03",
+			CreatedAt:  "2025-11-03T10:00:00Z",
+			UpdatedAt:  "2025-11-03T10:00:00Z",
+			CreatedBy:  "alice",
+			UpdatedBy:  "alice",
+		}
+		if err := repo.Create(inc); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// tamper runs stmt with the append-only triggers lifted, as someone with
+// direct access to the database file could.
+func tamper(t *testing.T, db *sql.DB, stmt string, args ...any) {
+	t.Helper()
+	for _, s := range []string{
+		`DROP TRIGGER record_history_no_update`,
+		`DROP TRIGGER record_history_no_delete`,
+	} {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(stmt, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func verify(t *testing.T, db *sql.DB) *domain.HistoryVerification {
+	t.Helper()
+	v, err := NewHistoryRepository(db).Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVerifyEmptyHistory(t *testing.T) {
+	v := verify(t, openTestDB(t))
+	if !v.Valid || v.Checked != 0 || v.HeadHash != "" {
+		t.Errorf("Verify = %+v, want valid with nothing checked", v)
+	}
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 6)
+
+	v := verify(t, db)
+	if !v.Valid || v.Checked != 6 || v.BrokenID != 0 {
+		t.Fatalf("Verify = %+v, want 6 valid entries", v)
+	}
+	var head string
+	if err := db.QueryRow(`SELECT hash FROM record_history ORDER BY id DESC LIMIT 1`).Scan(&head); err != nil {
+		t.Fatal(err)
+	}
+	if v.HeadHash != head {
+		t.Errorf("HeadHash = %s, want the newest entry's hash %s", v.HeadHash, head)
+	}
+
+	// The first entry starts the chain and the next links to it.
+	entries, err := NewHistoryRepository(db).List(domain.KindIncident, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].PrevHash != "" || entries[1].PrevHash != entries[0].Hash {
+		t.Errorf("history of incident 1: %+v", entries)
+	}
+}
+
+func TestHistoryIsAppendOnly(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 2)
+
+	if _, err := db.Exec(`UPDATE record_history SET actor = 'mallory' WHERE id = 1`); err == nil {
+		t.Error("updating a history entry succeeded")
+	}
+	if _, err := db.Exec(`DELETE FROM record_history WHERE id = 1`); err == nil {
+		t.Error("deleting a history entry succeeded")
+	}
+	if v := verify(t, db); !v.Valid {
+		t.Errorf("Verify = %+v after refused changes", v)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name     string
+		stmt     string
+		brokenID int
+		reason   string
+	}{
+		{"altered actor", `UPDATE record_history SET actor = 'mallory' WHERE id = 3`, 3, "content does not match its hash"},
+		{"altered changes", `UPDATE record_history SET changes = '[]' WHERE id = 4`, 4, "content does not match its hash"},
+		{"removed entry", `DELETE FROM record_history WHERE id = 3`, 4, "prevHash does not match the hash of entry 2"},
+		{"removed first entry", `DELETE FROM record_history WHERE id = 1`, 2, "first entry does not start the chain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			seedHistory(t, db, 6)
+			tamper(t, db, tt.stmt)
+
+			v := verify(t, db)
+			if v.Valid || v.BrokenID != tt.brokenID || !strings.Contains(v.Reason, tt.reason) {
+				t.Errorf("Verify = %+v, want entry %d broken: %s", v, tt.brokenID, tt.reason)
+			}
+			if v.HeadHash != "" {
+				t.Errorf("HeadHash = %q for a broken chain", v.HeadHash)
+			}
+		})
+	}
+}
+
+func TestVerifyDetectsRehashedEntry(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 4)
+
+	// Alter an entry and fix up its own hash: the next entry no longer links.
+	h, err := scanHistoryRow(db.QueryRow(`SELECT ` + historyColumns + ` FROM record_history WHERE id = 2`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.actor = "mallory"
+	tamper(t, db, `UPDATE record_history SET actor = ?, hash = ? WHERE id = 2`, h.actor, h.computeHash())
+
+	v := verify(t, db)
+	if v.Valid || v.BrokenID != 3 || v.Checked != 2 {
+		t.Errorf("Verify = %+v, want entry 3 broken after 2 checked", v)
+	}
+}
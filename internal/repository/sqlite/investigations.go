@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Incident investigations ----------
+
+const investigationColumns = `incident_id, problem, whys, fishbone, root_cause, created_at, created_by, updated_at, updated_by`
+
+func (r *IncidentRepository) Investigation(incidentID int) (*domain.Investigation, error) {
+	inv, err := scanInvestigation(r.db.QueryRow(`SELECT `+investigationColumns+` FROM incident_investigations WHERE incident_id = ?`, incidentID))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return inv, nil
+}
+
+func (r *IncidentRepository) SaveInvestigation(inc *domain.Incident, inv *domain.Investigation) error {
+	if inv.Whys == nil {
+		inv.Whys = []domain.WhyStep{}
+	}
+	if inv.Fishbone == nil {
+		inv.Fishbone = []domain.FishboneCategory{}
+	}
+	whys, err := json.Marshal(inv.Whys)
+	if err != nil {
+		return err
+	}
+	fishbone, err := json.Marshal(inv.Fishbone)
+	if err != nil {
+		return err
+	}
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := updateIncident(tx, inc); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+			INSERT INTO incident_investigations (incident_id, problem, whys, fishbone, root_cause, created_at, created_by, updated_at, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (incident_id) DO UPDATE SET problem=excluded.problem, whys=excluded.whys, fishbone=excluded.fishbone,
+				root_cause=excluded.root_cause, updated_at=excluded.updated_at, updated_by=excluded.updated_by`,
+			inv.IncidentID, inv.Problem, string(whys), string(fishbone), inv.RootCause,
+			inv.CreatedAt, inv.CreatedBy, inv.UpdatedAt, inv.UpdatedBy,
+		)
+		return err
+	})
+}
+
+func (r *IncidentRepository) DeleteInvestigation(incidentID int) error {
+	res, err := r.db.Exec(`DELETE FROM incident_investigations WHERE incident_id = ?`, incidentID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanInvestigation(row rowScanner) (*domain.Investigation, error) {
+	inv := &domain.Investigation{}
+	var whys, fishbone string
+	if err := row.Scan(&inv.IncidentID, &inv.Problem, &whys, &fishbone, &inv.RootCause,
+		&inv.CreatedAt, &inv.CreatedBy, &inv.UpdatedAt, &inv.UpdatedBy); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(whys), &inv.Whys); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fishbone), &inv.Fishbone); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
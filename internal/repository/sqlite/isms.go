@@ -0,0 +1,393 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Information asset repository ----------
+
+const assetColumns = `id, name, description, asset_type, owner, location, classification, confidentiality, integrity, availability,
+	criticality, created_at, created_by, updated_at, updated_by`
+
+var assetSortColumns = map[string]string{
+	"id":             "id",
+	"name":           "name",
+	"type":           "asset_type",
+	"owner":          "owner",
+	"classification": "classification",
+	"criticality":    "criticality",
+	"createdAt":      "created_at",
+	"updatedAt":      "updated_at",
+}
+
+type AssetRepository struct {
+	db *sql.DB
+}
+
+func NewAssetRepository(db *sql.DB) *AssetRepository {
+	return &AssetRepository{db: db}
+}
+
+func (r *AssetRepository) Create(a *domain.InformationAsset) error {
+	res, err := r.db.Exec(`
+		INSERT INTO information_assets (name, description, asset_type, owner, location, classification, confidentiality, integrity, availability,
+			criticality, created_at, created_by, updated_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Name, a.Description, a.Type, a.Owner, a.Location, a.Classification, a.Confidentiality, a.Integrity, a.Availability,
+		a.Criticality, a.CreatedAt, a.CreatedBy, a.UpdatedAt, a.UpdatedBy,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		a.ID = int(id)
+	}
+	return nil
+}
+
+func (r *AssetRepository) Update(a *domain.InformationAsset) error {
+	res, err := r.db.Exec(`
+		UPDATE information_assets SET name=?, description=?, asset_type=?, owner=?, location=?, classification=?,
+			confidentiality=?, integrity=?, availability=?, criticality=?, updated_at=?, updated_by=?
+		WHERE id=?`,
+		a.Name, a.Description, a.Type, a.Owner, a.Location, a.Classification,
+		a.Confidentiality, a.Integrity, a.Availability, a.Criticality, a.UpdatedAt, a.UpdatedBy, a.ID,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AssetRepository) GetByID(id int) (*domain.InformationAsset, error) {
+	a, err := scanAsset(r.db.QueryRow(`SELECT `+assetColumns+` FROM information_assets WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return a, nil
+}
+
+func (r *AssetRepository) List(q repository.AssetQuery) ([]*domain.InformationAsset, int, error) {
+	var w where
+	if q.Type != nil {
+		w.add("asset_type = ? COLLATE NOCASE", *q.Type)
+	}
+	if q.Classification != nil {
+		w.add("classification = ? COLLATE NOCASE", *q.Classification)
+	}
+	if q.Owner != nil {
+		w.add("owner = ? COLLATE NOCASE", *q.Owner)
+	}
+	w.addSearch(q.Search, "name", "description", "owner", "location")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, assetSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM information_assets`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+assetColumns+` FROM information_assets`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.InformationAsset, 0)
+	for rows.Next() {
+		a, err := scanAsset(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
+}
+
+func (r *AssetRepository) Delete(id int) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var risks int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM risk_assets WHERE asset_id = ?`, id).Scan(&risks); err != nil {
+			return err
+		}
+		if risks > 0 {
+			return fmt.Errorf("%w: asset %d is linked to %d risk(s)", repository.ErrInUse, id, risks)
+		}
+		res, err := tx.Exec(`DELETE FROM information_assets WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func scanAsset(row rowScanner) (*domain.InformationAsset, error) {
+	a := &domain.InformationAsset{}
+	if err := row.Scan(&a.ID, &a.Name, &a.Description, &a.Type, &a.Owner, &a.Location, &a.Classification,
+		&a.Confidentiality, &a.Integrity, &a.Availability, &a.Criticality,
+		&a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ---------- ISMS control repository ----------
+
+const ismsControlColumns = `id, theme, title, description, annex_a, applicable, justification, implementation_status, owner, updated_at, updated_by`
+
+var ismsControlSortColumns = map[string]string{
+	"id":                   "sort_key",
+	"theme":                "theme",
+	"title":                "title",
+	"implementationStatus": "implementation_status",
+	"owner":                "owner",
+	"updatedAt":            "updated_at",
+}
+
+type ISMSControlRepository struct {
+	db *sql.DB
+}
+
+func NewISMSControlRepository(db *sql.DB) *ISMSControlRepository {
+	return &ISMSControlRepository{db: db}
+}
+
+func (r *ISMSControlRepository) Create(c *domain.ISMSControl) error {
+	if _, err := insertISMSControl(r.db, c, ""); err != nil {
+		return uniqueViolation(err)
+	}
+	return nil
+}
+
+func (r *ISMSControlRepository) Seed(controls []domain.ISMSControl) (int, error) {
+	added := 0
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		for i := range controls {
+			n, err := insertISMSControl(tx, &controls[i], " ON CONFLICT (id) DO NOTHING")
+			if err != nil {
+				return err
+			}
+			added += int(n)
+		}
+		return nil
+	})
+	return added, err
+}
+
+// insertISMSControl inserts c with the given conflict clause and returns
+// the number of rows added.
+func insertISMSControl(db execer, c *domain.ISMSControl, onConflict string) (int64, error) {
+	res, err := db.Exec(`
+		INSERT INTO isms_controls (id, sort_key, theme, title, description, annex_a, applicable, justification, implementation_status,
+			owner, updated_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`+onConflict,
+		c.ID, controlSortKey(c.ID), c.Theme, c.Title, c.Description, c.AnnexA, c.Applicable, c.Justification, c.ImplementationStatus,
+		c.Owner, c.UpdatedAt, c.UpdatedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *ISMSControlRepository) Update(c *domain.ISMSControl) error {
+	res, err := r.db.Exec(`
+		UPDATE isms_controls SET theme=?, title=?, description=?, applicable=?, justification=?, implementation_status=?,
+			owner=?, updated_at=?, updated_by=?
+		WHERE id=?`,
+		c.Theme, c.Title, c.Description, c.Applicable, c.Justification, c.ImplementationStatus,
+		c.Owner, c.UpdatedAt, c.UpdatedBy, c.ID,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ISMSControlRepository) GetByID(id string) (*domain.ISMSControl, error) {
+	c, err := scanISMSControl(r.db.QueryRow(`SELECT `+ismsControlColumns+` FROM isms_controls WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return c, nil
+}
+
+func (r *ISMSControlRepository) List(q repository.ISMSControlQuery) ([]*domain.ISMSControl, int, error) {
+	var w where
+	if q.Theme != nil {
+		w.add("theme = ? COLLATE NOCASE", *q.Theme)
+	}
+	if q.Applicable != nil {
+		w.add("applicable = ?", *q.Applicable)
+	}
+	if q.Status != nil {
+		w.add("implementation_status = ? COLLATE NOCASE", *q.Status)
+	}
+	w.addSearch(q.Search, "id", "title", "description", "justification", "owner")
+
+	if q.Page.Sort == "" {
+		q.Page.Sort = "id"
+	}
+	tail, pageArgs, err := orderAndLimit(q.Page, ismsControlSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM isms_controls`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+ismsControlColumns+` FROM isms_controls`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.ISMSControl, 0)
+	for rows.Next() {
+		c, err := scanISMSControl(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, c)
+	}
+	return out, total, rows.Err()
+}
+
+func (r *ISMSControlRepository) Delete(id string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var risks int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM risk_isms_controls WHERE control_id = ?`, id).Scan(&risks); err != nil {
+			return err
+		}
+		if risks > 0 {
+			return fmt.Errorf("%w: control %s is linked to %d risk(s)", repository.ErrInUse, id, risks)
+		}
+		res, err := tx.Exec(`DELETE FROM isms_controls WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func scanISMSControl(row rowScanner) (*domain.ISMSControl, error) {
+	c := &domain.ISMSControl{}
+	if err := row.Scan(&c.ID, &c.Theme, &c.Title, &c.Description, &c.AnnexA, &c.Applicable, &c.Justification,
+		&c.ImplementationStatus, &c.Owner, &c.UpdatedAt, &c.UpdatedBy); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// controlSortKey zero-pads the numeric parts of a control ID so that IDs
+// sort by number: 5.9 before 5.10.
+func controlSortKey(id string) string {
+	parts := strings.Split(id, ".")
+	for i, p := range parts {
+		if p != "" && strings.Trim(p, "0123456789") == "" {
+			parts[i] = fmt.Sprintf("%08s", p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// ---------- Risk links ----------
+
+// loadRiskLinks fills in the asset and ISMS control links of risks, in the
+// order they were given.
+func loadRiskLinks(q queryer, risks ...*domain.Risk) error {
+	if len(risks) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.Risk, len(risks))
+	ids := make([]any, 0, len(risks))
+	for _, risk := range risks {
+		risk.AssetIDs = make([]int, 0)
+		risk.ISMSControls = make([]string, 0)
+		byID[risk.ID] = risk
+		ids = append(ids, risk.ID)
+	}
+
+	var w where
+	w.addIn("risk_id", ids...)
+	rows, err := q.Query(`SELECT risk_id, asset_id FROM risk_assets`+w.sql()+` ORDER BY risk_id, rowid`, w.args...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var riskID, assetID int
+		if err := rows.Scan(&riskID, &assetID); err != nil {
+			rows.Close()
+			return err
+		}
+		byID[riskID].AssetIDs = append(byID[riskID].AssetIDs, assetID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	rows, err = q.Query(`SELECT risk_id, control_id FROM risk_isms_controls`+w.sql()+` ORDER BY risk_id, rowid`, w.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var riskID int
+		var controlID string
+		if err := rows.Scan(&riskID, &controlID); err != nil {
+			return err
+		}
+		byID[riskID].ISMSControls = append(byID[riskID].ISMSControls, controlID)
+	}
+	return rows.Err()
+}
+
+// saveRiskLinks replaces the stored asset and ISMS control links of risk.
+func saveRiskLinks(tx *sql.Tx, risk *domain.Risk) error {
+	if _, err := tx.Exec(`DELETE FROM risk_assets WHERE risk_id = ?`, risk.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM risk_isms_controls WHERE risk_id = ?`, risk.ID); err != nil {
+		return err
+	}
+	for _, id := range risk.AssetIDs {
+		if _, err := tx.Exec(`INSERT INTO risk_assets (risk_id, asset_id) VALUES (?, ?)`, risk.ID, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range risk.ISMSControls {
+		if _, err := tx.Exec(`INSERT INTO risk_isms_controls (risk_id, control_id) VALUES (?, ?)`, risk.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1243 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ErrPendingMigrations is returned by CheckMigrations when the database is
+// behind the migrations compiled into this binary.
+var ErrPendingMigrations = errors.New("database has pending migrations")
+
+// ErrUnknownMigrations is returned by CheckMigrations when the database has
+// been migrated by a newer build than this one.
+var ErrUnknownMigrations = errors.New("database schema is newer than this build")
+
+// ErrBaselineRevert is returned by MigrateDown when it would revert the
+// baseline migration, which drops the core tables with all their records,
+// without being forced to.
+var ErrBaselineRevert = errors.New("reverting the baseline migration drops all records")
+
+// migration is a numbered, reversible schema change. Versions must be
+// strictly increasing; never edit or renumber a migration once released,
+// add a new one instead.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+	down    func(tx *sql.Tx) error
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create core IMS tables",
+		// IF NOT EXISTS lets databases created before migrations existed
+		// adopt this baseline without losing data.
+		up: execAll(
+			`CREATE TABLE IF NOT EXISTS risks (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title TEXT NOT NULL,
+				process TEXT NOT NULL,
+				domain TEXT NOT NULL,
+				description TEXT,
+				likelihood INTEGER NOT NULL,
+				impact INTEGER NOT NULL,
+				score INTEGER NOT NULL,
+				level TEXT NOT NULL,
+				owner TEXT,
+				status TEXT NOT NULL,
+				created_at TEXT NOT NULL
+			);`,
+			`CREATE TABLE IF NOT EXISTS incidents (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title TEXT NOT NULL,
+				description TEXT NOT NULL,
+				domain TEXT NOT NULL,
+				related_risk_id INTEGER,
+				severity INTEGER NOT NULL,
+				likelihood INTEGER NOT NULL,
+				risk_score INTEGER NOT NULL,
+				risk_level TEXT NOT NULL,
+				root_cause TEXT,
+				status TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			);`,
+			`CREATE TABLE IF NOT EXISTS audits (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title TEXT NOT NULL,
+				scope TEXT NOT NULL,
+				domain TEXT NOT NULL,
+				planned_date TEXT NOT NULL,
+				auditor TEXT,
+				status TEXT NOT NULL,
+				findings TEXT,
+				created_at TEXT NOT NULL
+			);`,
+			`CREATE TABLE IF NOT EXISTS actions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title TEXT NOT NULL,
+				description TEXT,
+				source_type TEXT NOT NULL,
+				source_id INTEGER NOT NULL,
+				owner TEXT,
+				due_date TEXT,
+				status TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			);`,
+		),
+		down: execAll(
+			`DROP TABLE actions;`,
+			`DROP TABLE audits;`,
+			`DROP TABLE incidents;`,
+			`DROP TABLE risks;`,
+		),
+	},
+	{
+		version: 2,
+		name:    "soft delete columns",
+		up: func(tx *sql.Tx) error {
+			// Columns may already exist on databases upgraded by the
+			// pre-migration schema bootstrap.
+			for _, table := range []string{"risks", "incidents", "audits", "actions"} {
+				if err := addColumnIfMissing(tx, table, "deleted_at", "TEXT"); err != nil {
+					return err
+				}
+				if err := addColumnIfMissing(tx, table, "deleted_by", "TEXT"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		down: execAll(
+			`ALTER TABLE risks DROP COLUMN deleted_at;`,
+			`ALTER TABLE risks DROP COLUMN deleted_by;`,
+			`ALTER TABLE incidents DROP COLUMN deleted_at;`,
+			`ALTER TABLE incidents DROP COLUMN deleted_by;`,
+			`ALTER TABLE audits DROP COLUMN deleted_at;`,
+			`ALTER TABLE audits DROP COLUMN deleted_by;`,
+			`ALTER TABLE actions DROP COLUMN deleted_at;`,
+			`ALTER TABLE actions DROP COLUMN deleted_by;`,
+		),
+	},
+	{
+		version: 3,
+		name:    "list filter indexes",
+		// Case-insensitive filters compare with COLLATE NOCASE, so the
+		// matching indexes use the same collation.
+		up: execAll(
+			`CREATE INDEX idx_risks_domain ON risks (domain);`,
+			`CREATE INDEX idx_risks_status ON risks (status COLLATE NOCASE);`,
+			`CREATE INDEX idx_risks_owner ON risks (owner COLLATE NOCASE);`,
+			`CREATE INDEX idx_risks_level ON risks (level COLLATE NOCASE);`,
+			`CREATE INDEX idx_risks_created_at ON risks (created_at);`,
+			`CREATE INDEX idx_incidents_domain ON incidents (domain);`,
+			`CREATE INDEX idx_incidents_status ON incidents (status COLLATE NOCASE);`,
+			`CREATE INDEX idx_incidents_risk_level ON incidents (risk_level COLLATE NOCASE);`,
+			`CREATE INDEX idx_incidents_related_risk_id ON incidents (related_risk_id);`,
+			`CREATE INDEX idx_incidents_created_at ON incidents (created_at);`,
+			`CREATE INDEX idx_audits_domain ON audits (domain);`,
+			`CREATE INDEX idx_audits_status ON audits (status COLLATE NOCASE);`,
+			`CREATE INDEX idx_audits_auditor ON audits (auditor COLLATE NOCASE);`,
+			`CREATE INDEX idx_audits_planned_date ON audits (planned_date);`,
+			`CREATE INDEX idx_actions_source ON actions (source_type COLLATE NOCASE, source_id);`,
+			`CREATE INDEX idx_actions_status ON actions (status COLLATE NOCASE);`,
+			`CREATE INDEX idx_actions_owner ON actions (owner COLLATE NOCASE);`,
+			`CREATE INDEX idx_actions_due_date ON actions (due_date);`,
+		),
+		down: execAll(
+			`DROP INDEX idx_risks_domain;`,
+			`DROP INDEX idx_risks_status;`,
+			`DROP INDEX idx_risks_owner;`,
+			`DROP INDEX idx_risks_level;`,
+			`DROP INDEX idx_risks_created_at;`,
+			`DROP INDEX idx_incidents_domain;`,
+			`DROP INDEX idx_incidents_status;`,
+			`DROP INDEX idx_incidents_risk_level;`,
+			`DROP INDEX idx_incidents_related_risk_id;`,
+			`DROP INDEX idx_incidents_created_at;`,
+			`DROP INDEX idx_audits_domain;`,
+			`DROP INDEX idx_audits_status;`,
+			`DROP INDEX idx_audits_auditor;`,
+			`DROP INDEX idx_audits_planned_date;`,
+			`DROP INDEX idx_actions_source;`,
+			`DROP INDEX idx_actions_status;`,
+			`DROP INDEX idx_actions_owner;`,
+			`DROP INDEX idx_actions_due_date;`,
+		),
+	},
+	{
+		version: 4,
+		name:    "users, api tokens and record stamps",
+		up: execAll(
+			`CREATE TABLE users (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				display_name TEXT NOT NULL DEFAULT '',
+				password_hash TEXT NOT NULL DEFAULT '',
+				active INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT NOT NULL
+			);`,
+			`CREATE TABLE api_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id INTEGER NOT NULL REFERENCES users (id),
+				name TEXT NOT NULL,
+				kind TEXT NOT NULL,
+				token_hash TEXT NOT NULL UNIQUE,
+				created_at TEXT NOT NULL,
+				expires_at TEXT,
+				last_used_at TEXT
+			);`,
+			`CREATE INDEX idx_api_tokens_user_id ON api_tokens (user_id);`,
+			`ALTER TABLE risks ADD COLUMN created_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE risks ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN created_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE audits ADD COLUMN created_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE audits ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE actions ADD COLUMN created_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE actions ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';`,
+		),
+		down: execAll(
+			`ALTER TABLE actions DROP COLUMN updated_by;`,
+			`ALTER TABLE actions DROP COLUMN created_by;`,
+			`ALTER TABLE audits DROP COLUMN updated_by;`,
+			`ALTER TABLE audits DROP COLUMN created_by;`,
+			`ALTER TABLE incidents DROP COLUMN updated_by;`,
+			`ALTER TABLE incidents DROP COLUMN created_by;`,
+			`ALTER TABLE risks DROP COLUMN updated_by;`,
+			`ALTER TABLE risks DROP COLUMN created_by;`,
+			`DROP TABLE api_tokens;`,
+			`DROP TABLE users;`,
+		),
+	},
+	{
+		version: 5,
+		name:    "user roles",
+		up: execAll(
+			`CREATE TABLE user_roles (
+				user_id INTEGER NOT NULL REFERENCES users (id),
+				role TEXT NOT NULL,
+				domain TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (user_id, role, domain)
+			);`,
+			// Accounts created before roles existed could do everything.
+			`INSERT INTO user_roles (user_id, role, domain) SELECT id, 'ims_manager', '' FROM users;`,
+		),
+		down: execAll(
+			`DROP TABLE user_roles;`,
+		),
+	},
+	{
+		version: 6,
+		name:    "record change history",
+		up: execAll(
+			`CREATE TABLE record_history (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				record_type TEXT NOT NULL,
+				record_id INTEGER NOT NULL,
+				action TEXT NOT NULL,
+				actor TEXT NOT NULL DEFAULT '',
+				changed_at TEXT NOT NULL,
+				changes TEXT NOT NULL
+			);`,
+			`CREATE INDEX idx_record_history_record ON record_history (record_type, record_id);`,
+			// Reject edits to history rows at the database level.
+			historyNoUpdateTrigger,
+			historyNoDeleteTrigger,
+		),
+		down: execAll(
+			`DROP TABLE record_history;`,
+		),
+	},
+	{
+		version: 7,
+		name:    "history hash chain",
+		up: func(tx *sql.Tx) error {
+			if err := execAll(
+				`DROP TRIGGER record_history_no_update;`,
+				`DROP TRIGGER record_history_no_delete;`,
+				`ALTER TABLE record_history ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';`,
+				`ALTER TABLE record_history ADD COLUMN hash TEXT NOT NULL DEFAULT '';`,
+			)(tx); err != nil {
+				return err
+			}
+			if err := backfillHistoryHashes(tx); err != nil {
+				return err
+			}
+			return execAll(historyNoUpdateTrigger, historyNoDeleteTrigger)(tx)
+		},
+		down: execAll(
+			`DROP TRIGGER record_history_no_update;`,
+			`DROP TRIGGER record_history_no_delete;`,
+			`ALTER TABLE record_history DROP COLUMN hash;`,
+			`ALTER TABLE record_history DROP COLUMN prev_hash;`,
+			historyNoUpdateTrigger,
+			historyNoDeleteTrigger,
+		),
+	},
+	{
+		version: 8,
+		name:    "notification outbox",
+		up: execAll(
+			`ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '';`,
+			`CREATE TABLE notification_outbox (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				channel TEXT NOT NULL,
+				recipient TEXT NOT NULL,
+				subject TEXT NOT NULL,
+				body TEXT NOT NULL,
+				event_type TEXT NOT NULL,
+				record_type TEXT NOT NULL,
+				record_id INTEGER NOT NULL,
+				status TEXT NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at TEXT NOT NULL,
+				last_error TEXT NOT NULL DEFAULT '',
+				created_at TEXT NOT NULL,
+				sent_at TEXT
+			);`,
+			`CREATE INDEX idx_notification_outbox_due ON notification_outbox (status, next_attempt_at);`,
+		),
+		down: execAll(
+			`DROP TABLE notification_outbox;`,
+			`ALTER TABLE users DROP COLUMN email;`,
+		),
+	},
+	{
+		version: 9,
+		name:    "webhook subscriptions",
+		up: execAll(
+			`CREATE TABLE webhooks (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				url TEXT NOT NULL,
+				events TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				secret TEXT NOT NULL,
+				active INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT NOT NULL,
+				created_by TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE TABLE webhook_deliveries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				webhook_id INTEGER NOT NULL REFERENCES webhooks (id),
+				event_id TEXT NOT NULL,
+				event_type TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				status TEXT NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				delivered_at TEXT
+			);`,
+			`CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);`,
+			`CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id);`,
+			`CREATE TABLE webhook_attempts (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				delivery_id INTEGER NOT NULL REFERENCES webhook_deliveries (id),
+				attempt INTEGER NOT NULL,
+				attempted_at TEXT NOT NULL,
+				status_code INTEGER NOT NULL,
+				duration_ms INTEGER NOT NULL,
+				error TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE INDEX idx_webhook_attempts_delivery ON webhook_attempts (delivery_id);`,
+		),
+		down: execAll(
+			`DROP TABLE webhook_attempts;`,
+			`DROP TABLE webhook_deliveries;`,
+			`DROP TABLE webhooks;`,
+		),
+	},
+	{
+		version: 10,
+		name:    "risk matrix",
+		up: execAll(
+			// A single row; until it exists the built-in 5x5 matrix applies.
+			`CREATE TABLE risk_matrix (
+				id INTEGER PRIMARY KEY CHECK (id = 1),
+				config TEXT NOT NULL,
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+		),
+		down: execAll(
+			`DROP TABLE risk_matrix;`,
+		),
+	},
+	{
+		version: 11,
+		name:    "residual risk and controls",
+		up: execAll(
+			`ALTER TABLE risks ADD COLUMN residual_likelihood INTEGER NOT NULL DEFAULT 0;`,
+			`ALTER TABLE risks ADD COLUMN residual_impact INTEGER NOT NULL DEFAULT 0;`,
+			`ALTER TABLE risks ADD COLUMN residual_score INTEGER NOT NULL DEFAULT 0;`,
+			`ALTER TABLE risks ADD COLUMN residual_level TEXT NOT NULL DEFAULT '';`,
+			// Without recorded controls the residual risk is the inherent risk.
+			`UPDATE risks SET residual_likelihood = likelihood, residual_impact = impact,
+				residual_score = score, residual_level = level;`,
+			`CREATE TABLE risk_controls (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				risk_id INTEGER NOT NULL REFERENCES risks (id),
+				position INTEGER NOT NULL,
+				name TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				control_type TEXT NOT NULL DEFAULT '',
+				effectiveness TEXT NOT NULL
+			);`,
+			`CREATE INDEX idx_risk_controls_risk ON risk_controls (risk_id, position);`,
+		),
+		down: execAll(
+			`DROP TABLE risk_controls;`,
+			`ALTER TABLE risks DROP COLUMN residual_level;`,
+			`ALTER TABLE risks DROP COLUMN residual_score;`,
+			`ALTER TABLE risks DROP COLUMN residual_impact;`,
+			`ALTER TABLE risks DROP COLUMN residual_likelihood;`,
+		),
+	},
+	{
+		version: 12,
+		name:    "risk treatment plans",
+		up: execAll(
+			`ALTER TABLE risks ADD COLUMN treatment_option TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE risks ADD COLUMN treatment_justification TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE risks ADD COLUMN treatment_approved_by TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE risks ADD COLUMN treatment_approved_at TEXT NOT NULL DEFAULT '';`,
+		),
+		down: execAll(
+			`ALTER TABLE risks DROP COLUMN treatment_approved_at;`,
+			`ALTER TABLE risks DROP COLUMN treatment_approved_by;`,
+			`ALTER TABLE risks DROP COLUMN treatment_justification;`,
+			`ALTER TABLE risks DROP COLUMN treatment_option;`,
+		),
+	},
+	{
+		version: 13,
+		name:    "risk review cycles",
+		up: func(tx *sql.Tx) error {
+			if err := execAll(
+				`ALTER TABLE risks ADD COLUMN last_reviewed_at TEXT NOT NULL DEFAULT '';`,
+				`ALTER TABLE risks ADD COLUMN next_review_at TEXT NOT NULL DEFAULT '';`,
+				`CREATE INDEX idx_risks_next_review ON risks (next_review_at);`,
+				`CREATE TABLE risk_reviews (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					risk_id INTEGER NOT NULL REFERENCES risks (id),
+					reviewer TEXT NOT NULL,
+					reviewed_at TEXT NOT NULL,
+					comments TEXT NOT NULL DEFAULT '',
+					rescored INTEGER NOT NULL DEFAULT 0,
+					previous_score INTEGER NOT NULL,
+					previous_level TEXT NOT NULL,
+					likelihood INTEGER NOT NULL,
+					impact INTEGER NOT NULL,
+					score INTEGER NOT NULL,
+					level TEXT NOT NULL,
+					residual_likelihood INTEGER NOT NULL,
+					residual_impact INTEGER NOT NULL,
+					residual_score INTEGER NOT NULL,
+					residual_level TEXT NOT NULL,
+					next_review_at TEXT NOT NULL DEFAULT ''
+				);`,
+				`CREATE INDEX idx_risk_reviews_risk ON risk_reviews (risk_id);`,
+			)(tx); err != nil {
+				return err
+			}
+			return backfillReviewSchedule(tx)
+		},
+		down: execAll(
+			`DROP TABLE risk_reviews;`,
+			`DROP INDEX idx_risks_next_review;`,
+			`ALTER TABLE risks DROP COLUMN next_review_at;`,
+			`ALTER TABLE risks DROP COLUMN last_reviewed_at;`,
+		),
+	},
+	{
+		version: 14,
+		name:    "risk score history",
+		up: func(tx *sql.Tx) error {
+			if err := execAll(
+				`CREATE TABLE risk_scores (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					risk_id INTEGER NOT NULL REFERENCES risks (id),
+					recorded_at TEXT NOT NULL,
+					changed_by TEXT NOT NULL DEFAULT '',
+					likelihood INTEGER NOT NULL,
+					impact INTEGER NOT NULL,
+					score INTEGER NOT NULL,
+					level TEXT NOT NULL,
+					residual_likelihood INTEGER NOT NULL,
+					residual_impact INTEGER NOT NULL,
+					residual_score INTEGER NOT NULL,
+					residual_level TEXT NOT NULL
+				);`,
+				`CREATE INDEX idx_risk_scores_risk ON risk_scores (risk_id, recorded_at);`,
+			)(tx); err != nil {
+				return err
+			}
+			return backfillRiskScores(tx)
+		},
+		down: execAll(
+			`DROP TABLE risk_scores;`,
+		),
+	},
+	{
+		version: 15,
+		name:    "process register",
+		up: func(tx *sql.Tx) error {
+			if err := execAll(
+				`CREATE TABLE processes (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+					description TEXT NOT NULL DEFAULT '',
+					owner TEXT NOT NULL DEFAULT '',
+					parent_id INTEGER REFERENCES processes (id),
+					domains TEXT NOT NULL DEFAULT '[]',
+					kpis TEXT NOT NULL DEFAULT '[]',
+					created_at TEXT NOT NULL,
+					created_by TEXT NOT NULL DEFAULT '',
+					updated_at TEXT NOT NULL,
+					updated_by TEXT NOT NULL DEFAULT ''
+				);`,
+				`CREATE INDEX idx_processes_parent ON processes (parent_id);`,
+				`ALTER TABLE risks ADD COLUMN process_id INTEGER REFERENCES processes (id);`,
+				`ALTER TABLE audits ADD COLUMN process_id INTEGER REFERENCES processes (id);`,
+			)(tx); err != nil {
+				return err
+			}
+			if err := backfillProcesses(tx); err != nil {
+				return err
+			}
+			return execAll(
+				`ALTER TABLE risks DROP COLUMN process;`,
+				`CREATE INDEX idx_risks_process ON risks (process_id);`,
+				`CREATE INDEX idx_audits_process ON audits (process_id);`,
+			)(tx)
+		},
+		down: execAll(
+			`ALTER TABLE risks ADD COLUMN process TEXT NOT NULL DEFAULT '';`,
+			`UPDATE risks SET process = COALESCE((SELECT name FROM processes WHERE processes.id = risks.process_id), '');`,
+			`DROP INDEX idx_audits_process;`,
+			`DROP INDEX idx_risks_process;`,
+			`ALTER TABLE audits DROP COLUMN process_id;`,
+			`ALTER TABLE risks DROP COLUMN process_id;`,
+			`DROP TABLE processes;`,
+		),
+	},
+	{
+		version: 16,
+		name:    "incident investigations",
+		up: execAll(
+			`CREATE TABLE incident_investigations (
+				incident_id INTEGER PRIMARY KEY REFERENCES incidents (id),
+				problem TEXT NOT NULL DEFAULT '',
+				whys TEXT NOT NULL DEFAULT '[]',
+				fishbone TEXT NOT NULL DEFAULT '[]',
+				root_cause TEXT NOT NULL DEFAULT '',
+				created_at TEXT NOT NULL,
+				created_by TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+		),
+		down: execAll(
+			`DROP TABLE incident_investigations;`,
+		),
+	},
+	{
+		version: 17,
+		name:    "attachments",
+		up: execAll(
+			`CREATE TABLE attachments (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				record_type TEXT NOT NULL,
+				record_id INTEGER NOT NULL,
+				file_name TEXT NOT NULL,
+				content_type TEXT NOT NULL,
+				size INTEGER NOT NULL,
+				sha256 TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				uploaded_at TEXT NOT NULL,
+				uploaded_by TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE INDEX idx_attachments_record ON attachments (record_type, record_id);`,
+			`CREATE INDEX idx_attachments_sha256 ON attachments (sha256);`,
+		),
+		down: execAll(
+			`DROP TABLE attachments;`,
+		),
+	},
+	{
+		version: 18,
+		name:    "ohs incident classification",
+		up: execAll(
+			`ALTER TABLE incidents ADD COLUMN occurred_on TEXT NOT NULL DEFAULT '';`,
+			`UPDATE incidents SET occurred_on = substr(created_at, 1, 10);`,
+			`ALTER TABLE incidents ADD COLUMN incident_type TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN injured_name TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN injured_job_title TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN injured_employment TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN body_part TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN injury_nature TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN days_lost INTEGER NOT NULL DEFAULT 0;`,
+			`ALTER TABLE incidents ADD COLUMN reportable INTEGER NOT NULL DEFAULT 0;`,
+			`ALTER TABLE incidents ADD COLUMN reporting_deadline TEXT NOT NULL DEFAULT '';`,
+			`ALTER TABLE incidents ADD COLUMN reported_on TEXT NOT NULL DEFAULT '';`,
+			`CREATE INDEX idx_incidents_type ON incidents (incident_type);`,
+			`CREATE INDEX idx_incidents_occurred ON incidents (occurred_on);`,
+			`CREATE TABLE hours_worked (
+				month TEXT PRIMARY KEY,
+				hours REAL NOT NULL,
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+		),
+		down: execAll(
+			`DROP TABLE hours_worked;`,
+			`DROP INDEX idx_incidents_occurred;`,
+			`DROP INDEX idx_incidents_type;`,
+			`ALTER TABLE incidents DROP COLUMN reported_on;`,
+			`ALTER TABLE incidents DROP COLUMN reporting_deadline;`,
+			`ALTER TABLE incidents DROP COLUMN reportable;`,
+			`ALTER TABLE incidents DROP COLUMN days_lost;`,
+			`ALTER TABLE incidents DROP COLUMN injury_nature;`,
+			`ALTER TABLE incidents DROP COLUMN body_part;`,
+			`ALTER TABLE incidents DROP COLUMN injured_employment;`,
+			`ALTER TABLE incidents DROP COLUMN injured_job_title;`,
+			`ALTER TABLE incidents DROP COLUMN injured_name;`,
+			`ALTER TABLE incidents DROP COLUMN incident_type;`,
+			`ALTER TABLE incidents DROP COLUMN occurred_on;`,
+		),
+	},
+	{
+		version: 19,
+		name:    "environmental aspects",
+		up: execAll(
+			`CREATE TABLE environmental_aspects (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				process_id INTEGER REFERENCES processes (id),
+				activity TEXT NOT NULL,
+				aspect TEXT NOT NULL,
+				impact TEXT NOT NULL,
+				condition TEXT NOT NULL,
+				severity INTEGER NOT NULL,
+				frequency INTEGER NOT NULL,
+				score INTEGER NOT NULL,
+				significant INTEGER NOT NULL DEFAULT 0,
+				legal_requirements TEXT NOT NULL DEFAULT '[]',
+				controls TEXT NOT NULL DEFAULT '[]',
+				owner TEXT NOT NULL DEFAULT '',
+				created_at TEXT NOT NULL,
+				created_by TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE INDEX idx_environmental_aspects_process ON environmental_aspects (process_id);`,
+			`ALTER TABLE incidents ADD COLUMN aspect_id INTEGER REFERENCES environmental_aspects (id);`,
+			`CREATE INDEX idx_incidents_aspect ON incidents (aspect_id);`,
+		),
+		down: execAll(
+			`DROP INDEX idx_incidents_aspect;`,
+			`ALTER TABLE incidents DROP COLUMN aspect_id;`,
+			`DROP TABLE environmental_aspects;`,
+		),
+	},
+	{
+		version: 20,
+		name:    "isms assets and controls",
+		up: execAll(
+			`CREATE TABLE information_assets (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				asset_type TEXT NOT NULL,
+				owner TEXT NOT NULL DEFAULT '',
+				location TEXT NOT NULL DEFAULT '',
+				classification TEXT NOT NULL,
+				confidentiality INTEGER NOT NULL,
+				integrity INTEGER NOT NULL,
+				availability INTEGER NOT NULL,
+				criticality INTEGER NOT NULL,
+				created_at TEXT NOT NULL,
+				created_by TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE TABLE isms_controls (
+				id TEXT PRIMARY KEY,
+				sort_key TEXT NOT NULL,
+				theme TEXT NOT NULL,
+				title TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				annex_a INTEGER NOT NULL DEFAULT 0,
+				applicable INTEGER NOT NULL DEFAULT 1,
+				justification TEXT NOT NULL DEFAULT '',
+				implementation_status TEXT NOT NULL,
+				owner TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL,
+				updated_by TEXT NOT NULL DEFAULT ''
+			);`,
+			`CREATE INDEX idx_isms_controls_sort ON isms_controls (sort_key);`,
+			`CREATE TABLE risk_assets (
+				risk_id INTEGER NOT NULL REFERENCES risks (id),
+				asset_id INTEGER NOT NULL REFERENCES information_assets (id),
+				PRIMARY KEY (risk_id, asset_id)
+			);`,
+			`CREATE INDEX idx_risk_assets_asset ON risk_assets (asset_id);`,
+			`CREATE TABLE risk_isms_controls (
+				risk_id INTEGER NOT NULL REFERENCES risks (id),
+				control_id TEXT NOT NULL REFERENCES isms_controls (id),
+				PRIMARY KEY (risk_id, control_id)
+			);`,
+			`CREATE INDEX idx_risk_isms_controls_control ON risk_isms_controls (control_id);`,
+		),
+		down: execAll(
+			`DROP TABLE risk_isms_controls;`,
+			`DROP TABLE risk_assets;`,
+			`DROP TABLE isms_controls;`,
+			`DROP TABLE information_assets;`,
+		),
+	},
+	{
+		version: 21,
+		name:    "notification dedup keys",
+		up: execAll(
+			`ALTER TABLE notification_outbox ADD COLUMN dedup_key TEXT NOT NULL DEFAULT '';`,
+			`CREATE UNIQUE INDEX idx_notification_outbox_dedup ON notification_outbox (dedup_key, channel, recipient)
+				WHERE dedup_key <> '';`,
+		),
+		down: execAll(
+			`DROP INDEX idx_notification_outbox_dedup;`,
+			`ALTER TABLE notification_outbox DROP COLUMN dedup_key;`,
+		),
+	},
+	{
+		version: 22,
+		name:    "event outbox",
+		up: execAll(
+			`CREATE TABLE event_outbox (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				event_id TEXT NOT NULL UNIQUE,
+				record_type TEXT NOT NULL,
+				event TEXT NOT NULL
+			);`,
+			`CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id);`,
+		),
+		down: execAll(
+			`DROP INDEX idx_webhook_deliveries_event;`,
+			`DROP TABLE event_outbox;`,
+		),
+	},
+}
+
+const (
+	historyNoUpdateTrigger = `CREATE TRIGGER record_history_no_update BEFORE UPDATE ON record_history
+		BEGIN SELECT RAISE(ABORT, 'record history is append-only'); END;`
+	historyNoDeleteTrigger = `CREATE TRIGGER record_history_no_delete BEFORE DELETE ON record_history
+		BEGIN SELECT RAISE(ABORT, 'record history is append-only'); END;`
+)
+
+// backfillHistoryHashes chains the history entries written before hashes
+// existed, in id order.
+func backfillHistoryHashes(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT ` + historyColumns + ` FROM record_history ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	var entries []*historyRow
+	for rows.Next() {
+		h, err := scanHistoryRow(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, h)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	prev := ""
+	for _, h := range entries {
+		h.prevHash = prev
+		h.hash = h.computeHash()
+		if _, err := tx.Exec(`UPDATE record_history SET prev_hash = ?, hash = ? WHERE id = ?`, h.prevHash, h.hash, h.id); err != nil {
+			return err
+		}
+		prev = h.hash
+	}
+	return nil
+}
+
+// backfillReviewSchedule gives the bands of a saved risk matrix the default
+// review frequency of the level with the same name (escalating levels fall
+// back to High's, others to Low's) and schedules the first review of every
+// risk from its creation date.
+func backfillReviewSchedule(tx *sql.Tx) error {
+	m, err := getRiskMatrix(tx)
+	if err != nil {
+		return err
+	}
+	if m.UpdatedAt != "" {
+		defaults := domain.DefaultRiskMatrix()
+		for i := range m.Bands {
+			b := &m.Bands[i]
+			b.ReviewDays = defaults.Bands[0].ReviewDays
+			if b.Escalate {
+				b.ReviewDays = defaults.Bands[len(defaults.Bands)-1].ReviewDays
+			}
+			for _, d := range defaults.Bands {
+				if strings.EqualFold(d.Level, b.Level) {
+					b.ReviewDays = d.ReviewDays
+				}
+			}
+		}
+		if err := saveRiskMatrix(tx, m); err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.Query(`SELECT id, level, created_at FROM risks`)
+	if err != nil {
+		return err
+	}
+	next := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var level, createdAt string
+		if err := rows.Scan(&id, &level, &createdAt); err != nil {
+			rows.Close()
+			return err
+		}
+		created, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			created = time.Now()
+		}
+		next[id] = m.NextReview(level, created)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for id, at := range next {
+		if _, err := tx.Exec(`UPDATE risks SET next_review_at = ? WHERE id = ?`, at, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillRiskScores rebuilds the score history of existing risks by
+// replaying their change history. Risks whose current scores the history
+// doesn't account for (changed before history was kept) get a closing point
+// at migration time; risks without history get one at their creation.
+func backfillRiskScores(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT `+historyColumns+` FROM record_history WHERE record_type = ? ORDER BY id`, domain.KindRisk)
+	if err != nil {
+		return err
+	}
+	var entries []*historyRow
+	for rows.Next() {
+		h, err := scanHistoryRow(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, h)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	var points []*domain.RiskScore
+	state := make(map[int]*domain.RiskScore)
+	last := make(map[int]*domain.RiskScore)
+	for _, h := range entries {
+		e, err := h.entry()
+		if err != nil {
+			return err
+		}
+		s := state[h.recordID]
+		if s == nil {
+			s = &domain.RiskScore{RiskID: h.recordID}
+			state[h.recordID] = s
+		}
+		changed := false
+		for _, c := range e.Changes {
+			if applyScoreChange(s, c) {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		p := *s
+		p.RecordedAt, p.ChangedBy = h.changedAt, h.actor
+		if p.ResidualScore == 0 {
+			// Rated before residual risk was tracked
+			p.ResidualLikelihood, p.ResidualImpact, p.ResidualScore, p.ResidualLevel = p.Likelihood, p.Impact, p.Score, p.Level
+		}
+		points = append(points, &p)
+		last[h.recordID] = &p
+	}
+
+	// The columns as of this migration; later ones change the risks table.
+	rows, err = tx.Query(`
+		SELECT id, created_at, created_by, updated_by, likelihood, impact, score, level,
+			residual_likelihood, residual_impact, residual_score, residual_level
+		FROM risks ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	var current []*domain.RiskScore
+	updatedBy := make(map[int]string)
+	for rows.Next() {
+		p := &domain.RiskScore{}
+		var by string
+		if err := rows.Scan(&p.RiskID, &p.RecordedAt, &p.ChangedBy, &by, &p.Likelihood, &p.Impact, &p.Score, &p.Level,
+			&p.ResidualLikelihood, &p.ResidualImpact, &p.ResidualScore, &p.ResidualLevel); err != nil {
+			rows.Close()
+			return err
+		}
+		current = append(current, p)
+		updatedBy[p.RiskID] = by
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	now := time.Now().Format(time.RFC3339)
+	for _, p := range current {
+		if l := last[p.RiskID]; l != nil {
+			if p.RecordedAt, p.ChangedBy = l.RecordedAt, l.ChangedBy; *p == *l {
+				continue
+			}
+			p.RecordedAt, p.ChangedBy = now, updatedBy[p.RiskID]
+		}
+		points = append(points, p)
+	}
+
+	for _, p := range points {
+		if err := insertScore(tx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyScoreChange applies a history field change to s, reporting whether
+// it was a change of a rating, score or level.
+func applyScoreChange(s *domain.RiskScore, c domain.FieldChange) bool {
+	n, _ := c.After.(float64) // JSON numbers
+	str, _ := c.After.(string)
+	switch c.Field {
+	case "likelihood":
+		s.Likelihood = int(n)
+	case "impact":
+		s.Impact = int(n)
+	case "score":
+		s.Score = int(n)
+	case "level":
+		s.Level = str
+	case "residualLikelihood":
+		s.ResidualLikelihood = int(n)
+	case "residualImpact":
+		s.ResidualImpact = int(n)
+	case "residualScore":
+		s.ResidualScore = int(n)
+	case "residualLevel":
+		s.ResidualLevel = str
+	default:
+		return false
+	}
+	return true
+}
+
+// backfillProcesses registers a process for every distinct free-text risk
+// process, ignoring letter case and surrounding spaces, and links the risks
+// to it. A process takes the spelling and the domains of its risks, oldest
+// first.
+func backfillProcesses(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, process, domain FROM risks ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	type entry struct {
+		process *domain.Process
+		risks   []int
+	}
+	var entries []*entry
+	byKey := make(map[string]*entry)
+	for rows.Next() {
+		var id int
+		var name, dom string
+		if err := rows.Scan(&id, &name, &dom); err != nil {
+			rows.Close()
+			return err
+		}
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			name = "Unassigned"
+		}
+		key := strings.ToLower(name)
+		e := byKey[key]
+		if e == nil {
+			e = &entry{process: &domain.Process{Name: name, Domains: []domain.Domain{}}}
+			byKey[key] = e
+			entries = append(entries, e)
+		}
+		e.risks = append(e.risks, id)
+		if !slices.Contains(e.process.Domains, domain.Domain(dom)) {
+			e.process.Domains = append(e.process.Domains, domain.Domain(dom))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	now := time.Now().Format(time.RFC3339)
+	for _, e := range entries {
+		p := e.process
+		p.CreatedAt, p.CreatedBy, p.UpdatedAt, p.UpdatedBy = now, "system", now, "system"
+		domains, kpis, err := processLists(p)
+		if err != nil {
+			return err
+		}
+		res, err := tx.Exec(`
+			INSERT INTO processes (name, domains, kpis, created_at, created_by, updated_at, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			p.Name, domains, kpis, p.CreatedAt, p.CreatedBy, p.UpdatedAt, p.UpdatedBy)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for _, riskID := range e.risks {
+			if _, err := tx.Exec(`UPDATE risks SET process_id = ? WHERE id = ?`, id, riskID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MigrationStatus describes one known migration and whether it is applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt string
+}
+
+// Migrate applies all pending migrations in order.
+func Migrate(db *sql.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []MigrationStatus
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+		now := time.Now().Format(time.RFC3339)
+		err := inTx(db, func(tx *sql.Tx) error {
+			if err := m.up(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				m.version, m.name, now)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		done = append(done, MigrationStatus{Version: m.version, Name: m.name, Applied: true, AppliedAt: now})
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the most recently applied migrations, newest first.
+// Reverting the baseline migration takes force; without it nothing is
+// reverted and ErrBaselineRevert is returned.
+func MigrateDown(db *sql.DB, steps int, force bool) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var revert []migration
+	for i := len(migrations) - 1; i >= 0 && len(revert) < steps; i-- {
+		if _, ok := applied[migrations[i].version]; ok {
+			revert = append(revert, migrations[i])
+		}
+	}
+	if n := len(revert); n > 0 && revert[n-1].version == migrations[0].version && !force {
+		return nil, ErrBaselineRevert
+	}
+
+	var done []MigrationStatus
+	for _, m := range revert {
+		err := inTx(db, func(tx *sql.Tx) error {
+			if err := m.down(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("revert migration %d (%s): %w", m.version, m.name, err)
+		}
+		done = append(done, MigrationStatus{Version: m.version, Name: m.name})
+	}
+	return done, nil
+}
+
+// Migrations reports every known migration and whether it has been applied.
+func Migrations(db *sql.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		at, ok := applied[m.version]
+		out = append(out, MigrationStatus{Version: m.version, Name: m.name, Applied: ok, AppliedAt: at})
+	}
+	return out, nil
+}
+
+// CheckMigrations verifies that the database schema matches this build. It
+// returns ErrPendingMigrations when migrations still need to be applied and
+// ErrUnknownMigrations when the database was migrated by a newer build.
+func CheckMigrations(db *sql.DB) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int]bool, len(migrations))
+	pending := 0
+	for _, m := range migrations {
+		known[m.version] = true
+		if _, ok := applied[m.version]; !ok {
+			pending++
+		}
+	}
+	for v := range applied {
+		if !known[v] {
+			return fmt.Errorf("%w: version %d is not known", ErrUnknownMigrations, v)
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d not applied", ErrPendingMigrations, pending)
+	}
+	return nil
+}
+
+// appliedMigrations returns applied versions mapped to their applied_at
+// timestamp, creating the bookkeeping table on first use.
+func appliedMigrations(db *sql.DB) (map[int]string, error) {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	);`); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int]string)
+	for rows.Next() {
+		var v int
+		var at string
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		out[v] = at
+	}
+	return out, rows.Err()
+}
+
+func inTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func execAll(stmts ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, s := range stmts {
+			if _, err := tx.Exec(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func addColumnIfMissing(tx *sql.Tx, table, column, decl string) error {
+	rows, err := tx.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			dflt       sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
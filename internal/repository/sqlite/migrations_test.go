@@ -0,0 +1,183 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// schema returns the SQL of every table, index and trigger except the
+// migration bookkeeping, by name.
+func schema(t *testing.T, db *sql.DB) map[string]string {
+	t.Helper()
+	rows, err := db.Query(`SELECT type || ' ' || name, COALESCE(sql, '') FROM sqlite_master
+		WHERE name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	out := make(map[string]string)
+	for rows.Next() {
+		var name, stmt string
+		if err := rows.Scan(&name, &stmt); err != nil {
+			t.Fatal(err)
+		}
+		out[name] = stmt
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func sameSchema(t *testing.T, step string, got, want map[string]string) {
+	t.Helper()
+	for name, stmt := range want {
+		if got[name] != stmt {
+			t.Errorf("%s: %s is\n%s\nwant\n%s", step, name, got[name], stmt)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s: unexpected %s", step, name)
+		}
+	}
+}
+
+func TestMigrationVersions(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %d (%s) has version %d, want %d", i, m.name, m.version, i+1)
+		}
+		if m.name == "" || m.up == nil || m.down == nil {
+			t.Errorf("migration %d is missing its name, up or down", m.version)
+		}
+	}
+}
+
+func TestMigrateUpDownRoundTrip(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "ims.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := CheckMigrations(db); !errors.Is(err, ErrPendingMigrations) {
+		t.Fatalf("CheckMigrations on an empty database = %v, want ErrPendingMigrations", err)
+	}
+	done, err := Migrate(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(done), len(migrations))
+	}
+	if err := CheckMigrations(db); err != nil {
+		t.Fatalf("CheckMigrations after Migrate = %v", err)
+	}
+	full := schema(t, db)
+
+	// Migrating again is a no-op.
+	if done, err := Migrate(db); err != nil || len(done) != 0 {
+		t.Fatalf("second Migrate applied %d, %v", len(done), err)
+	}
+
+	// Reverting the newest migrations, one more each time, and re-applying
+	// them restores the same schema.
+	for steps := 1; steps <= len(migrations); steps++ {
+		m := migrations[len(migrations)-steps]
+		reverted, err := MigrateDown(db, steps, true)
+		if err != nil {
+			t.Fatalf("reverting to before %d (%s): %v", m.version, m.name, err)
+		}
+		if len(reverted) != steps || reverted[steps-1].Version != m.version {
+			t.Fatalf("MigrateDown(%d) reverted %+v, down to version %d", steps, reverted, m.version)
+		}
+		if err := CheckMigrations(db); !errors.Is(err, ErrPendingMigrations) {
+			t.Fatalf("CheckMigrations after reverting %d = %v, want ErrPendingMigrations", m.version, err)
+		}
+		if done, err := Migrate(db); err != nil || len(done) != steps {
+			t.Fatalf("re-applying from %d (%s): %d applied, %v", m.version, m.name, len(done), err)
+		}
+		sameSchema(t, "after reverting and re-applying from "+m.name, schema(t, db), full)
+	}
+
+	if _, err := MigrateDown(db, len(migrations), true); err != nil {
+		t.Fatal(err)
+	}
+	// Everything is reverted and nothing is left behind.
+	if left := schema(t, db); len(left) != 0 {
+		t.Errorf("schema after reverting all migrations: %v", left)
+	}
+	status, err := Migrations(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if s.Applied {
+			t.Errorf("migration %d is still applied", s.Version)
+		}
+	}
+
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	sameSchema(t, "after migrating again from scratch", schema(t, db), full)
+}
+
+func TestMigrateDownKeepsBaseline(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 2)
+	full := schema(t, db)
+
+	for _, steps := range []int{len(migrations), len(migrations) + 5} {
+		reverted, err := MigrateDown(db, steps, false)
+		if !errors.Is(err, ErrBaselineRevert) || len(reverted) != 0 {
+			t.Fatalf("MigrateDown(%d) without force = %+v, %v; want ErrBaselineRevert", steps, reverted, err)
+		}
+	}
+	// Nothing was reverted, not even the migrations after the baseline.
+	if err := CheckMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	sameSchema(t, "after a refused revert", schema(t, db), full)
+	if _, err := NewIncidentRepository(db).GetByID(1); err != nil {
+		t.Fatalf("incident after a refused revert: %v", err)
+	}
+
+	// Down to the baseline needs no force.
+	reverted, err := MigrateDown(db, len(migrations)-1, false)
+	if err != nil || len(reverted) != len(migrations)-1 {
+		t.Fatalf("MigrateDown to the baseline = %d reverted, %v", len(reverted), err)
+	}
+	if _, err := MigrateDown(db, 1, false); !errors.Is(err, ErrBaselineRevert) {
+		t.Fatalf("reverting the baseline without force = %v, want ErrBaselineRevert", err)
+	}
+}
+
+func TestMigrateDownWithData(t *testing.T) {
+	db := openTestDB(t)
+	seedHistory(t, db, 4)
+
+	if _, err := MigrateDown(db, len(migrations), true); err != nil {
+		t.Fatalf("reverting a database with records: %v", err)
+	}
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckMigrationsUnknownVersion(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'from a newer build', '')`,
+		len(migrations)+1); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckMigrations(db); !errors.Is(err, ErrUnknownMigrations) {
+		t.Fatalf("CheckMigrations = %v, want ErrUnknownMigrations", err)
+	}
+}
@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Notification outbox ----------
+
+const notificationColumns = `id, channel, recipient, subject, body, event_type, record_type, record_id,
+	status, attempts, next_attempt_at, last_error, created_at, sent_at, dedup_key`
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Enqueue(n *domain.Notification) error {
+	if n.Status == "" {
+		n.Status = domain.NotificationPending
+	}
+	if n.NextAttemptAt == "" {
+		n.NextAttemptAt = n.CreatedAt
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO notification_outbox (channel, recipient, subject, body, event_type, record_type, record_id,
+			status, attempts, next_attempt_at, last_error, created_at, dedup_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (dedup_key, channel, recipient) WHERE dedup_key <> '' DO NOTHING`,
+		n.Channel, n.Recipient, n.Subject, n.Body, n.EventType, n.RecordType, n.RecordID,
+		n.Status, n.Attempts, n.NextAttemptAt, n.LastError, n.CreatedAt, n.DedupKey,
+	)
+	if err != nil {
+		return err
+	}
+	if added, err := res.RowsAffected(); err != nil || added == 0 {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		n.ID = int(id)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) Due(now string, limit int) ([]*domain.Notification, error) {
+	return r.query(`SELECT `+notificationColumns+` FROM notification_outbox
+		WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		domain.NotificationPending, now, limit)
+}
+
+func (r *NotificationRepository) MarkSent(id int, sentAt string) error {
+	return r.exec(`UPDATE notification_outbox
+		SET status = ?, attempts = attempts + 1, last_error = '', sent_at = ? WHERE id = ?`,
+		domain.NotificationSent, sentAt, id)
+}
+
+func (r *NotificationRepository) MarkFailed(id int, lastError, nextAttemptAt string, final bool) error {
+	status := domain.NotificationPending
+	if final {
+		status = domain.NotificationFailed
+	}
+	return r.exec(`UPDATE notification_outbox
+		SET status = ?, attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		status, lastError, nextAttemptAt, id)
+}
+
+func (r *NotificationRepository) List(status string, limit int) ([]*domain.Notification, error) {
+	w := &where{}
+	if status != "" {
+		w.add("status = ?", status)
+	}
+	args := append(w.args, limit)
+	return r.query(`SELECT `+notificationColumns+` FROM notification_outbox`+w.sql()+` ORDER BY id DESC LIMIT ?`, args...)
+}
+
+func (r *NotificationRepository) exec(query string, args ...any) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) query(query string, args ...any) ([]*domain.Notification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Notification, 0)
+	for rows.Next() {
+		n := &domain.Notification{}
+		var sentAt sql.NullString
+		if err := rows.Scan(&n.ID, &n.Channel, &n.Recipient, &n.Subject, &n.Body, &n.EventType, &n.RecordType, &n.RecordID,
+			&n.Status, &n.Attempts, &n.NextAttemptAt, &n.LastError, &n.CreatedAt, &sentAt, &n.DedupKey); err != nil {
+			return nil, err
+		}
+		n.SentAt = sentAt.String
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
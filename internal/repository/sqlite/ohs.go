@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Hours worked repository ----------
+
+type HoursWorkedRepository struct {
+	db *sql.DB
+}
+
+func NewHoursWorkedRepository(db *sql.DB) *HoursWorkedRepository {
+	return &HoursWorkedRepository{db: db}
+}
+
+func (r *HoursWorkedRepository) Set(h *domain.HoursWorked) error {
+	_, err := r.db.Exec(`
+		INSERT INTO hours_worked (month, hours, updated_at, updated_by) VALUES (?, ?, ?, ?)
+		ON CONFLICT (month) DO UPDATE SET hours = excluded.hours, updated_at = excluded.updated_at, updated_by = excluded.updated_by`,
+		h.Month, h.Hours, h.UpdatedAt, h.UpdatedBy,
+	)
+	return err
+}
+
+func (r *HoursWorkedRepository) List(from, to string) ([]*domain.HoursWorked, error) {
+	var w where
+	if from != "" {
+		w.add("month >= ?", from)
+	}
+	if to != "" {
+		w.add("month <= ?", to)
+	}
+	rows, err := r.db.Query(`SELECT month, hours, updated_at, updated_by FROM hours_worked`+w.sql()+` ORDER BY month`, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.HoursWorked, 0)
+	for rows.Next() {
+		h := &domain.HoursWorked{}
+		if err := rows.Scan(&h.Month, &h.Hours, &h.UpdatedAt, &h.UpdatedBy); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (r *HoursWorkedRepository) Delete(month string) error {
+	res, err := r.db.Exec(`DELETE FROM hours_worked WHERE month = ?`, month)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
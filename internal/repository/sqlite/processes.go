@@ -0,0 +1,207 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Process repository ----------
+
+const processColumns = `id, name, description, owner, parent_id, domains, kpis, created_at, created_by, updated_at, updated_by`
+
+// processName selects the name of a record's process in queries on risks,
+// audits and environmental aspects.
+const processName = `(SELECT name FROM processes WHERE processes.id = process_id)`
+
+var processSortColumns = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"owner":     "owner",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+type ProcessRepository struct {
+	db *sql.DB
+}
+
+func NewProcessRepository(db *sql.DB) *ProcessRepository {
+	return &ProcessRepository{db: db}
+}
+
+func (r *ProcessRepository) Create(p *domain.Process) error {
+	domains, kpis, err := processLists(p)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO processes (name, description, owner, parent_id, domains, kpis, created_at, created_by, updated_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.Name, p.Description, p.Owner, nullInt(p.ParentID), domains, kpis, p.CreatedAt, p.CreatedBy, p.UpdatedAt, p.UpdatedBy,
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		p.ID = int(id)
+	}
+	return nil
+}
+
+func (r *ProcessRepository) Update(p *domain.Process) error {
+	domains, kpis, err := processLists(p)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		UPDATE processes SET name=?, description=?, owner=?, parent_id=?, domains=?, kpis=?, updated_at=?, updated_by=?
+		WHERE id=?`,
+		p.Name, p.Description, p.Owner, nullInt(p.ParentID), domains, kpis, p.UpdatedAt, p.UpdatedBy, p.ID,
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProcessRepository) GetByID(id int) (*domain.Process, error) {
+	p, err := scanProcess(r.db.QueryRow(`SELECT `+processColumns+` FROM processes WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return p, nil
+}
+
+func (r *ProcessRepository) GetByName(name string) (*domain.Process, error) {
+	p, err := scanProcess(r.db.QueryRow(`SELECT `+processColumns+` FROM processes WHERE name = ? COLLATE NOCASE`, name))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return p, nil
+}
+
+func (r *ProcessRepository) List(q repository.ProcessQuery) ([]*domain.Process, int, error) {
+	var w where
+	if q.Domain != nil {
+		w.add("EXISTS (SELECT 1 FROM json_each(domains) WHERE value = ?)", string(*q.Domain))
+	}
+	if q.Owner != nil {
+		w.add("owner = ? COLLATE NOCASE", *q.Owner)
+	}
+	if q.ParentID != nil {
+		w.add("parent_id = ?", *q.ParentID)
+	}
+	w.addSearch(q.Search, "name", "description", "owner")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, processSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM processes`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+processColumns+` FROM processes`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Process, 0)
+	for rows.Next() {
+		p, err := scanProcess(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, p)
+	}
+	return out, total, rows.Err()
+}
+
+// Delete removes a process that no risk, audit, environmental aspect or
+// sub-process refers to. Soft-deleted risks and audits count, since they can
+// be restored.
+func (r *ProcessRepository) Delete(id int) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var risks, audits, aspects, children int
+		if err := tx.QueryRow(`
+			SELECT (SELECT COUNT(*) FROM risks WHERE process_id = ?),
+				(SELECT COUNT(*) FROM audits WHERE process_id = ?),
+				(SELECT COUNT(*) FROM environmental_aspects WHERE process_id = ?),
+				(SELECT COUNT(*) FROM processes WHERE parent_id = ?)`,
+			id, id, id, id).Scan(&risks, &audits, &aspects, &children); err != nil {
+			return err
+		}
+		if risks > 0 || audits > 0 || aspects > 0 || children > 0 {
+			return fmt.Errorf("%w: process %d has %d risk(s), %d audit(s), %d environmental aspect(s) and %d sub-process(es)",
+				repository.ErrInUse, id, risks, audits, aspects, children)
+		}
+		res, err := tx.Exec(`DELETE FROM processes WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func scanProcess(row rowScanner) (*domain.Process, error) {
+	p := &domain.Process{}
+	var parent sqlNullInt
+	var domains, kpis string
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &p.Owner, &parent, &domains, &kpis,
+		&p.CreatedAt, &p.CreatedBy, &p.UpdatedAt, &p.UpdatedBy); err != nil {
+		return nil, err
+	}
+	if parent.Valid {
+		id := parent.V
+		p.ParentID = &id
+	}
+	if err := json.Unmarshal([]byte(domains), &p.Domains); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(kpis), &p.KPIs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// processLists encodes the domains and KPIs of p for storage.
+func processLists(p *domain.Process) (domains, kpis string, err error) {
+	if p.Domains == nil {
+		p.Domains = []domain.Domain{}
+	}
+	if p.KPIs == nil {
+		p.KPIs = []domain.ProcessKPI{}
+	}
+	d, err := json.Marshal(p.Domains)
+	if err != nil {
+		return "", "", err
+	}
+	k, err := json.Marshal(p.KPIs)
+	if err != nil {
+		return "", "", err
+	}
+	return string(d), string(k), nil
+}
+
+func nullInt(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
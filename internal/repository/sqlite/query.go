@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// where accumulates AND-ed conditions and their arguments for a
+// parameterised WHERE clause.
+type where struct {
+	conds []string
+	args  []any
+}
+
+func (w *where) add(cond string, args ...any) {
+	w.conds = append(w.conds, cond)
+	w.args = append(w.args, args...)
+}
+
+func (w *where) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// addIn restricts column to one of values. An empty list matches nothing.
+func (w *where) addIn(column string, values ...any) {
+	if len(values) == 0 {
+		w.add("0")
+		return
+	}
+	w.add(column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")", values...)
+}
+
+func domainArgs(ds []domain.Domain) []any {
+	out := make([]any, len(ds))
+	for i, d := range ds {
+		out[i] = string(d)
+	}
+	return out
+}
+
+// addDateRange restricts column to the inclusive date range r. Columns hold
+// either YYYY-MM-DD dates or RFC3339 timestamps, both of which sort
+// lexicographically, so the upper bound is the start of the following day.
+func (w *where) addDateRange(column string, r repository.DateRange) error {
+	if r.From != "" {
+		if _, err := time.Parse(time.DateOnly, r.From); err != nil {
+			return fmt.Errorf("%w: invalid from date %q, expected YYYY-MM-DD", repository.ErrInvalidQuery, r.From)
+		}
+		w.add(column+" >= ?", r.From)
+	}
+	if r.To != "" {
+		to, err := time.Parse(time.DateOnly, r.To)
+		if err != nil {
+			return fmt.Errorf("%w: invalid to date %q, expected YYYY-MM-DD", repository.ErrInvalidQuery, r.To)
+		}
+		w.add(column+" < ?", to.AddDate(0, 0, 1).Format(time.DateOnly))
+	}
+	return nil
+}
+
+// addSearch adds a case-insensitive substring match of term over columns.
+func (w *where) addSearch(term string, columns ...string) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	pattern := "%" + escaped + "%"
+
+	parts := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, c := range columns {
+		parts[i] = c + ` LIKE ? ESCAPE '\'`
+		args[i] = pattern
+	}
+	w.add("("+strings.Join(parts, " OR ")+")", args...)
+}
+
+// orderAndLimit builds the ORDER BY / LIMIT / OFFSET tail for a page. Sort
+// fields are looked up in sortable (API field name -> column) so that only
+// whitelisted columns ever reach the SQL text.
+func orderAndLimit(p repository.Page, sortable map[string]string) (string, []any, error) {
+	col := "id"
+	if p.Sort != "" {
+		c, ok := sortable[p.Sort]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: cannot sort by %q", repository.ErrInvalidQuery, p.Sort)
+		}
+		col = c
+	}
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+
+	q := fmt.Sprintf(" ORDER BY %s %s", col, dir)
+	if col != "id" {
+		q += ", id " + dir
+	}
+
+	var args []any
+	if p.Limit > 0 {
+		q += " LIMIT ? OFFSET ?"
+		args = append(args, p.Limit, p.Offset)
+	} else if p.Offset > 0 {
+		q += " LIMIT -1 OFFSET ?"
+		args = append(args, p.Offset)
+	}
+	return q, args, nil
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// addIncident creates an incident from inc, filling in what it leaves out.
+func addIncident(t *testing.T, db *sql.DB, inc domain.Incident) *domain.Incident {
+	t.Helper()
+	if inc.Domain == "" {
+		inc.Domain = domain.DomainOHS
+	}
+	if inc.Status == "" {
+		inc.Status = "Open"
+	}
+	if inc.Severity == 0 {
+		inc.Severity, inc.Likelihood = 2, 2
+	}
+	if inc.OccurredOn == "" {
+		inc.OccurredOn = "2025-11-03"
+	}
+	if inc.CreatedAt == "" {
+		inc.CreatedAt = inc.OccurredOn + "T10:00:00Z"
+	}
+	inc.UpdatedAt, inc.CreatedBy, inc.UpdatedBy = inc.CreatedAt, "alice", "alice"
+	if err := NewIncidentRepository(db).Create(&inc); err != nil {
+		t.Fatal(err)
+	}
+	return &inc
+}
+
+func incidentIDs(incs []*domain.Incident) []int {
+	ids := []int{}
+	for _, inc := range incs {
+		ids = append(ids, inc.ID)
+	}
+	return ids
+}
+
+func TestOrderAndLimit(t *testing.T) {
+	sortable := map[string]string{"id": "id", "createdAt": "created_at"}
+	tests := []struct {
+		name string
+		page repository.Page
+		sql  string
+		args []any
+	}{
+		{"default", repository.Page{}, " ORDER BY id ASC", nil},
+		{"by id descending", repository.Page{Sort: "id", Desc: true}, " ORDER BY id DESC", nil},
+		{"by column with id tie-break", repository.Page{Sort: "createdAt", Desc: true}, " ORDER BY created_at DESC, id DESC", nil},
+		{"page", repository.Page{Limit: 10, Offset: 20}, " ORDER BY id ASC LIMIT ? OFFSET ?", []any{10, 20}},
+		{"offset without limit", repository.Page{Offset: 5}, " ORDER BY id ASC LIMIT -1 OFFSET ?", []any{5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, args, err := orderAndLimit(tt.page, sortable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q != tt.sql || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("orderAndLimit = %q %v, want %q %v", q, args, tt.sql, tt.args)
+			}
+		})
+	}
+
+	// Only whitelisted fields reach the SQL text.
+	for _, field := range []string{"created_at", "title", "id; DROP TABLE incidents", "CreatedAt"} {
+		if _, _, err := orderAndLimit(repository.Page{Sort: field}, sortable); !errors.Is(err, repository.ErrInvalidQuery) {
+			t.Errorf("sorting by %q = %v, want ErrInvalidQuery", field, err)
+		}
+	}
+}
+
+func TestListSortsAndPages(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewIncidentRepository(db)
+	for _, tt := range []struct {
+		title    string
+		severity int
+	}{{"Cut finger", 2}, {"Fall from ladder", 4}, {"Slip on oil", 2}, {"Burn", 3}, {"Dropped load", 5}} {
+		addIncident(t, db, domain.Incident{Title: tt.title, Severity: tt.severity, Likelihood: 1})
+	}
+
+	tests := []struct {
+		name  string
+		page  repository.Page
+		want  []int
+		total int
+	}{
+		{"all by id", repository.Page{}, []int{1, 2, 3, 4, 5}, 5},
+		{"first page", repository.Page{Limit: 2}, []int{1, 2}, 5},
+		{"last page", repository.Page{Limit: 2, Offset: 4}, []int{5}, 5},
+		{"past the end", repository.Page{Limit: 2, Offset: 10}, []int{}, 5},
+		{"offset only", repository.Page{Offset: 3}, []int{4, 5}, 5},
+		{"by title", repository.Page{Sort: "title"}, []int{4, 1, 5, 2, 3}, 5},
+		// Equal severities keep the ID order in the sort direction.
+		{"by severity descending", repository.Page{Sort: "severity", Desc: true}, []int{5, 2, 4, 3, 1}, 5},
+		{"by severity, second page", repository.Page{Sort: "severity", Limit: 2, Offset: 2}, []int{4, 2}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incs, total, err := repo.List(repository.IncidentQuery{Page: tt.page})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := incidentIDs(incs); !reflect.DeepEqual(got, tt.want) || total != tt.total {
+				t.Errorf("List = %v of %d, want %v of %d", got, total, tt.want, tt.total)
+			}
+		})
+	}
+
+	if _, _, err := repo.List(repository.IncidentQuery{Page: repository.Page{Sort: "root_cause"}}); !errors.Is(err, repository.ErrInvalidQuery) {
+		t.Errorf("sorting by a column name = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	var w where
+	if w.sql() != "" {
+		t.Errorf("empty where = %q", w.sql())
+	}
+	w.add("deleted_at IS NULL")
+	w.addIn("domain", "OHS", "Quality")
+	w.addSearch(` 50%_off\ `, "title", "description")
+	w.addSearch("  ", "title") // Ignored
+	wantSQL := ` WHERE deleted_at IS NULL AND domain IN (?, ?) AND (title LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`
+	wantArgs := []any{"OHS", "Quality", `%50\%\_off\\%`, `%50\%\_off\\%`}
+	if w.sql() != wantSQL || !reflect.DeepEqual(w.args, wantArgs) {
+		t.Errorf("where = %q %v, want %q %v", w.sql(), w.args, wantSQL, wantArgs)
+	}
+
+	var none where
+	none.addIn("domain")
+	if none.sql() != " WHERE 0" || len(none.args) != 0 {
+		t.Errorf("empty IN list = %q %v, want it to match nothing", none.sql(), none.args)
+	}
+}
+
+func TestAddDateRange(t *testing.T) {
+	tests := []struct {
+		name string
+		r    repository.DateRange
+		sql  string
+		args []any
+	}{
+		{"open", repository.DateRange{}, "", nil},
+		{"from", repository.DateRange{From: "2025-11-01"}, " WHERE created_at >= ?", []any{"2025-11-01"}},
+		// The upper bound includes the whole To day, also for timestamps.
+		{"to", repository.DateRange{To: "2025-11-30"}, " WHERE created_at < ?", []any{"2025-12-01"}},
+		{"both", repository.DateRange{From: "2024-12-31", To: "2024-12-31"}, " WHERE created_at >= ? AND created_at < ?", []any{"2024-12-31", "2025-01-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w where
+			if err := w.addDateRange("created_at", tt.r); err != nil {
+				t.Fatal(err)
+			}
+			if w.sql() != tt.sql || !reflect.DeepEqual(w.args, tt.args) {
+				t.Errorf("addDateRange = %q %v, want %q %v", w.sql(), w.args, tt.sql, tt.args)
+			}
+		})
+	}
+
+	for _, r := range []repository.DateRange{{From: "01.11.2025"}, {To: "2025-11-31"}, {From: "2025-11-01T00:00:00Z"}} {
+		var w where
+		if err := w.addDateRange("created_at", r); !errors.Is(err, repository.ErrInvalidQuery) {
+			t.Errorf("addDateRange(%+v) = %v, want ErrInvalidQuery", r, err)
+		}
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewIncidentRepository(db)
+	addIncident(t, db, domain.Incident{Title: "Fall from ladder", OccurredOn: "2025-10-31", CreatedAt: "2025-10-31T23:59:59Z"})
+	addIncident(t, db, domain.Incident{Title: "50% of pallets damaged", Domain: domain.DomainQuality, Status: "Investigation", OccurredOn: "2025-11-01"})
+	addIncident(t, db, domain.Incident{Title: "Forklift near miss", Description: "Driver_2 reversed blind", OccurredOn: "2025-11-30", CreatedAt: "2025-11-30T23:59:59Z"})
+	addIncident(t, db, domain.Incident{Title: "Oil spill", Domain: domain.DomainEnv, Reportable: true, OccurredOn: "2025-12-01", CreatedAt: "2025-12-01T00:00:00Z"})
+	deleted := addIncident(t, db, domain.Incident{Title: "Duplicate fall report"})
+	if err := repo.Delete(deleted.ID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	ptr := func(s string) *string { return &s }
+	ohs, env := domain.DomainOHS, domain.DomainEnv
+	reportable, notReportable := true, false
+	tests := []struct {
+		name string
+		q    repository.IncidentQuery
+		want []int
+	}{
+		{"no filters", repository.IncidentQuery{}, []int{1, 2, 3, 4}},
+		{"including deleted", repository.IncidentQuery{IncludeDeleted: true}, []int{1, 2, 3, 4, 5}},
+		{"status in any case", repository.IncidentQuery{Status: ptr("investigation")}, []int{2}},
+		{"domain", repository.IncidentQuery{Domain: &env}, []int{4}},
+		{"domains", repository.IncidentQuery{Domains: []domain.Domain{domain.DomainQuality, domain.DomainEnv}}, []int{2, 4}},
+		{"no domains", repository.IncidentQuery{Domains: []domain.Domain{}}, []int{}},
+		{"domain and status", repository.IncidentQuery{Domain: &ohs, Status: ptr("Open")}, []int{1, 3}},
+		{"reportable", repository.IncidentQuery{Reportable: &reportable}, []int{4}},
+		{"not reportable", repository.IncidentQuery{Reportable: &notReportable}, []int{1, 2, 3}},
+		{"occurred in November", repository.IncidentQuery{Occurred: repository.DateRange{From: "2025-11-01", To: "2025-11-30"}}, []int{2, 3}},
+		{"created up to a day", repository.IncidentQuery{Created: repository.DateRange{To: "2025-11-30"}}, []int{1, 2, 3}},
+		{"created from a day", repository.IncidentQuery{Created: repository.DateRange{From: "2025-11-30"}}, []int{3, 4}},
+		{"search in any case", repository.IncidentQuery{Search: "FALL"}, []int{1}},
+		{"search in description", repository.IncidentQuery{Search: "reversed"}, []int{3}},
+		{"percent is literal", repository.IncidentQuery{Search: "50%"}, []int{2}},
+		{"lone percent", repository.IncidentQuery{Search: "%"}, []int{2}},
+		{"underscore is literal", repository.IncidentQuery{Search: "driver_2"}, []int{3}},
+		{"lone underscore", repository.IncidentQuery{Search: "_"}, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incs, total, err := repo.List(tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := incidentIDs(incs); !reflect.DeepEqual(got, tt.want) || total != len(tt.want) {
+				t.Errorf("List = %v of %d, want %v", got, total, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := repo.List(repository.IncidentQuery{Occurred: repository.DateRange{From: "yesterday"}}); !errors.Is(err, repository.ErrInvalidQuery) {
+		t.Errorf("List with an invalid date = %v, want ErrInvalidQuery", err)
+	}
+}
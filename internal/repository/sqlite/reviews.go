@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ---------- Risk reviews ----------
+
+const reviewColumns = `id, risk_id, reviewer, reviewed_at, comments, rescored, previous_score, previous_level,
+	likelihood, impact, score, level, residual_likelihood, residual_impact, residual_score, residual_level, next_review_at`
+
+func (r *RiskRepository) AddReview(risk *domain.Risk, rv *domain.RiskReview) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := updateRisk(tx, risk); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`
+			INSERT INTO risk_reviews (risk_id, reviewer, reviewed_at, comments, rescored, previous_score, previous_level,
+				likelihood, impact, score, level, residual_likelihood, residual_impact, residual_score, residual_level, next_review_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rv.RiskID, rv.Reviewer, rv.ReviewedAt, rv.Comments, rv.Rescored, rv.PreviousScore, rv.PreviousLevel,
+			rv.Likelihood, rv.Impact, rv.Score, rv.Level,
+			rv.ResidualLikelihood, rv.ResidualImpact, rv.ResidualScore, rv.ResidualLevel, rv.NextReviewAt,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		rv.ID = int(id)
+		return nil
+	})
+}
+
+func (r *RiskRepository) Reviews(riskID int) ([]*domain.RiskReview, error) {
+	rows, err := r.db.Query(`SELECT `+reviewColumns+` FROM risk_reviews WHERE risk_id = ? ORDER BY id DESC`, riskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.RiskReview, 0)
+	for rows.Next() {
+		rv := &domain.RiskReview{}
+		if err := rows.Scan(
+			&rv.ID, &rv.RiskID, &rv.Reviewer, &rv.ReviewedAt, &rv.Comments, &rv.Rescored, &rv.PreviousScore, &rv.PreviousLevel,
+			&rv.Likelihood, &rv.Impact, &rv.Score, &rv.Level,
+			&rv.ResidualLikelihood, &rv.ResidualImpact, &rv.ResidualScore, &rv.ResidualLevel, &rv.NextReviewAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, rv)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ---------- Risk matrix repository ----------
+
+// riskMatrixConfig is the stored JSON form of the matrix; the bookkeeping
+// fields have their own columns.
+type riskMatrixConfig struct {
+	LikelihoodScale int               `json:"likelihoodScale"`
+	ImpactScale     int               `json:"impactScale"`
+	Bands           []domain.RiskBand `json:"bands"`
+	Cells           []domain.RiskCell `json:"cells"`
+}
+
+type RiskMatrixRepository struct {
+	db *sql.DB
+}
+
+func NewRiskMatrixRepository(db *sql.DB) *RiskMatrixRepository {
+	return &RiskMatrixRepository{db: db}
+}
+
+func (r *RiskMatrixRepository) Get() (*domain.RiskMatrix, error) {
+	return getRiskMatrix(r.db)
+}
+
+func (r *RiskMatrixRepository) Save(m *domain.RiskMatrix, risks []*domain.Risk, incidents []*domain.Incident) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := saveRiskMatrix(tx, m); err != nil {
+			return err
+		}
+		for _, risk := range risks {
+			if err := updateRisk(tx, risk); err != nil {
+				return fmt.Errorf("re-scoring risk %d: %w", risk.ID, err)
+			}
+		}
+		for _, inc := range incidents {
+			if err := updateIncident(tx, inc); err != nil {
+				return fmt.Errorf("re-scoring incident %d: %w", inc.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// rowQueryer is satisfied by *sql.DB and *sql.Tx.
+type rowQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func getRiskMatrix(q rowQueryer) (*domain.RiskMatrix, error) {
+	var config string
+	m := &domain.RiskMatrix{}
+	err := q.QueryRow(`SELECT config, updated_at, updated_by FROM risk_matrix WHERE id = 1`).
+		Scan(&config, &m.UpdatedAt, &m.UpdatedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DefaultRiskMatrix(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c riskMatrixConfig
+	if err := json.Unmarshal([]byte(config), &c); err != nil {
+		return nil, err
+	}
+	m.LikelihoodScale, m.ImpactScale, m.Bands, m.Cells = c.LikelihoodScale, c.ImpactScale, c.Bands, c.Cells
+	if m.Cells == nil {
+		m.Cells = []domain.RiskCell{}
+	}
+	return m, nil
+}
+
+func saveRiskMatrix(db execer, m *domain.RiskMatrix) error {
+	config, err := json.Marshal(riskMatrixConfig{
+		LikelihoodScale: m.LikelihoodScale,
+		ImpactScale:     m.ImpactScale,
+		Bands:           m.Bands,
+		Cells:           m.Cells,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		INSERT INTO risk_matrix (id, config, updated_at, updated_by) VALUES (1, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET config = excluded.config, updated_at = excluded.updated_at, updated_by = excluded.updated_by`,
+		string(config), m.UpdatedAt, m.UpdatedBy,
+	)
+	return err
+}
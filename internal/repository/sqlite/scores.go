@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ---------- Risk score history ----------
+
+const scoreColumns = `risk_id, recorded_at, changed_by, likelihood, impact, score, level,
+	residual_likelihood, residual_impact, residual_score, residual_level`
+
+// recordScore appends a score history point for risk when its ratings,
+// scores or levels differ from before (nil for created risks).
+func recordScore(tx *sql.Tx, before, risk *domain.Risk, actor string) error {
+	if before != nil && sameScore(before, risk) {
+		return nil
+	}
+	return insertScore(tx, &domain.RiskScore{
+		RiskID:             risk.ID,
+		RecordedAt:         time.Now().Format(time.RFC3339),
+		ChangedBy:          actor,
+		Likelihood:         risk.Likelihood,
+		Impact:             risk.Impact,
+		Score:              risk.Score,
+		Level:              risk.Level,
+		ResidualLikelihood: risk.ResidualLikelihood,
+		ResidualImpact:     risk.ResidualImpact,
+		ResidualScore:      risk.ResidualScore,
+		ResidualLevel:      risk.ResidualLevel,
+	})
+}
+
+func sameScore(a, b *domain.Risk) bool {
+	return a.Likelihood == b.Likelihood && a.Impact == b.Impact && a.Score == b.Score && a.Level == b.Level &&
+		a.ResidualLikelihood == b.ResidualLikelihood && a.ResidualImpact == b.ResidualImpact &&
+		a.ResidualScore == b.ResidualScore && a.ResidualLevel == b.ResidualLevel
+}
+
+func insertScore(tx *sql.Tx, s *domain.RiskScore) error {
+	_, err := tx.Exec(`
+		INSERT INTO risk_scores (`+scoreColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.RiskID, s.RecordedAt, s.ChangedBy, s.Likelihood, s.Impact, s.Score, s.Level,
+		s.ResidualLikelihood, s.ResidualImpact, s.ResidualScore, s.ResidualLevel,
+	)
+	return err
+}
+
+func (r *RiskRepository) ScoreHistory(riskID int) ([]*domain.RiskScore, error) {
+	return r.scores(`SELECT `+scoreColumns+` FROM risk_scores WHERE risk_id = ? ORDER BY id`, riskID)
+}
+
+func (r *RiskRepository) AllScores() ([]*domain.RiskScore, error) {
+	return r.scores(`SELECT ` + scoreColumns + ` FROM risk_scores ORDER BY id`)
+}
+
+func (r *RiskRepository) scores(query string, args ...any) ([]*domain.RiskScore, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.RiskScore, 0)
+	for rows.Next() {
+		s := &domain.RiskScore{}
+		if err := rows.Scan(
+			&s.RiskID, &s.RecordedAt, &s.ChangedBy, &s.Likelihood, &s.Impact, &s.Score, &s.Level,
+			&s.ResidualLikelihood, &s.ResidualImpact, &s.ResidualScore, &s.ResidualLevel,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -3,6 +3,9 @@ package sqlite
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/glebarez/sqlite"
 
@@ -10,6 +13,8 @@ import (
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
 
+// NewDB opens the SQLite database at path. The schema is managed by the
+// versioned migrations in migrations.go; see Migrate and CheckMigrations.
 func NewDB(path string) (*sql.DB, error) {
 	// Note: driver name is "sqlite" (glebarez/sqlite), not "sqlite3"
 	db, err := sql.Open("sqlite", path)
@@ -19,77 +24,84 @@ func NewDB(path string) (*sql.DB, error) {
 	if err := db.Ping(); err != nil {
 		return nil, err
 	}
-	if err := initSchema(db); err != nil {
-		return nil, err
-	}
+	// SQLite allows a single writer. One connection serialises the
+	// read-then-write transactions (change history) instead of failing them
+	// with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
 	return db, nil
 }
 
-func initSchema(db *sql.DB) error {
-	stmts := []string{
-		`CREATE TABLE IF NOT EXISTS risks (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			title TEXT NOT NULL,
-			process TEXT NOT NULL,
-			domain TEXT NOT NULL,
-			description TEXT,
-			likelihood INTEGER NOT NULL,
-			impact INTEGER NOT NULL,
-			score INTEGER NOT NULL,
-			level TEXT NOT NULL,
-			owner TEXT,
-			status TEXT NOT NULL,
-			created_at TEXT NOT NULL
-		);`,
-		`CREATE TABLE IF NOT EXISTS incidents (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			title TEXT NOT NULL,
-			description TEXT NOT NULL,
-			domain TEXT NOT NULL,
-			related_risk_id INTEGER,
-			severity INTEGER NOT NULL,
-			likelihood INTEGER NOT NULL,
-			risk_score INTEGER NOT NULL,
-			risk_level TEXT NOT NULL,
-			root_cause TEXT,
-			status TEXT NOT NULL,
-			created_at TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		);`,
-		`CREATE TABLE IF NOT EXISTS audits (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			title TEXT NOT NULL,
-			scope TEXT NOT NULL,
-			domain TEXT NOT NULL,
-			planned_date TEXT NOT NULL,
-			auditor TEXT,
-			status TEXT NOT NULL,
-			findings TEXT,
-			created_at TEXT NOT NULL
-		);`,
-		`CREATE TABLE IF NOT EXISTS actions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			title TEXT NOT NULL,
-			description TEXT,
-			source_type TEXT NOT NULL,
-			source_id INTEGER NOT NULL,
-			owner TEXT,
-			due_date TEXT,
-			status TEXT NOT NULL,
-			created_at TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		);`,
-	}
-	for _, s := range stmts {
-		if _, err := db.Exec(s); err != nil {
-			return err
-		}
+// ---------- Soft delete helpers ----------
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func notDeleted(includeDeleted bool) string {
+	if includeDeleted {
+		return ""
 	}
-	return nil
+	return " WHERE deleted_at IS NULL"
+}
+
+func softDelete(tx *sql.Tx, table, kind string, id int, deletedBy string) error {
+	now := time.Now().Format(time.RFC3339)
+	res, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET deleted_at=?, deleted_by=?
+		WHERE id=? AND deleted_at IS NULL`, table),
+		now, deletedBy, id,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return appendHistory(tx, kind, id, domain.HistoryDeleted, deletedBy, []domain.FieldChange{
+		{Field: "deletedAt", After: now},
+		{Field: "deletedBy", After: deletedBy},
+	})
+}
+
+func restore(tx *sql.Tx, table, kind string, id int, restoredBy string) error {
+	var deletedAt, deletedBy sql.NullString
+	err := tx.QueryRow(fmt.Sprintf(`
+		SELECT deleted_at, deleted_by FROM %s
+		WHERE id=? AND deleted_at IS NOT NULL`, table), id).Scan(&deletedAt, &deletedBy)
+	if err != nil {
+		return noRows(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET deleted_at=NULL, deleted_by=NULL WHERE id=?`, table), id); err != nil {
+		return err
+	}
+	return appendHistory(tx, kind, id, domain.HistoryRestored, restoredBy, []domain.FieldChange{
+		{Field: "deletedAt", Before: deletedAt.String},
+		{Field: "deletedBy", Before: deletedBy.String},
+	})
 }
 
 // ---------- Risk repository ----------
 
+const riskColumns = `id, title, process_id, ` + processName + `, domain, description, likelihood, impact, score, level, residual_likelihood, residual_impact, residual_score, residual_level, treatment_option, treatment_justification, treatment_approved_by, treatment_approved_at, last_reviewed_at, next_review_at, owner, status, created_at, created_by, updated_by, deleted_at, deleted_by`
+
+var riskSortColumns = map[string]string{
+	"id":            "id",
+	"title":         "title",
+	"process":       processName,
+	"domain":        "domain",
+	"likelihood":    "likelihood",
+	"impact":        "impact",
+	"score":         "score",
+	"residualScore": "residual_score",
+	"owner":         "owner",
+	"status":        "status",
+	"createdAt":     "created_at",
+	"nextReviewAt":  "next_review_at",
+}
+
 type RiskRepository struct {
 	db *sql.DB
 }
@@ -99,45 +111,86 @@ func NewRiskRepository(db *sql.DB) *RiskRepository {
 }
 
 func (r *RiskRepository) Create(risk *domain.Risk) error {
-	res, err := r.db.Exec(`
-		INSERT INTO risks (title, process, domain, description, likelihood, impact, score, level, owner, status, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		risk.Title, risk.Process, string(risk.Domain), risk.Description,
-		risk.Likelihood, risk.Impact, risk.Score, risk.Level,
-		risk.Owner, risk.Status, risk.CreatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	id, err := res.LastInsertId()
-	if err == nil {
+	option, justification, approvedBy, approvedAt := treatmentFields(risk.Treatment)
+	return inTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO risks (title, process_id, domain, description, likelihood, impact, score, level,
+				residual_likelihood, residual_impact, residual_score, residual_level,
+				treatment_option, treatment_justification, treatment_approved_by, treatment_approved_at,
+				last_reviewed_at, next_review_at, owner, status, created_at, created_by, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			risk.Title, risk.ProcessID, string(risk.Domain), risk.Description,
+			risk.Likelihood, risk.Impact, risk.Score, risk.Level,
+			risk.ResidualLikelihood, risk.ResidualImpact, risk.ResidualScore, risk.ResidualLevel,
+			option, justification, approvedBy, approvedAt,
+			risk.LastReviewedAt, risk.NextReviewAt, risk.Owner, risk.Status, risk.CreatedAt, risk.CreatedBy, risk.UpdatedBy,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 		risk.ID = int(id)
-	}
-	return nil
+		if err := saveRiskControls(tx, risk); err != nil {
+			return err
+		}
+		if err := saveRiskLinks(tx, risk); err != nil {
+			return err
+		}
+		if err := recordScore(tx, nil, risk, risk.CreatedBy); err != nil {
+			return err
+		}
+		return writeHistory(tx, domain.KindRisk, risk.ID, domain.HistoryCreated, risk.CreatedBy, nil, risk)
+	})
 }
 
 func (r *RiskRepository) Update(risk *domain.Risk) error {
-	res, err := r.db.Exec(`
-		UPDATE risks SET title=?, process=?, domain=?, description=?, likelihood=?, impact=?, score=?, level=?, owner=?, status=?, created_at=?
-		WHERE id=?`,
-		risk.Title, risk.Process, string(risk.Domain), risk.Description,
-		risk.Likelihood, risk.Impact, risk.Score, risk.Level,
-		risk.Owner, risk.Status, risk.CreatedAt, risk.ID,
-	)
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return updateRisk(tx, risk)
+	})
+}
+
+// updateRisk saves risk with its controls and links and records the change in history
+// and, when re-rated, in the score history.
+func updateRisk(tx *sql.Tx, risk *domain.Risk) error {
+	before, err := scanRisk(tx.QueryRow(`SELECT `+riskColumns+` FROM risks WHERE id = ? AND deleted_at IS NULL`, risk.ID))
 	if err != nil {
+		return noRows(err)
+	}
+	if err := loadRiskDetails(tx, before); err != nil {
 		return err
 	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return repository.ErrNotFound
+	option, justification, approvedBy, approvedAt := treatmentFields(risk.Treatment)
+	if _, err := tx.Exec(`
+		UPDATE risks SET title=?, process_id=?, domain=?, description=?, likelihood=?, impact=?, score=?, level=?,
+			residual_likelihood=?, residual_impact=?, residual_score=?, residual_level=?,
+			treatment_option=?, treatment_justification=?, treatment_approved_by=?, treatment_approved_at=?,
+			last_reviewed_at=?, next_review_at=?, owner=?, status=?, created_at=?, updated_by=?
+		WHERE id=? AND deleted_at IS NULL`,
+		risk.Title, risk.ProcessID, string(risk.Domain), risk.Description,
+		risk.Likelihood, risk.Impact, risk.Score, risk.Level,
+		risk.ResidualLikelihood, risk.ResidualImpact, risk.ResidualScore, risk.ResidualLevel,
+		option, justification, approvedBy, approvedAt,
+		risk.LastReviewedAt, risk.NextReviewAt, risk.Owner, risk.Status, risk.CreatedAt, risk.UpdatedBy, risk.ID,
+	); err != nil {
+		return err
 	}
-	return nil
+	if err := saveRiskControls(tx, risk); err != nil {
+		return err
+	}
+	if err := saveRiskLinks(tx, risk); err != nil {
+		return err
+	}
+	if err := recordScore(tx, before, risk, risk.UpdatedBy); err != nil {
+		return err
+	}
+	return writeHistory(tx, domain.KindRisk, risk.ID, domain.HistoryUpdated, risk.UpdatedBy, before, risk)
 }
 
-func (r *RiskRepository) GetAll() ([]*domain.Risk, error) {
-	rows, err := r.db.Query(`
-		SELECT id, title, process, domain, description, likelihood, impact, score, level, owner, status, created_at
-		FROM risks`)
+func (r *RiskRepository) GetAll(includeDeleted bool) ([]*domain.Risk, error) {
+	rows, err := r.db.Query(`SELECT ` + riskColumns + ` FROM risks` + notDeleted(includeDeleted))
 	if err != nil {
 		return nil, err
 	}
@@ -145,44 +198,268 @@ func (r *RiskRepository) GetAll() ([]*domain.Risk, error) {
 
 	var out []*domain.Risk
 	for rows.Next() {
-		var d string
-		risk := &domain.Risk{}
-		if err := rows.Scan(
-			&risk.ID, &risk.Title, &risk.Process, &d, &risk.Description,
-			&risk.Likelihood, &risk.Impact, &risk.Score, &risk.Level,
-			&risk.Owner, &risk.Status, &risk.CreatedAt,
-		); err != nil {
+		risk, err := scanRisk(rows)
+		if err != nil {
 			return nil, err
 		}
-		risk.Domain = domain.Domain(d)
 		out = append(out, risk)
 	}
-	return out, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	return out, loadRiskDetails(r.db, out...)
+}
+
+func (r *RiskRepository) List(q repository.RiskQuery) ([]*domain.Risk, int, error) {
+	var w where
+	if !q.IncludeDeleted {
+		w.add("deleted_at IS NULL")
+	}
+	if q.Domain != nil {
+		w.add("domain = ?", string(*q.Domain))
+	}
+	if q.Domains != nil {
+		w.addIn("domain", domainArgs(q.Domains)...)
+	}
+	if q.ProcessID != nil {
+		w.add("process_id = ?", *q.ProcessID)
+	}
+	if q.Process != nil {
+		w.add(processName+" = ? COLLATE NOCASE", *q.Process)
+	}
+	if q.Status != nil {
+		w.add("status = ? COLLATE NOCASE", *q.Status)
+	}
+	if q.Owner != nil {
+		w.add("owner = ? COLLATE NOCASE", *q.Owner)
+	}
+	if q.Level != nil {
+		w.add("level = ? COLLATE NOCASE", *q.Level)
+	}
+	if q.ResidualLevel != nil {
+		w.add("residual_level = ? COLLATE NOCASE", *q.ResidualLevel)
+	}
+	if q.AssetID != nil {
+		w.add("id IN (SELECT risk_id FROM risk_assets WHERE asset_id = ?)", *q.AssetID)
+	}
+	if q.ISMSControl != nil {
+		w.add("id IN (SELECT risk_id FROM risk_isms_controls WHERE control_id = ?)", *q.ISMSControl)
+	}
+	if q.ReviewDueBy != "" {
+		w.add("next_review_at != '' AND next_review_at <= ?", q.ReviewDueBy)
+	}
+	if err := w.addDateRange("created_at", q.Created); err != nil {
+		return nil, 0, err
+	}
+	w.addSearch(q.Search, "title", "description", processName, "owner")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, riskSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM risks`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+riskColumns+` FROM risks`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Risk, 0)
+	for rows.Next() {
+		risk, err := scanRisk(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, risk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+	return out, total, loadRiskDetails(r.db, out...)
 }
 
 func (r *RiskRepository) GetByID(id int) (*domain.Risk, error) {
-	row := r.db.QueryRow(`
-		SELECT id, title, process, domain, description, likelihood, impact, score, level, owner, status, created_at
-		FROM risks WHERE id = ?`, id)
+	row := r.db.QueryRow(`SELECT `+riskColumns+` FROM risks WHERE id = ? AND deleted_at IS NULL`, id)
+
+	risk, err := scanRisk(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return risk, loadRiskDetails(r.db, risk)
+}
 
+func (r *RiskRepository) GetDeletedByID(id int) (*domain.Risk, error) {
+	row := r.db.QueryRow(`SELECT `+riskColumns+` FROM risks WHERE id = ? AND deleted_at IS NOT NULL`, id)
+
+	risk, err := scanRisk(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return risk, loadRiskDetails(r.db, risk)
+}
+
+// Delete soft-deletes a risk. It refuses to delete a risk that is still
+// referenced by active incidents (related_risk_id) or actions
+// (source_type='Risk') and returns repository.ErrInUse instead; those records
+// must be unlinked or deleted first.
+func (r *RiskRepository) Delete(id int, deletedBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var incidents, actions int
+		if err := tx.QueryRow(`
+			SELECT COUNT(*) FROM incidents WHERE related_risk_id = ? AND deleted_at IS NULL`, id).Scan(&incidents); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(`
+			SELECT COUNT(*) FROM actions WHERE source_type = 'Risk' AND source_id = ? AND deleted_at IS NULL`, id).Scan(&actions); err != nil {
+			return err
+		}
+		if incidents > 0 || actions > 0 {
+			return fmt.Errorf("%w: risk %d is linked to %d incident(s) and %d action(s)",
+				repository.ErrInUse, id, incidents, actions)
+		}
+		return softDelete(tx, "risks", domain.KindRisk, id, deletedBy)
+	})
+}
+
+func (r *RiskRepository) Restore(id int, restoredBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return restore(tx, "risks", domain.KindRisk, id, restoredBy)
+	})
+}
+
+func scanRisk(row rowScanner) (*domain.Risk, error) {
 	var d string
+	var process, deletedAt, deletedBy sql.NullString
+	t := &domain.RiskTreatment{}
 	risk := &domain.Risk{}
 	if err := row.Scan(
-		&risk.ID, &risk.Title, &risk.Process, &d, &risk.Description,
+		&risk.ID, &risk.Title, &risk.ProcessID, &process, &d, &risk.Description,
 		&risk.Likelihood, &risk.Impact, &risk.Score, &risk.Level,
-		&risk.Owner, &risk.Status, &risk.CreatedAt,
+		&risk.ResidualLikelihood, &risk.ResidualImpact, &risk.ResidualScore, &risk.ResidualLevel,
+		&t.Option, &t.Justification, &t.ApprovedBy, &t.ApprovedAt,
+		&risk.LastReviewedAt, &risk.NextReviewAt, &risk.Owner, &risk.Status, &risk.CreatedAt,
+		&risk.CreatedBy, &risk.UpdatedBy, &deletedAt, &deletedBy,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
 		return nil, err
 	}
 	risk.Domain = domain.Domain(d)
+	risk.Process = process.String
+	risk.DeletedAt = deletedAt.String
+	risk.DeletedBy = deletedBy.String
+	if t.Option != "" {
+		risk.Treatment = t
+	}
 	return risk, nil
 }
 
+// treatmentFields flattens a treatment into its columns; a nil treatment
+// is stored as empty strings.
+func treatmentFields(t *domain.RiskTreatment) (option, justification, approvedBy, approvedAt string) {
+	if t == nil {
+		return "", "", "", ""
+	}
+	return t.Option, t.Justification, t.ApprovedBy, t.ApprovedAt
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// loadRiskDetails fills in the controls and the asset and ISMS control
+// links of risks.
+func loadRiskDetails(q queryer, risks ...*domain.Risk) error {
+	if err := loadRiskControls(q, risks...); err != nil {
+		return err
+	}
+	return loadRiskLinks(q, risks...)
+}
+
+// loadRiskControls fills in the controls of risks with one query.
+func loadRiskControls(q queryer, risks ...*domain.Risk) error {
+	if len(risks) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.Risk, len(risks))
+	ids := make([]any, 0, len(risks))
+	for _, risk := range risks {
+		risk.Controls = make([]domain.RiskControl, 0)
+		byID[risk.ID] = risk
+		ids = append(ids, risk.ID)
+	}
+
+	var w where
+	w.addIn("risk_id", ids...)
+	rows, err := q.Query(`
+		SELECT risk_id, name, description, control_type, effectiveness
+		FROM risk_controls`+w.sql()+` ORDER BY risk_id, position`, w.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var riskID int
+		var c domain.RiskControl
+		if err := rows.Scan(&riskID, &c.Name, &c.Description, &c.Type, &c.Effectiveness); err != nil {
+			return err
+		}
+		byID[riskID].Controls = append(byID[riskID].Controls, c)
+	}
+	return rows.Err()
+}
+
+// saveRiskControls replaces the stored controls of risk with risk.Controls.
+func saveRiskControls(tx *sql.Tx, risk *domain.Risk) error {
+	if _, err := tx.Exec(`DELETE FROM risk_controls WHERE risk_id = ?`, risk.ID); err != nil {
+		return err
+	}
+	for i, c := range risk.Controls {
+		if _, err := tx.Exec(`
+			INSERT INTO risk_controls (risk_id, position, name, description, control_type, effectiveness)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			risk.ID, i, c.Name, c.Description, c.Type, c.Effectiveness,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ---------- Incident repository ----------
 
+const incidentColumns = `id, title, description, domain, related_risk_id, severity, likelihood, risk_score, risk_level, root_cause, status, occurred_on,
+	incident_type, injured_name, injured_job_title, injured_employment, body_part, injury_nature, days_lost, reportable, reporting_deadline, reported_on,
+	aspect_id, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by`
+
+var incidentSortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"domain":     "domain",
+	"severity":   "severity",
+	"likelihood": "likelihood",
+	"riskScore":  "risk_score",
+	"status":     "status",
+	"occurredOn": "occurred_on",
+	"type":       "incident_type",
+	"daysLost":   "days_lost",
+	"createdAt":  "created_at",
+	"updatedAt":  "updated_at",
+}
+
 type IncidentRepository struct {
 	db *sql.DB
 }
@@ -192,55 +469,73 @@ func NewIncidentRepository(db *sql.DB) *IncidentRepository {
 }
 
 func (r *IncidentRepository) Create(inc *domain.Incident) error {
-	var related interface{} = nil
-	if inc.RelatedRiskID != nil {
-		related = *inc.RelatedRiskID
-	}
-	res, err := r.db.Exec(`
-		INSERT INTO incidents (title, description, domain, related_risk_id, severity, likelihood, risk_score, risk_level, root_cause, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		inc.Title, inc.Description, string(inc.Domain),
-		related, inc.Severity, inc.Likelihood, inc.RiskScore,
-		inc.RiskLevel, inc.RootCause, inc.Status,
-		inc.CreatedAt, inc.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	id, err := res.LastInsertId()
-	if err == nil {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var related interface{} = nil
+		if inc.RelatedRiskID != nil {
+			related = *inc.RelatedRiskID
+		}
+		name, jobTitle, employment := injuredFields(inc.InjuredPerson)
+		res, err := tx.Exec(`
+			INSERT INTO incidents (title, description, domain, related_risk_id, severity, likelihood, risk_score, risk_level, root_cause, status, occurred_on,
+				incident_type, injured_name, injured_job_title, injured_employment, body_part, injury_nature, days_lost, reportable, reporting_deadline, reported_on,
+				aspect_id, created_at, updated_at, created_by, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			inc.Title, inc.Description, string(inc.Domain),
+			related, inc.Severity, inc.Likelihood, inc.RiskScore,
+			inc.RiskLevel, inc.RootCause, inc.Status, inc.OccurredOn,
+			inc.Type, name, jobTitle, employment, inc.BodyPart, inc.InjuryNature, inc.DaysLost,
+			inc.Reportable, inc.ReportingDeadline, inc.ReportedOn,
+			nullInt(inc.AspectID), inc.CreatedAt, inc.UpdatedAt, inc.CreatedBy, inc.UpdatedBy,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 		inc.ID = int(id)
-	}
-	return nil
+		return writeHistory(tx, domain.KindIncident, inc.ID, domain.HistoryCreated, inc.CreatedBy, nil, inc)
+	})
 }
 
 func (r *IncidentRepository) Update(inc *domain.Incident) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return updateIncident(tx, inc)
+	})
+}
+
+// updateIncident saves inc and records the change in history.
+func updateIncident(tx *sql.Tx, inc *domain.Incident) error {
 	var related interface{} = nil
 	if inc.RelatedRiskID != nil {
 		related = *inc.RelatedRiskID
 	}
-	res, err := r.db.Exec(`
+	before, err := scanIncident(tx.QueryRow(`SELECT `+incidentColumns+` FROM incidents WHERE id = ? AND deleted_at IS NULL`, inc.ID))
+	if err != nil {
+		return noRows(err)
+	}
+	name, jobTitle, employment := injuredFields(inc.InjuredPerson)
+	if _, err := tx.Exec(`
 		UPDATE incidents
-		SET title=?, description=?, domain=?, related_risk_id=?, severity=?, likelihood=?, risk_score=?, risk_level=?, root_cause=?, status=?, created_at=?, updated_at=?
-		WHERE id=?`,
+		SET title=?, description=?, domain=?, related_risk_id=?, severity=?, likelihood=?, risk_score=?, risk_level=?, root_cause=?, status=?, occurred_on=?,
+			incident_type=?, injured_name=?, injured_job_title=?, injured_employment=?, body_part=?, injury_nature=?, days_lost=?,
+			reportable=?, reporting_deadline=?, reported_on=?, aspect_id=?, created_at=?, updated_at=?, updated_by=?
+		WHERE id=? AND deleted_at IS NULL`,
 		inc.Title, inc.Description, string(inc.Domain),
 		related, inc.Severity, inc.Likelihood, inc.RiskScore, inc.RiskLevel,
-		inc.RootCause, inc.Status, inc.CreatedAt, inc.UpdatedAt, inc.ID,
-	)
-	if err != nil {
+		inc.RootCause, inc.Status, inc.OccurredOn,
+		inc.Type, name, jobTitle, employment, inc.BodyPart, inc.InjuryNature, inc.DaysLost,
+		inc.Reportable, inc.ReportingDeadline, inc.ReportedOn,
+		nullInt(inc.AspectID), inc.CreatedAt, inc.UpdatedAt, inc.UpdatedBy, inc.ID,
+	); err != nil {
 		return err
 	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return repository.ErrNotFound
-	}
-	return nil
+	return writeHistory(tx, domain.KindIncident, inc.ID, domain.HistoryUpdated, inc.UpdatedBy, before, inc)
 }
 
-func (r *IncidentRepository) GetAll() ([]*domain.Incident, error) {
-	rows, err := r.db.Query(`
-		SELECT id, title, description, domain, related_risk_id, severity, likelihood, risk_score, risk_level, root_cause, status, created_at, updated_at
-		FROM incidents`)
+func (r *IncidentRepository) GetAll(includeDeleted bool) ([]*domain.Incident, error) {
+	rows, err := r.db.Query(`SELECT ` + incidentColumns + ` FROM incidents` + notDeleted(includeDeleted))
 	if err != nil {
 		return nil, err
 	}
@@ -248,44 +543,139 @@ func (r *IncidentRepository) GetAll() ([]*domain.Incident, error) {
 
 	var out []*domain.Incident
 	for rows.Next() {
-		var d string
-		var related sqlNullInt
-		inc := &domain.Incident{}
-		if err := rows.Scan(
-			&inc.ID, &inc.Title, &inc.Description, &d, &related,
-			&inc.Severity, &inc.Likelihood, &inc.RiskScore,
-			&inc.RiskLevel, &inc.RootCause, &inc.Status,
-			&inc.CreatedAt, &inc.UpdatedAt,
-		); err != nil {
+		inc, err := scanIncident(rows)
+		if err != nil {
 			return nil, err
 		}
-		inc.Domain = domain.Domain(d)
-		if related.Valid {
-			id := related.V
-			inc.RelatedRiskID = &id
+		out = append(out, inc)
+	}
+	return out, rows.Err()
+}
+
+func (r *IncidentRepository) List(q repository.IncidentQuery) ([]*domain.Incident, int, error) {
+	var w where
+	if !q.IncludeDeleted {
+		w.add("deleted_at IS NULL")
+	}
+	if q.Domain != nil {
+		w.add("domain = ?", string(*q.Domain))
+	}
+	if q.Domains != nil {
+		w.addIn("domain", domainArgs(q.Domains)...)
+	}
+	if q.Status != nil {
+		w.add("status = ? COLLATE NOCASE", *q.Status)
+	}
+	if q.Level != nil {
+		w.add("risk_level = ? COLLATE NOCASE", *q.Level)
+	}
+	if q.RelatedRiskID != nil {
+		w.add("related_risk_id = ?", *q.RelatedRiskID)
+	}
+	if q.ProcessID != nil {
+		w.add("related_risk_id IN (SELECT id FROM risks WHERE process_id = ?)", *q.ProcessID)
+	}
+	if q.AspectID != nil {
+		w.add("aspect_id = ?", *q.AspectID)
+	}
+	if q.Type != nil {
+		w.add("incident_type = ? COLLATE NOCASE", *q.Type)
+	}
+	if q.Reportable != nil {
+		w.add("reportable = ?", *q.Reportable)
+	}
+	if err := w.addDateRange("created_at", q.Created); err != nil {
+		return nil, 0, err
+	}
+	if err := w.addDateRange("occurred_on", q.Occurred); err != nil {
+		return nil, 0, err
+	}
+	w.addSearch(q.Search, "title", "description", "root_cause")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, incidentSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM incidents`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+incidentColumns+` FROM incidents`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Incident, 0)
+	for rows.Next() {
+		inc, err := scanIncident(rows)
+		if err != nil {
+			return nil, 0, err
 		}
 		out = append(out, inc)
 	}
-	return out, nil
+	return out, total, rows.Err()
 }
 
 func (r *IncidentRepository) GetByID(id int) (*domain.Incident, error) {
-	row := r.db.QueryRow(`
-		SELECT id, title, description, domain, related_risk_id, severity, likelihood, risk_score, risk_level, root_cause, status, created_at, updated_at
-		FROM incidents WHERE id = ?`, id)
+	row := r.db.QueryRow(`SELECT `+incidentColumns+` FROM incidents WHERE id = ? AND deleted_at IS NULL`, id)
+
+	inc, err := scanIncident(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (r *IncidentRepository) GetDeletedByID(id int) (*domain.Incident, error) {
+	row := r.db.QueryRow(`SELECT `+incidentColumns+` FROM incidents WHERE id = ? AND deleted_at IS NOT NULL`, id)
+
+	inc, err := scanIncident(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return inc, nil
+}
 
+// Delete soft-deletes an incident unless active actions still point at it.
+func (r *IncidentRepository) Delete(id int, deletedBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := ensureNoActiveActions(tx, "Incident", id); err != nil {
+			return err
+		}
+		return softDelete(tx, "incidents", domain.KindIncident, id, deletedBy)
+	})
+}
+
+func (r *IncidentRepository) Restore(id int, restoredBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return restore(tx, "incidents", domain.KindIncident, id, restoredBy)
+	})
+}
+
+func scanIncident(row rowScanner) (*domain.Incident, error) {
 	var d string
-	var related sqlNullInt
+	var related, aspect sqlNullInt
+	var deletedAt, deletedBy sql.NullString
+	var injured domain.InjuredPerson
 	inc := &domain.Incident{}
 	if err := row.Scan(
 		&inc.ID, &inc.Title, &inc.Description, &d, &related,
 		&inc.Severity, &inc.Likelihood, &inc.RiskScore,
-		&inc.RiskLevel, &inc.RootCause, &inc.Status,
-		&inc.CreatedAt, &inc.UpdatedAt,
+		&inc.RiskLevel, &inc.RootCause, &inc.Status, &inc.OccurredOn,
+		&inc.Type, &injured.Name, &injured.JobTitle, &injured.Employment, &inc.BodyPart, &inc.InjuryNature, &inc.DaysLost,
+		&inc.Reportable, &inc.ReportingDeadline, &inc.ReportedOn,
+		&aspect, &inc.CreatedAt, &inc.UpdatedAt, &inc.CreatedBy, &inc.UpdatedBy,
+		&deletedAt, &deletedBy,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
 		return nil, err
 	}
 	inc.Domain = domain.Domain(d)
@@ -293,11 +683,41 @@ func (r *IncidentRepository) GetByID(id int) (*domain.Incident, error) {
 		id := related.V
 		inc.RelatedRiskID = &id
 	}
+	if aspect.Valid {
+		id := aspect.V
+		inc.AspectID = &id
+	}
+	if injured.Name != "" {
+		inc.InjuredPerson = &injured
+	}
+	inc.DeletedAt = deletedAt.String
+	inc.DeletedBy = deletedBy.String
 	return inc, nil
 }
 
+// injuredFields flattens an injured person into its columns; nil leaves
+// them empty.
+func injuredFields(p *domain.InjuredPerson) (name, jobTitle, employment string) {
+	if p == nil {
+		return "", "", ""
+	}
+	return p.Name, p.JobTitle, p.Employment
+}
+
 // ---------- Audit repository ----------
 
+const auditColumns = `id, title, scope, domain, process_id, ` + processName + `, planned_date, auditor, status, findings, created_at, created_by, updated_by, deleted_at, deleted_by`
+
+var auditSortColumns = map[string]string{
+	"id":          "id",
+	"title":       "title",
+	"domain":      "domain",
+	"plannedDate": "planned_date",
+	"auditor":     "auditor",
+	"status":      "status",
+	"createdAt":   "created_at",
+}
+
 type AuditRepository struct {
 	db *sql.DB
 }
@@ -307,44 +727,46 @@ func NewAuditRepository(db *sql.DB) *AuditRepository {
 }
 
 func (r *AuditRepository) Create(a *domain.Audit) error {
-	res, err := r.db.Exec(`
-		INSERT INTO audits (title, scope, domain, planned_date, auditor, status, findings, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		a.Title, a.Scope, string(a.Domain), a.PlannedDate, a.Auditor,
-		a.Status, a.Findings, a.CreatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	id, err := res.LastInsertId()
-	if err == nil {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO audits (title, scope, domain, process_id, planned_date, auditor, status, findings, created_at, created_by, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			a.Title, a.Scope, string(a.Domain), nullInt(a.ProcessID), a.PlannedDate, a.Auditor,
+			a.Status, a.Findings, a.CreatedAt, a.CreatedBy, a.UpdatedBy,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 		a.ID = int(id)
-	}
-	return nil
+		return writeHistory(tx, domain.KindAudit, a.ID, domain.HistoryCreated, a.CreatedBy, nil, a)
+	})
 }
 
 func (r *AuditRepository) Update(a *domain.Audit) error {
-	res, err := r.db.Exec(`
-		UPDATE audits
-		SET title=?, scope=?, domain=?, planned_date=?, auditor=?, status=?, findings=?, created_at=?
-		WHERE id=?`,
-		a.Title, a.Scope, string(a.Domain), a.PlannedDate, a.Auditor,
-		a.Status, a.Findings, a.CreatedAt, a.ID,
-	)
-	if err != nil {
-		return err
-	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return repository.ErrNotFound
-	}
-	return nil
+	return inTx(r.db, func(tx *sql.Tx) error {
+		before, err := scanAudit(tx.QueryRow(`SELECT `+auditColumns+` FROM audits WHERE id = ? AND deleted_at IS NULL`, a.ID))
+		if err != nil {
+			return noRows(err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE audits
+			SET title=?, scope=?, domain=?, process_id=?, planned_date=?, auditor=?, status=?, findings=?, created_at=?, updated_by=?
+			WHERE id=? AND deleted_at IS NULL`,
+			a.Title, a.Scope, string(a.Domain), nullInt(a.ProcessID), a.PlannedDate, a.Auditor,
+			a.Status, a.Findings, a.CreatedAt, a.UpdatedBy, a.ID,
+		); err != nil {
+			return err
+		}
+		return writeHistory(tx, domain.KindAudit, a.ID, domain.HistoryUpdated, a.UpdatedBy, before, a)
+	})
 }
 
-func (r *AuditRepository) GetAll() ([]*domain.Audit, error) {
-	rows, err := r.db.Query(`
-		SELECT id, title, scope, domain, planned_date, auditor, status, findings, created_at
-		FROM audits`)
+func (r *AuditRepository) GetAll(includeDeleted bool) ([]*domain.Audit, error) {
+	rows, err := r.db.Query(`SELECT ` + auditColumns + ` FROM audits` + notDeleted(includeDeleted))
 	if err != nil {
 		return nil, err
 	}
@@ -352,44 +774,148 @@ func (r *AuditRepository) GetAll() ([]*domain.Audit, error) {
 
 	var out []*domain.Audit
 	for rows.Next() {
-		var d string
-		a := &domain.Audit{}
-		if err := rows.Scan(
-			&a.ID, &a.Title, &a.Scope, &d,
-			&a.PlannedDate, &a.Auditor, &a.Status,
-			&a.Findings, &a.CreatedAt,
-		); err != nil {
+		a, err := scanAudit(rows)
+		if err != nil {
 			return nil, err
 		}
-		a.Domain = domain.Domain(d)
 		out = append(out, a)
 	}
-	return out, nil
+	return out, rows.Err()
+}
+
+func (r *AuditRepository) List(q repository.AuditQuery) ([]*domain.Audit, int, error) {
+	var w where
+	if !q.IncludeDeleted {
+		w.add("deleted_at IS NULL")
+	}
+	if q.Domain != nil {
+		w.add("domain = ?", string(*q.Domain))
+	}
+	if q.Domains != nil {
+		w.addIn("domain", domainArgs(q.Domains)...)
+	}
+	if q.Status != nil {
+		w.add("status = ? COLLATE NOCASE", *q.Status)
+	}
+	if q.Auditor != nil {
+		w.add("auditor = ? COLLATE NOCASE", *q.Auditor)
+	}
+	if q.ProcessID != nil {
+		w.add("process_id = ?", *q.ProcessID)
+	}
+	if err := w.addDateRange("planned_date", q.Planned); err != nil {
+		return nil, 0, err
+	}
+	w.addSearch(q.Search, "title", "scope", "findings")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, auditSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audits`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+auditColumns+` FROM audits`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Audit, 0)
+	for rows.Next() {
+		a, err := scanAudit(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
 }
 
 func (r *AuditRepository) GetByID(id int) (*domain.Audit, error) {
-	row := r.db.QueryRow(`
-		SELECT id, title, scope, domain, planned_date, auditor, status, findings, created_at
-		FROM audits WHERE id = ?`, id)
+	row := r.db.QueryRow(`SELECT `+auditColumns+` FROM audits WHERE id = ? AND deleted_at IS NULL`, id)
+
+	a, err := scanAudit(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
 
+func (r *AuditRepository) GetDeletedByID(id int) (*domain.Audit, error) {
+	row := r.db.QueryRow(`SELECT `+auditColumns+` FROM audits WHERE id = ? AND deleted_at IS NOT NULL`, id)
+
+	a, err := scanAudit(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// Delete soft-deletes an audit unless active actions still point at it.
+func (r *AuditRepository) Delete(id int, deletedBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := ensureNoActiveActions(tx, "Audit", id); err != nil {
+			return err
+		}
+		return softDelete(tx, "audits", domain.KindAudit, id, deletedBy)
+	})
+}
+
+func (r *AuditRepository) Restore(id int, restoredBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return restore(tx, "audits", domain.KindAudit, id, restoredBy)
+	})
+}
+
+func scanAudit(row rowScanner) (*domain.Audit, error) {
 	var d string
+	var processID sqlNullInt
+	var process, deletedAt, deletedBy sql.NullString
 	a := &domain.Audit{}
 	if err := row.Scan(
-		&a.ID, &a.Title, &a.Scope, &d,
+		&a.ID, &a.Title, &a.Scope, &d, &processID, &process,
 		&a.PlannedDate, &a.Auditor, &a.Status,
-		&a.Findings, &a.CreatedAt,
+		&a.Findings, &a.CreatedAt, &a.CreatedBy, &a.UpdatedBy,
+		&deletedAt, &deletedBy,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
 		return nil, err
 	}
 	a.Domain = domain.Domain(d)
+	if processID.Valid {
+		id := processID.V
+		a.ProcessID = &id
+	}
+	a.Process = process.String
+	a.DeletedAt = deletedAt.String
+	a.DeletedBy = deletedBy.String
 	return a, nil
 }
 
 // ---------- Action repository ----------
 
+const actionColumns = `id, title, description, source_type, source_id, owner, due_date, status, created_at, updated_at, created_by, updated_by, deleted_at, deleted_by`
+
+var actionSortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"sourceType": "source_type",
+	"owner":      "owner",
+	"dueDate":    "due_date",
+	"status":     "status",
+	"createdAt":  "created_at",
+	"updatedAt":  "updated_at",
+}
+
 type ActionRepository struct {
 	db *sql.DB
 }
@@ -399,44 +925,47 @@ func NewActionRepository(db *sql.DB) *ActionRepository {
 }
 
 func (r *ActionRepository) Create(a *domain.Action) error {
-	res, err := r.db.Exec(`
-		INSERT INTO actions (title, description, source_type, source_id, owner, due_date, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		a.Title, a.Description, a.SourceType, a.SourceID,
-		a.Owner, a.DueDate, a.Status, a.CreatedAt, a.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	id, err := res.LastInsertId()
-	if err == nil {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO actions (title, description, source_type, source_id, owner, due_date, status, created_at, updated_at, created_by, updated_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			a.Title, a.Description, a.SourceType, a.SourceID,
+			a.Owner, a.DueDate, a.Status, a.CreatedAt, a.UpdatedAt,
+			a.CreatedBy, a.UpdatedBy,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 		a.ID = int(id)
-	}
-	return nil
+		return writeHistory(tx, domain.KindAction, a.ID, domain.HistoryCreated, a.CreatedBy, nil, a)
+	})
 }
 
 func (r *ActionRepository) Update(a *domain.Action) error {
-	res, err := r.db.Exec(`
-		UPDATE actions
-		SET title=?, description=?, source_type=?, source_id=?, owner=?, due_date=?, status=?, created_at=?, updated_at=?
-		WHERE id=?`,
-		a.Title, a.Description, a.SourceType, a.SourceID,
-		a.Owner, a.DueDate, a.Status, a.CreatedAt, a.UpdatedAt, a.ID,
-	)
-	if err != nil {
-		return err
-	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return repository.ErrNotFound
-	}
-	return nil
+	return inTx(r.db, func(tx *sql.Tx) error {
+		before, err := scanAction(tx.QueryRow(`SELECT `+actionColumns+` FROM actions WHERE id = ? AND deleted_at IS NULL`, a.ID))
+		if err != nil {
+			return noRows(err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE actions
+			SET title=?, description=?, source_type=?, source_id=?, owner=?, due_date=?, status=?, created_at=?, updated_at=?, updated_by=?
+			WHERE id=? AND deleted_at IS NULL`,
+			a.Title, a.Description, a.SourceType, a.SourceID,
+			a.Owner, a.DueDate, a.Status, a.CreatedAt, a.UpdatedAt, a.UpdatedBy, a.ID,
+		); err != nil {
+			return err
+		}
+		return writeHistory(tx, domain.KindAction, a.ID, domain.HistoryUpdated, a.UpdatedBy, before, a)
+	})
 }
 
-func (r *ActionRepository) GetAll() ([]*domain.Action, error) {
-	rows, err := r.db.Query(`
-		SELECT id, title, description, source_type, source_id, owner, due_date, status, created_at, updated_at
-		FROM actions`)
+func (r *ActionRepository) GetAll(includeDeleted bool) ([]*domain.Action, error) {
+	rows, err := r.db.Query(`SELECT ` + actionColumns + ` FROM actions` + notDeleted(includeDeleted))
 	if err != nil {
 		return nil, err
 	}
@@ -444,36 +973,152 @@ func (r *ActionRepository) GetAll() ([]*domain.Action, error) {
 
 	var out []*domain.Action
 	for rows.Next() {
-		a := &domain.Action{}
-		if err := rows.Scan(
-			&a.ID, &a.Title, &a.Description, &a.SourceType, &a.SourceID,
-			&a.Owner, &a.DueDate, &a.Status, &a.CreatedAt, &a.UpdatedAt,
-		); err != nil {
+		a, err := scanAction(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, a)
 	}
-	return out, nil
+	return out, rows.Err()
+}
+
+func (r *ActionRepository) List(q repository.ActionQuery) ([]*domain.Action, int, error) {
+	var w where
+	if !q.IncludeDeleted {
+		w.add("deleted_at IS NULL")
+	}
+	if q.Status != nil {
+		w.add("status = ? COLLATE NOCASE", *q.Status)
+	}
+	if q.SourceType != nil {
+		w.add("source_type = ? COLLATE NOCASE", *q.SourceType)
+	}
+	if q.SourceID != nil {
+		w.add("source_id = ?", *q.SourceID)
+	}
+	if q.Owner != nil {
+		w.add("owner = ? COLLATE NOCASE", *q.Owner)
+	}
+	if q.Domains != nil {
+		// Actions take the domain of the record they were raised from.
+		args := domainArgs(q.Domains)
+		var risks, incidents, audits where
+		risks.addIn("domain", args...)
+		incidents.addIn("domain", args...)
+		audits.addIn("domain", args...)
+		w.add(`((source_type = 'Risk' AND source_id IN (SELECT id FROM risks`+risks.sql()+`))
+			OR (source_type = 'Incident' AND source_id IN (SELECT id FROM incidents`+incidents.sql()+`))
+			OR (source_type = 'Audit' AND source_id IN (SELECT id FROM audits`+audits.sql()+`)))`,
+			append(append(risks.args, incidents.args...), audits.args...)...)
+	}
+	if q.ProcessID != nil {
+		w.add(`((source_type = 'Risk' AND source_id IN (SELECT id FROM risks WHERE process_id = ?))
+			OR (source_type = 'Incident' AND source_id IN (SELECT id FROM incidents
+				WHERE related_risk_id IN (SELECT id FROM risks WHERE process_id = ?)))
+			OR (source_type = 'Audit' AND source_id IN (SELECT id FROM audits WHERE process_id = ?)))`,
+			*q.ProcessID, *q.ProcessID, *q.ProcessID)
+	}
+	if err := w.addDateRange("due_date", q.Due); err != nil {
+		return nil, 0, err
+	}
+	w.addSearch(q.Search, "title", "description")
+
+	tail, pageArgs, err := orderAndLimit(q.Page, actionSortColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM actions`+w.sql(), w.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+actionColumns+` FROM actions`+w.sql()+tail, append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Action, 0)
+	for rows.Next() {
+		a, err := scanAction(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
 }
 
 func (r *ActionRepository) GetByID(id int) (*domain.Action, error) {
-	row := r.db.QueryRow(`
-		SELECT id, title, description, source_type, source_id, owner, due_date, status, created_at, updated_at
-		FROM actions WHERE id = ?`, id)
+	row := r.db.QueryRow(`SELECT `+actionColumns+` FROM actions WHERE id = ? AND deleted_at IS NULL`, id)
 
+	a, err := scanAction(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *ActionRepository) GetDeletedByID(id int) (*domain.Action, error) {
+	row := r.db.QueryRow(`SELECT `+actionColumns+` FROM actions WHERE id = ? AND deleted_at IS NOT NULL`, id)
+
+	a, err := scanAction(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *ActionRepository) Delete(id int, deletedBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return softDelete(tx, "actions", domain.KindAction, id, deletedBy)
+	})
+}
+
+func (r *ActionRepository) Restore(id int, restoredBy string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return restore(tx, "actions", domain.KindAction, id, restoredBy)
+	})
+}
+
+func scanAction(row rowScanner) (*domain.Action, error) {
+	var deletedAt, deletedBy sql.NullString
 	a := &domain.Action{}
 	if err := row.Scan(
 		&a.ID, &a.Title, &a.Description, &a.SourceType, &a.SourceID,
 		&a.Owner, &a.DueDate, &a.Status, &a.CreatedAt, &a.UpdatedAt,
+		&a.CreatedBy, &a.UpdatedBy, &deletedAt, &deletedBy,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
 		return nil, err
 	}
+	a.DeletedAt = deletedAt.String
+	a.DeletedBy = deletedBy.String
 	return a, nil
 }
 
+// ensureNoActiveActions returns repository.ErrInUse when active actions are
+// still sourced from the given record.
+func ensureNoActiveActions(tx *sql.Tx, sourceType string, id int) error {
+	var n int
+	if err := tx.QueryRow(`
+		SELECT COUNT(*) FROM actions WHERE source_type = ? AND source_id = ? AND deleted_at IS NULL`,
+		sourceType, id).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: %s %d is linked to %d action(s)",
+			repository.ErrInUse, strings.ToLower(sourceType), id, n)
+	}
+	return nil
+}
+
 // small helper for nullable integer
 type sqlNullInt struct {
 	Valid bool
@@ -0,0 +1,260 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- User repository ----------
+
+const userColumns = `id, username, display_name, email, password_hash, active, created_at`
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(u *domain.User) error {
+	res, err := r.db.Exec(`
+		INSERT INTO users (username, display_name, email, password_hash, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		u.Username, u.DisplayName, u.Email, u.PasswordHash, u.Active, u.CreatedAt,
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		u.ID = int(id)
+	}
+	return nil
+}
+
+func (r *UserRepository) Update(u *domain.User) error {
+	res, err := r.db.Exec(`
+		UPDATE users SET username=?, display_name=?, email=?, password_hash=?, active=?
+		WHERE id=?`,
+		u.Username, u.DisplayName, u.Email, u.PasswordHash, u.Active, u.ID,
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) GetAll() ([]*domain.User, error) {
+	rows, err := r.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.User, 0)
+	byID := make(map[int]*domain.User)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+		byID[u.ID] = u
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	roleRows, err := r.db.Query(`SELECT user_id, role, domain FROM user_roles ORDER BY user_id, role, domain`)
+	if err != nil {
+		return nil, err
+	}
+	defer roleRows.Close()
+	for roleRows.Next() {
+		var userID int
+		var ra domain.RoleAssignment
+		if err := roleRows.Scan(&userID, &ra.Role, &ra.Domain); err != nil {
+			return nil, err
+		}
+		if u, ok := byID[userID]; ok {
+			u.Roles = append(u.Roles, ra)
+		}
+	}
+	return out, roleRows.Err()
+}
+
+func (r *UserRepository) GetByID(id int) (*domain.User, error) {
+	return r.getOne(`SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (r *UserRepository) GetByUsername(username string) (*domain.User, error) {
+	return r.getOne(`SELECT `+userColumns+` FROM users WHERE username = ? COLLATE NOCASE`, username)
+}
+
+func (r *UserRepository) getOne(query string, args ...any) (*domain.User, error) {
+	u, err := scanUser(r.db.QueryRow(query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	if u.Roles, err = r.roles(u.ID); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *UserRepository) roles(userID int) ([]domain.RoleAssignment, error) {
+	rows, err := r.db.Query(`SELECT role, domain FROM user_roles WHERE user_id = ? ORDER BY role, domain`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.RoleAssignment, 0)
+	for rows.Next() {
+		var ra domain.RoleAssignment
+		if err := rows.Scan(&ra.Role, &ra.Domain); err != nil {
+			return nil, err
+		}
+		out = append(out, ra)
+	}
+	return out, rows.Err()
+}
+
+func (r *UserRepository) SetRoles(userID int, roles []domain.RoleAssignment) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM user_roles WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		for _, ra := range roles {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO user_roles (user_id, role, domain) VALUES (?, ?, ?)`,
+				userID, ra.Role, ra.Domain); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	u := &domain.User{Roles: make([]domain.RoleAssignment, 0)}
+	if err := row.Scan(&u.ID, &u.Username, &u.DisplayName, &u.Email, &u.PasswordHash, &u.Active, &u.CreatedAt); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// ---------- Token repository ----------
+
+const tokenColumns = `id, user_id, name, kind, token_hash, created_at, expires_at, last_used_at`
+
+type TokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) Create(t *domain.APIToken) error {
+	res, err := r.db.Exec(`
+		INSERT INTO api_tokens (user_id, name, kind, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		t.UserID, t.Name, t.Kind, t.TokenHash, t.CreatedAt, nullString(t.ExpiresAt),
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		t.ID = int(id)
+	}
+	return nil
+}
+
+func (r *TokenRepository) GetByHash(hash string) (*domain.APIToken, error) {
+	t, err := scanToken(r.db.QueryRow(`SELECT `+tokenColumns+` FROM api_tokens WHERE token_hash = ?`, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TokenRepository) ListByUser(userID int) ([]*domain.APIToken, error) {
+	rows, err := r.db.Query(`SELECT `+tokenColumns+` FROM api_tokens WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.APIToken, 0)
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TokenRepository) Touch(id int, usedAt string) error {
+	_, err := r.db.Exec(`UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, usedAt, id)
+	return err
+}
+
+func (r *TokenRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanToken(row rowScanner) (*domain.APIToken, error) {
+	var expiresAt, lastUsedAt sql.NullString
+	t := &domain.APIToken{}
+	if err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Kind, &t.TokenHash, &t.CreatedAt, &expiresAt, &lastUsedAt); err != nil {
+		return nil, err
+	}
+	t.ExpiresAt = expiresAt.String
+	t.LastUsedAt = lastUsedAt.String
+	return t, nil
+}
+
+// ---------- helpers ----------
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// uniqueViolation maps SQLite unique constraint failures to
+// repository.ErrDuplicate.
+func uniqueViolation(err error) error {
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return repository.ErrDuplicate
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ---------- Webhook repository ----------
+
+const (
+	webhookColumns  = `id, url, events, description, secret, active, created_at, created_by, updated_at, updated_by`
+	deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.created_at, d.delivered_at`
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(w *domain.Webhook) error {
+	events, err := json.Marshal(w.Events)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO webhooks (url, events, description, secret, active, created_at, created_by, updated_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		w.URL, string(events), w.Description, w.Secret, w.Active, w.CreatedAt, w.CreatedBy, w.UpdatedAt, w.UpdatedBy,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		w.ID = int(id)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Update(w *domain.Webhook) error {
+	events, err := json.Marshal(w.Events)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`
+		UPDATE webhooks SET url=?, events=?, description=?, secret=?, active=?, updated_at=?, updated_by=?
+		WHERE id=?`,
+		w.URL, string(events), w.Description, w.Secret, w.Active, w.UpdatedAt, w.UpdatedBy, w.ID,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(id int) (*domain.Webhook, error) {
+	w, err := scanWebhook(r.db.QueryRow(`SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id))
+	if err != nil {
+		return nil, noRows(err)
+	}
+	return w, nil
+}
+
+func (r *WebhookRepository) List() ([]*domain.Webhook, error) {
+	rows, err := r.db.Query(`SELECT ` + webhookColumns + ` FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.Webhook, 0)
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *WebhookRepository) Delete(id int) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			DELETE FROM webhook_attempts WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE webhook_id = ?)`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	w := &domain.Webhook{}
+	var events string
+	if err := row.Scan(&w.ID, &w.URL, &events, &w.Description, &w.Secret, &w.Active,
+		&w.CreatedAt, &w.CreatedBy, &w.UpdatedAt, &w.UpdatedBy); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &w.Events); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ---------- Deliveries ----------
+
+func (r *WebhookRepository) Enqueue(d *domain.WebhookDelivery) error {
+	if d.Status == "" {
+		d.Status = domain.DeliveryPending
+	}
+	if d.NextAttemptAt == "" {
+		d.NextAttemptAt = d.CreatedAt
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		d.WebhookID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if added, err := res.RowsAffected(); err != nil || added == 0 {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		d.ID = int(id)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Due(now string, limit int) ([]*domain.WebhookDelivery, error) {
+	return r.queryDeliveries(`
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active = 1
+		ORDER BY d.next_attempt_at, d.id LIMIT ?`,
+		domain.DeliveryPending, now, limit)
+}
+
+func (r *WebhookRepository) RecordAttempt(deliveryID int, a domain.WebhookAttempt, status, nextAttemptAt string) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var deliveredAt any
+		if status == domain.DeliveryDelivered {
+			deliveredAt = a.AttemptedAt
+		}
+		res, err := tx.Exec(`
+			UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, delivered_at = ?
+			WHERE id = ?`,
+			status, a.Attempt, nextAttemptAt, deliveredAt, deliveryID)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			return repository.ErrNotFound
+		}
+		_, err = tx.Exec(`
+			INSERT INTO webhook_attempts (delivery_id, attempt, attempted_at, status_code, duration_ms, error)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			deliveryID, a.Attempt, a.AttemptedAt, a.StatusCode, a.DurationMs, a.Error)
+		return err
+	})
+}
+
+func (r *WebhookRepository) Deliveries(webhookID int, page repository.Page) ([]*domain.WebhookDelivery, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = ?`, webhookID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries d WHERE d.webhook_id = ? ORDER BY d.id DESC`
+	args := []any{webhookID}
+	if page.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, page.Limit, page.Offset)
+	}
+	out, err := r.queryDeliveries(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadAttempts(out); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *WebhookRepository) queryDeliveries(query string, args ...any) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*domain.WebhookDelivery, 0)
+	for rows.Next() {
+		d := &domain.WebhookDelivery{AttemptLog: make([]domain.WebhookAttempt, 0)}
+		var payload string
+		var deliveredAt sql.NullString
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.CreatedAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		d.Payload = json.RawMessage(payload)
+		d.DeliveredAt = deliveredAt.String
+		if d.Status != domain.DeliveryPending {
+			d.NextAttemptAt = ""
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// loadAttempts fills in the attempt log of each delivery.
+func (r *WebhookRepository) loadAttempts(ds []*domain.WebhookDelivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.WebhookDelivery, len(ds))
+	ids := make([]any, len(ds))
+	for i, d := range ds {
+		byID[d.ID] = d
+		ids[i] = d.ID
+	}
+	w := &where{}
+	w.addIn("delivery_id", ids...)
+	rows, err := r.db.Query(`
+		SELECT delivery_id, attempt, attempted_at, status_code, duration_ms, error
+		FROM webhook_attempts`+w.sql()+` ORDER BY delivery_id, attempt`, w.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var a domain.WebhookAttempt
+		if err := rows.Scan(&id, &a.Attempt, &a.AttemptedAt, &a.StatusCode, &a.DurationMs, &a.Error); err != nil {
+			return err
+		}
+		byID[id].AttemptLog = append(byID[id].AttemptLog, a)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,104 @@
+// Package scheduler runs periodic background jobs inside the server process
+// and keeps the outcome of each job's last run for /health.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a named task run every Interval. Run returns a short summary of
+// what it did.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (string, error)
+}
+
+// JobStatus reports a job's schedule and its last run.
+// swagger:model JobStatus
+type JobStatus struct {
+	Name       string `json:"name"`
+	Interval   string `json:"interval"`             // Go duration, e.g. "1h0m0s"
+	LastRun    string `json:"lastRun,omitempty"`    // Start of the last run (RFC3339)
+	LastResult string `json:"lastResult,omitempty"` // Summary returned by the last successful run
+	LastError  string `json:"lastError,omitempty"`  // Error of the last run, if it failed
+	NextRun    string `json:"nextRun,omitempty"`
+}
+
+type Scheduler struct {
+	mu     sync.Mutex
+	jobs   []Job
+	status map[string]*JobStatus
+}
+
+func New() *Scheduler {
+	return &Scheduler{status: make(map[string]*JobStatus)}
+}
+
+// Add registers a job. Jobs with a non-positive interval are ignored.
+func (s *Scheduler) Add(job Job) {
+	if job.Interval <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+	s.status[job.Name] = &JobStatus{Name: job.Name, Interval: job.Interval.String()}
+}
+
+// Start runs every job once immediately and then on its interval until ctx
+// is cancelled. It does not block.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	start := time.Now()
+	result, err := job.Run(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.status[job.Name]
+	st.LastRun = start.Format(time.RFC3339)
+	st.NextRun = start.Add(job.Interval).Format(time.RFC3339)
+	if err != nil {
+		st.LastError = err.Error()
+		log.Printf("job %s failed: %v", job.Name, err)
+		return
+	}
+	st.LastResult, st.LastError = result, ""
+	log.Printf("job %s: %s", job.Name, result)
+}
+
+// Status returns a snapshot of all jobs in registration order.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out = append(out, *s.status[job.Name])
+	}
+	return out
+}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"github.com/xenakil/integraflow-ims/internal/auth"
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
@@ -14,6 +18,8 @@ type ActionService struct {
 	riskRepo  repository.RiskRepository
 	incRepo   repository.IncidentRepository
 	auditRepo repository.AuditRepository
+	history   repository.HistoryRepository
+	events    *EventRelay
 }
 
 func NewActionService(
@@ -21,12 +27,16 @@ func NewActionService(
 	riskRepo repository.RiskRepository,
 	incRepo repository.IncidentRepository,
 	auditRepo repository.AuditRepository,
+	history repository.HistoryRepository,
+	events *EventRelay,
 ) *ActionService {
 	return &ActionService{
 		repo:      repo,
 		riskRepo:  riskRepo,
 		incRepo:   incRepo,
 		auditRepo: auditRepo,
+		history:   history,
+		events:    events,
 	}
 }
 
@@ -40,44 +50,65 @@ type CreateActionInput struct {
 }
 
 type ActionListFilter struct {
-	Status     *string
-	SourceType *string
+	Status         *string
+	SourceType     *string
+	SourceID       *int
+	ProcessID      *int // Process of the source record
+	Owner          *string
+	Due            repository.DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           repository.Page
 }
 
-func (s *ActionService) CreateAction(in CreateActionInput) (*domain.Action, error) {
+func (s *ActionService) CreateAction(ctx context.Context, in CreateActionInput) (*domain.Action, error) {
 	if strings.TrimSpace(in.Title) == "" || in.SourceID == 0 || strings.TrimSpace(in.SourceType) == "" {
 		return nil, fmt.Errorf("%w: title, sourceType and sourceId are required", ErrValidation)
 	}
+	in.DueDate = strings.TrimSpace(in.DueDate)
+	if in.DueDate != "" && !validDate(in.DueDate) {
+		return nil, fmt.Errorf("%w: dueDate must be a date (YYYY-MM-DD)", ErrValidation)
+	}
 
 	var canonicalType string
+	var dom domain.Domain
 	switch strings.ToLower(strings.TrimSpace(in.SourceType)) {
 	case "risk":
 		canonicalType = "Risk"
-		if _, err := s.riskRepo.GetByID(in.SourceID); err != nil {
+		r, err := s.riskRepo.GetByID(in.SourceID)
+		if err != nil {
 			if err == repository.ErrNotFound {
 				return nil, fmt.Errorf("%w: source risk not found", ErrValidation)
 			}
 			return nil, err
 		}
+		dom = r.Domain
 	case "incident":
 		canonicalType = "Incident"
-		if _, err := s.incRepo.GetByID(in.SourceID); err != nil {
+		inc, err := s.incRepo.GetByID(in.SourceID)
+		if err != nil {
 			if err == repository.ErrNotFound {
 				return nil, fmt.Errorf("%w: source incident not found", ErrValidation)
 			}
 			return nil, err
 		}
+		dom = inc.Domain
 	case "audit":
 		canonicalType = "Audit"
-		if _, err := s.auditRepo.GetByID(in.SourceID); err != nil {
+		audit, err := s.auditRepo.GetByID(in.SourceID)
+		if err != nil {
 			if err == repository.ErrNotFound {
 				return nil, fmt.Errorf("%w: source audit not found", ErrValidation)
 			}
 			return nil, err
 		}
+		dom = audit.Domain
 	default:
 		return nil, fmt.Errorf("%w: sourceType must be risk, incident or audit", ErrValidation)
 	}
+	if err := authorize(ctx, permContribute, dom, "raising actions"); err != nil {
+		return nil, err
+	}
 
 	now := time.Now().Format(time.RFC3339)
 
@@ -91,31 +122,34 @@ func (s *ActionService) CreateAction(in CreateActionInput) (*domain.Action, erro
 		Status:      "Open",
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		CreatedBy:   auth.Actor(ctx),
+		UpdatedBy:   auth.Actor(ctx),
 	}
 
 	if err := s.repo.Create(act); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
 	return act, nil
 }
 
-func (s *ActionService) ListActions(filter ActionListFilter) ([]*domain.Action, error) {
-	all, err := s.repo.GetAll()
+func (s *ActionService) ListActions(ctx context.Context, filter ActionListFilter) ([]*domain.Action, int, error) {
+	domains, err := readScope(ctx, nil, "listing actions")
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-
-	out := make([]*domain.Action, 0)
-	for _, a := range all {
-		if filter.Status != nil && !strings.EqualFold(a.Status, *filter.Status) {
-			continue
-		}
-		if filter.SourceType != nil && !strings.EqualFold(a.SourceType, *filter.SourceType) {
-			continue
-		}
-		out = append(out, a)
-	}
-	return out, nil
+	return s.repo.List(repository.ActionQuery{
+		Domains:        domains,
+		Status:         filter.Status,
+		SourceType:     filter.SourceType,
+		SourceID:       filter.SourceID,
+		ProcessID:      filter.ProcessID,
+		Owner:          filter.Owner,
+		Due:            filter.Due,
+		Search:         filter.Search,
+		IncludeDeleted: filter.IncludeDeleted,
+		Page:           filter.Page,
+	})
 }
 
 type UpdateActionInput struct {
@@ -123,29 +157,311 @@ type UpdateActionInput struct {
 	DueDate *string
 }
 
-func (s *ActionService) UpdateAction(id int, in UpdateActionInput) (*domain.Action, error) {
+func (s *ActionService) UpdateAction(ctx context.Context, id int, in UpdateActionInput) (*domain.Action, error) {
 	a, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorize(ctx, a, permContribute, "editing actions"); err != nil {
+		return nil, err
+	}
+	from := a.Status
 
 	if in.Status != nil {
-		st := strings.TrimSpace(*in.Status)
-		normalized := strings.Title(strings.ToLower(st))
-		switch normalized {
-		case "Open", "In Progress", "Done", "Overdue":
-		default:
+		normalized, ok := domain.ActionWorkflow.Normalize(*in.Status)
+		if !ok {
 			return nil, fmt.Errorf("%w: invalid action status", ErrValidation)
 		}
 		a.Status = normalized
 	}
 	if in.DueDate != nil {
-		a.DueDate = *in.DueDate
+		due := strings.TrimSpace(*in.DueDate)
+		if due != "" && !validDate(due) {
+			return nil, fmt.Errorf("%w: dueDate must be a date (YYYY-MM-DD)", ErrValidation)
+		}
+		a.DueDate = due
+	}
+	if err := domain.ActionWorkflow.Check(from, a.Status, a); err != nil {
+		return nil, err
 	}
 	a.UpdatedAt = time.Now().Format(time.RFC3339)
+	a.UpdatedBy = auth.Actor(ctx)
 
 	if err := s.repo.Update(a); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
+	if a.SourceType == "Risk" && a.Status == "Done" && from != "Done" {
+		s.mitigateTreatedRisk(auth.SystemContext(ctx), a.SourceID)
+	}
+	if in.DueDate != nil && a.Status == "Overdue" && validDate(a.DueDate) && a.DueDate >= time.Now().Format(time.DateOnly) {
+		s.reopenRescheduled(auth.SystemContext(ctx), a)
+	}
 	return a, nil
 }
+
+// reopenRescheduled moves an Overdue action whose due date was moved to
+// today or later back to the status it was marked overdue from, Open if
+// history doesn't tell. It runs as the system user, like MarkOverdue, so
+// the change shows up in history under "system". The new due date is
+// already saved, so failures are only logged.
+func (s *ActionService) reopenRescheduled(ctx context.Context, a *domain.Action) {
+	entries, err := s.history.List(domain.KindAction, a.ID)
+	if err != nil {
+		log.Printf("action %d: finding status before overdue: %v", a.ID, err)
+	}
+	to := statusBeforeOverdue(entries)
+
+	if err := domain.ActionWorkflow.Check(a.Status, to, a); err != nil {
+		log.Printf("action %d: rescheduled but %v", a.ID, err)
+		return
+	}
+	from := a.Status
+	a.Status = to
+	a.UpdatedAt = time.Now().Format(time.RFC3339)
+	a.UpdatedBy = auth.Actor(ctx)
+	if err := s.repo.Update(a); err != nil {
+		log.Printf("action %d: reopening after reschedule: %v", a.ID, err)
+		a.Status = from
+		return
+	}
+	s.events.flush(ctx)
+}
+
+// mitigateTreatedRisk moves a risk with a treatment plan to avoid, reduce or
+// transfer it to Mitigated once every action raised from it is Done. It runs
+// as the system user, since whoever completed the last action need not be
+// allowed to change the risk's status, so the change shows up in history
+// under "system". The action is already saved, so failures are only logged.
+func (s *ActionService) mitigateTreatedRisk(ctx context.Context, riskID int) {
+	r, err := s.riskRepo.GetByID(riskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("risk %d: checking treatment: %v", riskID, err)
+		return
+	}
+	if r.Treatment == nil || r.Treatment.Option == domain.TreatmentAccept || r.Status == "Mitigated" {
+		return
+	}
+
+	q := riskActionsQuery(riskID, nil)
+	q.Page.Limit = 1
+	_, total, err := s.repo.List(q)
+	if err != nil {
+		log.Printf("risk %d: checking treatment: %v", riskID, err)
+		return
+	}
+	done := "Done"
+	q = riskActionsQuery(riskID, &done)
+	q.Page.Limit = 1
+	_, completed, err := s.repo.List(q)
+	if err != nil {
+		log.Printf("risk %d: checking treatment: %v", riskID, err)
+		return
+	}
+	if total == 0 || completed < total {
+		return
+	}
+
+	if err := domain.RiskWorkflow.Check(r.Status, "Mitigated", r); err != nil {
+		log.Printf("risk %d: treatment complete but %v", riskID, err)
+		return
+	}
+	r.Status = "Mitigated"
+	r.UpdatedBy = auth.Actor(ctx)
+	if err := s.riskRepo.Update(r); err != nil {
+		log.Printf("risk %d: marking mitigated: %v", riskID, err)
+		return
+	}
+	s.events.flush(ctx)
+}
+
+func (s *ActionService) DeleteAction(ctx context.Context, id int) error {
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, a, permApprove, "deleting actions"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id, auth.Actor(ctx))
+}
+
+func (s *ActionService) RestoreAction(ctx context.Context, id int) (*domain.Action, error) {
+	a, err := s.repo.GetDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, a, permApprove, "restoring actions"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restore(id, auth.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// MarkOverdue moves every Open or In Progress action whose due date is
+// before today to Overdue and returns how many were changed. Actions without
+// a parseable due date are skipped. It is run by the scheduler as the
+// system user, so the changes show up in history under "system".
+func (s *ActionService) MarkOverdue(ctx context.Context, today time.Time) (int, error) {
+	actions, _, err := s.repo.List(repository.ActionQuery{
+		Due: repository.DateRange{To: today.AddDate(0, 0, -1).Format(time.DateOnly)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, a := range actions {
+		if a.Status != "Open" && a.Status != "In Progress" {
+			continue
+		}
+		// The query compares text, so empty and free-form due dates match too.
+		if _, err := time.Parse(time.DateOnly, a.DueDate); err != nil {
+			continue
+		}
+		if err := s.authorize(ctx, a, permContribute, "marking actions overdue"); err != nil {
+			return n, err
+		}
+		if err := domain.ActionWorkflow.Check(a.Status, "Overdue", a); err != nil {
+			return n, err
+		}
+		a.Status = "Overdue"
+		a.UpdatedAt = time.Now().Format(time.RFC3339)
+		a.UpdatedBy = auth.Actor(ctx)
+		if err := s.repo.Update(a); err != nil {
+			return n, fmt.Errorf("action %d: %w", a.ID, err)
+		}
+		s.events.flush(ctx)
+		n++
+	}
+	return n, nil
+}
+
+// RemindDueSoon sends r a reminder for every Open or In Progress action due
+// from today to days ahead and returns how many reminders were new. It is
+// run by the scheduler as the system user.
+func (s *ActionService) RemindDueSoon(ctx context.Context, today time.Time, days int, r Reminder) (int, error) {
+	actions, _, err := s.repo.List(repository.ActionQuery{
+		Due: repository.DateRange{From: today.Format(time.DateOnly), To: today.AddDate(0, 0, days).Format(time.DateOnly)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, a := range actions {
+		if a.Status != "Open" && a.Status != "In Progress" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, a.DueDate); err != nil {
+			continue
+		}
+		if err := s.authorize(ctx, a, permRead, "reminding action owners"); err != nil {
+			return n, err
+		}
+		dom, err := s.sourceDomain(a)
+		if err != nil {
+			return n, fmt.Errorf("action %d: %w", a.ID, err)
+		}
+		added, err := r.Remind(ctx, newEvent(ctx, domain.EventActionDueSoon, domain.KindAction, a.ID, dom, a))
+		if err != nil {
+			return n, fmt.Errorf("action %d: %w", a.ID, err)
+		}
+		if added {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// statusBeforeOverdue returns the status an action was in when it was last
+// marked Overdue: In Progress or, by default, Open.
+func statusBeforeOverdue(entries []*domain.HistoryEntry) string {
+	for i := len(entries) - 1; i >= 0; i-- {
+		for _, c := range entries[i].Changes {
+			if c.Field == "status" && c.After == "Overdue" {
+				if c.Before == "In Progress" {
+					return "In Progress"
+				}
+				return "Open"
+			}
+		}
+	}
+	return "Open"
+}
+
+// ActionHistory returns the change history of an action, including deleted
+// ones.
+func (s *ActionService) ActionHistory(ctx context.Context, id int) ([]*domain.HistoryEntry, error) {
+	a, err := s.repo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		a, err = s.repo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, a, permRead, "viewing action history"); err != nil {
+		return nil, err
+	}
+	return s.history.List(domain.KindAction, id)
+}
+
+// authorize checks p against the domain of the record the action was raised
+// from. The source may itself be soft-deleted.
+func (s *ActionService) authorize(ctx context.Context, a *domain.Action, p permission, what string) error {
+	dom, err := s.sourceDomain(a)
+	if err != nil {
+		return err
+	}
+	return authorize(ctx, p, dom, what)
+}
+
+func (s *ActionService) sourceDomain(a *domain.Action) (domain.Domain, error) {
+	return sourceDomain(s.riskRepo, s.incRepo, s.auditRepo, a)
+}
+
+// sourceDomain returns the domain of the record a was raised from, which
+// may be soft-deleted.
+func sourceDomain(
+	risks repository.RiskRepository,
+	incidents repository.IncidentRepository,
+	audits repository.AuditRepository,
+	a *domain.Action,
+) (domain.Domain, error) {
+	switch a.SourceType {
+	case "Risk":
+		r, err := risks.GetByID(a.SourceID)
+		if errors.Is(err, repository.ErrNotFound) {
+			r, err = risks.GetDeletedByID(a.SourceID)
+		}
+		if err != nil {
+			return "", err
+		}
+		return r.Domain, nil
+	case "Incident":
+		inc, err := incidents.GetByID(a.SourceID)
+		if errors.Is(err, repository.ErrNotFound) {
+			inc, err = incidents.GetDeletedByID(a.SourceID)
+		}
+		if err != nil {
+			return "", err
+		}
+		return inc.Domain, nil
+	case "Audit":
+		audit, err := audits.GetByID(a.SourceID)
+		if errors.Is(err, repository.ErrNotFound) {
+			audit, err = audits.GetDeletedByID(a.SourceID)
+		}
+		if err != nil {
+			return "", err
+		}
+		return audit.Domain, nil
+	default:
+		return "", fmt.Errorf("unknown action source type %q", a.SourceType)
+	}
+}
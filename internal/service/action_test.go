@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func TestCompletingTreatmentMitigatesRiskAsSystem(t *testing.T) {
+	env := newTestEnv(t)
+	contributor := as(domain.RoleContributor, domain.DomainQuality)
+
+	r, err := env.risks.CreateRisk(contributor, CreateRiskInput{
+		Title: "Supplier delivers late", Process: "Production", Domain: "Quality",
+		Likelihood: 3, Impact: 3, Owner: "bob",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.risks.SetTreatment(contributor, r.ID, TreatmentInput{Option: "reduce", Justification: "Second supplier"}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, title := range []string{"Qualify second supplier", "Add buffer stock"} {
+		a, err := env.actions.CreateAction(contributor, CreateActionInput{Title: title, SourceType: "risk", SourceID: r.ID, Owner: "bob", DueDate: "2030-01-31"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, a.ID)
+	}
+
+	// The contributor can't mitigate the risk themselves.
+	if _, err := env.risks.UpdateStatus(contributor, r.ID, "Mitigated"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("UpdateStatus by a contributor = %v, want ErrForbidden", err)
+	}
+
+	done := "Done"
+	for i, id := range ids {
+		if _, err := env.actions.UpdateAction(contributor, id, UpdateActionInput{Status: &done}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := env.risks.GetRisk(manager, r.ID, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last := i == len(ids)-1; (got.Status == "Mitigated") != last {
+			t.Fatalf("after %d of %d actions done the risk is %s", i+1, len(ids), got.Status)
+		}
+	}
+
+	got, err := env.risks.GetRisk(manager, r.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UpdatedBy != "system" {
+		t.Errorf("risk updated by %q, want system", got.UpdatedBy)
+	}
+	entries, err := env.risks.RiskHistory(manager, r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := entries[len(entries)-1]; last.Actor != "system" {
+		t.Errorf("mitigation recorded in history by %q, want system", last.Actor)
+	}
+	events := env.events.Of(domain.EventRiskStatusChanged)
+	if len(events) != 1 || events[0].Actor != "system" || events[0].To != "Mitigated" {
+		t.Errorf("status change events %+v, want one to Mitigated by system", events)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// AspectService manages the environmental aspects and impacts register,
+// which belongs to the Environment domain.
+type AspectService struct {
+	repo      repository.AspectRepository
+	processes repository.ProcessRepository
+}
+
+func NewAspectService(repo repository.AspectRepository, processes repository.ProcessRepository) *AspectService {
+	return &AspectService{repo: repo, processes: processes}
+}
+
+type CreateAspectInput struct {
+	ProcessID         *int // Optional process the activity belongs to
+	Activity          string
+	Aspect            string
+	Impact            string
+	Condition         string // Normal if empty
+	Severity          int
+	Frequency         int
+	LegalRequirements []string
+	Controls          []domain.RiskControl
+	Owner             string
+}
+
+type AspectListFilter struct {
+	ProcessID   *int
+	Condition   *string
+	Significant *bool
+	Owner       *string
+	Search      string
+	Page        repository.Page
+}
+
+func (s *AspectService) CreateAspect(ctx context.Context, in CreateAspectInput) (*domain.EnvironmentalAspect, error) {
+	if err := authorize(ctx, permContribute, domain.DomainEnv, "registering environmental aspects"); err != nil {
+		return nil, err
+	}
+	condition := in.Condition
+	if strings.TrimSpace(condition) == "" {
+		condition = domain.ConditionNormal
+	}
+	controls, err := normalizeControls(in.Controls)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	a := &domain.EnvironmentalAspect{
+		Activity:          strings.TrimSpace(in.Activity),
+		Aspect:            strings.TrimSpace(in.Aspect),
+		Impact:            strings.TrimSpace(in.Impact),
+		Condition:         condition,
+		Severity:          in.Severity,
+		Frequency:         in.Frequency,
+		LegalRequirements: normalizeLegalRequirements(in.LegalRequirements),
+		Controls:          controls,
+		Owner:             strings.TrimSpace(in.Owner),
+		CreatedAt:         now,
+		CreatedBy:         auth.Actor(ctx),
+		UpdatedAt:         now,
+		UpdatedBy:         auth.Actor(ctx),
+	}
+	if err := s.setProcess(a, in.ProcessID); err != nil {
+		return nil, err
+	}
+	if err := scoreAspect(a); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *AspectService) ListAspects(ctx context.Context, filter AspectListFilter) ([]*domain.EnvironmentalAspect, int, error) {
+	if err := authorize(ctx, permRead, domain.DomainEnv, "viewing environmental aspects"); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(repository.AspectQuery{
+		ProcessID:   filter.ProcessID,
+		Condition:   filter.Condition,
+		Significant: filter.Significant,
+		Owner:       filter.Owner,
+		Search:      filter.Search,
+		Page:        filter.Page,
+	})
+}
+
+func (s *AspectService) GetAspect(ctx context.Context, id int) (*domain.EnvironmentalAspect, error) {
+	if err := authorize(ctx, permRead, domain.DomainEnv, "viewing environmental aspects"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// UpdateAspectInput carries a partial update; nil fields are left
+// unchanged. A ProcessID of 0 unlinks the process.
+type UpdateAspectInput struct {
+	ProcessID         *int
+	Activity          *string
+	Aspect            *string
+	Impact            *string
+	Condition         *string
+	Severity          *int
+	Frequency         *int
+	LegalRequirements []string             // Replaces the legal requirements when non-nil
+	Controls          []domain.RiskControl // Replaces the controls when non-nil
+	Owner             *string
+}
+
+// UpdateAspect applies a partial update and re-scores the aspect.
+func (s *AspectService) UpdateAspect(ctx context.Context, id int, in UpdateAspectInput) (*domain.EnvironmentalAspect, error) {
+	if err := authorize(ctx, permContribute, domain.DomainEnv, "editing environmental aspects"); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.ProcessID != nil {
+		pid := in.ProcessID
+		if *pid == 0 {
+			pid = nil
+		}
+		if err := s.setProcess(a, pid); err != nil {
+			return nil, err
+		}
+	}
+	if in.Activity != nil {
+		a.Activity = strings.TrimSpace(*in.Activity)
+	}
+	if in.Aspect != nil {
+		a.Aspect = strings.TrimSpace(*in.Aspect)
+	}
+	if in.Impact != nil {
+		a.Impact = strings.TrimSpace(*in.Impact)
+	}
+	if in.Condition != nil {
+		a.Condition = *in.Condition
+	}
+	if in.Severity != nil {
+		a.Severity = *in.Severity
+	}
+	if in.Frequency != nil {
+		a.Frequency = *in.Frequency
+	}
+	if in.LegalRequirements != nil {
+		a.LegalRequirements = normalizeLegalRequirements(in.LegalRequirements)
+	}
+	if in.Controls != nil {
+		if a.Controls, err = normalizeControls(in.Controls); err != nil {
+			return nil, err
+		}
+	}
+	if in.Owner != nil {
+		a.Owner = strings.TrimSpace(*in.Owner)
+	}
+	if err := scoreAspect(a); err != nil {
+		return nil, err
+	}
+	a.UpdatedAt = time.Now().Format(time.RFC3339)
+	a.UpdatedBy = auth.Actor(ctx)
+
+	if err := s.repo.Update(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteAspect removes an aspect. Aspects linked to incidents are not
+// deleted; repository.ErrInUse is returned instead.
+func (s *AspectService) DeleteAspect(ctx context.Context, id int) error {
+	if err := authorize(ctx, permApprove, domain.DomainEnv, "deleting environmental aspects"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// setProcess links the aspect to the process with id, or to none when id is
+// nil.
+func (s *AspectService) setProcess(a *domain.EnvironmentalAspect, id *int) error {
+	a.ProcessID, a.Process = nil, ""
+	if id == nil {
+		return nil
+	}
+	p, err := resolveProcess(s.processes, *id, "")
+	if err != nil {
+		return err
+	}
+	a.ProcessID, a.Process = &p.ID, p.Name
+	return nil
+}
+
+// scoreAspect checks the required fields and ratings of a and sets its
+// score and significance.
+func scoreAspect(a *domain.EnvironmentalAspect) error {
+	if a.Activity == "" || a.Aspect == "" || a.Impact == "" {
+		return fmt.Errorf("%w: activity, aspect and impact are required", ErrValidation)
+	}
+	condition, ok := oneOf(a.Condition, domain.AspectConditions)
+	if !ok {
+		return fmt.Errorf("%w: condition must be one of %s", ErrValidation, strings.Join(domain.AspectConditions, ", "))
+	}
+	a.Condition = condition
+	if a.Severity < 1 || a.Severity > domain.AspectScale || a.Frequency < 1 || a.Frequency > domain.AspectScale {
+		return fmt.Errorf("%w: severity and frequency must be between 1 and %d", ErrValidation, domain.AspectScale)
+	}
+	a.Score, a.Significant = domain.AspectSignificance(a.Severity, a.Frequency, a.LegalRequirements)
+	return nil
+}
+
+// normalizeLegalRequirements trims the references and drops empty and
+// repeated ones.
+func normalizeLegalRequirements(in []string) []string {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool)
+	for _, ref := range in {
+		ref = strings.Join(strings.Fields(ref), " ")
+		if ref == "" || seen[strings.ToLower(ref)] {
+			continue
+		}
+		seen[strings.ToLower(ref)] = true
+		out = append(out, ref)
+	}
+	return out
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// DefaultMaxAttachmentSize is the upload limit when none is configured.
+const DefaultMaxAttachmentSize = 20 << 20
+
+// AttachmentService keeps files on risks, incidents, audits and actions.
+// Access follows the owning record: reading it allows downloading its
+// files, contributing to it allows uploading, and deleting a file takes the
+// right to delete the record.
+type AttachmentService struct {
+	repo      repository.AttachmentRepository
+	store     repository.FileStore
+	risks     repository.RiskRepository
+	incidents repository.IncidentRepository
+	audits    repository.AuditRepository
+	actions   repository.ActionRepository
+	maxSize   int64
+
+	// contents is held for reading from storing an upload's content until
+	// its attachment refers to it, and for writing while content without
+	// references is removed, so a removal can't take content an upload
+	// is about to refer to.
+	contents sync.RWMutex
+}
+
+// NewAttachmentService creates the service; maxSize 0 means
+// DefaultMaxAttachmentSize.
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	store repository.FileStore,
+	risks repository.RiskRepository,
+	incidents repository.IncidentRepository,
+	audits repository.AuditRepository,
+	actions repository.ActionRepository,
+	maxSize int64,
+) *AttachmentService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+	return &AttachmentService{
+		repo: repo, store: store, risks: risks, incidents: incidents, audits: audits, actions: actions, maxSize: maxSize,
+	}
+}
+
+// MaxSize is the largest file accepted, in bytes.
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+type UploadInput struct {
+	FileName    string
+	Description string
+	Content     io.Reader
+}
+
+// Upload stores a file and attaches it to a record of the given kind
+// (domain.KindRisk, ...). The content type is sniffed from the content and
+// only taken from the file name when sniffing is inconclusive.
+func (s *AttachmentService) Upload(ctx context.Context, kind string, recordID int, in UploadInput) (*domain.Attachment, error) {
+	dom, err := s.recordDomain(kind, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permContribute, dom, "attaching files"); err != nil {
+		return nil, err
+	}
+	name := cleanFileName(in.FileName)
+	if name == "" {
+		return nil, fmt.Errorf("%w: file name is required", ErrValidation)
+	}
+
+	content := bufio.NewReaderSize(in.Content, 512)
+	head, err := content.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrValidation)
+	}
+
+	s.contents.RLock()
+	sum, size, err := s.store.Put(content, s.maxSize)
+	if err != nil {
+		s.contents.RUnlock()
+		return nil, err
+	}
+	a := &domain.Attachment{
+		RecordType:  kind,
+		RecordID:    recordID,
+		FileName:    name,
+		ContentType: sniffContentType(head, name),
+		Size:        size,
+		SHA256:      sum,
+		Description: strings.TrimSpace(in.Description),
+		UploadedAt:  time.Now().Format(time.RFC3339),
+		UploadedBy:  auth.Actor(ctx),
+	}
+	err = s.repo.Create(a)
+	s.contents.RUnlock()
+	if err != nil {
+		s.removeUnreferenced(sum)
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAttachments returns the attachments of a record, oldest first.
+func (s *AttachmentService) ListAttachments(ctx context.Context, kind string, recordID int) ([]*domain.Attachment, error) {
+	dom, err := s.recordDomain(kind, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, dom, "viewing attachments"); err != nil {
+		return nil, err
+	}
+	return s.repo.List(kind, recordID)
+}
+
+func (s *AttachmentService) GetAttachment(ctx context.Context, id int) (*domain.Attachment, error) {
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, a, permRead, "viewing attachments"); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Download returns an attachment with its content, which the caller must
+// close.
+func (s *AttachmentService) Download(ctx context.Context, id int) (*domain.Attachment, io.ReadSeekCloser, error) {
+	a, err := s.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.store.Open(a.SHA256)
+	if err != nil {
+		return nil, nil, fmt.Errorf("attachment %d: %w", id, err)
+	}
+	return a, content, nil
+}
+
+// DeleteAttachment removes an attachment, and its content once no other
+// attachment shares it.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id int) error {
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, a, permApprove, "deleting attachments"); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.removeUnreferenced(a.SHA256)
+	return nil
+}
+
+func (s *AttachmentService) authorize(ctx context.Context, a *domain.Attachment, p permission, what string) error {
+	dom, err := s.recordDomain(a.RecordType, a.RecordID)
+	if err != nil {
+		return err
+	}
+	return authorize(ctx, p, dom, what)
+}
+
+// recordDomain returns the domain of the record an attachment belongs to.
+// Attachments of deleted records are out of reach until the record is
+// restored.
+func (s *AttachmentService) recordDomain(kind string, id int) (domain.Domain, error) {
+	switch kind {
+	case domain.KindRisk:
+		r, err := s.risks.GetByID(id)
+		if err != nil {
+			return "", err
+		}
+		return r.Domain, nil
+	case domain.KindIncident:
+		inc, err := s.incidents.GetByID(id)
+		if err != nil {
+			return "", err
+		}
+		return inc.Domain, nil
+	case domain.KindAudit:
+		a, err := s.audits.GetByID(id)
+		if err != nil {
+			return "", err
+		}
+		return a.Domain, nil
+	case domain.KindAction:
+		a, err := s.actions.GetByID(id)
+		if err != nil {
+			return "", err
+		}
+		return sourceDomain(s.risks, s.incidents, s.audits, a)
+	default:
+		return "", fmt.Errorf("unknown attachment record type %q", kind)
+	}
+}
+
+// removeUnreferenced deletes stored content no attachment refers to any
+// more. Failures only leave an orphaned file behind, so they are logged.
+func (s *AttachmentService) removeUnreferenced(sum string) {
+	s.contents.Lock()
+	defer s.contents.Unlock()
+	n, err := s.repo.References(sum)
+	if err == nil && n == 0 {
+		err = s.store.Remove(sum)
+	}
+	if err != nil {
+		log.Printf("attachment content %s: %v", sum, err)
+	}
+}
+
+// cleanFileName keeps the base name of an uploaded file without control
+// characters, at most 255 bytes long.
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "." || name == "/" {
+		return ""
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// sniffContentType detects the type from the first bytes of the content,
+// falling back to the file extension when they are not recognised.
+func sniffContentType(head []byte, name string) string {
+	ct := http.DetectContentType(head)
+	if ct == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+			return byExt
+		}
+	}
+	return ct
+}
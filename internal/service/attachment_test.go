@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/repository/filestore"
+	"github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+func newAttachmentService(t *testing.T, env *testEnv, maxSize int64) (*AttachmentService, *filestore.Store) {
+	t.Helper()
+	store, err := filestore.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAttachmentService(sqlite.NewAttachmentRepository(env.db), store,
+		sqlite.NewRiskRepository(env.db), sqlite.NewIncidentRepository(env.db),
+		sqlite.NewAuditRepository(env.db), sqlite.NewActionRepository(env.db), maxSize), store
+}
+
+func upload(t *testing.T, s *AttachmentService, recordID int, name, content string) *domain.Attachment {
+	t.Helper()
+	a, err := s.Upload(manager, domain.KindIncident, recordID, UploadInput{FileName: name, Content: strings.NewReader(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func download(t *testing.T, s *AttachmentService, id int) string {
+	t.Helper()
+	_, f, err := s.Download(manager, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestUploadDownload(t *testing.T) {
+	env := newTestEnv(t)
+	s, _ := newAttachmentService(t, env, 0)
+	inc := reportIncident(t, env)
+
+	a := upload(t, s, inc.ID, `C:\photos\ladder.txt`, "third rung is worn")
+	if a.FileName != "ladder.txt" || a.Size != 18 || a.UploadedBy != "ims_manager" || !strings.HasPrefix(a.ContentType, "text/plain") {
+		t.Errorf("attachment %+v", a)
+	}
+	if got := download(t, s, a.ID); got != "third rung is worn" {
+		t.Errorf("downloaded %q", got)
+	}
+	list, err := s.ListAttachments(manager, domain.KindIncident, inc.ID)
+	if err != nil || len(list) != 1 || list[0].ID != a.ID {
+		t.Errorf("ListAttachments = %v, %v", list, err)
+	}
+	if _, err := s.GetAttachment(as(domain.RoleViewer, domain.DomainQuality), a.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading an OHS attachment as a Quality viewer = %v, want ErrForbidden", err)
+	}
+}
+
+func TestUploadLimits(t *testing.T) {
+	env := newTestEnv(t)
+	s, _ := newAttachmentService(t, env, 8)
+	inc := reportIncident(t, env)
+
+	for _, tt := range []struct {
+		name    string
+		in      UploadInput
+		wantErr error
+	}{
+		{"over the size limit", UploadInput{FileName: "big.txt", Content: strings.NewReader("123456789")}, repository.ErrTooLarge},
+		{"empty file", UploadInput{FileName: "empty.txt", Content: strings.NewReader("")}, ErrValidation},
+		{"no file name", UploadInput{FileName: " / ", Content: strings.NewReader("1234")}, ErrValidation},
+	} {
+		if _, err := s.Upload(manager, domain.KindIncident, inc.ID, tt.in); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Upload = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if a := upload(t, s, inc.ID, "max.txt", "12345678"); a.Size != 8 {
+		t.Errorf("size %d at the limit", a.Size)
+	}
+}
+
+func TestDeleteKeepsSharedContent(t *testing.T) {
+	env := newTestEnv(t)
+	s, store := newAttachmentService(t, env, 0)
+	inc := reportIncident(t, env)
+
+	first := upload(t, s, inc.ID, "report.txt", "permit to work")
+	second := upload(t, s, inc.ID, "copy.txt", "permit to work")
+	if first.SHA256 != second.SHA256 {
+		t.Fatalf("identical uploads have checksums %s and %s", first.SHA256, second.SHA256)
+	}
+
+	if err := s.DeleteAttachment(as(domain.RoleContributor, domain.DomainOHS), first.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("deleting as a contributor = %v, want ErrForbidden", err)
+	}
+	if err := s.DeleteAttachment(manager, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetAttachment(manager, first.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetAttachment after delete = %v, want ErrNotFound", err)
+	}
+	if got := download(t, s, second.ID); got != "permit to work" {
+		t.Errorf("shared content %q after deleting the other attachment", got)
+	}
+
+	if err := s.DeleteAttachment(manager, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(second.SHA256); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("content after deleting its last attachment: %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteDoesNotRemoveContentOfConcurrentUploads(t *testing.T) {
+	env := newTestEnv(t)
+	s, _ := newAttachmentService(t, env, 0)
+	inc := reportIncident(t, env)
+
+	// Each round deletes the only attachment of some content while the same
+	// content is uploaded again; the new attachment must keep its content.
+	for i := 0; i < 20; i++ {
+		old := upload(t, s, inc.ID, "checklist.txt", "inspection checklist")
+		var (
+			wg  sync.WaitGroup
+			a   *domain.Attachment
+			err error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a, err = s.Upload(manager, domain.KindIncident, inc.ID, UploadInput{FileName: "checklist.txt", Content: strings.NewReader("inspection checklist")})
+		}()
+		go func() {
+			defer wg.Done()
+			if err := s.DeleteAttachment(manager, old.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+		wg.Wait()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := download(t, s, a.ID); got != "inspection checklist" {
+			t.Fatalf("round %d: downloaded %q", i, got)
+		}
+		if err := s.DeleteAttachment(manager, a.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,31 +1,38 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/xenakil/integraflow-ims/internal/auth"
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
 
 type AuditService struct {
-	repo repository.AuditRepository
+	repo      repository.AuditRepository
+	processes repository.ProcessRepository
+	history   repository.HistoryRepository
+	events    *EventRelay
 }
 
-func NewAuditService(repo repository.AuditRepository) *AuditService {
-	return &AuditService{repo: repo}
+func NewAuditService(repo repository.AuditRepository, processes repository.ProcessRepository, history repository.HistoryRepository, events *EventRelay) *AuditService {
+	return &AuditService{repo: repo, processes: processes, history: history, events: events}
 }
 
 type CreateAuditInput struct {
 	Title       string
 	Scope       string
 	Domain      string
+	ProcessID   *int // Optional audited process
 	PlannedDate string
 	Auditor     string
 }
 
-func (s *AuditService) CreateAudit(in CreateAuditInput) (*domain.Audit, error) {
+func (s *AuditService) CreateAudit(ctx context.Context, in CreateAuditInput) (*domain.Audit, error) {
 	if strings.TrimSpace(in.Title) == "" || strings.TrimSpace(in.Scope) == "" || strings.TrimSpace(in.Domain) == "" {
 		return nil, fmt.Errorf("%w: title, scope and domain are required", ErrValidation)
 	}
@@ -34,6 +41,9 @@ func (s *AuditService) CreateAudit(in CreateAuditInput) (*domain.Audit, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
 	}
+	if err := authorize(ctx, permAudit, dom, "planning audits"); err != nil {
+		return nil, err
+	}
 
 	audit := &domain.Audit{
 		Title:       in.Title,
@@ -44,47 +54,68 @@ func (s *AuditService) CreateAudit(in CreateAuditInput) (*domain.Audit, error) {
 		Status:      "Planned",
 		Findings:    "",
 		CreatedAt:   time.Now().Format(time.RFC3339),
+		CreatedBy:   auth.Actor(ctx),
+		UpdatedBy:   auth.Actor(ctx),
+	}
+	if err := s.setProcess(audit, in.ProcessID); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(audit); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
 	return audit, nil
 }
 
-func (s *AuditService) ListAudits(statusFilter *string) ([]*domain.Audit, error) {
-	all, err := s.repo.GetAll()
-	if err != nil {
-		return nil, err
-	}
+type AuditListFilter struct {
+	Domain         *domain.Domain
+	ProcessID      *int
+	Status         *string
+	Auditor        *string
+	Planned        repository.DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           repository.Page
+}
 
-	out := make([]*domain.Audit, 0)
-	for _, a := range all {
-		if statusFilter != nil && !strings.EqualFold(a.Status, *statusFilter) {
-			continue
-		}
-		out = append(out, a)
+func (s *AuditService) ListAudits(ctx context.Context, filter AuditListFilter) ([]*domain.Audit, int, error) {
+	domains, err := readScope(ctx, filter.Domain, "listing audits")
+	if err != nil {
+		return nil, 0, err
 	}
-	return out, nil
+	return s.repo.List(repository.AuditQuery{
+		Domain:         filter.Domain,
+		Domains:        domains,
+		ProcessID:      filter.ProcessID,
+		Status:         filter.Status,
+		Auditor:        filter.Auditor,
+		Planned:        filter.Planned,
+		Search:         filter.Search,
+		IncludeDeleted: filter.IncludeDeleted,
+		Page:           filter.Page,
+	})
 }
 
 type UpdateAuditInput struct {
-	Status   *string
-	Findings *string
+	Status    *string
+	Findings  *string
+	ProcessID *int // 0 unlinks the process
 }
 
-func (s *AuditService) UpdateAudit(id int, in UpdateAuditInput) (*domain.Audit, error) {
+func (s *AuditService) UpdateAudit(ctx context.Context, id int, in UpdateAuditInput) (*domain.Audit, error) {
 	audit, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(ctx, permAudit, audit.Domain, "editing audits"); err != nil {
+		return nil, err
+	}
+	from := audit.Status
 
 	if in.Status != nil {
-		st := strings.TrimSpace(*in.Status)
-		normalized := strings.Title(strings.ToLower(st))
-		switch normalized {
-		case "Planned", "In Progress", "Completed":
-		default:
+		normalized, ok := domain.AuditWorkflow.Normalize(*in.Status)
+		if !ok {
 			return nil, fmt.Errorf("%w: invalid audit status", ErrValidation)
 		}
 		audit.Status = normalized
@@ -92,9 +123,81 @@ func (s *AuditService) UpdateAudit(id int, in UpdateAuditInput) (*domain.Audit,
 	if in.Findings != nil {
 		audit.Findings = *in.Findings
 	}
+	if in.ProcessID != nil {
+		id := in.ProcessID
+		if *id == 0 {
+			id = nil
+		}
+		if err := s.setProcess(audit, id); err != nil {
+			return nil, err
+		}
+	}
+	if err := domain.AuditWorkflow.Check(from, audit.Status, audit); err != nil {
+		return nil, err
+	}
+	audit.UpdatedBy = auth.Actor(ctx)
 
 	if err := s.repo.Update(audit); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
 	return audit, nil
 }
+
+// DeleteAudit soft-deletes an audit. Audits that still have active actions
+// are not deleted; repository.ErrInUse is returned instead.
+func (s *AuditService) DeleteAudit(ctx context.Context, id int) error {
+	audit, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorize(ctx, permAudit, audit.Domain, "deleting audits"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id, auth.Actor(ctx))
+}
+
+func (s *AuditService) RestoreAudit(ctx context.Context, id int) (*domain.Audit, error) {
+	audit, err := s.repo.GetDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permAudit, audit.Domain, "restoring audits"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restore(id, auth.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// AuditHistory returns the change history of an audit, including deleted
+// ones.
+func (s *AuditService) AuditHistory(ctx context.Context, id int) ([]*domain.HistoryEntry, error) {
+	audit, err := s.repo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		audit, err = s.repo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, audit.Domain, "viewing audit history"); err != nil {
+		return nil, err
+	}
+	return s.history.List(domain.KindAudit, id)
+}
+
+// setProcess links the audit to the process with id, or to none when id is
+// nil.
+func (s *AuditService) setProcess(audit *domain.Audit, id *int) error {
+	audit.ProcessID, audit.Process = nil, ""
+	if id == nil {
+		return nil
+	}
+	p, err := resolveProcess(s.processes, *id, "")
+	if err != nil {
+		return err
+	}
+	audit.ProcessID, audit.Process = &p.ID, p.Name
+	return nil
+}
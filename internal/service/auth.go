@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+var ErrUnauthenticated = errors.New("authentication required")
+
+// SessionTTL is how long a token issued by Login stays valid.
+const SessionTTL = 12 * time.Hour
+
+// touchInterval throttles last_used_at writes for busy tokens.
+const touchInterval = 5 * time.Minute
+
+// dummyPasswordHash is a bcrypt hash at the default cost that Login checks
+// passwords against when there is no user or no password, so failed logins
+// take as long whether or not the username exists.
+const dummyPasswordHash = "$2a$10$ywVR5t.0Fh3l/pTcBi2XM.4lGTiaKvZR9dBL8tsEleM5XoF.c9cky"
+
+type AuthService struct {
+	users  repository.UserRepository
+	tokens repository.TokenRepository
+}
+
+func NewAuthService(users repository.UserRepository, tokens repository.TokenRepository) *AuthService {
+	return &AuthService{users: users, tokens: tokens}
+}
+
+// Authenticate resolves a bearer token to its active user. Unknown, expired
+// and deactivated credentials all yield ErrUnauthenticated.
+func (s *AuthService) Authenticate(token string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+	t, err := s.tokens.GetByHash(auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUnauthenticated
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if t.ExpiresAt != "" {
+		exp, err := time.Parse(time.RFC3339, t.ExpiresAt)
+		if err != nil || now.After(exp) {
+			return nil, ErrUnauthenticated
+		}
+	}
+
+	u, err := s.users.GetByID(t.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUnauthenticated
+		}
+		return nil, err
+	}
+	if !u.Active {
+		return nil, ErrUnauthenticated
+	}
+
+	if last, err := time.Parse(time.RFC3339, t.LastUsedAt); err != nil || now.Sub(last) > touchInterval {
+		_ = s.tokens.Touch(t.ID, now.Format(time.RFC3339))
+	}
+	return u, nil
+}
+
+// Login checks a username/password pair and issues a session token.
+func (s *AuthService) Login(username, password string) (string, *domain.APIToken, error) {
+	u, err := s.users.GetByUsername(strings.TrimSpace(username))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", nil, err
+	}
+	hash := dummyPasswordHash
+	if u != nil && u.PasswordHash != "" {
+		hash = u.PasswordHash
+	}
+	ok := auth.CheckPassword(hash, password)
+	if u == nil || u.PasswordHash == "" || !u.Active || !ok {
+		return "", nil, fmt.Errorf("%w: invalid username or password", ErrUnauthenticated)
+	}
+	return s.issueToken(u, domain.TokenKindSession, "login", SessionTTL)
+}
+
+// Logout revokes the token used for the current request.
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	t, err := s.tokens.GetByHash(auth.HashToken(token))
+	if err != nil {
+		return err
+	}
+	return s.tokens.Delete(t.ID)
+}
+
+// Me returns the authenticated user.
+func (s *AuthService) Me(ctx context.Context) (*domain.User, error) {
+	u := auth.UserFromContext(ctx)
+	if u == nil {
+		return nil, ErrUnauthenticated
+	}
+	return u, nil
+}
+
+type CreateUserInput struct {
+	Username    string
+	DisplayName string
+	Email       string // optional; notifications are only emailed to users with an address
+	Password    string // optional; users without a password can only use API tokens
+	Roles       []domain.RoleAssignment
+}
+
+func (s *AuthService) CreateUser(ctx context.Context, in CreateUserInput) (*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "creating users"); err != nil {
+		return nil, err
+	}
+	roles, err := normalizeRoles(in.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	username := strings.TrimSpace(in.Username)
+	if username == "" || strings.ContainsAny(username, " \t\n") {
+		return nil, fmt.Errorf("%w: username is required and must not contain spaces", ErrValidation)
+	}
+	email, err := normalizeEmail(in.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &domain.User{
+		Username:    username,
+		DisplayName: strings.TrimSpace(in.DisplayName),
+		Email:       email,
+		Active:      true,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+	if in.Password != "" {
+		hash, err := auth.HashPassword(in.Password)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		u.PasswordHash = hash
+	}
+
+	if err := s.users.Create(u); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: username %q is taken", err, username)
+		}
+		return nil, err
+	}
+	if err := s.users.SetRoles(u.ID, roles); err != nil {
+		return nil, err
+	}
+	u.Roles = roles
+	return u, nil
+}
+
+// SetPassword replaces the password of the given user.
+func (s *AuthService) SetPassword(ctx context.Context, username, password string) error {
+	if err := authorize(ctx, permManageUsers, "", "changing passwords"); err != nil {
+		return err
+	}
+	u, err := s.users.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	u.PasswordHash = hash
+	return s.users.Update(u)
+}
+
+func (s *AuthService) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "looking up users"); err != nil {
+		return nil, err
+	}
+	return s.users.GetByUsername(username)
+}
+
+func (s *AuthService) GetUser(ctx context.Context, id int) (*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "viewing users"); err != nil {
+		return nil, err
+	}
+	return s.users.GetByID(id)
+}
+
+func (s *AuthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "listing users"); err != nil {
+		return nil, err
+	}
+	return s.users.GetAll()
+}
+
+// UpdateUserInput carries a partial update; nil fields are left unchanged.
+type UpdateUserInput struct {
+	DisplayName *string
+	Email       *string // Empty clears the address
+	Active      *bool
+}
+
+func (s *AuthService) UpdateUser(ctx context.Context, id int, in UpdateUserInput) (*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "editing users"); err != nil {
+		return nil, err
+	}
+	u, err := s.users.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.DisplayName != nil {
+		u.DisplayName = strings.TrimSpace(*in.DisplayName)
+	}
+	if in.Email != nil {
+		if u.Email, err = normalizeEmail(*in.Email); err != nil {
+			return nil, err
+		}
+	}
+	if in.Active != nil {
+		if !*in.Active && isCaller(ctx, u) {
+			return nil, fmt.Errorf("%w: you cannot deactivate your own account", ErrValidation)
+		}
+		u.Active = *in.Active
+	}
+	if err := s.users.Update(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SetRoles replaces the role assignments of a user.
+func (s *AuthService) SetRoles(ctx context.Context, id int, roles []domain.RoleAssignment) (*domain.User, error) {
+	if err := authorize(ctx, permManageUsers, "", "assigning roles"); err != nil {
+		return nil, err
+	}
+	roles, err := normalizeRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if isCaller(ctx, u) && !can(&domain.User{Roles: roles}, permManageUsers, "") {
+		return nil, fmt.Errorf("%w: you cannot remove your own user administration rights", ErrValidation)
+	}
+	if err := s.users.SetRoles(u.ID, roles); err != nil {
+		return nil, err
+	}
+	u.Roles = roles
+	return u, nil
+}
+
+// normalizeRoles validates role names and domains and returns them in
+// canonical form.
+func normalizeRoles(in []domain.RoleAssignment) ([]domain.RoleAssignment, error) {
+	out := make([]domain.RoleAssignment, 0, len(in))
+	for _, ra := range in {
+		role, err := domain.ParseRole(string(ra.Role))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v %q", ErrValidation, err, ra.Role)
+		}
+		var dom domain.Domain
+		if ra.Domain != "" {
+			if dom, err = domain.ParseDomain(string(ra.Domain)); err != nil {
+				return nil, fmt.Errorf("%w: %v %q", ErrValidation, err, ra.Domain)
+			}
+		}
+		out = append(out, domain.RoleAssignment{Role: role, Domain: dom})
+	}
+	return out, nil
+}
+
+func isCaller(ctx context.Context, u *domain.User) bool {
+	caller := auth.UserFromContext(ctx)
+	return caller != nil && !caller.System && caller.ID == u.ID
+}
+
+// CreateToken issues a long-lived API token for the authenticated user. A
+// zero ttl creates a token that does not expire. The plaintext token is only
+// returned here.
+func (s *AuthService) CreateToken(ctx context.Context, name string, ttl time.Duration) (string, *domain.APIToken, error) {
+	u := auth.UserFromContext(ctx)
+	if u == nil {
+		return "", nil, ErrUnauthenticated
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: token name is required", ErrValidation)
+	}
+	if ttl < 0 {
+		return "", nil, fmt.Errorf("%w: token lifetime must not be negative", ErrValidation)
+	}
+	return s.issueToken(u, domain.TokenKindAPI, name, ttl)
+}
+
+// ListTokens returns the authenticated user's tokens (without secrets).
+func (s *AuthService) ListTokens(ctx context.Context) ([]*domain.APIToken, error) {
+	u := auth.UserFromContext(ctx)
+	if u == nil {
+		return nil, ErrUnauthenticated
+	}
+	return s.tokens.ListByUser(u.ID)
+}
+
+// RevokeToken deletes one of the authenticated user's tokens.
+func (s *AuthService) RevokeToken(ctx context.Context, id int) error {
+	tokens, err := s.ListTokens(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if t.ID == id {
+			return s.tokens.Delete(id)
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (s *AuthService) issueToken(u *domain.User, kind, name string, ttl time.Duration) (string, *domain.APIToken, error) {
+	plain, hash, err := auth.NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	t := &domain.APIToken{
+		UserID:    u.ID,
+		Name:      name,
+		Kind:      kind,
+		TokenHash: hash,
+		CreatedAt: now.Format(time.RFC3339),
+	}
+	if ttl > 0 {
+		t.ExpiresAt = now.Add(ttl).Format(time.RFC3339)
+	}
+
+	if err := s.tokens.Create(t); err != nil {
+		return "", nil, err
+	}
+	return plain, t, nil
+}
+
+// normalizeEmail validates a bare email address. Empty is allowed.
+func normalizeEmail(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return "", fmt.Errorf("%w: invalid email address %q", ErrValidation, s)
+	}
+	return s, nil
+}
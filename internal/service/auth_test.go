@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+const testPassword = "correct-horse-battery"
+
+func addUser(t *testing.T, env *testEnv, username, password string) *domain.User {
+	t.Helper()
+	u, err := env.auth.CreateUser(manager, CreateUserInput{
+		Username: username,
+		Password: password,
+		Roles:    []domain.RoleAssignment{{Role: domain.RoleContributor, Domain: domain.DomainQuality}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestLogin(t *testing.T) {
+	env := newTestEnv(t)
+	alice := addUser(t, env, "alice", testPassword)
+	addUser(t, env, "tokenonly", "")
+	disabled := addUser(t, env, "carol", testPassword)
+	active := false
+	if _, err := env.auth.UpdateUser(manager, disabled.ID, UpdateUserInput{Active: &active}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name, username, password string
+	}{
+		{"wrong password", "alice", "wrong-password"},
+		{"unknown user", "mallory", testPassword},
+		{"disabled user", "carol", testPassword},
+		{"user without a password", "tokenonly", ""},
+		{"empty password", "alice", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, tok, err := env.auth.Login(tt.username, tt.password)
+			if !errors.Is(err, ErrUnauthenticated) || plain != "" || tok != nil {
+				t.Fatalf("Login = %q, %v, %v; want ErrUnauthenticated", plain, tok, err)
+			}
+			// Every failure reads the same, so usernames can't be probed.
+			if err.Error() != "authentication required: invalid username or password" {
+				t.Errorf("Login error %q", err)
+			}
+		})
+	}
+
+	plain, tok, err := env.auth.Login(" alice ", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Kind != domain.TokenKindSession || tok.UserID != alice.ID {
+		t.Errorf("session token %+v", tok)
+	}
+	exp, err := time.Parse(time.RFC3339, tok.ExpiresAt)
+	if err != nil || time.Until(exp) < SessionTTL-time.Minute || time.Until(exp) > SessionTTL {
+		t.Errorf("session expires at %q, want in %v", tok.ExpiresAt, SessionTTL)
+	}
+	u, err := env.auth.Authenticate(plain)
+	if err != nil || u.Username != "alice" {
+		t.Fatalf("Authenticate = %v, %v; want alice", u, err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	env := newTestEnv(t)
+	addUser(t, env, "alice", testPassword)
+
+	login := func() (string, *domain.APIToken) {
+		t.Helper()
+		plain, tok, err := env.auth.Login("alice", testPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plain, tok
+	}
+	rejected := func(name, token string) {
+		t.Helper()
+		if u, err := env.auth.Authenticate(token); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: Authenticate = %v, %v; want ErrUnauthenticated", name, u, err)
+		}
+	}
+
+	rejected("empty token", "")
+	rejected("unknown token", "ims_0000000000000000000000000000000000000000")
+
+	plain, tok := login()
+	if _, err := env.db.Exec(`UPDATE api_tokens SET expires_at = ? WHERE id = ?`,
+		time.Now().Add(-time.Second).Format(time.RFC3339), tok.ID); err != nil {
+		t.Fatal(err)
+	}
+	rejected("expired token", plain)
+
+	plain, _ = login()
+	ctx := auth.WithUser(context.Background(), mustAuthenticate(t, env, plain))
+	if err := env.auth.Logout(ctx, plain); err != nil {
+		t.Fatal(err)
+	}
+	rejected("logged out token", plain)
+
+	plain, tok = login()
+	ctx = auth.WithUser(context.Background(), mustAuthenticate(t, env, plain))
+	if err := env.auth.RevokeToken(ctx, tok.ID); err != nil {
+		t.Fatal(err)
+	}
+	rejected("revoked token", plain)
+
+	// Deactivating a user rejects their existing tokens.
+	plain, _ = login()
+	u := mustAuthenticate(t, env, plain)
+	active := false
+	if _, err := env.auth.UpdateUser(manager, u.ID, UpdateUserInput{Active: &active}); err != nil {
+		t.Fatal(err)
+	}
+	rejected("token of a deactivated user", plain)
+}
+
+func TestRevokeTokenOfAnotherUser(t *testing.T) {
+	env := newTestEnv(t)
+	addUser(t, env, "alice", testPassword)
+	addUser(t, env, "bob", testPassword)
+	alicePlain, aliceTok, err := env.auth.Login("alice", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobPlain, _, err := env.auth.Login("bob", testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bob := auth.WithUser(context.Background(), mustAuthenticate(t, env, bobPlain))
+	if err := env.auth.RevokeToken(bob, aliceTok.ID); err == nil {
+		t.Fatal("bob revoked alice's token")
+	}
+	mustAuthenticate(t, env, alicePlain)
+}
+
+func mustAuthenticate(t *testing.T, env *testEnv, token string) *domain.User {
+	t.Helper()
+	u, err := env.auth.Authenticate(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// permission is an operation class checked against the caller's roles.
+type permission string
+
+const (
+	permRead        permission = "read"         // View records
+	permContribute  permission = "contribute"   // Raise and edit risks, incidents and actions
+	permApprove     permission = "approve"      // Accept/mitigate risks, approve treatment plans, close incidents, delete and restore records
+	permAcceptRisk  permission = "accept risk"  // Approve accepting risks in escalating levels of the risk matrix
+	permAudit       permission = "audit"        // Plan, edit and complete audits
+	permManageUsers permission = "manage users" // Create users and assign roles (global roles only)
+	permIntegrate   permission = "integrate"    // Manage webhook subscriptions (global roles only)
+	permConfigure   permission = "configure"    // Change system-wide settings such as the risk matrix (global roles only)
+)
+
+var rolePermissions = map[domain.Role][]permission{
+	domain.RoleViewer:       {permRead},
+	domain.RoleContributor:  {permRead, permContribute},
+	domain.RoleProcessOwner: {permRead, permContribute, permApprove},
+	domain.RoleAuditor:      {permRead, permAudit},
+	domain.RoleIMSManager:   {permRead, permContribute, permApprove, permAcceptRisk, permAudit, permManageUsers, permIntegrate, permConfigure},
+}
+
+// allRoles lists roles in order of increasing privilege, for messages.
+var allRoles = []domain.Role{
+	domain.RoleViewer, domain.RoleContributor, domain.RoleProcessOwner, domain.RoleAuditor, domain.RoleIMSManager,
+}
+
+func roleGrants(role domain.Role, p permission) bool {
+	for _, rp := range rolePermissions[role] {
+		if rp == p {
+			return true
+		}
+	}
+	return false
+}
+
+// can reports whether u holds p in dom. An empty dom asks for p in every
+// domain, which only unscoped role assignments satisfy.
+func can(u *domain.User, p permission, dom domain.Domain) bool {
+	if u == nil {
+		return false
+	}
+	if u.System {
+		return true
+	}
+	for _, ra := range u.Roles {
+		if (ra.Domain == "" || ra.Domain == dom) && roleGrants(ra.Role, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize returns ErrForbidden with a reason naming the roles that would
+// allow what, unless the user in ctx holds p in dom.
+func authorize(ctx context.Context, p permission, dom domain.Domain, what string) error {
+	u := auth.UserFromContext(ctx)
+	if u == nil {
+		return ErrUnauthenticated
+	}
+	if can(u, p, dom) {
+		return nil
+	}
+
+	var roles []string
+	for _, r := range allRoles {
+		if roleGrants(r, p) {
+			roles = append(roles, string(r))
+		}
+	}
+	needed := "a"
+	switch {
+	case len(roles) == len(allRoles):
+	case len(roles) == 1:
+		needed = "the " + roles[0]
+	default:
+		needed = "the " + strings.Join(roles[:len(roles)-1], ", ") + " or " + roles[len(roles)-1]
+	}
+	scope := "for all domains"
+	if dom != "" {
+		scope = "for " + string(dom)
+	}
+	return fmt.Errorf("%w: %s requires %s role %s", ErrForbidden, what, needed, scope)
+}
+
+// readScope returns the domains the caller may read, or nil when they may
+// read every domain. Asking for a specific domain the caller cannot read is
+// forbidden rather than silently empty.
+func readScope(ctx context.Context, requested *domain.Domain, what string) ([]domain.Domain, error) {
+	if requested != nil {
+		if err := authorize(ctx, permRead, *requested, what); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	u := auth.UserFromContext(ctx)
+	if u == nil {
+		return nil, ErrUnauthenticated
+	}
+	if can(u, permRead, "") {
+		return nil, nil
+	}
+	doms := make([]domain.Domain, 0)
+	for _, ra := range u.Roles {
+		if roleGrants(ra.Role, permRead) {
+			doms = append(doms, ra.Domain)
+		}
+	}
+	if len(doms) == 0 {
+		return nil, fmt.Errorf("%w: %s requires a role in at least one domain", ErrForbidden, what)
+	}
+	return doms, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func TestCanByRole(t *testing.T) {
+	all := []permission{permRead, permContribute, permApprove, permAcceptRisk, permAudit, permManageUsers, permIntegrate, permConfigure}
+	granted := map[domain.Role][]permission{
+		domain.RoleViewer:       {permRead},
+		domain.RoleContributor:  {permRead, permContribute},
+		domain.RoleProcessOwner: {permRead, permContribute, permApprove},
+		domain.RoleAuditor:      {permRead, permAudit},
+		domain.RoleIMSManager:   all,
+	}
+	for role, perms := range granted {
+		global := &domain.User{Roles: []domain.RoleAssignment{{Role: role}}}
+		scoped := &domain.User{Roles: []domain.RoleAssignment{{Role: role, Domain: domain.DomainQuality}}}
+		for _, p := range all {
+			want := slices.Contains(perms, p)
+			for _, tt := range []struct {
+				name string
+				u    *domain.User
+				dom  domain.Domain
+				want bool
+			}{
+				{"global role in a domain", global, domain.DomainOHS, want},
+				{"global role in every domain", global, "", want},
+				{"scoped role in its domain", scoped, domain.DomainQuality, want},
+				{"scoped role in another domain", scoped, domain.DomainOHS, false},
+				{"scoped role in every domain", scoped, "", false},
+			} {
+				if got := can(tt.u, p, tt.dom); got != tt.want {
+					t.Errorf("%s: %s can %s in %q = %v, want %v", role, tt.name, p, tt.dom, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+func TestCanCombinesAssignments(t *testing.T) {
+	u := &domain.User{Roles: []domain.RoleAssignment{
+		{Role: domain.RoleViewer},
+		{Role: domain.RoleProcessOwner, Domain: domain.DomainOHS},
+	}}
+	for _, tt := range []struct {
+		p    permission
+		dom  domain.Domain
+		want bool
+	}{
+		{permRead, domain.DomainQuality, true},
+		{permContribute, domain.DomainQuality, false},
+		{permApprove, domain.DomainOHS, true},
+		{permApprove, "", false},
+	} {
+		if got := can(u, tt.p, tt.dom); got != tt.want {
+			t.Errorf("can %s in %q = %v, want %v", tt.p, tt.dom, got, tt.want)
+		}
+	}
+
+	if can(nil, permRead, domain.DomainQuality) {
+		t.Error("a missing user can read")
+	}
+	if !can(auth.System, permConfigure, "") {
+		t.Error("the system user can't configure")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		p      permission
+		dom    domain.Domain
+		err    error
+		reason string
+	}{
+		{"no user", context.Background(), permRead, domain.DomainQuality, ErrUnauthenticated, ""},
+		{"allowed", as(domain.RoleContributor, domain.DomainQuality), permContribute, domain.DomainQuality, nil, ""},
+		{"system", auth.SystemContext(context.Background()), permManageUsers, "", nil, ""},
+		{"cross-domain", as(domain.RoleProcessOwner, domain.DomainQuality), permApprove, domain.DomainOHS, ErrForbidden,
+			"closing incidents requires the process_owner or ims_manager role for OHS"},
+		{"scoped role for a global permission", as(domain.RoleIMSManager, domain.DomainQuality), permManageUsers, "", ErrForbidden,
+			"closing incidents requires the ims_manager role for all domains"},
+		{"missing role", as(domain.RoleViewer, ""), permAudit, domain.DomainEnv, ErrForbidden,
+			"closing incidents requires the auditor or ims_manager role for Environment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(tt.ctx, tt.p, tt.dom, "closing incidents")
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("authorize = %v, want %v", err, tt.err)
+			}
+			if tt.reason != "" && !strings.HasSuffix(err.Error(), tt.reason) {
+				t.Errorf("reason %q, want %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestReadScope(t *testing.T) {
+	quality, ohs := domain.DomainQuality, domain.DomainOHS
+	multi := auth.WithUser(context.Background(), &domain.User{Username: "multi", Roles: []domain.RoleAssignment{
+		{Role: domain.RoleViewer, Domain: quality},
+		{Role: domain.RoleAuditor, Domain: ohs},
+	}})
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requested *domain.Domain
+		want      []domain.Domain
+		err       error
+	}{
+		{"no user", context.Background(), nil, nil, ErrUnauthenticated},
+		{"global viewer", as(domain.RoleViewer, ""), nil, nil, nil},
+		{"scoped viewer", as(domain.RoleViewer, quality), nil, []domain.Domain{quality}, nil},
+		{"several domains", multi, nil, []domain.Domain{quality, ohs}, nil},
+		{"requested own domain", as(domain.RoleContributor, quality), &quality, nil, nil},
+		{"requested other domain", as(domain.RoleContributor, quality), &ohs, nil, ErrForbidden},
+		{"no roles", auth.WithUser(context.Background(), &domain.User{Username: "nobody"}), nil, nil, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readScope(tt.ctx, tt.requested, "listing risks")
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("readScope = %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("readScope = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServicesEnforceDomainRoles(t *testing.T) {
+	env := newTestEnv(t)
+	inc, err := env.incidents.CreateIncident(as(domain.RoleContributor, domain.DomainOHS), CreateIncidentInput{
+		Title: "Fall from ladder", Description: "Slipped on the third rung", Domain: "OHS",
+		Severity: 3, Likelihood: 2, OccurredOn: "2026-01-05",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	investigation, closed, rootCause := "Investigation", "Closed", "Worn rung"
+	if _, err := env.incidents.UpdateIncident(as(domain.RoleContributor, domain.DomainOHS), inc.ID, UpdateIncidentInput{Status: &investigation}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ctx := range []context.Context{
+		as(domain.RoleProcessOwner, domain.DomainQuality), // another domain
+		as(domain.RoleContributor, domain.DomainOHS),      // too weak a role
+		as(domain.RoleViewer, ""),
+	} {
+		_, err := env.incidents.UpdateIncident(ctx, inc.ID, UpdateIncidentInput{Status: &closed, RootCause: &rootCause})
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("closing as %s = %v, want ErrForbidden", auth.Actor(ctx), err)
+		}
+	}
+	if _, err := env.incidents.GetIncident(as(domain.RoleViewer, domain.DomainQuality), inc.ID, false); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading an OHS incident as a Quality viewer = %v, want ErrForbidden", err)
+	}
+	incidents, _, err := env.incidents.ListIncidents(as(domain.RoleViewer, domain.DomainQuality), IncidentListFilter{})
+	if err != nil || len(incidents) != 0 {
+		t.Errorf("a Quality viewer lists %d incidents, %v; want none", len(incidents), err)
+	}
+
+	got, err := env.incidents.UpdateIncident(as(domain.RoleProcessOwner, domain.DomainOHS), inc.ID, UpdateIncidentInput{Status: &closed, RootCause: &rootCause})
+	if err != nil || got.Status != closed {
+		t.Fatalf("closing as an OHS process owner = %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"context"
+	"math"
+	"time"
+
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
@@ -9,44 +13,93 @@ type DashboardService struct {
 	riskRepo   repository.RiskRepository
 	incRepo    repository.IncidentRepository
 	actionRepo repository.ActionRepository
+	matrix     repository.RiskMatrixRepository
+	hours      repository.HoursWorkedRepository
+	aspects    repository.AspectRepository
 }
 
 func NewDashboardService(
 	riskRepo repository.RiskRepository,
 	incRepo repository.IncidentRepository,
 	actionRepo repository.ActionRepository,
+	matrix repository.RiskMatrixRepository,
+	hours repository.HoursWorkedRepository,
+	aspects repository.AspectRepository,
 ) *DashboardService {
 	return &DashboardService{
 		riskRepo:   riskRepo,
 		incRepo:    incRepo,
 		actionRepo: actionRepo,
+		matrix:     matrix,
+		hours:      hours,
+		aspects:    aspects,
 	}
 }
 
-func (s *DashboardService) GetDashboard() (*domain.Dashboard, error) {
-	risks, err := s.riskRepo.GetAll()
+// GetDashboard summarises the records in the domains the caller can read.
+func (s *DashboardService) GetDashboard(ctx context.Context) (*domain.Dashboard, error) {
+	domains, err := readScope(ctx, nil, "viewing the dashboard")
+	if err != nil {
+		return nil, err
+	}
+	risks, _, err := s.riskRepo.List(repository.RiskQuery{Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	incidents, _, err := s.incRepo.List(repository.IncidentQuery{Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	actions, _, err := s.actionRepo.List(repository.ActionQuery{Domains: domains})
 	if err != nil {
 		return nil, err
 	}
-	incidents, err := s.incRepo.GetAll()
+	m, err := s.matrix.Get()
 	if err != nil {
 		return nil, err
 	}
-	actions, err := s.actionRepo.GetAll()
+	from, to := last12Months(time.Now())
+	hours, err := s.hours.List(from, to)
 	if err != nil {
 		return nil, err
 	}
 
+	var aspects []*domain.EnvironmentalAspect
+	if inDomains(domain.DomainEnv, domains) {
+		significant := true
+		if aspects, _, err = s.aspects.List(repository.AspectQuery{Significant: &significant}); err != nil {
+			return nil, err
+		}
+	}
+
 	dash := &domain.Dashboard{
-		ActionsByStatus:   make(map[string]int),
-		IncidentsByDomain: make(map[domain.Domain]int),
+		ActionsByStatus:               make(map[string]int),
+		IncidentsByDomain:             make(map[domain.Domain]int),
+		RiskReduction:                 make(map[domain.Domain]domain.RiskReduction),
+		SignificantAspectsByCondition: make(map[string]int),
 	}
 
+	today := time.Now().Format(time.DateOnly)
 	dash.TotalRisks = len(risks)
 	for _, r := range risks {
-		if r.Level == "High" {
+		if m.Escalates(r.Level) {
 			dash.HighRisks++
 		}
+		if r.NextReviewAt != "" && r.NextReviewAt < today {
+			dash.OverdueReviews++
+		}
+		rr := dash.RiskReduction[r.Domain]
+		rr.Risks++
+		rr.InherentScore += r.Score
+		rr.ResidualScore += r.ResidualScore
+		dash.RiskReduction[r.Domain] = rr
+	}
+	for dom, rr := range dash.RiskReduction {
+		if rr.InherentScore > 0 {
+			pct := float64(rr.InherentScore-rr.ResidualScore) / float64(rr.InherentScore) * 100
+			rr.ReductionPercent = math.Round(pct*10) / 10
+		}
+		dash.RiskReduction[dom] = rr
 	}
 
 	dash.TotalIncidents = len(incidents)
@@ -56,6 +109,14 @@ func (s *DashboardService) GetDashboard() (*domain.Dashboard, error) {
 		}
 		dash.IncidentsByDomain[inc.Domain]++
 	}
+	if inDomains(domain.DomainOHS, domains) {
+		dash.OHS = ohsStatistics(incidents, hours, from, to, today)
+	}
+
+	dash.SignificantAspects = len(aspects)
+	for _, a := range aspects {
+		dash.SignificantAspectsByCondition[a.Condition]++
+	}
 
 	for _, a := range actions {
 		dash.ActionsByStatus[a.Status]++
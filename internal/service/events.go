@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// EventPublisher receives record change events after the change was saved.
+// Publish returns an error when the event could not be queued; the relay
+// then keeps it and tries again, so publishers must drop events with an ID
+// they have seen before.
+type EventPublisher interface {
+	Publish(ctx context.Context, e domain.Event) error
+}
+
+// Reminder queues a reminder for an event and reports whether it was new;
+// reminders that were queued before are not repeated.
+type Reminder interface {
+	Remind(ctx context.Context, e domain.Event) (bool, error)
+}
+
+// Publishers fans an event out to several publishers.
+type Publishers []EventPublisher
+
+func (ps Publishers) Publish(ctx context.Context, e domain.Event) error {
+	var errs []error
+	for _, p := range ps {
+		errs = append(errs, p.Publish(ctx, e))
+	}
+	return errors.Join(errs...)
+}
+
+// relayBatch is how many staged events the relay reads at a time.
+const relayBatch = 100
+
+// EventRelay hands the record change events that the repositories stage in
+// the transaction of each change to a publisher, oldest first, and drops
+// them once published. Services relay right after saving; the relay job
+// picks up events left behind by a crash or a failed publish.
+type EventRelay struct {
+	outbox    repository.EventOutboxRepository
+	publisher EventPublisher
+
+	mu sync.Mutex // One relay at a time, so events are published in order
+}
+
+func NewEventRelay(outbox repository.EventOutboxRepository, publisher EventPublisher) *EventRelay {
+	return &EventRelay{outbox: outbox, publisher: publisher}
+}
+
+// Relay publishes the staged events and returns how many were published. It
+// stops at the first event that fails to publish, leaving it staged.
+func (r *EventRelay) Relay(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for {
+		events, err := r.outbox.Pending(relayBatch)
+		if err != nil {
+			return n, err
+		}
+		for _, e := range events {
+			if err := r.publisher.Publish(ctx, e); err != nil {
+				return n, fmt.Errorf("%s %s #%d: %w", e.Type, e.RecordType, e.RecordID, err)
+			}
+			if err := r.outbox.Remove(e.ID); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(events) < relayBatch {
+			return n, nil
+		}
+	}
+}
+
+// flush relays the events of a change that was just saved. Failures are
+// only logged: the change is saved and the relay job tries again. A nil
+// relay does nothing.
+func (r *EventRelay) flush(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	if _, err := r.Relay(ctx); err != nil {
+		log.Printf("events: %v", err)
+	}
+}
+
+// newEvent builds an event that is not a record change, such as a reminder;
+// the repositories stage record change events themselves.
+func newEvent(ctx context.Context, typ, kind string, id int, dom domain.Domain, record any) domain.Event {
+	return domain.Event{
+		ID:         newEventID(),
+		Type:       typ,
+		RecordType: kind,
+		RecordID:   id,
+		Domain:     dom,
+		Actor:      auth.Actor(ctx),
+		OccurredAt: time.Now().Format(time.RFC3339),
+		Record:     record,
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+// flaky is an EventPublisher that fails until it is fixed.
+type flaky struct {
+	recorder
+	broken bool
+}
+
+func (f *flaky) Publish(ctx context.Context, e domain.Event) error {
+	if f.broken {
+		return errors.New("outbox unavailable")
+	}
+	return f.recorder.Publish(ctx, e)
+}
+
+func reportIncident(t *testing.T, env *testEnv) *domain.Incident {
+	t.Helper()
+	inc, err := env.incidents.CreateIncident(manager, CreateIncidentInput{
+		Title: "Fall from ladder", Description: "Slipped on the third rung", Domain: "OHS",
+		Severity: 3, Likelihood: 2, OccurredOn: "2026-01-05",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inc
+}
+
+func TestSavedChangesArePublished(t *testing.T) {
+	env := newTestEnv(t)
+	inc := reportIncident(t, env)
+	investigation := "Investigation"
+	if _, err := env.incidents.UpdateIncident(manager, inc.ID, UpdateIncidentInput{Status: &investigation}); err != nil {
+		t.Fatal(err)
+	}
+
+	created, changed := env.events.Of(domain.EventIncidentCreated), env.events.Of(domain.EventIncidentStatusChanged)
+	if len(created) != 1 || len(changed) != 1 {
+		t.Fatalf("published %d created and %d status events, want 1 each", len(created), len(changed))
+	}
+	if rec, ok := changed[0].Record.(*domain.Incident); !ok || rec.ID != inc.ID || changed[0].To != investigation {
+		t.Errorf("status event %+v", changed[0])
+	}
+	if n, err := env.relay.Relay(manager); err != nil || n != 0 {
+		t.Errorf("Relay after publishing = %d, %v; want nothing left", n, err)
+	}
+}
+
+func TestRelayPublishesEventsLeftBehind(t *testing.T) {
+	env := newTestEnv(t)
+	publisher := &flaky{broken: true}
+	relay := NewEventRelay(sqlite.NewEventOutboxRepository(env.db), publisher)
+	env.incidents.events = relay
+
+	// The change is saved although its event could not be published.
+	inc := reportIncident(t, env)
+	if _, err := env.incidents.GetIncident(manager, inc.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := relay.Relay(manager); err == nil || n != 0 {
+		t.Fatalf("Relay to a broken publisher = %d, %v; want an error", n, err)
+	}
+
+	publisher.broken = false
+	if n, err := relay.Relay(manager); err != nil || n != 1 {
+		t.Fatalf("Relay = %d, %v; want 1", n, err)
+	}
+	if got := publisher.Of(domain.EventIncidentCreated); len(got) != 1 || got[0].RecordID != inc.ID {
+		t.Errorf("published %+v", got)
+	}
+	if n, err := relay.Relay(manager); err != nil || n != 0 {
+		t.Errorf("second Relay = %d, %v; want nothing left", n, err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// Heatmap places the risks matching filter in the cells of the risk matrix
+// by their inherent or residual ratings (basis, default inherent). Paging
+// and sorting are ignored.
+func (s *RiskService) Heatmap(ctx context.Context, filter RiskListFilter, basis string) (*domain.RiskHeatmap, error) {
+	if basis == "" {
+		basis = domain.HeatmapInherent
+	}
+	basis, ok := oneOf(basis, domain.HeatmapBases)
+	if !ok {
+		return nil, fmt.Errorf("%w: basis must be one of %s", ErrValidation, strings.Join(domain.HeatmapBases, ", "))
+	}
+
+	filter.Page = repository.Page{}
+	risks, _, err := s.ListRisks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &domain.RiskHeatmap{
+		Basis:           basis,
+		LikelihoodScale: m.LikelihoodScale,
+		ImpactScale:     m.ImpactScale,
+		Levels:          make([]string, 0, len(m.Bands)),
+		Cells:           make([]domain.HeatmapCell, 0, m.LikelihoodScale*m.ImpactScale),
+	}
+	for _, b := range m.Bands {
+		h.Levels = append(h.Levels, b.Level)
+	}
+	for l := 1; l <= m.LikelihoodScale; l++ {
+		for i := 1; i <= m.ImpactScale; i++ {
+			score, level := m.Assess(l, i)
+			h.Cells = append(h.Cells, domain.HeatmapCell{Likelihood: l, Impact: i, Score: score, Level: level, RiskIDs: []int{}})
+		}
+	}
+
+	for _, r := range risks {
+		l, i := r.Likelihood, r.Impact
+		if basis == domain.HeatmapResidual {
+			l, i = r.ResidualLikelihood, r.ResidualImpact
+		}
+		if !m.InRange(l, i) {
+			continue // Only deleted risks can be rated outside the matrix
+		}
+		c := &h.Cells[(l-1)*m.ImpactScale+i-1]
+		c.Count++
+		c.RiskIDs = append(c.RiskIDs, r.ID)
+		h.Total++
+	}
+	return h, nil
+}
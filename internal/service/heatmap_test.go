@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// heatmapCells returns the risk IDs of the non-empty cells by likelihood
+// and impact.
+func heatmapCells(t *testing.T, h *domain.RiskHeatmap) map[[2]int][]int {
+	t.Helper()
+	out := make(map[[2]int][]int)
+	for _, c := range h.Cells {
+		if c.Count != len(c.RiskIDs) {
+			t.Errorf("cell %d×%d counts %d risks but lists %v", c.Likelihood, c.Impact, c.Count, c.RiskIDs)
+		}
+		if c.Count > 0 {
+			out[[2]int{c.Likelihood, c.Impact}] = c.RiskIDs
+		}
+	}
+	return out
+}
+
+func TestHeatmapBasis(t *testing.T) {
+	env := newTestEnv(t)
+	two, one := 2, 1
+	treated := addRisk(t, env, CreateRiskInput{Likelihood: 4, Impact: 5, ResidualLikelihood: &two, ResidualImpact: &one})
+	untreated := addRisk(t, env, CreateRiskInput{Likelihood: 4, Impact: 5})
+	other := addRisk(t, env, CreateRiskInput{Likelihood: 2, Impact: 1})
+
+	tests := []struct {
+		basis string
+		want  map[[2]int][]int
+	}{
+		{"", map[[2]int][]int{{4, 5}: {treated.ID, untreated.ID}, {2, 1}: {other.ID}}},
+		{"Inherent", map[[2]int][]int{{4, 5}: {treated.ID, untreated.ID}, {2, 1}: {other.ID}}},
+		{"residual", map[[2]int][]int{{4, 5}: {untreated.ID}, {2, 1}: {treated.ID, other.ID}}},
+	}
+	for _, tt := range tests {
+		h, err := env.risks.Heatmap(manager, RiskListFilter{}, tt.basis)
+		if err != nil {
+			t.Fatalf("basis %q: %v", tt.basis, err)
+		}
+		want := domain.HeatmapInherent
+		if tt.basis == "residual" {
+			want = domain.HeatmapResidual
+		}
+		if h.Basis != want || h.Total != 3 {
+			t.Errorf("basis %q: heat map on %q with %d risks, want %q with 3", tt.basis, h.Basis, h.Total, want)
+		}
+		if got := heatmapCells(t, h); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("basis %q: cells %v, want %v", tt.basis, got, tt.want)
+		}
+	}
+
+	if _, err := env.risks.Heatmap(manager, RiskListFilter{}, "target"); !errors.Is(err, ErrValidation) {
+		t.Errorf("basis target = %v, want ErrValidation", err)
+	}
+}
+
+func TestHeatmapFollowsMatrix(t *testing.T) {
+	env := newTestEnv(t)
+	addRisk(t, env, CreateRiskInput{Likelihood: 2, Impact: 3})
+	m := domain.DefaultRiskMatrix()
+	m.LikelihoodScale, m.ImpactScale = 3, 4
+	m.Bands = m.Bands[:2]
+	m.Cells = []domain.RiskCell{{Likelihood: 1, Impact: 4, Level: "Medium"}}
+	if _, err := env.matrix.UpdateMatrix(manager, m); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := env.risks.Heatmap(manager, RiskListFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.LikelihoodScale != 3 || h.ImpactScale != 4 || len(h.Cells) != 12 || !reflect.DeepEqual(h.Levels, []string{"Low", "Medium"}) {
+		t.Fatalf("heat map %d×%d with %d cells and levels %v", h.LikelihoodScale, h.ImpactScale, len(h.Cells), h.Levels)
+	}
+	for i, c := range h.Cells {
+		if c.Likelihood != i/4+1 || c.Impact != i%4+1 || c.Score != c.Likelihood*c.Impact {
+			t.Errorf("cell %d is %d×%d scored %d", i, c.Likelihood, c.Impact, c.Score)
+		}
+	}
+	if c := h.Cells[3]; c.Level != "Medium" {
+		t.Errorf("cell 1×4 is %s, want the Medium override", c.Level)
+	}
+	if c := h.Cells[6]; c.Count != 1 {
+		t.Errorf("cell 2×3 counts %d risks, want 1", c.Count)
+	}
+
+	// The heat map only shows risks the caller can read.
+	h, err = env.risks.Heatmap(as(domain.RoleViewer, domain.DomainOHS), RiskListFilter{}, "")
+	if err != nil || h.Total != 0 {
+		t.Errorf("an OHS viewer sees %d Quality risks, %v", h.Total, err)
+	}
+}
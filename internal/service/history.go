@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// HistoryService covers the change history as a whole; the history of a
+// single record is served by the record's own service.
+type HistoryService struct {
+	history repository.HistoryRepository
+}
+
+func NewHistoryService(history repository.HistoryRepository) *HistoryService {
+	return &HistoryService{history: history}
+}
+
+// VerifyHistory checks the hash chain over all history entries. It is
+// evidence for auditors, so it needs the auditor or ims_manager role for
+// all domains.
+func (s *HistoryService) VerifyHistory(ctx context.Context) (*domain.HistoryVerification, error) {
+	if err := authorize(ctx, permAudit, "", "verifying the change history"); err != nil {
+		return nil, err
+	}
+	return s.history.Verify()
+}
@@ -1,10 +1,13 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/xenakil/integraflow-ims/internal/auth"
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
@@ -12,10 +15,21 @@ import (
 type IncidentService struct {
 	incRepo  repository.IncidentRepository
 	riskRepo repository.RiskRepository
+	aspects  repository.AspectRepository
+	matrix   repository.RiskMatrixRepository
+	history  repository.HistoryRepository
+	events   *EventRelay
 }
 
-func NewIncidentService(incRepo repository.IncidentRepository, riskRepo repository.RiskRepository) *IncidentService {
-	return &IncidentService{incRepo: incRepo, riskRepo: riskRepo}
+func NewIncidentService(
+	incRepo repository.IncidentRepository,
+	riskRepo repository.RiskRepository,
+	aspects repository.AspectRepository,
+	matrix repository.RiskMatrixRepository,
+	history repository.HistoryRepository,
+	events *EventRelay,
+) *IncidentService {
+	return &IncidentService{incRepo: incRepo, riskRepo: riskRepo, aspects: aspects, matrix: matrix, history: history, events: events}
 }
 
 type CreateIncidentInput struct {
@@ -23,16 +37,39 @@ type CreateIncidentInput struct {
 	Description   string
 	Domain        string
 	RelatedRiskID *int
+	AspectID      *int // Environmental aspect; Environment incidents only
 	Severity      int
 	Likelihood    int
+	OccurredOn    string // YYYY-MM-DD, today if empty
+
+	// OHS classification; see classifyIncident.
+	Type              string
+	InjuredPerson     *domain.InjuredPerson
+	BodyPart          string
+	InjuryNature      string
+	DaysLost          int
+	Reportable        bool
+	ReportingDeadline string
+	ReportedOn        string
 }
 
 type IncidentListFilter struct {
-	Domain *domain.Domain
-	Status *string
+	Domain         *domain.Domain
+	ProcessID      *int // Process of the related risk
+	Status         *string
+	Level          *string
+	RelatedRiskID  *int
+	AspectID       *int
+	Type           *string
+	Reportable     *bool
+	Created        repository.DateRange
+	Occurred       repository.DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           repository.Page
 }
 
-func (s *IncidentService) CreateIncident(in CreateIncidentInput) (*domain.Incident, error) {
+func (s *IncidentService) CreateIncident(ctx context.Context, in CreateIncidentInput) (*domain.Incident, error) {
 	if strings.TrimSpace(in.Title) == "" || strings.TrimSpace(in.Description) == "" {
 		return nil, fmt.Errorf("%w: title and description are required", ErrValidation)
 	}
@@ -41,9 +78,13 @@ func (s *IncidentService) CreateIncident(in CreateIncidentInput) (*domain.Incide
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
 	}
+	if err := authorize(ctx, permContribute, dom, "reporting incidents"); err != nil {
+		return nil, err
+	}
 
-	if in.Severity < 1 || in.Severity > 5 || in.Likelihood < 1 || in.Likelihood > 5 {
-		return nil, fmt.Errorf("%w: severity and likelihood must be between 1 and 5", ErrValidation)
+	score, level, err := assess(s.matrix, in.Likelihood, in.Severity, "severity")
+	if err != nil {
+		return nil, err
 	}
 
 	if in.RelatedRiskID != nil {
@@ -55,82 +96,276 @@ func (s *IncidentService) CreateIncident(in CreateIncidentInput) (*domain.Incide
 		}
 	}
 
-	score := in.Severity * in.Likelihood
-	level := domain.RiskLevelFromScore(score)
 	now := time.Now().Format(time.RFC3339)
+	occurred := strings.TrimSpace(in.OccurredOn)
+	if occurred == "" {
+		occurred = time.Now().Format(time.DateOnly)
+	}
 
 	inc := &domain.Incident{
-		Title:         in.Title,
-		Description:   in.Description,
-		Domain:        dom,
-		RelatedRiskID: in.RelatedRiskID,
-		Severity:      in.Severity,
-		Likelihood:    in.Likelihood,
-		RiskScore:     score,
-		RiskLevel:     level,
-		RootCause:     "",
-		Status:        "Open",
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		Title:             in.Title,
+		Description:       in.Description,
+		Domain:            dom,
+		RelatedRiskID:     in.RelatedRiskID,
+		Severity:          in.Severity,
+		Likelihood:        in.Likelihood,
+		RiskScore:         score,
+		RiskLevel:         level,
+		RootCause:         "",
+		Status:            "Open",
+		OccurredOn:        occurred,
+		Type:              in.Type,
+		InjuredPerson:     in.InjuredPerson,
+		BodyPart:          in.BodyPart,
+		InjuryNature:      in.InjuryNature,
+		DaysLost:          in.DaysLost,
+		Reportable:        in.Reportable,
+		ReportingDeadline: strings.TrimSpace(in.ReportingDeadline),
+		ReportedOn:        strings.TrimSpace(in.ReportedOn),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		CreatedBy:         auth.Actor(ctx),
+		UpdatedBy:         auth.Actor(ctx),
+	}
+	if err := classifyIncident(inc); err != nil {
+		return nil, err
+	}
+	if err := s.setAspect(inc, in.AspectID); err != nil {
+		return nil, err
 	}
 
 	if err := s.incRepo.Create(inc); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
 	return inc, nil
 }
 
-func (s *IncidentService) ListIncidents(filter IncidentListFilter) ([]*domain.Incident, error) {
-	all, err := s.incRepo.GetAll()
+func (s *IncidentService) ListIncidents(ctx context.Context, filter IncidentListFilter) ([]*domain.Incident, int, error) {
+	domains, err := readScope(ctx, filter.Domain, "listing incidents")
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-
-	out := make([]*domain.Incident, 0)
-	for _, inc := range all {
-		if filter.Domain != nil && inc.Domain != *filter.Domain {
-			continue
-		}
-		if filter.Status != nil && !strings.EqualFold(inc.Status, *filter.Status) {
-			continue
-		}
-		out = append(out, inc)
-	}
-	return out, nil
+	return s.incRepo.List(repository.IncidentQuery{
+		Domain:         filter.Domain,
+		Domains:        domains,
+		ProcessID:      filter.ProcessID,
+		Status:         filter.Status,
+		Level:          filter.Level,
+		RelatedRiskID:  filter.RelatedRiskID,
+		AspectID:       filter.AspectID,
+		Type:           filter.Type,
+		Reportable:     filter.Reportable,
+		Created:        filter.Created,
+		Occurred:       filter.Occurred,
+		Search:         filter.Search,
+		IncludeDeleted: filter.IncludeDeleted,
+		Page:           filter.Page,
+	})
 }
 
-func (s *IncidentService) GetIncident(id int) (*domain.Incident, error) {
-	return s.incRepo.GetByID(id)
+// GetIncident returns an incident; a deleted one only when includeDeleted
+// is set.
+func (s *IncidentService) GetIncident(ctx context.Context, id int, includeDeleted bool) (*domain.Incident, error) {
+	inc, err := s.incRepo.GetByID(id)
+	if includeDeleted && errors.Is(err, repository.ErrNotFound) {
+		inc, err = s.incRepo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, inc.Domain, "viewing incidents"); err != nil {
+		return nil, err
+	}
+	return inc, nil
 }
 
+// UpdateIncidentInput carries a partial update; nil fields are left
+// unchanged. Setting Reportable to false clears the reporting dates, an
+// InjuredPerson without a name removes the injured person and an AspectID of
+// 0 unlinks the environmental aspect.
 type UpdateIncidentInput struct {
-	RootCause *string
-	Status    *string
+	RootCause  *string
+	Status     *string
+	OccurredOn *string
+	AspectID   *int
+
+	Type              *string
+	InjuredPerson     *domain.InjuredPerson
+	BodyPart          *string
+	InjuryNature      *string
+	DaysLost          *int
+	Reportable        *bool
+	ReportingDeadline *string
+	ReportedOn        *string
 }
 
-func (s *IncidentService) UpdateIncident(id int, in UpdateIncidentInput) (*domain.Incident, error) {
+func (s *IncidentService) UpdateIncident(ctx context.Context, id int, in UpdateIncidentInput) (*domain.Incident, error) {
 	inc, err := s.incRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(ctx, permContribute, inc.Domain, "editing incidents"); err != nil {
+		return nil, err
+	}
+	from := inc.Status
 
 	if in.RootCause != nil {
 		inc.RootCause = strings.TrimSpace(*in.RootCause)
 	}
+	if err := applyClassification(inc, in); err != nil {
+		return nil, err
+	}
+	if in.AspectID != nil {
+		id := in.AspectID
+		if *id == 0 {
+			id = nil
+		}
+		if err := s.setAspect(inc, id); err != nil {
+			return nil, err
+		}
+	}
 	if in.Status != nil {
-		status := strings.TrimSpace(*in.Status)
-		normalized := strings.Title(strings.ToLower(status))
-		switch normalized {
-		case "Open", "Investigation", "Closed":
-		default:
+		normalized, ok := domain.IncidentWorkflow.Normalize(*in.Status)
+		if !ok {
 			return nil, fmt.Errorf("%w: invalid incident status", ErrValidation)
 		}
+		if normalized == "Closed" && inc.Status != "Closed" {
+			if err := authorize(ctx, permApprove, inc.Domain, "closing incidents"); err != nil {
+				return nil, err
+			}
+		}
 		inc.Status = normalized
 	}
+	if err := domain.IncidentWorkflow.Check(from, inc.Status, inc); err != nil {
+		return nil, err
+	}
 	inc.UpdatedAt = time.Now().Format(time.RFC3339)
+	inc.UpdatedBy = auth.Actor(ctx)
 
 	if err := s.incRepo.Update(inc); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
 	return inc, nil
 }
+
+// DeleteIncident soft-deletes an incident. Incidents that still have active
+// actions are not deleted; repository.ErrInUse is returned instead.
+func (s *IncidentService) DeleteIncident(ctx context.Context, id int) error {
+	inc, err := s.incRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorize(ctx, permApprove, inc.Domain, "deleting incidents"); err != nil {
+		return err
+	}
+	return s.incRepo.Delete(id, auth.Actor(ctx))
+}
+
+func (s *IncidentService) RestoreIncident(ctx context.Context, id int) (*domain.Incident, error) {
+	inc, err := s.incRepo.GetDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permApprove, inc.Domain, "restoring incidents"); err != nil {
+		return nil, err
+	}
+	if err := s.incRepo.Restore(id, auth.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	if inc, err = s.incRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	// Re-score against the current risk matrix; see RiskService.RestoreRisk.
+	m, err := s.matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+	if !m.InRange(inc.Likelihood, inc.Severity) {
+		return inc, nil
+	}
+	if score, level := m.Assess(inc.Likelihood, inc.Severity); score != inc.RiskScore || level != inc.RiskLevel {
+		inc.RiskScore, inc.RiskLevel = score, level
+		inc.UpdatedAt = time.Now().Format(time.RFC3339)
+		inc.UpdatedBy = auth.Actor(ctx)
+		if err := s.incRepo.Update(inc); err != nil {
+			return nil, err
+		}
+	}
+	return inc, nil
+}
+
+// IncidentHistory returns the change history of an incident, including
+// deleted ones.
+func (s *IncidentService) IncidentHistory(ctx context.Context, id int) ([]*domain.HistoryEntry, error) {
+	inc, err := s.incRepo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		inc, err = s.incRepo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, inc.Domain, "viewing incident history"); err != nil {
+		return nil, err
+	}
+	return s.history.List(domain.KindIncident, id)
+}
+
+// setAspect links the incident to the environmental aspect with id, or to
+// none when id is nil. Only Environment incidents can have an aspect.
+func (s *IncidentService) setAspect(inc *domain.Incident, id *int) error {
+	inc.AspectID = nil
+	if id == nil {
+		return nil
+	}
+	if inc.Domain != domain.DomainEnv {
+		return fmt.Errorf("%w: only %s incidents can be linked to an environmental aspect", ErrValidation, domain.DomainEnv)
+	}
+	if _, err := s.aspects.GetByID(*id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: environmental aspect %d does not exist", ErrValidation, *id)
+		}
+		return err
+	}
+	inc.AspectID = id
+	return nil
+}
+
+// applyClassification applies the OHS fields of in to inc and checks the
+// result.
+func applyClassification(inc *domain.Incident, in UpdateIncidentInput) error {
+	if in.OccurredOn != nil {
+		inc.OccurredOn = strings.TrimSpace(*in.OccurredOn)
+	}
+	if in.Type != nil {
+		inc.Type = strings.TrimSpace(*in.Type)
+	}
+	if in.InjuredPerson != nil {
+		p := *in.InjuredPerson
+		inc.InjuredPerson = &p
+	}
+	if in.BodyPart != nil {
+		inc.BodyPart = *in.BodyPart
+	}
+	if in.InjuryNature != nil {
+		inc.InjuryNature = *in.InjuryNature
+	}
+	if in.DaysLost != nil {
+		inc.DaysLost = *in.DaysLost
+	}
+	if in.Reportable != nil {
+		inc.Reportable = *in.Reportable
+		if !inc.Reportable {
+			inc.ReportingDeadline, inc.ReportedOn = "", ""
+		}
+	}
+	if in.ReportingDeadline != nil {
+		inc.ReportingDeadline = strings.TrimSpace(*in.ReportingDeadline)
+	}
+	if in.ReportedOn != nil {
+		inc.ReportedOn = strings.TrimSpace(*in.ReportedOn)
+	}
+	return classifyIncident(inc)
+}
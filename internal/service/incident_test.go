@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+func TestGetDeletedIncident(t *testing.T) {
+	env := newTestEnv(t)
+	inc := reportIncident(t, env)
+	if err := env.incidents.DeleteIncident(manager, inc.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.incidents.GetIncident(manager, inc.ID, false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetIncident of a deleted incident = %v, want ErrNotFound", err)
+	}
+	got, err := env.incidents.GetIncident(as(domain.RoleViewer, domain.DomainOHS), inc.ID, true)
+	if err != nil || got.DeletedBy != "ims_manager" {
+		t.Fatalf("GetIncident including deleted = %+v, %v", got, err)
+	}
+	if _, err := env.incidents.GetIncident(as(domain.RoleViewer, domain.DomainQuality), inc.ID, true); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading a deleted OHS incident as a Quality viewer = %v, want ErrForbidden", err)
+	}
+}
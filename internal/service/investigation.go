@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// InvestigationInput creates or replaces the investigation of an incident.
+type InvestigationInput struct {
+	Problem  string
+	Whys     []domain.WhyStep
+	Fishbone []domain.FishboneCategory
+}
+
+// GetInvestigation returns the investigation of an incident.
+func (s *IncidentService) GetInvestigation(ctx context.Context, id int) (*domain.Investigation, error) {
+	inc, err := s.incRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, inc.Domain, "viewing incident investigations"); err != nil {
+		return nil, err
+	}
+	return s.incRepo.Investigation(id)
+}
+
+// SaveInvestigation creates or replaces the investigation of an incident.
+// When it flags a root cause, the incident's RootCause is set to it;
+// otherwise the incident's RootCause is left as it is.
+func (s *IncidentService) SaveInvestigation(ctx context.Context, id int, in InvestigationInput) (*domain.Investigation, error) {
+	inc, err := s.incRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permContribute, inc.Domain, "investigating incidents"); err != nil {
+		return nil, err
+	}
+
+	whys, err := normalizeWhys(in.Whys)
+	if err != nil {
+		return nil, err
+	}
+	fishbone, err := normalizeFishbone(in.Fishbone)
+	if err != nil {
+		return nil, err
+	}
+	if len(whys) == 0 && len(fishbone) == 0 {
+		return nil, fmt.Errorf("%w: an investigation needs whys or a fishbone diagram", ErrValidation)
+	}
+	roots := flaggedWhys(whys)
+	for _, c := range fishbone {
+		roots = append(roots, flaggedCauses(c.Causes)...)
+	}
+	if len(roots) > 1 {
+		return nil, fmt.Errorf("%w: only one why or cause can be flagged as the root cause, got %d", ErrValidation, len(roots))
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	inv := &domain.Investigation{
+		IncidentID: id,
+		Problem:    strings.TrimSpace(in.Problem),
+		Whys:       whys,
+		Fishbone:   fishbone,
+		CreatedAt:  now,
+		CreatedBy:  auth.Actor(ctx),
+		UpdatedAt:  now,
+		UpdatedBy:  auth.Actor(ctx),
+	}
+	if prev, err := s.incRepo.Investigation(id); err == nil {
+		inv.CreatedAt, inv.CreatedBy = prev.CreatedAt, prev.CreatedBy
+	}
+	if len(roots) == 1 {
+		inv.RootCause = roots[0]
+		if inc.RootCause != inv.RootCause {
+			inc.RootCause = inv.RootCause
+			inc.UpdatedAt = now
+			inc.UpdatedBy = auth.Actor(ctx)
+		}
+	}
+
+	if err := s.incRepo.SaveInvestigation(inc, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// DeleteInvestigation removes the investigation of an incident. The
+// incident keeps its RootCause.
+func (s *IncidentService) DeleteInvestigation(ctx context.Context, id int) error {
+	inc, err := s.incRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorize(ctx, permApprove, inc.Domain, "deleting incident investigations"); err != nil {
+		return err
+	}
+	return s.incRepo.DeleteInvestigation(id)
+}
+
+func normalizeWhys(whys []domain.WhyStep) ([]domain.WhyStep, error) {
+	out := make([]domain.WhyStep, 0, len(whys))
+	for i, w := range whys {
+		w.Question = strings.TrimSpace(w.Question)
+		w.Answer = strings.TrimSpace(w.Answer)
+		if w.Answer == "" {
+			return nil, fmt.Errorf("%w: whys[%d] needs an answer", ErrValidation, i)
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+// normalizeFishbone checks the categories, which may appear once each, and
+// their causes.
+func normalizeFishbone(categories []domain.FishboneCategory) ([]domain.FishboneCategory, error) {
+	out := make([]domain.FishboneCategory, 0, len(categories))
+	seen := make(map[string]bool)
+	for i, c := range categories {
+		category, ok := oneOf(c.Category, domain.CauseCategories)
+		if !ok {
+			return nil, fmt.Errorf("%w: fishbone[%d].category must be one of %s", ErrValidation, i, strings.Join(domain.CauseCategories, ", "))
+		}
+		if seen[category] {
+			return nil, fmt.Errorf("%w: fishbone category %s appears more than once", ErrValidation, category)
+		}
+		seen[category] = true
+		causes, err := normalizeCauses(c.Causes, fmt.Sprintf("fishbone[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, domain.FishboneCategory{Category: category, Causes: causes})
+	}
+	return out, nil
+}
+
+func normalizeCauses(causes []domain.CauseNode, path string) ([]domain.CauseNode, error) {
+	out := make([]domain.CauseNode, 0, len(causes))
+	for i, c := range causes {
+		at := fmt.Sprintf("%s.causes[%d]", path, i)
+		c.Cause = strings.TrimSpace(c.Cause)
+		if c.Cause == "" {
+			return nil, fmt.Errorf("%w: %s needs a cause", ErrValidation, at)
+		}
+		sub, err := normalizeCauses(c.Causes, at)
+		if err != nil {
+			return nil, err
+		}
+		if len(sub) == 0 {
+			sub = nil
+		}
+		c.Causes = sub
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func flaggedWhys(whys []domain.WhyStep) []string {
+	var out []string
+	for _, w := range whys {
+		if w.RootCause {
+			out = append(out, w.Answer)
+		}
+	}
+	return out
+}
+
+func flaggedCauses(causes []domain.CauseNode) []string {
+	var out []string
+	for _, c := range causes {
+		if c.RootCause {
+			out = append(out, c.Cause)
+		}
+		out = append(out, flaggedCauses(c.Causes)...)
+	}
+	return out
+}
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ISMSService manages the information asset inventory and the controls
+// catalogue, and generates the Statement of Applicability. Both belong to
+// the Information Security domain; applicability decisions take the right
+// to approve in it.
+type ISMSService struct {
+	assets   repository.AssetRepository
+	controls repository.ISMSControlRepository
+	risks    repository.RiskRepository
+}
+
+func NewISMSService(assets repository.AssetRepository, controls repository.ISMSControlRepository, risks repository.RiskRepository) *ISMSService {
+	return &ISMSService{assets: assets, controls: controls, risks: risks}
+}
+
+// ---------- Information assets ----------
+
+type CreateAssetInput struct {
+	Name            string
+	Description     string
+	Type            string
+	Owner           string
+	Location        string
+	Classification  string // Internal if empty
+	Confidentiality int
+	Integrity       int
+	Availability    int
+}
+
+type AssetListFilter struct {
+	Type           *string
+	Classification *string
+	Owner          *string
+	Search         string
+	Page           repository.Page
+}
+
+func (s *ISMSService) CreateAsset(ctx context.Context, in CreateAssetInput) (*domain.InformationAsset, error) {
+	if err := authorize(ctx, permContribute, domain.DomainISMS, "registering information assets"); err != nil {
+		return nil, err
+	}
+	classification := in.Classification
+	if strings.TrimSpace(classification) == "" {
+		classification = domain.ClassificationInternal
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	a := &domain.InformationAsset{
+		Name:            strings.TrimSpace(in.Name),
+		Description:     strings.TrimSpace(in.Description),
+		Type:            in.Type,
+		Owner:           strings.TrimSpace(in.Owner),
+		Location:        strings.TrimSpace(in.Location),
+		Classification:  classification,
+		Confidentiality: in.Confidentiality,
+		Integrity:       in.Integrity,
+		Availability:    in.Availability,
+		CreatedAt:       now,
+		CreatedBy:       auth.Actor(ctx),
+		UpdatedAt:       now,
+		UpdatedBy:       auth.Actor(ctx),
+	}
+	if err := checkAsset(a); err != nil {
+		return nil, err
+	}
+	if err := s.assets.Create(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *ISMSService) ListAssets(ctx context.Context, filter AssetListFilter) ([]*domain.InformationAsset, int, error) {
+	if err := authorize(ctx, permRead, domain.DomainISMS, "viewing information assets"); err != nil {
+		return nil, 0, err
+	}
+	return s.assets.List(repository.AssetQuery{
+		Type:           filter.Type,
+		Classification: filter.Classification,
+		Owner:          filter.Owner,
+		Search:         filter.Search,
+		Page:           filter.Page,
+	})
+}
+
+func (s *ISMSService) GetAsset(ctx context.Context, id int) (*domain.InformationAsset, error) {
+	if err := authorize(ctx, permRead, domain.DomainISMS, "viewing information assets"); err != nil {
+		return nil, err
+	}
+	return s.assets.GetByID(id)
+}
+
+// UpdateAssetInput carries a partial update; nil fields are left unchanged.
+type UpdateAssetInput struct {
+	Name            *string
+	Description     *string
+	Type            *string
+	Owner           *string
+	Location        *string
+	Classification  *string
+	Confidentiality *int
+	Integrity       *int
+	Availability    *int
+}
+
+func (s *ISMSService) UpdateAsset(ctx context.Context, id int, in UpdateAssetInput) (*domain.InformationAsset, error) {
+	if err := authorize(ctx, permContribute, domain.DomainISMS, "editing information assets"); err != nil {
+		return nil, err
+	}
+	a, err := s.assets.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		a.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.Description != nil {
+		a.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.Type != nil {
+		a.Type = *in.Type
+	}
+	if in.Owner != nil {
+		a.Owner = strings.TrimSpace(*in.Owner)
+	}
+	if in.Location != nil {
+		a.Location = strings.TrimSpace(*in.Location)
+	}
+	if in.Classification != nil {
+		a.Classification = *in.Classification
+	}
+	if in.Confidentiality != nil {
+		a.Confidentiality = *in.Confidentiality
+	}
+	if in.Integrity != nil {
+		a.Integrity = *in.Integrity
+	}
+	if in.Availability != nil {
+		a.Availability = *in.Availability
+	}
+	if err := checkAsset(a); err != nil {
+		return nil, err
+	}
+	a.UpdatedAt = time.Now().Format(time.RFC3339)
+	a.UpdatedBy = auth.Actor(ctx)
+
+	if err := s.assets.Update(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteAsset removes an asset. Assets linked to risks are not deleted;
+// repository.ErrInUse is returned instead.
+func (s *ISMSService) DeleteAsset(ctx context.Context, id int) error {
+	if err := authorize(ctx, permApprove, domain.DomainISMS, "deleting information assets"); err != nil {
+		return err
+	}
+	return s.assets.Delete(id)
+}
+
+// checkAsset validates a, normalizes its type and classification and sets
+// its criticality.
+func checkAsset(a *domain.InformationAsset) error {
+	if a.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	t, ok := oneOf(a.Type, domain.AssetTypes)
+	if !ok {
+		return fmt.Errorf("%w: type must be one of %s", ErrValidation, strings.Join(domain.AssetTypes, ", "))
+	}
+	a.Type = t
+	c, ok := oneOf(a.Classification, domain.Classifications)
+	if !ok {
+		return fmt.Errorf("%w: classification must be one of %s", ErrValidation, strings.Join(domain.Classifications, ", "))
+	}
+	a.Classification = c
+	for _, r := range []int{a.Confidentiality, a.Integrity, a.Availability} {
+		if r < 1 || r > domain.AssetRatingScale {
+			return fmt.Errorf("%w: confidentiality, integrity and availability must be between 1 and %d", ErrValidation, domain.AssetRatingScale)
+		}
+	}
+	a.Criticality = max(a.Confidentiality, a.Integrity, a.Availability)
+	return nil
+}
+
+// ---------- Controls catalogue ----------
+
+var controlIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]{0,31}$`)
+
+type CreateControlInput struct {
+	ID                   string
+	Theme                string
+	Title                string
+	Description          string
+	Applicable           *bool // Defaults to true
+	Justification        string
+	ImplementationStatus string // Not Implemented if empty
+	Owner                string
+}
+
+type ControlListFilter struct {
+	Theme      *string
+	Applicable *bool
+	Status     *string
+	Search     string
+	Page       repository.Page
+}
+
+// SeedAnnexA adds the ISO 27001:2022 Annex A controls missing from the
+// catalogue as applicable and not implemented, and returns how many it
+// added. Controls already in the catalogue are left as they are.
+func (s *ISMSService) SeedAnnexA(ctx context.Context) (int, error) {
+	if err := authorize(ctx, permConfigure, "", "seeding the controls catalogue"); err != nil {
+		return 0, err
+	}
+	now := time.Now().Format(time.RFC3339)
+	controls := make([]domain.ISMSControl, len(domain.AnnexA))
+	for i, c := range domain.AnnexA {
+		c.AnnexA = true
+		c.Applicable = true
+		c.ImplementationStatus = domain.ImplementationNotImplemented
+		c.UpdatedAt = now
+		c.UpdatedBy = auth.Actor(ctx)
+		controls[i] = c
+	}
+	return s.controls.Seed(controls)
+}
+
+// CreateControl adds a control of the organisation's own to the catalogue.
+func (s *ISMSService) CreateControl(ctx context.Context, in CreateControlInput) (*domain.ISMSControl, error) {
+	if err := authorize(ctx, permApprove, domain.DomainISMS, "managing the controls catalogue"); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(in.ID)
+	if !controlIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: id must be 1 to 32 letters, digits, dots or hyphens", ErrValidation)
+	}
+	if slices.ContainsFunc(domain.AnnexA, func(c domain.ISMSControl) bool { return c.ID == id }) {
+		return nil, fmt.Errorf("%w: %s is an Annex A control; seed the catalogue instead", ErrValidation, id)
+	}
+	status := in.ImplementationStatus
+	if strings.TrimSpace(status) == "" {
+		status = domain.ImplementationNotImplemented
+	}
+	c := &domain.ISMSControl{
+		ID:                   id,
+		Theme:                in.Theme,
+		Title:                strings.TrimSpace(in.Title),
+		Description:          strings.TrimSpace(in.Description),
+		Applicable:           in.Applicable == nil || *in.Applicable,
+		Justification:        strings.TrimSpace(in.Justification),
+		ImplementationStatus: status,
+		Owner:                strings.TrimSpace(in.Owner),
+		UpdatedAt:            time.Now().Format(time.RFC3339),
+		UpdatedBy:            auth.Actor(ctx),
+	}
+	if err := checkControl(c); err != nil {
+		return nil, err
+	}
+	if err := s.controls.Create(c); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: control %s is already in the catalogue", err, id)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *ISMSService) ListControls(ctx context.Context, filter ControlListFilter) ([]*domain.ISMSControl, int, error) {
+	if err := authorize(ctx, permRead, domain.DomainISMS, "viewing the controls catalogue"); err != nil {
+		return nil, 0, err
+	}
+	return s.controls.List(repository.ISMSControlQuery{
+		Theme:      filter.Theme,
+		Applicable: filter.Applicable,
+		Status:     filter.Status,
+		Search:     filter.Search,
+		Page:       filter.Page,
+	})
+}
+
+func (s *ISMSService) GetControl(ctx context.Context, id string) (*domain.ISMSControl, error) {
+	if err := authorize(ctx, permRead, domain.DomainISMS, "viewing the controls catalogue"); err != nil {
+		return nil, err
+	}
+	return s.controls.GetByID(id)
+}
+
+// UpdateControlInput carries a partial update; nil fields are left
+// unchanged. The theme, title and description of Annex A controls can't be
+// changed.
+type UpdateControlInput struct {
+	Theme                *string
+	Title                *string
+	Description          *string
+	Applicable           *bool
+	Justification        *string
+	ImplementationStatus *string
+	Owner                *string
+}
+
+func (s *ISMSService) UpdateControl(ctx context.Context, id string, in UpdateControlInput) (*domain.ISMSControl, error) {
+	if err := authorize(ctx, permApprove, domain.DomainISMS, "managing the controls catalogue"); err != nil {
+		return nil, err
+	}
+	c, err := s.controls.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c.AnnexA && (in.Theme != nil || in.Title != nil || in.Description != nil) {
+		return nil, fmt.Errorf("%w: the theme, title and description of Annex A controls can't be changed", ErrValidation)
+	}
+	if in.Theme != nil {
+		c.Theme = *in.Theme
+	}
+	if in.Title != nil {
+		c.Title = strings.TrimSpace(*in.Title)
+	}
+	if in.Description != nil {
+		c.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.Applicable != nil {
+		c.Applicable = *in.Applicable
+	}
+	if in.Justification != nil {
+		c.Justification = strings.TrimSpace(*in.Justification)
+	}
+	if in.ImplementationStatus != nil {
+		c.ImplementationStatus = *in.ImplementationStatus
+	}
+	if in.Owner != nil {
+		c.Owner = strings.TrimSpace(*in.Owner)
+	}
+	if err := checkControl(c); err != nil {
+		return nil, err
+	}
+	c.UpdatedAt = time.Now().Format(time.RFC3339)
+	c.UpdatedBy = auth.Actor(ctx)
+
+	if err := s.controls.Update(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteControl removes a control of the organisation's own. Annex A
+// controls stay in the catalogue and are excluded by marking them not
+// applicable; controls linked to risks are not deleted and
+// repository.ErrInUse is returned instead.
+func (s *ISMSService) DeleteControl(ctx context.Context, id string) error {
+	if err := authorize(ctx, permApprove, domain.DomainISMS, "managing the controls catalogue"); err != nil {
+		return err
+	}
+	c, err := s.controls.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if c.AnnexA {
+		return fmt.Errorf("%w: Annex A controls can't be deleted; mark them not applicable instead", ErrValidation)
+	}
+	return s.controls.Delete(id)
+}
+
+// checkControl validates c and normalizes its theme and implementation
+// status. Excluding a control requires a justification.
+func checkControl(c *domain.ISMSControl) error {
+	if c.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrValidation)
+	}
+	theme, ok := oneOf(c.Theme, domain.ControlThemes)
+	if !ok {
+		return fmt.Errorf("%w: theme must be one of %s", ErrValidation, strings.Join(domain.ControlThemes, ", "))
+	}
+	c.Theme = theme
+	status, ok := oneOf(c.ImplementationStatus, domain.ImplementationStatuses)
+	if !ok {
+		return fmt.Errorf("%w: implementationStatus must be one of %s", ErrValidation, strings.Join(domain.ImplementationStatuses, ", "))
+	}
+	c.ImplementationStatus = status
+	if !c.Applicable && c.Justification == "" {
+		return fmt.Errorf("%w: control %s needs a justification to be excluded", ErrValidation, c.ID)
+	}
+	return nil
+}
+
+// ---------- Statement of Applicability ----------
+
+// StatementOfApplicability lists the controls catalogue in ID order with
+// the active Information Security risks linked to each control.
+func (s *ISMSService) StatementOfApplicability(ctx context.Context) (*domain.StatementOfApplicability, error) {
+	if err := authorize(ctx, permRead, domain.DomainISMS, "viewing the statement of applicability"); err != nil {
+		return nil, err
+	}
+	controls, _, err := s.controls.List(repository.ISMSControlQuery{})
+	if err != nil {
+		return nil, err
+	}
+	isms := domain.DomainISMS
+	risks, _, err := s.risks.List(repository.RiskQuery{Domain: &isms})
+	if err != nil {
+		return nil, err
+	}
+	return statementOfApplicability(controls, risks, time.Now()), nil
+}
+
+func statementOfApplicability(controls []*domain.ISMSControl, risks []*domain.Risk, now time.Time) *domain.StatementOfApplicability {
+	byControl := make(map[string][]domain.SoARisk)
+	for _, r := range risks {
+		for _, id := range r.ISMSControls {
+			byControl[id] = append(byControl[id], domain.SoARisk{
+				ID: r.ID, Title: r.Title, ResidualLevel: r.ResidualLevel, Status: r.Status,
+			})
+		}
+	}
+
+	soa := &domain.StatementOfApplicability{
+		GeneratedAt: now.Format(time.RFC3339),
+		Controls:    make([]domain.SoAEntry, 0, len(controls)),
+		ByStatus:    make(map[string]int),
+	}
+	for _, c := range controls {
+		linked := byControl[c.ID]
+		if linked == nil {
+			linked = []domain.SoARisk{}
+		}
+		soa.Controls = append(soa.Controls, domain.SoAEntry{ISMSControl: *c, Risks: linked})
+		if c.Applicable {
+			soa.Applicable++
+			soa.ByStatus[c.ImplementationStatus]++
+		} else {
+			soa.Excluded++
+		}
+	}
+	return soa
+}
+
+// ---------- Risk links ----------
+
+// linkISMS sets the assets and catalogue controls of r, checking that they
+// exist. Nil lists leave the links unchanged.
+func linkISMS(assets repository.AssetRepository, controls repository.ISMSControlRepository, r *domain.Risk, assetIDs []int, controlIDs []string) error {
+	if assetIDs != nil {
+		ids := make([]int, 0, len(assetIDs))
+		for _, id := range assetIDs {
+			if slices.Contains(ids, id) {
+				continue
+			}
+			if _, err := assets.GetByID(id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return fmt.Errorf("%w: asset %d does not exist", ErrValidation, id)
+				}
+				return err
+			}
+			ids = append(ids, id)
+		}
+		r.AssetIDs = ids
+	}
+	if controlIDs != nil {
+		ids := make([]string, 0, len(controlIDs))
+		for _, id := range controlIDs {
+			id = strings.TrimSpace(id)
+			if slices.Contains(ids, id) {
+				continue
+			}
+			if _, err := controls.GetByID(id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return fmt.Errorf("%w: control %q is not in the controls catalogue", ErrValidation, id)
+				}
+				return err
+			}
+			ids = append(ids, id)
+		}
+		r.ISMSControls = ids
+	}
+	if r.Domain != domain.DomainISMS && (len(r.AssetIDs) > 0 || len(r.ISMSControls) > 0) {
+		return fmt.Errorf("%w: only %s risks can be linked to assets and ISMS controls", ErrValidation, domain.DomainISMS)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// OHSService keeps the hours worked and computes injury statistics from the
+// OHS classification of incidents. Anyone with a role can read the hours;
+// the statistics need read access to OHS, and recording hours requires the
+// ims_manager role for all domains.
+type OHSService struct {
+	hours   repository.HoursWorkedRepository
+	incRepo repository.IncidentRepository
+}
+
+func NewOHSService(hours repository.HoursWorkedRepository, incRepo repository.IncidentRepository) *OHSService {
+	return &OHSService{hours: hours, incRepo: incRepo}
+}
+
+// Codes returns the classification values incidents accept.
+func (s *OHSService) Codes(ctx context.Context) (*domain.OHSCodes, error) {
+	if _, err := readScope(ctx, nil, "viewing OHS codes"); err != nil {
+		return nil, err
+	}
+	return &domain.OHSCodes{
+		IncidentTypes:   domain.IncidentTypes,
+		EmploymentTypes: domain.EmploymentTypes,
+		BodyParts:       domain.BodyParts,
+		InjuryNatures:   domain.InjuryNatures,
+	}, nil
+}
+
+// ListHoursWorked returns the recorded months from from to to (YYYY-MM,
+// inclusive; either may be empty).
+func (s *OHSService) ListHoursWorked(ctx context.Context, from, to string) ([]*domain.HoursWorked, error) {
+	if _, err := readScope(ctx, nil, "viewing hours worked"); err != nil {
+		return nil, err
+	}
+	for _, m := range []string{from, to} {
+		if m != "" && !validMonth(m) {
+			return nil, fmt.Errorf("%w: invalid month %q, expected YYYY-MM", ErrValidation, m)
+		}
+	}
+	return s.hours.List(from, to)
+}
+
+// SetHoursWorked records the hours worked in a month, replacing an earlier
+// figure.
+func (s *OHSService) SetHoursWorked(ctx context.Context, month string, hours float64) (*domain.HoursWorked, error) {
+	if err := authorize(ctx, permConfigure, "", "recording hours worked"); err != nil {
+		return nil, err
+	}
+	if !validMonth(month) {
+		return nil, fmt.Errorf("%w: invalid month %q, expected YYYY-MM", ErrValidation, month)
+	}
+	if hours <= 0 || math.IsInf(hours, 0) || math.IsNaN(hours) {
+		return nil, fmt.Errorf("%w: hours must be positive", ErrValidation)
+	}
+	h := &domain.HoursWorked{
+		Month:     month,
+		Hours:     hours,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+		UpdatedBy: auth.Actor(ctx),
+	}
+	if err := s.hours.Set(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *OHSService) DeleteHoursWorked(ctx context.Context, month string) error {
+	if err := authorize(ctx, permConfigure, "", "recording hours worked"); err != nil {
+		return err
+	}
+	return s.hours.Delete(month)
+}
+
+// Statistics summarises the injuries of the OHS incidents that occurred from
+// month from to month to (YYYY-MM, inclusive). Without a period it covers
+// the last 12 months.
+func (s *OHSService) Statistics(ctx context.Context, from, to string) (*domain.OHSStatistics, error) {
+	if err := authorize(ctx, permRead, domain.DomainOHS, "viewing OHS statistics"); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if from == "" && to == "" {
+		from, to = last12Months(now)
+	}
+	for _, m := range []string{from, to} {
+		if !validMonth(m) {
+			return nil, fmt.Errorf("%w: from and to must be months (YYYY-MM)", ErrValidation)
+		}
+	}
+	if from > to {
+		return nil, fmt.Errorf("%w: from is after to", ErrValidation)
+	}
+
+	ohs := domain.DomainOHS
+	incidents, _, err := s.incRepo.List(repository.IncidentQuery{Domain: &ohs})
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.hours.List(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return ohsStatistics(incidents, hours, from, to, now.Format(time.DateOnly)), nil
+}
+
+// ohsStatistics summarises the OHS incidents that occurred in the months
+// from to to; incidents of other domains are ignored. The rates only count
+// injuries in months with hours worked, so a month without hours doesn't
+// inflate them.
+func ohsStatistics(incidents []*domain.Incident, hours []*domain.HoursWorked, from, to, today string) *domain.OHSStatistics {
+	st := &domain.OHSStatistics{From: from, To: to, ByType: make(map[string]int)}
+	covered := make(map[string]bool)
+	for _, h := range hours {
+		if h.Month >= from && h.Month <= to {
+			covered[h.Month] = true
+			st.HoursWorked += h.Hours
+		}
+	}
+	st.MonthsCovered = len(covered)
+
+	var ratedLostTime, ratedRecordable int
+	for _, inc := range incidents {
+		if inc.Domain != domain.DomainOHS {
+			continue
+		}
+		if inc.Reportable && inc.ReportedOn == "" && inc.ReportingDeadline < today {
+			st.OverdueReports++
+		}
+		month := inc.OccurredOn[:min(7, len(inc.OccurredOn))]
+		if month < from || month > to {
+			continue
+		}
+		if inc.Reportable {
+			st.ReportableIncidents++
+		}
+		if inc.Type == "" {
+			continue
+		}
+		st.ByType[inc.Type]++
+		st.DaysLost += inc.DaysLost
+		if domain.IsRecordable(inc.Type) {
+			st.RecordableInjuries++
+			if covered[month] {
+				ratedRecordable++
+			}
+		}
+		if domain.IsLostTime(inc.Type) {
+			st.LostTimeInjuries++
+			if covered[month] {
+				ratedLostTime++
+			}
+		}
+	}
+	if st.HoursWorked > 0 {
+		st.LTIFR = injuryRate(ratedLostTime, 1_000_000, st.HoursWorked)
+		st.TRIR = injuryRate(ratedRecordable, 200_000, st.HoursWorked)
+	}
+	return st
+}
+
+// injuryRate is the number of injuries per base hours worked, to two
+// decimals.
+func injuryRate(injuries int, base, hours float64) *float64 {
+	r := math.Round(float64(injuries)*base/hours*100) / 100
+	return &r
+}
+
+// last12Months returns the month eleven months before now's and now's.
+func last12Months(now time.Time) (from, to string) {
+	first := time.Date(now.Year(), now.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	return first.Format("2006-01"), now.Format("2006-01")
+}
+
+func validMonth(m string) bool {
+	_, err := time.Parse("2006-01", m)
+	return err == nil
+}
+
+// classifyIncident normalises the OHS classification of inc and checks it
+// is consistent: only OHS incidents take a type and injury details, injury
+// details need an injury type, days lost a Lost Time injury, and a
+// reportable incident a reporting deadline.
+func classifyIncident(inc *domain.Incident) error {
+	today := time.Now().Format(time.DateOnly)
+	if !validDate(inc.OccurredOn) {
+		return fmt.Errorf("%w: occurredOn must be a date (YYYY-MM-DD)", ErrValidation)
+	}
+	if inc.OccurredOn > today {
+		return fmt.Errorf("%w: occurredOn can't be in the future", ErrValidation)
+	}
+
+	if inc.Type != "" {
+		t, ok := oneOf(inc.Type, domain.IncidentTypes)
+		if !ok {
+			return fmt.Errorf("%w: type must be one of %s", ErrValidation, strings.Join(domain.IncidentTypes, ", "))
+		}
+		inc.Type = t
+	}
+	if p := inc.InjuredPerson; p != nil {
+		p.Name, p.JobTitle = strings.TrimSpace(p.Name), strings.TrimSpace(p.JobTitle)
+		switch {
+		case p.Name == "" && p.JobTitle == "" && strings.TrimSpace(p.Employment) == "":
+			inc.InjuredPerson = nil
+		case p.Name == "":
+			return fmt.Errorf("%w: injuredPerson.name is required", ErrValidation)
+		case strings.TrimSpace(p.Employment) != "":
+			e, ok := oneOf(p.Employment, domain.EmploymentTypes)
+			if !ok {
+				return fmt.Errorf("%w: injuredPerson.employment must be one of %s", ErrValidation, strings.Join(domain.EmploymentTypes, ", "))
+			}
+			p.Employment = e
+		}
+	}
+	var err error
+	if inc.BodyPart, err = codeOf(inc.BodyPart, domain.BodyParts, "bodyPart"); err != nil {
+		return err
+	}
+	if inc.InjuryNature, err = codeOf(inc.InjuryNature, domain.InjuryNatures, "injuryNature"); err != nil {
+		return err
+	}
+	if !domain.IsInjury(inc.Type) && (inc.InjuredPerson != nil || inc.BodyPart != "" || inc.InjuryNature != "") {
+		return fmt.Errorf("%w: injury details need an injury type (First Aid, Medical Treatment, Restricted Work, Lost Time or Fatality)", ErrValidation)
+	}
+	if inc.Domain != domain.DomainOHS && (inc.Type != "" || inc.InjuredPerson != nil || inc.BodyPart != "" || inc.InjuryNature != "" || inc.DaysLost != 0) {
+		return fmt.Errorf("%w: only %s incidents take a type, injuredPerson, bodyPart, injuryNature or daysLost", ErrValidation, domain.DomainOHS)
+	}
+	if inc.DaysLost < 0 {
+		return fmt.Errorf("%w: daysLost can't be negative", ErrValidation)
+	}
+	if inc.DaysLost > 0 && inc.Type != domain.IncidentLostTime {
+		return fmt.Errorf("%w: daysLost only applies to Lost Time injuries", ErrValidation)
+	}
+
+	if !inc.Reportable {
+		if inc.ReportingDeadline != "" || inc.ReportedOn != "" {
+			return fmt.Errorf("%w: reportingDeadline and reportedOn only apply to reportable incidents", ErrValidation)
+		}
+		return nil
+	}
+	if !validDate(inc.ReportingDeadline) {
+		return fmt.Errorf("%w: a reportable incident needs a reportingDeadline (YYYY-MM-DD)", ErrValidation)
+	}
+	if inc.ReportingDeadline < inc.OccurredOn {
+		return fmt.Errorf("%w: reportingDeadline is before occurredOn", ErrValidation)
+	}
+	if inc.ReportedOn != "" {
+		if !validDate(inc.ReportedOn) {
+			return fmt.Errorf("%w: reportedOn must be a date (YYYY-MM-DD)", ErrValidation)
+		}
+		if inc.ReportedOn < inc.OccurredOn || inc.ReportedOn > today {
+			return fmt.Errorf("%w: reportedOn must be between occurredOn and today", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// codeOf returns the code of a code list matching value in any letter case;
+// an empty value stays empty.
+func codeOf(value string, codes []domain.Code, field string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	for _, c := range codes {
+		if strings.EqualFold(c.Code, value) {
+			return c.Code, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown %s code %q", ErrValidation, field, value)
+}
+
+func validDate(d string) bool {
+	_, err := time.Parse(time.DateOnly, d)
+	return err == nil
+}
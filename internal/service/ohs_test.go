@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func TestOHSStatistics(t *testing.T) {
+	ohs := func(typ, occurred string) *domain.Incident {
+		return &domain.Incident{Domain: domain.DomainOHS, Type: typ, OccurredOn: occurred}
+	}
+	lostTime := ohs(domain.IncidentLostTime, "2025-01-10")
+	lostTime.DaysLost = 5
+	overdue := ohs("", "2024-12-28") // Before the period, but still overdue
+	overdue.Reportable, overdue.ReportingDeadline = true, "2025-01-07"
+	reported := ohs(domain.IncidentFatality, "2025-02-03")
+	reported.Reportable, reported.ReportingDeadline, reported.ReportedOn = true, "2025-02-13", "2025-02-04"
+	notDue := ohs(domain.IncidentNearMiss, "2025-03-30")
+	notDue.Reportable, notDue.ReportingDeadline = true, "2025-04-09"
+	incidents := []*domain.Incident{
+		lostTime,
+		reported,
+		ohs(domain.IncidentMedicalTreatment, "2025-02-20"),
+		ohs(domain.IncidentFirstAid, "2025-01-15"),
+		ohs(domain.IncidentNearMiss, "2025-02-01"),
+		ohs(domain.IncidentRestrictedWork, "2025-03-05"), // No hours recorded for March
+		ohs(domain.IncidentLostTime, "2025-04-01"),       // After the period
+		overdue,
+		notDue,
+		{Domain: domain.DomainQuality, Type: domain.IncidentLostTime, OccurredOn: "2025-01-20"},
+	}
+	hours := []*domain.HoursWorked{
+		{Month: "2024-12", Hours: 90_000},
+		{Month: "2025-01", Hours: 100_000},
+		{Month: "2025-02", Hours: 150_000},
+	}
+
+	st := ohsStatistics(incidents, hours, "2025-01", "2025-03", "2025-04-01")
+	if st.MonthsCovered != 2 || st.HoursWorked != 250_000 {
+		t.Errorf("covered %d months with %v hours, want 2 and 250000", st.MonthsCovered, st.HoursWorked)
+	}
+	wantByType := map[string]int{
+		domain.IncidentLostTime: 1, domain.IncidentFatality: 1, domain.IncidentMedicalTreatment: 1,
+		domain.IncidentFirstAid: 1, domain.IncidentNearMiss: 2, domain.IncidentRestrictedWork: 1,
+	}
+	if !reflect.DeepEqual(st.ByType, wantByType) {
+		t.Errorf("by type %v, want %v", st.ByType, wantByType)
+	}
+	if st.RecordableInjuries != 4 || st.LostTimeInjuries != 2 || st.DaysLost != 5 {
+		t.Errorf("%d recordable, %d lost time, %d days lost; want 4, 2, 5", st.RecordableInjuries, st.LostTimeInjuries, st.DaysLost)
+	}
+	if st.ReportableIncidents != 2 || st.OverdueReports != 1 {
+		t.Errorf("%d reportable, %d overdue; want 2, 1", st.ReportableIncidents, st.OverdueReports)
+	}
+	// The restricted work injury in March has no hours to be rated against:
+	// LTIFR = 2 × 1,000,000 / 250,000, TRIR = 3 × 200,000 / 250,000.
+	if st.LTIFR == nil || *st.LTIFR != 8 || st.TRIR == nil || *st.TRIR != 2.4 {
+		t.Errorf("LTIFR %v, TRIR %v; want 8, 2.4", deref(st.LTIFR), deref(st.TRIR))
+	}
+}
+
+func TestOHSStatisticsRates(t *testing.T) {
+	injury := []*domain.Incident{{Domain: domain.DomainOHS, Type: domain.IncidentLostTime, OccurredOn: "2025-06-12"}}
+	tests := []struct {
+		name        string
+		incidents   []*domain.Incident
+		hours       []*domain.HoursWorked
+		ltifr, trir *float64
+	}{
+		{"no hours", injury, nil, nil, nil},
+		{"no injuries", nil, []*domain.HoursWorked{{Month: "2025-06", Hours: 40_000}}, ptrFloat(0), ptrFloat(0)},
+		{"rounded to two decimals", injury, []*domain.HoursWorked{{Month: "2025-06", Hours: 300_000}}, ptrFloat(3.33), ptrFloat(0.67)},
+	}
+	for _, tt := range tests {
+		st := ohsStatistics(tt.incidents, tt.hours, "2025-06", "2025-06", "2025-07-01")
+		if !reflect.DeepEqual(st.LTIFR, tt.ltifr) || !reflect.DeepEqual(st.TRIR, tt.trir) {
+			t.Errorf("%s: LTIFR %v, TRIR %v; want %v, %v", tt.name, deref(st.LTIFR), deref(st.TRIR), deref(tt.ltifr), deref(tt.trir))
+		}
+	}
+}
+
+func TestOHSStatisticsPeriod(t *testing.T) {
+	// The period is checked before anything is read.
+	s := NewOHSService(nil, nil)
+	for _, tt := range []struct{ from, to string }{
+		{"2025-13", "2025-12"},
+		{"2025-01", "2025"},
+		{"2025-06", "2025-01"},
+		{"2025-01", ""},
+	} {
+		if _, err := s.Statistics(manager, tt.from, tt.to); !errors.Is(err, ErrValidation) {
+			t.Errorf("Statistics(%q, %q) = %v, want ErrValidation", tt.from, tt.to, err)
+		}
+	}
+	if _, err := s.Statistics(as(domain.RoleIMSManager, domain.DomainQuality), "2025-01", "2025-12"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Statistics as a Quality manager = %v, want ErrForbidden", err)
+	}
+}
+
+func ptrFloat(f float64) *float64 { return &f }
+
+func deref(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// ProcessService manages the process register. Anyone with a role can read
+// it; changing it requires the ims_manager role for all domains.
+type ProcessService struct {
+	repo      repository.ProcessRepository
+	risks     repository.RiskRepository
+	incidents repository.IncidentRepository
+	audits    repository.AuditRepository
+	actions   repository.ActionRepository
+	matrix    repository.RiskMatrixRepository
+}
+
+func NewProcessService(
+	repo repository.ProcessRepository,
+	risks repository.RiskRepository,
+	incidents repository.IncidentRepository,
+	audits repository.AuditRepository,
+	actions repository.ActionRepository,
+	matrix repository.RiskMatrixRepository,
+) *ProcessService {
+	return &ProcessService{repo: repo, risks: risks, incidents: incidents, audits: audits, actions: actions, matrix: matrix}
+}
+
+type CreateProcessInput struct {
+	Name        string
+	Description string
+	Owner       string
+	ParentID    *int
+	Domains     []string
+	KPIs        []domain.ProcessKPI
+}
+
+type ProcessListFilter struct {
+	Domain   *domain.Domain
+	Owner    *string
+	ParentID *int
+	Search   string
+	Page     repository.Page
+}
+
+func (s *ProcessService) CreateProcess(ctx context.Context, in CreateProcessInput) (*domain.Process, error) {
+	if err := authorize(ctx, permConfigure, "", "managing the process register"); err != nil {
+		return nil, err
+	}
+	name := normalizeProcessName(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	domains, err := parseDomains(in.Domains)
+	if err != nil {
+		return nil, err
+	}
+	kpis, err := normalizeKPIs(in.KPIs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	p := &domain.Process{
+		Name:        name,
+		Description: strings.TrimSpace(in.Description),
+		Owner:       strings.TrimSpace(in.Owner),
+		Domains:     domains,
+		KPIs:        kpis,
+		CreatedAt:   now,
+		CreatedBy:   auth.Actor(ctx),
+		UpdatedAt:   now,
+		UpdatedBy:   auth.Actor(ctx),
+	}
+	if err := s.setParent(p, in.ParentID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(p); err != nil {
+		return nil, processNameTaken(err, name)
+	}
+	return p, nil
+}
+
+func (s *ProcessService) ListProcesses(ctx context.Context, filter ProcessListFilter) ([]*domain.Process, int, error) {
+	if _, err := readScope(ctx, nil, "viewing the process register"); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(repository.ProcessQuery{
+		Domain:   filter.Domain,
+		Owner:    filter.Owner,
+		ParentID: filter.ParentID,
+		Search:   filter.Search,
+		Page:     filter.Page,
+	})
+}
+
+func (s *ProcessService) GetProcess(ctx context.Context, id int) (*domain.Process, error) {
+	if _, err := readScope(ctx, nil, "viewing the process register"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// UpdateProcessInput carries a partial update; nil fields are left
+// unchanged. A ParentID of 0 makes the process top-level.
+type UpdateProcessInput struct {
+	Name        *string
+	Description *string
+	Owner       *string
+	ParentID    *int
+	Domains     []string            // Replaces the domains when non-nil
+	KPIs        []domain.ProcessKPI // Replaces the KPIs when non-nil
+}
+
+// UpdateProcess changes a process. Renaming it renames it on every risk and
+// audit that refers to it.
+func (s *ProcessService) UpdateProcess(ctx context.Context, id int, in UpdateProcessInput) (*domain.Process, error) {
+	if err := authorize(ctx, permConfigure, "", "managing the process register"); err != nil {
+		return nil, err
+	}
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Name != nil {
+		if p.Name = normalizeProcessName(*in.Name); p.Name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrValidation)
+		}
+	}
+	if in.Description != nil {
+		p.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.Owner != nil {
+		p.Owner = strings.TrimSpace(*in.Owner)
+	}
+	if in.ParentID != nil {
+		parent := in.ParentID
+		if *parent == 0 {
+			parent = nil
+		}
+		if err := s.setParent(p, parent); err != nil {
+			return nil, err
+		}
+	}
+	if in.Domains != nil {
+		if p.Domains, err = parseDomains(in.Domains); err != nil {
+			return nil, err
+		}
+	}
+	if in.KPIs != nil {
+		if p.KPIs, err = normalizeKPIs(in.KPIs); err != nil {
+			return nil, err
+		}
+	}
+	p.UpdatedAt = time.Now().Format(time.RFC3339)
+	p.UpdatedBy = auth.Actor(ctx)
+
+	if err := s.repo.Update(p); err != nil {
+		return nil, processNameTaken(err, p.Name)
+	}
+	return p, nil
+}
+
+// DeleteProcess removes a process. Processes that risks, audits or
+// sub-processes still refer to are not deleted; repository.ErrInUse is
+// returned instead.
+func (s *ProcessService) DeleteProcess(ctx context.Context, id int) error {
+	if err := authorize(ctx, permConfigure, "", "managing the process register"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// Summary aggregates the risks, incidents, audits and actions of a process
+// in the domains the caller can read.
+func (s *ProcessService) Summary(ctx context.Context, id int) (*domain.ProcessSummary, error) {
+	domains, err := readScope(ctx, nil, "viewing process summaries")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	subprocesses, _, err := s.repo.List(repository.ProcessQuery{ParentID: &id})
+	if err != nil {
+		return nil, err
+	}
+	risks, _, err := s.risks.List(repository.RiskQuery{ProcessID: &id, Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	incidents, _, err := s.incidents.List(repository.IncidentQuery{ProcessID: &id, Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	audits, _, err := s.audits.List(repository.AuditQuery{ProcessID: &id, Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	actions, _, err := s.actions.List(repository.ActionQuery{ProcessID: &id, Domains: domains})
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := &domain.ProcessSummary{
+		Process:      p,
+		Subprocesses: subprocesses,
+		Risks:        recordCounts(len(risks)),
+		RisksByLevel: levelCounts(m),
+		Incidents:    recordCounts(len(incidents)),
+		Audits:       recordCounts(len(audits)),
+		Actions:      recordCounts(len(actions)),
+	}
+	for _, r := range risks {
+		sum.Risks.ByStatus[r.Status]++
+		sum.RisksByLevel[r.Level]++
+		if m.Escalates(r.Level) {
+			sum.HighRisks++
+		}
+	}
+	for _, inc := range incidents {
+		sum.Incidents.ByStatus[inc.Status]++
+	}
+	for _, a := range audits {
+		sum.Audits.ByStatus[a.Status]++
+	}
+	for _, a := range actions {
+		sum.Actions.ByStatus[a.Status]++
+	}
+	return sum, nil
+}
+
+// setParent makes parentID (nil for none) the parent of p, refusing
+// unknown processes and cycles.
+func (s *ProcessService) setParent(p *domain.Process, parentID *int) error {
+	p.ParentID = nil
+	for next := parentID; next != nil; {
+		if p.ID != 0 && *next == p.ID {
+			return fmt.Errorf("%w: a process can't be its own ancestor", ErrValidation)
+		}
+		parent, err := s.repo.GetByID(*next)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: parent process %d does not exist", ErrValidation, *next)
+		}
+		if err != nil {
+			return err
+		}
+		next = parent.ParentID
+	}
+	p.ParentID = parentID
+	return nil
+}
+
+// resolveProcess looks a process up by ID, or else by name regardless of
+// letter case and spacing. Unknown processes are validation errors.
+func resolveProcess(repo repository.ProcessRepository, id int, name string) (*domain.Process, error) {
+	var p *domain.Process
+	var err error
+	switch {
+	case id != 0:
+		if p, err = repo.GetByID(id); errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: process %d does not exist", ErrValidation, id)
+		}
+	case normalizeProcessName(name) != "":
+		if p, err = repo.GetByName(normalizeProcessName(name)); errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: process %q is not in the process register", ErrValidation, name)
+		}
+	default:
+		return nil, fmt.Errorf("%w: processId or process is required", ErrValidation)
+	}
+	return p, err
+}
+
+// normalizeProcessName trims a name and collapses runs of spaces.
+func normalizeProcessName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func processNameTaken(err error, name string) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("%w: process %q is already registered", err, name)
+	}
+	return err
+}
+
+// parseDomains parses and de-duplicates domain names.
+func parseDomains(names []string) ([]domain.Domain, error) {
+	out := make([]domain.Domain, 0, len(names))
+	for _, n := range names {
+		d, err := domain.ParseDomain(n)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		if !inDomains(d, out) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func normalizeKPIs(kpis []domain.ProcessKPI) ([]domain.ProcessKPI, error) {
+	out := make([]domain.ProcessKPI, 0, len(kpis))
+	for i, k := range kpis {
+		k.Name = strings.TrimSpace(k.Name)
+		k.Target = strings.TrimSpace(k.Target)
+		k.Frequency = strings.TrimSpace(k.Frequency)
+		if k.Name == "" || k.Target == "" {
+			return nil, fmt.Errorf("%w: kpis[%d] needs a name and a target", ErrValidation, i)
+		}
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+func recordCounts(total int) domain.RecordCounts {
+	return domain.RecordCounts{Total: total, ByStatus: make(map[string]int)}
+}
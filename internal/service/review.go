@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// ReviewInput records a periodic review. Nil ratings are confirmed as they
+// are.
+type ReviewInput struct {
+	Likelihood         *int
+	Impact             *int
+	ResidualLikelihood *int
+	ResidualImpact     *int
+	Comments           string
+}
+
+// ReviewRisk records a review of a risk, applies any re-scoring and
+// schedules the next review from today.
+func (s *RiskService) ReviewRisk(ctx context.Context, id int, in ReviewInput) (*domain.RiskReview, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permContribute, r.Domain, "reviewing risks"); err != nil {
+		return nil, err
+	}
+	before := *r
+
+	applyRatings(r, in.Likelihood, in.Impact, in.ResidualLikelihood, in.ResidualImpact)
+	now := time.Now()
+	r.LastReviewedAt = now.Format(time.RFC3339)
+	if err := s.rate(r); err != nil {
+		return nil, err
+	}
+	if r.Level != before.Level {
+		withdrawApproval(r)
+	}
+	r.UpdatedBy = auth.Actor(ctx)
+
+	rv := &domain.RiskReview{
+		RiskID:     r.ID,
+		Reviewer:   auth.Actor(ctx),
+		ReviewedAt: r.LastReviewedAt,
+		Comments:   in.Comments,
+		Rescored: r.Likelihood != before.Likelihood || r.Impact != before.Impact ||
+			r.ResidualLikelihood != before.ResidualLikelihood || r.ResidualImpact != before.ResidualImpact,
+		PreviousScore:      before.Score,
+		PreviousLevel:      before.Level,
+		Likelihood:         r.Likelihood,
+		Impact:             r.Impact,
+		Score:              r.Score,
+		Level:              r.Level,
+		ResidualLikelihood: r.ResidualLikelihood,
+		ResidualImpact:     r.ResidualImpact,
+		ResidualScore:      r.ResidualScore,
+		ResidualLevel:      r.ResidualLevel,
+		NextReviewAt:       r.NextReviewAt,
+	}
+	if err := s.repo.AddReview(r, rv); err != nil {
+		return nil, err
+	}
+	s.events.flush(ctx)
+	return rv, nil
+}
+
+// ListReviews returns the reviews of a risk, newest first.
+func (s *RiskService) ListReviews(ctx context.Context, id int) ([]*domain.RiskReview, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, r.Domain, "viewing risk reviews"); err != nil {
+		return nil, err
+	}
+	return s.repo.Reviews(id)
+}
+
+// ListReviewsDue lists risks due for review within the next withinDays days,
+// including overdue ones, ordered by due date unless filter.Page asks
+// otherwise.
+func (s *RiskService) ListReviewsDue(ctx context.Context, withinDays int, filter RiskListFilter) ([]*domain.Risk, int, error) {
+	if withinDays < 0 {
+		return nil, 0, fmt.Errorf("%w: within must not be negative", ErrValidation)
+	}
+	filter.ReviewDueBy = time.Now().AddDate(0, 0, withinDays).Format(time.DateOnly)
+	if filter.Page.Sort == "" {
+		filter.Page.Sort = "nextReviewAt"
+	}
+	return s.ListRisks(ctx, filter)
+}
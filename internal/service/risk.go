@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/xenakil/integraflow-ims/internal/auth"
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
 )
@@ -13,107 +15,506 @@ import (
 var ErrValidation = errors.New("validation error")
 
 type RiskService struct {
-	repo repository.RiskRepository
+	repo      repository.RiskRepository
+	processes repository.ProcessRepository
+	assets    repository.AssetRepository
+	controls  repository.ISMSControlRepository
+	matrix    repository.RiskMatrixRepository
+	actions   repository.ActionRepository
+	history   repository.HistoryRepository
+	events    *EventRelay
 }
 
-func NewRiskService(repo repository.RiskRepository) *RiskService {
-	return &RiskService{repo: repo}
+func NewRiskService(
+	repo repository.RiskRepository,
+	processes repository.ProcessRepository,
+	assets repository.AssetRepository,
+	controls repository.ISMSControlRepository,
+	matrix repository.RiskMatrixRepository,
+	actions repository.ActionRepository,
+	history repository.HistoryRepository,
+	events *EventRelay,
+) *RiskService {
+	return &RiskService{
+		repo: repo, processes: processes, assets: assets, controls: controls,
+		matrix: matrix, actions: actions, history: history, events: events,
+	}
 }
 
 type CreateRiskInput struct {
-	Title       string
-	Process     string
-	Domain      string
-	Description string
-	Likelihood  int
-	Impact      int
-	Owner       string
+	Title              string
+	ProcessID          int
+	Process            string // Process name, used when ProcessID is 0
+	Domain             string
+	Description        string
+	Likelihood         int
+	Impact             int
+	ResidualLikelihood *int // Defaults to Likelihood
+	ResidualImpact     *int // Defaults to Impact
+	Controls           []domain.RiskControl
+	AssetIDs           []int    // Information Security risks only
+	ISMSControls       []string // IDs of catalogue controls; Information Security risks only
+	Owner              string
 }
 
 type RiskListFilter struct {
-	Domain *domain.Domain
-	Status *string
+	Domain         *domain.Domain
+	ProcessID      *int
+	Process        *string // Process name
+	Status         *string
+	Owner          *string
+	Level          *string
+	ResidualLevel  *string
+	AssetID        *int
+	ISMSControl    *string
+	ReviewDueBy    string // YYYY-MM-DD
+	Created        repository.DateRange
+	Search         string
+	IncludeDeleted bool
+	Page           repository.Page
 }
 
-func (s *RiskService) CreateRisk(in CreateRiskInput) (*domain.Risk, error) {
+func (s *RiskService) CreateRisk(ctx context.Context, in CreateRiskInput) (*domain.Risk, error) {
 	if strings.TrimSpace(in.Title) == "" {
 		return nil, fmt.Errorf("%w: title is required", ErrValidation)
 	}
-	if strings.TrimSpace(in.Process) == "" {
-		return nil, fmt.Errorf("%w: process is required", ErrValidation)
+	process, err := resolveProcess(s.processes, in.ProcessID, in.Process)
+	if err != nil {
+		return nil, err
 	}
 
 	dom, err := domain.ParseDomain(in.Domain)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
 	}
-
-	if in.Likelihood < 1 || in.Likelihood > 5 || in.Impact < 1 || in.Impact > 5 {
-		return nil, fmt.Errorf("%w: likelihood and impact must be between 1 and 5", ErrValidation)
+	if err := authorize(ctx, permContribute, dom, "raising risks"); err != nil {
+		return nil, err
 	}
 
-	score := in.Likelihood * in.Impact
-	level := domain.RiskLevelFromScore(score)
+	controls, err := normalizeControls(in.Controls)
+	if err != nil {
+		return nil, err
+	}
 
 	r := &domain.Risk{
-		Title:       in.Title,
-		Process:     in.Process,
-		Domain:      dom,
-		Description: in.Description,
-		Likelihood:  in.Likelihood,
-		Impact:      in.Impact,
-		Score:       score,
-		Level:       level,
-		Owner:       in.Owner,
-		Status:      "Open",
-		CreatedAt:   time.Now().Format(time.RFC3339),
+		Title:              in.Title,
+		ProcessID:          process.ID,
+		Process:            process.Name,
+		Domain:             dom,
+		Description:        in.Description,
+		Likelihood:         in.Likelihood,
+		Impact:             in.Impact,
+		ResidualLikelihood: in.Likelihood,
+		ResidualImpact:     in.Impact,
+		Controls:           controls,
+		AssetIDs:           []int{},
+		ISMSControls:       []string{},
+		Owner:              in.Owner,
+		Status:             "Open",
+		CreatedAt:          time.Now().Format(time.RFC3339),
+		CreatedBy:          auth.Actor(ctx),
+		UpdatedBy:          auth.Actor(ctx),
+	}
+	if in.ResidualLikelihood != nil {
+		r.ResidualLikelihood = *in.ResidualLikelihood
+	}
+	if in.ResidualImpact != nil {
+		r.ResidualImpact = *in.ResidualImpact
+	}
+	if err := linkISMS(s.assets, s.controls, r, in.AssetIDs, in.ISMSControls); err != nil {
+		return nil, err
+	}
+	if err := s.rate(r); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(r); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
+	return r, nil
+}
+
+func (s *RiskService) ListRisks(ctx context.Context, filter RiskListFilter) ([]*domain.Risk, int, error) {
+	domains, err := readScope(ctx, filter.Domain, "listing risks")
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(repository.RiskQuery{
+		Domain:         filter.Domain,
+		Domains:        domains,
+		ProcessID:      filter.ProcessID,
+		Process:        filter.Process,
+		Status:         filter.Status,
+		Owner:          filter.Owner,
+		Level:          filter.Level,
+		ResidualLevel:  filter.ResidualLevel,
+		AssetID:        filter.AssetID,
+		ISMSControl:    filter.ISMSControl,
+		ReviewDueBy:    filter.ReviewDueBy,
+		Created:        filter.Created,
+		Search:         filter.Search,
+		IncludeDeleted: filter.IncludeDeleted,
+		Page:           filter.Page,
+	})
+}
+
+// GetRisk returns a risk; a deleted one only when includeDeleted is set.
+func (s *RiskService) GetRisk(ctx context.Context, id int, includeDeleted bool) (*domain.Risk, error) {
+	r, err := s.repo.GetByID(id)
+	if includeDeleted && errors.Is(err, repository.ErrNotFound) {
+		r, err = s.repo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, r.Domain, "viewing risks"); err != nil {
+		return nil, err
+	}
 	return r, nil
 }
 
-func (s *RiskService) ListRisks(filter RiskListFilter) ([]*domain.Risk, error) {
-	all, err := s.repo.GetAll()
+// UpdateRiskInput carries a partial update; nil fields are left unchanged.
+type UpdateRiskInput struct {
+	Title              *string
+	ProcessID          *int
+	Process            *string // Process name, used when ProcessID is nil
+	Domain             *string
+	Description        *string
+	Likelihood         *int
+	Impact             *int
+	ResidualLikelihood *int
+	ResidualImpact     *int
+	Controls           []domain.RiskControl // Replaces the controls when non-nil
+	AssetIDs           []int                // Replaces the linked assets when non-nil
+	ISMSControls       []string             // Replaces the linked catalogue controls when non-nil
+	Owner              *string
+	Status             *string
+}
+
+// UpdateRisk applies a partial update to a risk and recomputes its inherent
+// and residual scores and levels. A residual rating that equals the inherent
+// one follows it when only the inherent rating changes.
+func (s *RiskService) UpdateRisk(ctx context.Context, id int, in UpdateRiskInput) (*domain.Risk, error) {
+	r, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(ctx, permContribute, r.Domain, "editing risks"); err != nil {
+		return nil, err
+	}
+	from, fromLevel := r.Status, r.Level
 
-	out := make([]*domain.Risk, 0)
-	for _, r := range all {
-		if filter.Domain != nil && r.Domain != *filter.Domain {
-			continue
+	if in.Title != nil {
+		if strings.TrimSpace(*in.Title) == "" {
+			return nil, fmt.Errorf("%w: title must not be empty", ErrValidation)
 		}
-		if filter.Status != nil && !strings.EqualFold(r.Status, *filter.Status) {
-			continue
+		r.Title = *in.Title
+	}
+	if in.ProcessID != nil || in.Process != nil {
+		id, name := 0, ""
+		if in.ProcessID != nil {
+			id = *in.ProcessID
+		} else {
+			name = *in.Process
 		}
-		out = append(out, r)
+		process, err := resolveProcess(s.processes, id, name)
+		if err != nil {
+			return nil, err
+		}
+		r.ProcessID, r.Process = process.ID, process.Name
+	}
+	if in.Domain != nil {
+		dom, err := domain.ParseDomain(*in.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		if err := authorize(ctx, permContribute, dom, "moving risks"); err != nil {
+			return nil, err
+		}
+		r.Domain = dom
+	}
+	if in.Description != nil {
+		r.Description = *in.Description
+	}
+	applyRatings(r, in.Likelihood, in.Impact, in.ResidualLikelihood, in.ResidualImpact)
+	if in.Controls != nil {
+		if r.Controls, err = normalizeControls(in.Controls); err != nil {
+			return nil, err
+		}
+	}
+	if err := linkISMS(s.assets, s.controls, r, in.AssetIDs, in.ISMSControls); err != nil {
+		return nil, err
+	}
+	if in.Owner != nil {
+		r.Owner = *in.Owner
+	}
+	if in.Status != nil {
+		status, err := normalizeRiskStatus(*in.Status)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeRiskStatus(ctx, r.Domain, status); err != nil {
+			return nil, err
+		}
+		r.Status = status
 	}
-	return out, nil
-}
 
-func (s *RiskService) UpdateStatus(id int, status string) (*domain.Risk, error) {
-	status = strings.TrimSpace(status)
-	if status == "" {
-		return nil, fmt.Errorf("%w: status is required", ErrValidation)
+	if err := s.rate(r); err != nil {
+		return nil, err
+	}
+	if r.Level != fromLevel {
+		withdrawApproval(r)
+	}
+	if err := domain.RiskWorkflow.Check(from, r.Status, r); err != nil {
+		return nil, err
+	}
+	if err := s.checkAcceptance(from, r.Status, r); err != nil {
+		return nil, err
 	}
+	r.UpdatedBy = auth.Actor(ctx)
 
-	normalized := strings.Title(strings.ToLower(status))
-	switch normalized {
-	case "Open", "Accepted", "Mitigated":
-	default:
-		return nil, fmt.Errorf("%w: invalid risk status", ErrValidation)
+	if err := s.repo.Update(r); err != nil {
+		return nil, err
+	}
+	s.events.flush(ctx)
+	return r, nil
+}
+
+func (s *RiskService) UpdateStatus(ctx context.Context, id int, status string) (*domain.Risk, error) {
+	normalized, err := normalizeRiskStatus(status)
+	if err != nil {
+		return nil, err
 	}
 
 	r, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(ctx, permContribute, r.Domain, "editing risks"); err != nil {
+		return nil, err
+	}
+	if err := authorizeRiskStatus(ctx, r.Domain, normalized); err != nil {
+		return nil, err
+	}
+	if err := domain.RiskWorkflow.Check(r.Status, normalized, r); err != nil {
+		return nil, err
+	}
+	if err := s.checkAcceptance(r.Status, normalized, r); err != nil {
+		return nil, err
+	}
 	r.Status = normalized
+	r.UpdatedBy = auth.Actor(ctx)
 	if err := s.repo.Update(r); err != nil {
 		return nil, err
 	}
+	s.events.flush(ctx)
+	return r, nil
+}
+
+// DeleteRisk soft-deletes a risk. Risks still referenced by incidents or
+// actions are not deleted; repository.ErrInUse is returned instead.
+func (s *RiskService) DeleteRisk(ctx context.Context, id int) error {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorize(ctx, permApprove, r.Domain, "deleting risks"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id, auth.Actor(ctx))
+}
+
+func (s *RiskService) RestoreRisk(ctx context.Context, id int) (*domain.Risk, error) {
+	r, err := s.repo.GetDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permApprove, r.Domain, "restoring risks"); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restore(id, auth.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	if r, err = s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	// The risk matrix may have changed while the risk was deleted. Ratings
+	// outside the current scales are kept until the risk is re-rated.
+	m, err := s.matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+	if !m.InRange(r.Likelihood, r.Impact) || !m.InRange(r.ResidualLikelihood, r.ResidualImpact) {
+		return r, nil
+	}
+	fromLevel := r.Level
+	if rescored, rescheduled := rescoreRisk(m, r), scheduleReview(m, r); rescored || rescheduled {
+		if r.Level != fromLevel {
+			withdrawApproval(r)
+		}
+		r.UpdatedBy = auth.Actor(ctx)
+		if err := s.repo.Update(r); err != nil {
+			return nil, err
+		}
+		s.events.flush(ctx)
+	}
 	return r, nil
 }
+
+// RiskHistory returns the change history of a risk, including deleted ones.
+func (s *RiskService) RiskHistory(ctx context.Context, id int) ([]*domain.HistoryEntry, error) {
+	r, err := s.repo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		r, err = s.repo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, r.Domain, "viewing risk history"); err != nil {
+		return nil, err
+	}
+	return s.history.List(domain.KindRisk, id)
+}
+
+// applyRatings sets the given ratings on r. A residual rating that equals
+// the inherent one follows it unless given.
+func applyRatings(r *domain.Risk, likelihood, impact, residualLikelihood, residualImpact *int) {
+	if likelihood != nil {
+		if r.ResidualLikelihood == r.Likelihood && residualLikelihood == nil {
+			r.ResidualLikelihood = *likelihood
+		}
+		r.Likelihood = *likelihood
+	}
+	if impact != nil {
+		if r.ResidualImpact == r.Impact && residualImpact == nil {
+			r.ResidualImpact = *impact
+		}
+		r.Impact = *impact
+	}
+	if residualLikelihood != nil {
+		r.ResidualLikelihood = *residualLikelihood
+	}
+	if residualImpact != nil {
+		r.ResidualImpact = *residualImpact
+	}
+}
+
+// rate validates the inherent and residual ratings of r against the risk
+// matrix and sets its scores, levels and next review date.
+func (s *RiskService) rate(r *domain.Risk) error {
+	m, err := s.matrix.Get()
+	if err != nil {
+		return err
+	}
+	if !m.InRange(r.Likelihood, r.Impact) {
+		return fmt.Errorf("%w: likelihood must be between 1 and %d and impact between 1 and %d",
+			ErrValidation, m.LikelihoodScale, m.ImpactScale)
+	}
+	if !m.InRange(r.ResidualLikelihood, r.ResidualImpact) {
+		return fmt.Errorf("%w: residual likelihood must be between 1 and %d and residual impact between 1 and %d",
+			ErrValidation, m.LikelihoodScale, m.ImpactScale)
+	}
+	if r.ResidualLikelihood > r.Likelihood || r.ResidualImpact > r.Impact {
+		return fmt.Errorf("%w: residual likelihood and impact can't exceed the inherent likelihood (%d) and impact (%d)",
+			ErrValidation, r.Likelihood, r.Impact)
+	}
+	rescoreRisk(m, r)
+	scheduleReview(m, r)
+	return nil
+}
+
+// rescoreRisk sets the inherent and residual scores and levels of r from m
+// and reports whether any of them changed. The ratings must be in range.
+func rescoreRisk(m *domain.RiskMatrix, r *domain.Risk) bool {
+	score, level := m.Assess(r.Likelihood, r.Impact)
+	residualScore, residualLevel := m.Assess(r.ResidualLikelihood, r.ResidualImpact)
+	changed := score != r.Score || level != r.Level ||
+		residualScore != r.ResidualScore || residualLevel != r.ResidualLevel
+	r.Score, r.Level = score, level
+	r.ResidualScore, r.ResidualLevel = residualScore, residualLevel
+	return changed
+}
+
+// scheduleReview sets the next review date of r from its last review (or
+// creation) and the review frequency of its level, and reports whether it
+// changed.
+func scheduleReview(m *domain.RiskMatrix, r *domain.Risk) bool {
+	from := r.LastReviewedAt
+	if from == "" {
+		from = r.CreatedAt
+	}
+	base, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		base = time.Now()
+	}
+	next := m.NextReview(r.Level, base)
+	changed := next != r.NextReviewAt
+	r.NextReviewAt = next
+	return changed
+}
+
+// normalizeControls validates controls and normalises their type and
+// effectiveness to the canonical spelling. Effectiveness defaults to
+// Not Tested.
+func normalizeControls(in []domain.RiskControl) ([]domain.RiskControl, error) {
+	out := make([]domain.RiskControl, 0, len(in))
+	for i, c := range in {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			return nil, fmt.Errorf("%w: control %d has no name", ErrValidation, i+1)
+		}
+		if strings.TrimSpace(c.Type) != "" {
+			t, ok := oneOf(c.Type, domain.ControlTypes)
+			if !ok {
+				return nil, fmt.Errorf("%w: control %q has invalid type %q (%s)",
+					ErrValidation, c.Name, c.Type, strings.Join(domain.ControlTypes, ", "))
+			}
+			c.Type = t
+		}
+		if strings.TrimSpace(c.Effectiveness) == "" {
+			c.Effectiveness = domain.EffectivenessNotTested
+		}
+		e, ok := oneOf(c.Effectiveness, domain.ControlEffectivenesses)
+		if !ok {
+			return nil, fmt.Errorf("%w: control %q has invalid effectiveness %q (%s)",
+				ErrValidation, c.Name, c.Effectiveness, strings.Join(domain.ControlEffectivenesses, ", "))
+		}
+		c.Effectiveness = e
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// oneOf maps value in any letter case to one of options.
+func oneOf(value string, options []string) (string, bool) {
+	for _, o := range options {
+		if strings.EqualFold(o, strings.TrimSpace(value)) {
+			return o, true
+		}
+	}
+	return "", false
+}
+
+// authorizeRiskStatus checks that the caller may move a risk in dom to
+// status. Accepting or mitigating a risk is a process owner decision.
+func authorizeRiskStatus(ctx context.Context, dom domain.Domain, status string) error {
+	if status == "Open" {
+		return nil
+	}
+	return authorize(ctx, permApprove, dom, "marking risks "+status)
+}
+
+func normalizeRiskStatus(status string) (string, error) {
+	status = strings.TrimSpace(status)
+	if status == "" {
+		return "", fmt.Errorf("%w: status is required", ErrValidation)
+	}
+
+	normalized, ok := domain.RiskWorkflow.Normalize(status)
+	if !ok {
+		return "", fmt.Errorf("%w: invalid risk status", ErrValidation)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+func addRisk(t *testing.T, env *testEnv, in CreateRiskInput) *domain.Risk {
+	t.Helper()
+	if in.Title == "" {
+		in.Title = "Supplier delivers late"
+	}
+	if in.Process == "" && in.ProcessID == 0 {
+		in.Process = "Production"
+	}
+	if in.Domain == "" {
+		in.Domain = "Quality"
+	}
+	r, err := env.risks.CreateRisk(manager, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestGetDeletedRisk(t *testing.T) {
+	env := newTestEnv(t)
+	r := addRisk(t, env, CreateRiskInput{Likelihood: 2, Impact: 2})
+	if err := env.risks.DeleteRisk(manager, r.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.risks.GetRisk(manager, r.ID, false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetRisk of a deleted risk = %v, want ErrNotFound", err)
+	}
+	got, err := env.risks.GetRisk(manager, r.ID, true)
+	if err != nil || got.DeletedBy != "ims_manager" {
+		t.Fatalf("GetRisk including deleted = %+v, %v", got, err)
+	}
+	if _, err := env.risks.GetRisk(as(domain.RoleViewer, domain.DomainOHS), r.ID, true); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading a deleted Quality risk as an OHS viewer = %v, want ErrForbidden", err)
+	}
+	if _, err := env.risks.GetRisk(manager, r.ID+1, true); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetRisk of a missing risk = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRiskProcessMustBeRegistered(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.risks.CreateRisk(manager, CreateRiskInput{
+		Title: "Supplier delivers late", Process: "Purchasing", Domain: "Quality", Likelihood: 2, Impact: 2,
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("CreateRisk with an unknown process = %v, want ErrValidation", err)
+	}
+	if _, err := env.processes.GetByName("Purchasing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("the unknown process was registered: %v", err)
+	}
+
+	r := addRisk(t, env, CreateRiskInput{Process: "  production ", Likelihood: 2, Impact: 2})
+	if r.Process != "Production" {
+		t.Errorf("risk process %q, want the registered name", r.Process)
+	}
+	purchasing := "Purchasing"
+	if _, err := env.risks.UpdateRisk(manager, r.ID, UpdateRiskInput{Process: &purchasing}); !errors.Is(err, ErrValidation) {
+		t.Errorf("UpdateRisk to an unknown process = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// RiskMatrixService reads and replaces the risk matrix. Replacing it
+// re-scores existing risks and incidents.
+type RiskMatrixService struct {
+	matrix   repository.RiskMatrixRepository
+	riskRepo repository.RiskRepository
+	incRepo  repository.IncidentRepository
+	events   *EventRelay
+}
+
+func NewRiskMatrixService(
+	matrix repository.RiskMatrixRepository,
+	riskRepo repository.RiskRepository,
+	incRepo repository.IncidentRepository,
+	events *EventRelay,
+) *RiskMatrixService {
+	return &RiskMatrixService{matrix: matrix, riskRepo: riskRepo, incRepo: incRepo, events: events}
+}
+
+// MatrixUpdate reports a saved matrix and how many records were re-scored.
+type MatrixUpdate struct {
+	Matrix            *domain.RiskMatrix
+	RescoredRisks     int
+	RescoredIncidents int
+}
+
+// GetMatrix returns the active matrix. Anyone with a role may read it.
+func (s *RiskMatrixService) GetMatrix(ctx context.Context) (*domain.RiskMatrix, error) {
+	if _, err := readScope(ctx, nil, "viewing the risk matrix"); err != nil {
+		return nil, err
+	}
+	return s.matrix.Get()
+}
+
+// UpdateMatrix validates m and recomputes the scores and levels of every
+// active risk (inherent and residual) and incident, then saves the matrix and
+// the re-scored records together; level changes are published once saved.
+// The matrix is refused while records are rated outside its scales; deleted
+// records are re-scored when restored.
+func (s *RiskMatrixService) UpdateMatrix(ctx context.Context, m *domain.RiskMatrix) (*MatrixUpdate, error) {
+	if err := authorize(ctx, permConfigure, "", "changing the risk matrix"); err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	risks, err := s.riskRepo.GetAll(false)
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := s.incRepo.GetAll(false)
+	if err != nil {
+		return nil, err
+	}
+	outside := 0
+	for _, r := range risks {
+		if !m.InRange(r.Likelihood, r.Impact) || !m.InRange(r.ResidualLikelihood, r.ResidualImpact) {
+			outside++
+		}
+	}
+	for _, inc := range incidents {
+		if !m.InRange(inc.Likelihood, inc.Severity) {
+			outside++
+		}
+	}
+	if outside > 0 {
+		return nil, fmt.Errorf("%w: %d active risk(s) or incident(s) are rated outside the %d×%d scales; re-rate them first",
+			domain.ErrInvalidMatrix, outside, m.LikelihoodScale, m.ImpactScale)
+	}
+
+	m.UpdatedAt = time.Now().Format(time.RFC3339)
+	m.UpdatedBy = auth.Actor(ctx)
+
+	res := &MatrixUpdate{Matrix: m}
+	var changedRisks []*domain.Risk
+	for _, r := range risks {
+		fromLevel := r.Level
+		rescored, rescheduled := rescoreRisk(m, r), scheduleReview(m, r)
+		if !rescored && !rescheduled {
+			continue
+		}
+		if r.Level != fromLevel {
+			withdrawApproval(r)
+		}
+		r.UpdatedBy = auth.Actor(ctx)
+		changedRisks = append(changedRisks, r)
+		if rescored {
+			res.RescoredRisks++
+		}
+	}
+	var changedIncidents []*domain.Incident
+	for _, inc := range incidents {
+		score, level := m.Assess(inc.Likelihood, inc.Severity)
+		if score == inc.RiskScore && level == inc.RiskLevel {
+			continue
+		}
+		inc.RiskScore, inc.RiskLevel = score, level
+		inc.UpdatedAt = m.UpdatedAt
+		inc.UpdatedBy = auth.Actor(ctx)
+		changedIncidents = append(changedIncidents, inc)
+		res.RescoredIncidents++
+	}
+
+	if err := s.matrix.Save(m, changedRisks, changedIncidents); err != nil {
+		return nil, err
+	}
+	s.events.flush(ctx)
+	return res, nil
+}
+
+// assess scores a rating with the active risk matrix. impactName is how the
+// second rating is called in messages ("impact" or "severity").
+func assess(matrix repository.RiskMatrixRepository, likelihood, impact int, impactName string) (int, string, error) {
+	m, err := matrix.Get()
+	if err != nil {
+		return 0, "", err
+	}
+	if !m.InRange(likelihood, impact) {
+		return 0, "", fmt.Errorf("%w: likelihood must be between 1 and %d and %s between 1 and %d",
+			ErrValidation, m.LikelihoodScale, impactName, m.ImpactScale)
+	}
+	score, level := m.Assess(likelihood, impact)
+	return score, level, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+func TestUpdateMatrixRescores(t *testing.T) {
+	env := newTestEnv(t)
+	medium := addRisk(t, env, CreateRiskInput{Likelihood: 3, Impact: 3}) // 9, Medium
+	low := addRisk(t, env, CreateRiskInput{Likelihood: 1, Impact: 2})    // 2, Low
+	inc := reportIncident(t, env)                                        // 2×3 = 6, Low
+	env.events.events = nil
+
+	// Medium now starts at 10 and severity 3 with likelihood 2 is High.
+	m := domain.DefaultRiskMatrix()
+	m.Bands[1].MinScore = 10
+	m.Cells = []domain.RiskCell{{Likelihood: 2, Impact: 3, Level: "high"}}
+	res, err := env.matrix.UpdateMatrix(manager, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RescoredRisks != 1 || res.RescoredIncidents != 1 {
+		t.Errorf("re-scored %d risks and %d incidents, want 1 each", res.RescoredRisks, res.RescoredIncidents)
+	}
+
+	got, err := env.risks.GetRisk(manager, medium.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != "Low" || got.ResidualLevel != "Low" || got.Score != 9 || got.UpdatedBy != "ims_manager" {
+		t.Errorf("risk rated 3×3 is %d %s, residual %s", got.Score, got.Level, got.ResidualLevel)
+	}
+	if got.NextReviewAt == medium.NextReviewAt {
+		t.Errorf("review date %s kept after moving to Low", got.NextReviewAt)
+	}
+	if entries, err := env.risks.RiskHistory(manager, low.ID); err != nil || len(entries) != 1 {
+		t.Errorf("a risk whose level did not change has %d history entries, %v; want 1", len(entries), err)
+	}
+	gotInc, err := env.incidents.GetIncident(manager, inc.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotInc.RiskLevel != "High" || gotInc.RiskScore != 6 {
+		t.Errorf("incident rated 2×3 is %d %s, want 6 High", gotInc.RiskScore, gotInc.RiskLevel)
+	}
+
+	changed := env.events.Of(domain.EventRiskLevelChanged)
+	if len(changed) != 1 || changed[0].RecordID != medium.ID || changed[0].From != "Medium" || changed[0].To != "Low" {
+		t.Errorf("level change events %+v", changed)
+	}
+	saved, err := env.matrix.GetMatrix(manager)
+	if err != nil || saved.Bands[1].MinScore != 10 || saved.Cells[0].Level != "High" || saved.UpdatedBy != "ims_manager" {
+		t.Errorf("saved matrix %+v, %v", saved, err)
+	}
+}
+
+func TestUpdateMatrixWithdrawsApprovalOnLevelChange(t *testing.T) {
+	env := newTestEnv(t)
+	r := addRisk(t, env, CreateRiskInput{Likelihood: 3, Impact: 3})
+	if _, err := env.risks.SetTreatment(manager, r.ID, TreatmentInput{Option: "reduce", Justification: "Second supplier"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.risks.ApproveTreatment(manager, r.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	m := domain.DefaultRiskMatrix()
+	m.Bands[2].MinScore = 9
+	if _, err := env.matrix.UpdateMatrix(manager, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.risks.GetRisk(manager, r.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != "High" || got.Treatment.Approved() {
+		t.Errorf("risk is %s with treatment approved by %q; want High and unapproved", got.Level, got.Treatment.ApprovedBy)
+	}
+}
+
+func TestUpdateMatrixRefusesRatingsOutsideScales(t *testing.T) {
+	env := newTestEnv(t)
+	addRisk(t, env, CreateRiskInput{Likelihood: 5, Impact: 2})
+	before, err := env.matrix.GetMatrix(manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := domain.DefaultRiskMatrix()
+	m.LikelihoodScale = 4
+	_, err = env.matrix.UpdateMatrix(manager, m)
+	if !errors.Is(err, domain.ErrInvalidMatrix) || !strings.Contains(err.Error(), "1 active risk(s) or incident(s) are rated outside the 4×5 scales") {
+		t.Fatalf("UpdateMatrix = %v, want a refusal", err)
+	}
+	after, err := env.matrix.GetMatrix(manager)
+	if err != nil || after.LikelihoodScale != before.LikelihoodScale || after.UpdatedAt != before.UpdatedAt {
+		t.Errorf("the refused matrix was saved: %+v, %v", after, err)
+	}
+
+	if _, err := env.matrix.UpdateMatrix(as(domain.RoleProcessOwner, ""), domain.DefaultRiskMatrix()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("UpdateMatrix as a process owner = %v, want ErrForbidden", err)
+	}
+	m = domain.DefaultRiskMatrix()
+	m.Bands = nil
+	if _, err := env.matrix.UpdateMatrix(manager, m); !errors.Is(err, domain.ErrInvalidMatrix) {
+		t.Errorf("UpdateMatrix without bands = %v, want ErrInvalidMatrix", err)
+	}
+}
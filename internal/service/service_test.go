@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+// recorder is an EventPublisher that keeps every event.
+type recorder struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (r *recorder) Publish(_ context.Context, e domain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+	return nil
+}
+
+// Of returns the recorded events of type typ.
+func (r *recorder) Of(typ string) []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Event
+	for _, e := range r.events {
+		if e.Type == typ {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// testEnv wires the services to a migrated database in a temporary file.
+type testEnv struct {
+	db     *sql.DB
+	relay  *EventRelay
+	events *recorder // What the relay published
+
+	processes *sqlite.ProcessRepository
+
+	auth      *AuthService
+	risks     *RiskService
+	incidents *IncidentService
+	audits    *AuditService
+	actions   *ActionService
+	matrix    *RiskMatrixService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "ims.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := sqlite.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	users := sqlite.NewUserRepository(db)
+	tokens := sqlite.NewTokenRepository(db)
+	risks := sqlite.NewRiskRepository(db)
+	incidents := sqlite.NewIncidentRepository(db)
+	audits := sqlite.NewAuditRepository(db)
+	actions := sqlite.NewActionRepository(db)
+	history := sqlite.NewHistoryRepository(db)
+	matrix := sqlite.NewRiskMatrixRepository(db)
+	processes := sqlite.NewProcessRepository(db)
+	aspects := sqlite.NewAspectRepository(db)
+	assets := sqlite.NewAssetRepository(db)
+	controls := sqlite.NewISMSControlRepository(db)
+
+	env := &testEnv{db: db, events: &recorder{}, processes: processes}
+	env.relay = NewEventRelay(sqlite.NewEventOutboxRepository(db), env.events)
+	env.auth = NewAuthService(users, tokens)
+	env.risks = NewRiskService(risks, processes, assets, controls, matrix, actions, history, env.relay)
+	env.incidents = NewIncidentService(incidents, risks, aspects, matrix, history, env.relay)
+	env.audits = NewAuditService(audits, processes, history, env.relay)
+	env.actions = NewActionService(actions, risks, incidents, audits, history, env.relay)
+	env.matrix = NewRiskMatrixService(matrix, risks, incidents, env.relay)
+
+	if err := processes.Create(&domain.Process{
+		Name:    "Production",
+		Domains: []domain.Domain{domain.DomainQuality, domain.DomainOHS},
+		KPIs:    []domain.ProcessKPI{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+// as returns a context acting as a user named after role holding role in
+// dom, or in every domain when dom is empty.
+func as(role domain.Role, dom domain.Domain) context.Context {
+	name := string(role)
+	if dom != "" {
+		name += "@" + string(dom)
+	}
+	return auth.WithUser(context.Background(), &domain.User{
+		Username: name,
+		Active:   true,
+		Roles:    []domain.RoleAssignment{{Role: role, Domain: dom}},
+	})
+}
+
+var manager = as(domain.RoleIMSManager, "")
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// TreatmentInput sets a risk's treatment plan.
+type TreatmentInput struct {
+	Option        string // avoid, reduce, transfer, accept
+	Justification string
+}
+
+// GetTreatment returns the treatment plan of a risk with the actions raised
+// from it.
+func (s *RiskService) GetTreatment(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, r.Domain, "viewing treatment plans"); err != nil {
+		return nil, err
+	}
+	return s.treatmentPlan(r)
+}
+
+// SetTreatment creates or replaces the treatment plan of a risk. A changed
+// plan needs to be approved again.
+func (s *RiskService) SetTreatment(ctx context.Context, id int, in TreatmentInput) (*domain.TreatmentPlan, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permContribute, r.Domain, "planning risk treatment"); err != nil {
+		return nil, err
+	}
+
+	option, ok := oneOf(in.Option, domain.TreatmentOptions)
+	if !ok {
+		return nil, fmt.Errorf("%w: option must be one of %s", ErrValidation, strings.Join(domain.TreatmentOptions, ", "))
+	}
+	justification := strings.TrimSpace(in.Justification)
+	if justification == "" {
+		return nil, fmt.Errorf("%w: justification is required", ErrValidation)
+	}
+
+	t := r.Treatment
+	if t == nil || t.Option != option || t.Justification != justification {
+		r.Treatment = &domain.RiskTreatment{Option: option, Justification: justification}
+		r.UpdatedBy = auth.Actor(ctx)
+		if err := s.repo.Update(r); err != nil {
+			return nil, err
+		}
+	}
+	return s.treatmentPlan(r)
+}
+
+// ApproveTreatment records the caller as approver of a risk's treatment
+// plan. Approving the acceptance of a risk in an escalating level of the risk
+// matrix takes the ims_manager role; other plans can be approved by process
+// owners.
+func (s *RiskService) ApproveTreatment(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permApprove, r.Domain, "approving treatment plans"); err != nil {
+		return nil, err
+	}
+	if r.Treatment == nil {
+		return nil, fmt.Errorf("%w: risk %d has no treatment plan to approve", ErrValidation, id)
+	}
+	if r.Treatment.Option == domain.TreatmentAccept {
+		m, err := s.matrix.Get()
+		if err != nil {
+			return nil, err
+		}
+		if m.Escalates(r.Level) {
+			if err := authorize(ctx, permAcceptRisk, r.Domain, "approving the acceptance of "+r.Level+" risks"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	r.Treatment.ApprovedBy = auth.Actor(ctx)
+	r.Treatment.ApprovedAt = time.Now().Format(time.RFC3339)
+	r.UpdatedBy = auth.Actor(ctx)
+	if err := s.repo.Update(r); err != nil {
+		return nil, err
+	}
+	return s.treatmentPlan(r)
+}
+
+func (s *RiskService) treatmentPlan(r *domain.Risk) (*domain.TreatmentPlan, error) {
+	actions, _, err := s.actions.List(riskActionsQuery(r.ID, nil))
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.TreatmentPlan{
+		RiskID:     r.ID,
+		RiskStatus: r.Status,
+		RiskLevel:  r.Level,
+		Treatment:  r.Treatment,
+		Actions:    actions,
+	}
+	for _, a := range actions {
+		if a.Status == "Done" {
+			plan.ActionsDone++
+		}
+	}
+	return plan, nil
+}
+
+// checkAcceptance enforces that a risk in an escalating level of the risk
+// matrix is only accepted under an approved plan to accept it. It returns a
+// *domain.TransitionError like the workflow guards.
+func (s *RiskService) checkAcceptance(from, to string, r *domain.Risk) error {
+	if to != "Accepted" || from == to {
+		return nil
+	}
+	m, err := s.matrix.Get()
+	if err != nil {
+		return err
+	}
+	if !m.Escalates(r.Level) {
+		return nil
+	}
+	if r.Treatment != nil && r.Treatment.Option == domain.TreatmentAccept && r.Treatment.Approved() {
+		return nil
+	}
+	return &domain.TransitionError{
+		Code:    "guard_failed",
+		Entity:  domain.RiskWorkflow.Entity,
+		From:    from,
+		To:      to,
+		Guard:   "approved_acceptance_required",
+		Message: fmt.Sprintf("a %s risk can't be accepted without an approved treatment plan to accept it", r.Level),
+	}
+}
+
+// withdrawApproval clears the approval of r's treatment plan, which no
+// longer covers the risk as rated.
+func withdrawApproval(r *domain.Risk) {
+	if r.Treatment.Approved() {
+		r.Treatment.ApprovedBy, r.Treatment.ApprovedAt = "", ""
+	}
+}
+
+// riskActionsQuery selects the actions raised from a risk.
+func riskActionsQuery(riskID int, status *string) repository.ActionQuery {
+	sourceType := "Risk"
+	return repository.ActionQuery{SourceType: &sourceType, SourceID: &riskID, Status: status}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+const (
+	defaultTrendWeeks = 12
+	maxTrendWeeks     = 104
+)
+
+// ScoreHistory returns every rating of a risk, oldest first, including for
+// deleted risks.
+func (s *RiskService) ScoreHistory(ctx context.Context, id int) ([]*domain.RiskScore, error) {
+	r, err := s.repo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		r, err = s.repo.GetDeletedByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, permRead, r.Domain, "viewing risk score history"); err != nil {
+		return nil, err
+	}
+	return s.repo.ScoreHistory(id)
+}
+
+// RiskTrend returns the risk profile week by week for the last weeks weeks
+// (12 if 0), oldest first, in dom or the domains the caller can read. A week
+// counts the risks that existed and were not deleted at its end, at
+// the latest score they had then; the current week counts them as of now.
+func (s *RiskService) RiskTrend(ctx context.Context, weeks int, dom *domain.Domain) ([]*domain.RiskTrendPoint, error) {
+	if weeks == 0 {
+		weeks = defaultTrendWeeks
+	}
+	if weeks < 0 || weeks > maxTrendWeeks {
+		return nil, fmt.Errorf("%w: weeks must be between 1 and %d", ErrValidation, maxTrendWeeks)
+	}
+	domains, err := readScope(ctx, dom, "viewing the risk trend")
+	if err != nil {
+		return nil, err
+	}
+	if dom != nil {
+		domains = []domain.Domain{*dom}
+	}
+
+	risks, err := s.repo.GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.repo.AllScores()
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.matrix.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[int][]*domain.RiskScore)
+	for _, sc := range scores {
+		history[sc.RiskID] = append(history[sc.RiskID], sc)
+	}
+	for _, h := range history {
+		sort.SliceStable(h, func(i, j int) bool { return parseTime(h[i].RecordedAt).Before(parseTime(h[j].RecordedAt)) })
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	out := make([]*domain.RiskTrendPoint, 0, weeks)
+	for w := weeks - 1; w >= 0; w-- {
+		start := monday.AddDate(0, 0, -7*w)
+		end := start.AddDate(0, 0, 7)
+		if end.After(now) {
+			end = now
+		}
+		p := &domain.RiskTrendPoint{
+			WeekStart: start.Format(time.DateOnly),
+			ByLevel:   levelCounts(m),
+			ByDomain:  make(map[domain.Domain]map[string]int),
+		}
+		total := 0
+		for _, r := range risks {
+			if !inDomains(r.Domain, domains) || !parseTime(r.CreatedAt).Before(end) {
+				continue
+			}
+			if r.DeletedAt != "" && parseTime(r.DeletedAt).Before(end) {
+				continue
+			}
+			sc := scoreAt(history[r.ID], end)
+			if sc == nil {
+				continue
+			}
+			p.Total++
+			total += sc.Score
+			p.ByLevel[sc.Level]++
+			if p.ByDomain[r.Domain] == nil {
+				p.ByDomain[r.Domain] = levelCounts(m)
+			}
+			p.ByDomain[r.Domain][sc.Level]++
+		}
+		if p.Total > 0 {
+			p.AverageScore = math.Round(float64(total)/float64(p.Total)*10) / 10
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// scoreAt returns the latest of the time-ordered scores recorded before t.
+func scoreAt(scores []*domain.RiskScore, t time.Time) *domain.RiskScore {
+	var at *domain.RiskScore
+	for _, sc := range scores {
+		if !parseTime(sc.RecordedAt).Before(t) {
+			break
+		}
+		at = sc
+	}
+	return at
+}
+
+// levelCounts returns a zero count for every level of m.
+func levelCounts(m *domain.RiskMatrix) map[string]int {
+	counts := make(map[string]int, len(m.Bands))
+	for _, b := range m.Bands {
+		counts[b.Level] = 0
+	}
+	return counts
+}
+
+func inDomains(d domain.Domain, domains []domain.Domain) bool {
+	if domains == nil {
+		return true
+	}
+	for _, x := range domains {
+		if x == d {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
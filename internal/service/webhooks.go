@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/auth"
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+// webhookSecretPrefix makes webhook signing keys recognisable.
+const webhookSecretPrefix = "whsec_"
+
+// WebhookService manages webhook subscriptions. Webhooks receive events from
+// every domain, so all operations require the ims_manager role for all
+// domains.
+type WebhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+type CreateWebhookInput struct {
+	URL         string
+	Events      []string
+	Description string
+	Secret      string // optional; generated when empty
+}
+
+// CreateWebhook registers a subscription. The returned webhook carries the
+// signing secret, which is not shown again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, in CreateWebhookInput) (*domain.Webhook, error) {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return nil, err
+	}
+	u, err := normalizeWebhookURL(in.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeEvents(in.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret := strings.TrimSpace(in.Secret)
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < 16 {
+		return nil, fmt.Errorf("%w: secret must be at least 16 characters", ErrValidation)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	w := &domain.Webhook{
+		URL:         u,
+		Events:      events,
+		Description: strings.TrimSpace(in.Description),
+		Active:      true,
+		Secret:      secret,
+		CreatedAt:   now,
+		CreatedBy:   auth.Actor(ctx),
+		UpdatedAt:   now,
+		UpdatedBy:   auth.Actor(ctx),
+	}
+	if err := s.repo.Create(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return nil, err
+	}
+	return s.repo.List()
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id int) (*domain.Webhook, error) {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// UpdateWebhookInput carries a partial update; nil fields are left unchanged.
+type UpdateWebhookInput struct {
+	URL          *string
+	Events       []string // nil leaves the filter unchanged
+	Description  *string
+	Active       *bool
+	RotateSecret bool
+}
+
+// UpdateWebhook changes a subscription. When RotateSecret is set the
+// returned webhook carries the new secret.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id int, in UpdateWebhookInput) (*domain.Webhook, error) {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return nil, err
+	}
+	w, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.URL != nil {
+		if w.URL, err = normalizeWebhookURL(*in.URL); err != nil {
+			return nil, err
+		}
+	}
+	if in.Events != nil {
+		if w.Events, err = normalizeEvents(in.Events); err != nil {
+			return nil, err
+		}
+	}
+	if in.Description != nil {
+		w.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.Active != nil {
+		w.Active = *in.Active
+	}
+	if in.RotateSecret {
+		if w.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	w.UpdatedAt = time.Now().Format(time.RFC3339)
+	w.UpdatedBy = auth.Actor(ctx)
+
+	if err := s.repo.Update(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// DeleteWebhook removes a subscription and its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// ListDeliveries returns a webhook's deliveries, newest first, with the
+// status code and response time of every attempt.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id int, page repository.Page) ([]*domain.WebhookDelivery, int, error) {
+	if err := authorize(ctx, permIntegrate, "", "managing webhooks"); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.Deliveries(id, page)
+}
+
+func normalizeWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: url must be an absolute http or https URL", ErrValidation)
+	}
+	return raw, nil
+}
+
+// normalizeEvents validates event types and removes duplicates. "*" stands
+// for every event type and replaces the others.
+func normalizeEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(domain.EventTypes))
+	for _, t := range domain.EventTypes {
+		known[t] = true
+	}
+
+	out := make([]string, 0, len(events))
+	seen := make(map[string]bool)
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == domain.WebhookAllEvents {
+			return []string{domain.WebhookAllEvents}, nil
+		}
+		if !known[e] {
+			return nil, fmt.Errorf("%w: unknown event type %q (known: %s, or *)", ErrValidation, e, strings.Join(domain.EventTypes, ", "))
+		}
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrValidation)
+	}
+	return out, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
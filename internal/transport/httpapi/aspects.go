@@ -0,0 +1,221 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Environmental aspect handlers ---------
+
+func (s *Server) handleAspects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listAspects(w, r)
+	case http.MethodPost:
+		s.createAspect(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleAspectByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/aspects/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if sub != "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getAspect(w, r, id)
+	case http.MethodPatch:
+		s.updateAspect(w, r, id)
+	case http.MethodDelete:
+		s.deleteAspect(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listAspects godoc
+// @Summary      List environmental aspects
+// @Description  Returns the aspects and impacts register, filtered, sorted and paged in the database.
+// @Tags         aspects
+// @Produce      json
+// @Param        processId    query    int     false  "Only aspects of this process"
+// @Param        condition    query    string  false  "Condition filter (Normal|Abnormal|Emergency)"
+// @Param        significant  query    bool    false  "Only significant (true) or non-significant (false) aspects"
+// @Param        owner        query    string  false  "Owner filter"
+// @Param        q            query    string  false  "Free-text search in activity, aspect, impact and owner"
+// @Param        limit        query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset       query    int     false  "Number of records to skip"
+// @Param        sort         query    string  false  "Sort order as field:asc|desc, e.g. score:desc"
+// @Success      200          {array}  domain.EnvironmentalAspect
+// @Header       200          {integer} X-Total-Count "Total number of matching records"
+// @Failure      400          {string} string
+// @Failure      403          {string} string
+// @Failure      500          {string} string
+// @Security     BearerAuth
+// @Router       /api/aspects [get]
+func (s *Server) listAspects(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	processID, err := queryInt(qs, "processId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	significant, err := queryOptionalBool(qs, "significant")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	aspects, total, err := s.aspectSvc.ListAspects(r.Context(), service.AspectListFilter{
+		ProcessID:   processID,
+		Condition:   queryString(qs, "condition"),
+		Significant: significant,
+		Owner:       queryString(qs, "owner"),
+		Search:      qs.Get("q"),
+		Page:        page,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, aspects)
+}
+
+// createAspect godoc
+// @Summary      Register environmental aspect
+// @Description  Adds an aspect to the register. Its score is severity x frequency; it is significant when the score is 12 or more or a legal requirement applies. Requires contributing to the Environment domain.
+// @Tags         aspects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateAspectRequest  true  "Aspect"
+// @Success      201      {object}  domain.EnvironmentalAspect
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/aspects [post]
+func (s *Server) createAspect(w http.ResponseWriter, r *http.Request) {
+	var req CreateAspectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	a, err := s.aspectSvc.CreateAspect(r.Context(), service.CreateAspectInput{
+		ProcessID:         req.ProcessID,
+		Activity:          req.Activity,
+		Aspect:            req.Aspect,
+		Impact:            req.Impact,
+		Condition:         req.Condition,
+		Severity:          req.Severity,
+		Frequency:         req.Frequency,
+		LegalRequirements: req.LegalRequirements,
+		Controls:          req.Controls,
+		Owner:             req.Owner,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, a)
+}
+
+// getAspect godoc
+// @Summary      Get environmental aspect
+// @Description  Returns an aspect of the register.
+// @Tags         aspects
+// @Produce      json
+// @Param        id   path      int  true  "Aspect ID"
+// @Success      200  {object}  domain.EnvironmentalAspect
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/aspects/{id} [get]
+func (s *Server) getAspect(w http.ResponseWriter, r *http.Request, id int) {
+	a, err := s.aspectSvc.GetAspect(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, a)
+}
+
+// updateAspect godoc
+// @Summary      Update environmental aspect
+// @Description  Changes an aspect and re-scores it. Omitted fields are left unchanged.
+// @Tags         aspects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "Aspect ID"
+// @Param        request  body      UpdateAspectRequest  true  "Fields to change"
+// @Success      200      {object}  domain.EnvironmentalAspect
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/aspects/{id} [patch]
+func (s *Server) updateAspect(w http.ResponseWriter, r *http.Request, id int) {
+	var req UpdateAspectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	a, err := s.aspectSvc.UpdateAspect(r.Context(), id, service.UpdateAspectInput{
+		ProcessID:         req.ProcessID,
+		Activity:          req.Activity,
+		Aspect:            req.Aspect,
+		Impact:            req.Impact,
+		Condition:         req.Condition,
+		Severity:          req.Severity,
+		Frequency:         req.Frequency,
+		LegalRequirements: req.LegalRequirements,
+		Controls:          req.Controls,
+		Owner:             req.Owner,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, a)
+}
+
+// deleteAspect godoc
+// @Summary      Delete environmental aspect
+// @Description  Removes an aspect from the register. Aspects linked to incidents (including deleted ones) are refused with 409.
+// @Tags         aspects
+// @Param        id   path      int     true  "Aspect ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/aspects/{id} [delete]
+func (s *Server) deleteAspect(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.aspectSvc.DeleteAspect(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
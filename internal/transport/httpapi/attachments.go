@@ -0,0 +1,196 @@
+package httpapi
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Attachment handlers ---------
+
+// multipartOverhead is allowed on top of the file size for the form's
+// boundaries, headers and description.
+const multipartOverhead = 1 << 20
+
+// handleRecordAttachments serves /api/{risks|incidents|audits|actions}/{id}/attachments.
+func (s *Server) handleRecordAttachments(w http.ResponseWriter, r *http.Request, kind string, id int) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listAttachments(w, r, kind, id)
+	case http.MethodPost:
+		s.uploadAttachment(w, r, kind, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleAttachmentByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/attachments/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getAttachment(w, r, id)
+		case http.MethodDelete:
+			s.deleteAttachment(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "download":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.downloadAttachment(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// listAttachments godoc
+// @Summary      List attachments of a record
+// @Description  Lists the files attached to a risk, incident, audit or action, oldest first. The path segment is risks, incidents, audits or actions.
+// @Tags         attachments
+// @Produce      json
+// @Param        records  path      string  true  "risks, incidents, audits or actions"
+// @Param        id       path      int     true  "Record ID"
+// @Success      200      {array}   domain.Attachment
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/{records}/{id}/attachments [get]
+func (s *Server) listAttachments(w http.ResponseWriter, r *http.Request, kind string, id int) {
+	list, err := s.attachSvc.ListAttachments(r.Context(), kind, id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, list)
+}
+
+// uploadAttachment godoc
+// @Summary      Upload an attachment
+// @Description  Attaches a file to a risk, incident, audit or action as multipart/form-data with the file in the "file" field. The content type is sniffed from the content, and the SHA-256 checksum is recorded; identical content is stored once. Files over the configured limit (ATTACHMENT_MAX_MB, default 20) are refused with 413.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        records      path      string  true   "risks, incidents, audits or actions"
+// @Param        id           path      int     true   "Record ID"
+// @Param        file         formData  file    true   "File to attach"
+// @Param        description  formData  string  false  "What the file shows"
+// @Success      201          {object}  domain.Attachment
+// @Failure      400          {string}  string
+// @Failure      403          {string}  string
+// @Failure      404          {string}  string
+// @Failure      413          {string}  string
+// @Failure      500          {string}  string
+// @Security     BearerAuth
+// @Router       /api/{records}/{id}/attachments [post]
+func (s *Server) uploadAttachment(w http.ResponseWriter, r *http.Request, kind string, id int) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.attachSvc.MaxSize()+multipartOverhead)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "file too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, `multipart field "file" is required`, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	a, err := s.attachSvc.Upload(r.Context(), kind, id, service.UploadInput{
+		FileName:    header.Filename,
+		Description: r.FormValue("description"),
+		Content:     file,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, a)
+}
+
+// getAttachment godoc
+// @Summary      Get attachment metadata
+// @Tags         attachments
+// @Produce      json
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {object}  domain.Attachment
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/attachments/{id} [get]
+func (s *Server) getAttachment(w http.ResponseWriter, r *http.Request, id int) {
+	a, err := s.attachSvc.GetAttachment(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, a)
+}
+
+// downloadAttachment godoc
+// @Summary      Download attachment
+// @Description  Returns the file as uploaded, with its sniffed content type and its SHA-256 checksum as ETag. Range requests are supported.
+// @Tags         attachments
+// @Produce      octet-stream
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/attachments/{id}/download [get]
+func (s *Server) downloadAttachment(w http.ResponseWriter, r *http.Request, id int) {
+	a, content, err := s.attachSvc.Download(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", strconv.Quote(a.SHA256))
+	uploaded, _ := time.Parse(time.RFC3339, a.UploadedAt)
+	http.ServeContent(w, r, a.FileName, uploaded, content)
+}
+
+// deleteAttachment godoc
+// @Summary      Delete attachment
+// @Description  Removes an attachment. Requires the process_owner or ims_manager role for the record's domain.
+// @Tags         attachments
+// @Param        id   path      int     true  "Attachment ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/attachments/{id} [delete]
+func (s *Server) deleteAttachment(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.attachSvc.DeleteAttachment(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,196 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// isPublicPath reports whether path can be called without a token.
+func isPublicPath(path string) bool {
+	return path == "/health" ||
+		path == "/api/auth/login" ||
+		strings.HasPrefix(path, "/swagger/")
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// --------- Auth handlers ---------
+
+// handleLogin godoc
+// @Summary      Log in
+// @Description  Exchanges a username and password for a session token valid for 12 hours.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      LoginRequest   true  "Credentials"
+// @Success      200      {object}  TokenResponse
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      500      {string}  string
+// @Router       /api/auth/login [post]
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	token, t, err := s.authSvc.Login(req.Username, req.Password)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, TokenResponse{Token: token, APIToken: *t})
+}
+
+// handleLogout godoc
+// @Summary      Log out
+// @Description  Revokes the token used for this request.
+// @Tags         auth
+// @Success      204
+// @Failure      401  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/auth/logout [post]
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := s.authSvc.Logout(r.Context(), bearerToken(r)); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMe godoc
+// @Summary      Current user
+// @Description  Returns the authenticated user.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  domain.User
+// @Failure      401  {string}  string
+// @Security     BearerAuth
+// @Router       /api/auth/me [get]
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	u, err := s.authSvc.Me(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, u)
+}
+
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listTokens(w, r)
+	case http.MethodPost:
+		s.createToken(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleTokenByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/auth/tokens/")
+	if err != nil || sub != "" {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		s.revokeToken(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listTokens godoc
+// @Summary      List API tokens
+// @Description  Lists the current user's API and session tokens. Secrets are never returned.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {array}   domain.APIToken
+// @Failure      401  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/auth/tokens [get]
+func (s *Server) listTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := s.authSvc.ListTokens(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, tokens)
+}
+
+// createToken godoc
+// @Summary      Create API token
+// @Description  Issues a long-lived API token for the current user. The token is only shown in this response.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateTokenRequest  true  "Token name and lifetime"
+// @Success      201      {object}  TokenResponse
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/auth/tokens [post]
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, t, err := s.authSvc.CreateToken(r.Context(), req.Name, ttl)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, TokenResponse{Token: token, APIToken: *t})
+}
+
+// revokeToken godoc
+// @Summary      Revoke API token
+// @Description  Deletes one of the current user's tokens.
+// @Tags         auth
+// @Param        id   path      int     true  "Token ID"
+// @Success      204
+// @Failure      401  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/auth/tokens/{id} [delete]
+func (s *Server) revokeToken(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.authSvc.RevokeToken(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,15 +1,26 @@
 package httpapi
 
+import (
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/scheduler"
+)
+
 // CreateRiskRequest represents the payload to create a new IMS risk.
 // swagger:model CreateRiskRequest
 type CreateRiskRequest struct {
-	Title       string `json:"title"`       // Short name of the risk
-	Process     string `json:"process"`     // Process where the risk occurs
-	Domain      string `json:"domain"`      // Domain: quality|environment|ohs|isms
-	Description string `json:"description"` // Detailed risk description
-	Likelihood  int    `json:"likelihood"`  // 1 (rare) - 5 (almost certain)
-	Impact      int    `json:"impact"`      // 1 (minor) - 5 (catastrophic)
-	Owner       string `json:"owner"`       // Responsible person or role
+	Title              string               `json:"title"`              // Short name of the risk
+	ProcessID          int                  `json:"processId"`          // Process where the risk occurs, from /api/processes
+	Process            string               `json:"process"`            // Or the name of a registered process; unknown names are refused
+	Domain             string               `json:"domain"`             // Domain: quality|environment|ohs|isms
+	Description        string               `json:"description"`        // Detailed risk description
+	Likelihood         int                  `json:"likelihood"`         // Inherent likelihood, 1 (rare) up to the risk matrix's likelihoodScale (default 5)
+	Impact             int                  `json:"impact"`             // Inherent impact, 1 (minor) up to the risk matrix's impactScale (default 5)
+	ResidualLikelihood *int                 `json:"residualLikelihood"` // Likelihood with controls in place; defaults to likelihood
+	ResidualImpact     *int                 `json:"residualImpact"`     // Impact with controls in place; defaults to impact
+	Controls           []domain.RiskControl `json:"controls"`           // Controls applied to the risk
+	AssetIDs           []int                `json:"assetIds"`           // Information assets at risk, isms risks only
+	ISMSControls       []string             `json:"ismsControls"`       // Catalogue control IDs treating the risk, e.g. ["5.15"]; isms risks only
+	Owner              string               `json:"owner"`              // Responsible person or role
 }
 
 // UpdateRiskStatusRequest represents payload to update risk status.
@@ -18,6 +29,43 @@ type UpdateRiskStatusRequest struct {
 	Status string `json:"status"` // Open, Accepted, Mitigated
 }
 
+// PatchRiskRequest represents a partial update of a risk; omitted fields are left unchanged.
+// swagger:model PatchRiskRequest
+type PatchRiskRequest struct {
+	Title              *string              `json:"title"`
+	ProcessID          *int                 `json:"processId"`
+	Process            *string              `json:"process"` // Name of a registered process, if processId is omitted
+	Domain             *string              `json:"domain"`  // quality|environment|ohs|isms
+	Description        *string              `json:"description"`
+	Likelihood         *int                 `json:"likelihood"`         // 1 to the risk matrix's likelihoodScale
+	Impact             *int                 `json:"impact"`             // 1 to the risk matrix's impactScale
+	ResidualLikelihood *int                 `json:"residualLikelihood"` // At most likelihood
+	ResidualImpact     *int                 `json:"residualImpact"`     // At most impact
+	Controls           []domain.RiskControl `json:"controls"`           // Replaces the controls when present
+	AssetIDs           []int                `json:"assetIds"`           // Replaces the linked assets when present
+	ISMSControls       []string             `json:"ismsControls"`       // Replaces the linked catalogue controls when present
+	Owner              *string              `json:"owner"`
+	Status             *string              `json:"status"` // Open, Accepted, Mitigated
+}
+
+// SetTreatmentRequest sets a risk's treatment plan.
+// swagger:model SetTreatmentRequest
+type SetTreatmentRequest struct {
+	Option        string `json:"option"`        // avoid|reduce|transfer|accept
+	Justification string `json:"justification"` // Why this option was chosen
+}
+
+// CreateReviewRequest records a periodic risk review; omitted ratings are
+// confirmed unchanged.
+// swagger:model CreateReviewRequest
+type CreateReviewRequest struct {
+	Likelihood         *int   `json:"likelihood"`
+	Impact             *int   `json:"impact"`
+	ResidualLikelihood *int   `json:"residualLikelihood"`
+	ResidualImpact     *int   `json:"residualImpact"`
+	Comments           string `json:"comments"`
+}
+
 // CreateIncidentRequest represents payload to create an incident.
 // swagger:model CreateIncidentRequest
 type CreateIncidentRequest struct {
@@ -25,15 +73,52 @@ type CreateIncidentRequest struct {
 	Description   string `json:"description"`
 	Domain        string `json:"domain"`        // quality|environment|ohs|isms
 	RelatedRiskID *int   `json:"relatedRiskId"` // Optional link to risk
-	Severity      int    `json:"severity"`      // 1-5
-	Likelihood    int    `json:"likelihood"`    // 1-5
+	AspectID      *int   `json:"aspectId"`      // Optional environmental aspect, Environment incidents only
+	Severity      int    `json:"severity"`      // 1 to the risk matrix's impactScale
+	Likelihood    int    `json:"likelihood"`    // 1 to the risk matrix's likelihoodScale
+	OccurredOn    string `json:"occurredOn"`    // YYYY-MM-DD, default today
+
+	Type              string                `json:"type"` // Near Miss, Dangerous Occurrence, First Aid, Medical Treatment, Restricted Work, Lost Time, Fatality
+	InjuredPerson     *domain.InjuredPerson `json:"injuredPerson"`
+	BodyPart          string                `json:"bodyPart"`          // Code, see GET /api/ohs/codes
+	InjuryNature      string                `json:"injuryNature"`      // Code, see GET /api/ohs/codes
+	DaysLost          int                   `json:"daysLost"`          // Lost Time injuries only
+	Reportable        bool                  `json:"reportable"`        // Must be reported to the regulator
+	ReportingDeadline string                `json:"reportingDeadline"` // YYYY-MM-DD, required when reportable
+	ReportedOn        string                `json:"reportedOn"`        // YYYY-MM-DD
 }
 
-// UpdateIncidentRequest represents payload to update an incident.
+// UpdateIncidentRequest represents payload to update an incident; omitted
+// fields are left unchanged.
 // swagger:model UpdateIncidentRequest
 type UpdateIncidentRequest struct {
-	RootCause *string `json:"rootCause"`
-	Status    *string `json:"status"` // Open, Investigation, Closed
+	RootCause  *string `json:"rootCause"`
+	Status     *string `json:"status"` // Open, Investigation, Closed
+	OccurredOn *string `json:"occurredOn"`
+	AspectID   *int    `json:"aspectId"` // Environmental aspect; 0 unlinks it
+
+	Type              *string               `json:"type"`
+	InjuredPerson     *domain.InjuredPerson `json:"injuredPerson"` // Replaces the injured person; without a name removes it
+	BodyPart          *string               `json:"bodyPart"`
+	InjuryNature      *string               `json:"injuryNature"`
+	DaysLost          *int                  `json:"daysLost"`
+	Reportable        *bool                 `json:"reportable"` // false clears reportingDeadline and reportedOn
+	ReportingDeadline *string               `json:"reportingDeadline"`
+	ReportedOn        *string               `json:"reportedOn"`
+}
+
+// SetHoursWorkedRequest records the hours worked in a month.
+// swagger:model SetHoursWorkedRequest
+type SetHoursWorkedRequest struct {
+	Hours float64 `json:"hours"`
+}
+
+// SaveInvestigationRequest creates or replaces an incident's investigation.
+// swagger:model SaveInvestigationRequest
+type SaveInvestigationRequest struct {
+	Problem  string                    `json:"problem"`
+	Whys     []domain.WhyStep          `json:"whys"`     // 5-Whys chain, in order
+	Fishbone []domain.FishboneCategory `json:"fishbone"` // Ishikawa diagram, one entry per category
 }
 
 // CreateAuditRequest represents payload to create an audit.
@@ -42,6 +127,7 @@ type CreateAuditRequest struct {
 	Title       string `json:"title"`
 	Scope       string `json:"scope"`
 	Domain      string `json:"domain"`      // quality|environment|ohs|isms
+	ProcessID   *int   `json:"processId"`   // Optional audited process
 	PlannedDate string `json:"plannedDate"` // YYYY-MM-DD
 	Auditor     string `json:"auditor"`
 }
@@ -49,8 +135,127 @@ type CreateAuditRequest struct {
 // UpdateAuditRequest represents payload to update an audit.
 // swagger:model UpdateAuditRequest
 type UpdateAuditRequest struct {
-	Status   *string `json:"status"`   // Planned, In Progress, Completed
-	Findings *string `json:"findings"` // Summary of audit findings
+	Status    *string `json:"status"`    // Planned, In Progress, Completed
+	Findings  *string `json:"findings"`  // Summary of audit findings
+	ProcessID *int    `json:"processId"` // Audited process; 0 unlinks it
+}
+
+// CreateProcessRequest represents payload to register a process.
+// swagger:model CreateProcessRequest
+type CreateProcessRequest struct {
+	Name        string              `json:"name"` // Unique regardless of letter case
+	Description string              `json:"description"`
+	Owner       string              `json:"owner"`    // Process owner
+	ParentID    *int                `json:"parentId"` // Optional parent process
+	Domains     []string            `json:"domains"`  // quality|environment|ohs|isms
+	KPIs        []domain.ProcessKPI `json:"kpis"`
+}
+
+// UpdateProcessRequest represents a partial update of a process; omitted
+// fields are left unchanged.
+// swagger:model UpdateProcessRequest
+type UpdateProcessRequest struct {
+	Name        *string             `json:"name"`
+	Description *string             `json:"description"`
+	Owner       *string             `json:"owner"`
+	ParentID    *int                `json:"parentId"` // 0 makes the process top-level
+	Domains     []string            `json:"domains"`  // Replaces the domains when present
+	KPIs        []domain.ProcessKPI `json:"kpis"`     // Replaces the KPIs when present
+}
+
+// CreateAspectRequest represents payload to register an environmental
+// aspect.
+// swagger:model CreateAspectRequest
+type CreateAspectRequest struct {
+	ProcessID         *int                 `json:"processId"` // Optional process the activity belongs to
+	Activity          string               `json:"activity"`
+	Aspect            string               `json:"aspect"`
+	Impact            string               `json:"impact"`
+	Condition         string               `json:"condition"`         // Normal (default), Abnormal, Emergency
+	Severity          int                  `json:"severity"`          // 1 to 5
+	Frequency         int                  `json:"frequency"`         // 1 to 5
+	LegalRequirements []string             `json:"legalRequirements"` // Compliance obligations; any makes the aspect significant
+	Controls          []domain.RiskControl `json:"controls"`
+	Owner             string               `json:"owner"`
+}
+
+// UpdateAspectRequest represents a partial update of an environmental
+// aspect; omitted fields are left unchanged.
+// swagger:model UpdateAspectRequest
+type UpdateAspectRequest struct {
+	ProcessID         *int                 `json:"processId"` // 0 unlinks the process
+	Activity          *string              `json:"activity"`
+	Aspect            *string              `json:"aspect"`
+	Impact            *string              `json:"impact"`
+	Condition         *string              `json:"condition"`
+	Severity          *int                 `json:"severity"`
+	Frequency         *int                 `json:"frequency"`
+	LegalRequirements []string             `json:"legalRequirements"` // Replaces the legal requirements when present
+	Controls          []domain.RiskControl `json:"controls"`          // Replaces the controls when present
+	Owner             *string              `json:"owner"`
+}
+
+// CreateAssetRequest represents payload to register an information asset.
+// swagger:model CreateAssetRequest
+type CreateAssetRequest struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Type            string `json:"type"` // Information, Software, Hardware, Service, People, Site
+	Owner           string `json:"owner"`
+	Location        string `json:"location"`
+	Classification  string `json:"classification"`  // Public, Internal (default), Confidential, Restricted
+	Confidentiality int    `json:"confidentiality"` // 1 (Low) to 3 (High)
+	Integrity       int    `json:"integrity"`       // 1 (Low) to 3 (High)
+	Availability    int    `json:"availability"`    // 1 (Low) to 3 (High)
+}
+
+// UpdateAssetRequest represents a partial update of an information asset;
+// omitted fields are left unchanged.
+// swagger:model UpdateAssetRequest
+type UpdateAssetRequest struct {
+	Name            *string `json:"name"`
+	Description     *string `json:"description"`
+	Type            *string `json:"type"`
+	Owner           *string `json:"owner"`
+	Location        *string `json:"location"`
+	Classification  *string `json:"classification"`
+	Confidentiality *int    `json:"confidentiality"`
+	Integrity       *int    `json:"integrity"`
+	Availability    *int    `json:"availability"`
+}
+
+// CreateControlRequest represents payload to add a control of the
+// organisation's own to the ISMS controls catalogue.
+// swagger:model CreateControlRequest
+type CreateControlRequest struct {
+	ID                   string `json:"id"`    // e.g. ORG-1; Annex A numbers are taken
+	Theme                string `json:"theme"` // Organizational, People, Physical, Technological
+	Title                string `json:"title"`
+	Description          string `json:"description"`
+	Applicable           *bool  `json:"applicable"`           // Default true
+	Justification        string `json:"justification"`        // Required when not applicable
+	ImplementationStatus string `json:"implementationStatus"` // Not Implemented (default), Planned, Partially Implemented, Implemented
+	Owner                string `json:"owner"`
+}
+
+// UpdateControlRequest represents a partial update of a catalogue control;
+// omitted fields are left unchanged.
+// swagger:model UpdateControlRequest
+type UpdateControlRequest struct {
+	Theme                *string `json:"theme"`       // Not for Annex A controls
+	Title                *string `json:"title"`       // Not for Annex A controls
+	Description          *string `json:"description"` // Not for Annex A controls
+	Applicable           *bool   `json:"applicable"`
+	Justification        *string `json:"justification"` // Required when not applicable
+	ImplementationStatus *string `json:"implementationStatus"`
+	Owner                *string `json:"owner"`
+}
+
+// SeedControlsResponse reports how many Annex A controls were added to the
+// catalogue.
+// swagger:model SeedControlsResponse
+type SeedControlsResponse struct {
+	Added int `json:"added"`
 }
 
 // CreateActionRequest represents payload to create a CAPA action.
@@ -70,3 +275,92 @@ type UpdateActionRequest struct {
 	Status  *string `json:"status"`  // Open, In Progress, Done, Overdue
 	DueDate *string `json:"dueDate"` // Optional new due date
 }
+
+// LoginRequest represents a username/password login.
+// swagger:model LoginRequest
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateTokenRequest represents payload to create an API token.
+// swagger:model CreateTokenRequest
+type CreateTokenRequest struct {
+	Name          string `json:"name"`          // What the token is used for, e.g. "BI export"
+	ExpiresInDays int    `json:"expiresInDays"` // 0 = never expires
+}
+
+// TokenResponse returns a newly issued bearer token. The token value is
+// only ever shown once.
+// swagger:model TokenResponse
+type TokenResponse struct {
+	Token string `json:"token"`
+	domain.APIToken
+}
+
+// CreateUserRequest represents payload to create a user account.
+// swagger:model CreateUserRequest
+type CreateUserRequest struct {
+	Username    string                  `json:"username"`
+	DisplayName string                  `json:"displayName"`
+	Email       string                  `json:"email"`    // Optional; used for notifications
+	Password    string                  `json:"password"` // Optional; at least 10 characters
+	Roles       []domain.RoleAssignment `json:"roles"`
+}
+
+// UpdateUserRequest represents a partial update of a user account.
+// swagger:model UpdateUserRequest
+type UpdateUserRequest struct {
+	DisplayName *string `json:"displayName"`
+	Email       *string `json:"email"`  // Empty string clears the address
+	Active      *bool   `json:"active"` // false blocks login and all of the user's tokens
+}
+
+// SetRolesRequest replaces a user's role assignments.
+// swagger:model SetRolesRequest
+type SetRolesRequest struct {
+	Roles []domain.RoleAssignment `json:"roles"` // Omit domain for a role in every domain
+}
+
+// HealthResponse reports server and background job status.
+// swagger:model HealthResponse
+type HealthResponse struct {
+	Status string                `json:"status"`
+	Jobs   []scheduler.JobStatus `json:"jobs"`
+}
+
+// CreateWebhookRequest represents payload to subscribe a webhook.
+// swagger:model CreateWebhookRequest
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"` // e.g. ["incident.created", "action.status_changed"], or ["*"]
+	Description string   `json:"description"`
+	Secret      string   `json:"secret"` // Optional signing key (min. 16 characters); generated when empty
+}
+
+// UpdateWebhookRequest represents a partial update of a webhook.
+// swagger:model UpdateWebhookRequest
+type UpdateWebhookRequest struct {
+	URL          *string  `json:"url"`
+	Events       []string `json:"events"` // Replaces the filter when present
+	Description  *string  `json:"description"`
+	Active       *bool    `json:"active"`       // false pauses deliveries; they are kept and sent on reactivation
+	RotateSecret bool     `json:"rotateSecret"` // Issue a new signing secret
+}
+
+// WebhookSecretResponse returns a webhook together with its signing secret,
+// which is only set when the secret was created or rotated.
+// swagger:model WebhookSecretResponse
+type WebhookSecretResponse struct {
+	Secret string `json:"secret,omitempty"`
+	domain.Webhook
+}
+
+// RiskMatrixUpdateResponse returns the saved risk matrix and how many active
+// records were re-scored against it.
+// swagger:model RiskMatrixUpdateResponse
+type RiskMatrixUpdateResponse struct {
+	domain.RiskMatrix
+	RescoredRisks     int `json:"rescoredRisks"`
+	RescoredIncidents int `json:"rescoredIncidents"`
+}
@@ -0,0 +1,181 @@
+package httpapi
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Risk heat map handlers ---------
+
+// getRiskHeatmap godoc
+// @Summary      Risk heat map
+// @Description  Counts the risks in each likelihood x impact cell of the risk matrix, with their IDs, by inherent or residual ratings. Every cell of the matrix is returned, by likelihood then impact.
+// @Tags         risks
+// @Produce      json
+// @Param        basis      query  string  false  "Ratings to place risks by (inherent|residual, default inherent)"
+// @Param        domain     query  string  false  "Domain filter (quality|environment|ohs|isms)"
+// @Param        processId  query  int     false  "Process filter"
+// @Param        process    query  string  false  "Process name filter"
+// @Param        owner      query  string  false  "Owner filter"
+// @Param        status     query  string  false  "Status filter (Open|Accepted|Mitigated)"
+// @Success      200        {object} domain.RiskHeatmap
+// @Failure      400        {string} string
+// @Failure      403        {string} string
+// @Failure      500        {string} string
+// @Security     BearerAuth
+// @Router       /api/risks/heatmap [get]
+func (s *Server) getRiskHeatmap(w http.ResponseWriter, r *http.Request) {
+	h, ok := s.riskHeatmap(w, r)
+	if !ok {
+		return
+	}
+	s.respondJSON(w, http.StatusOK, h)
+}
+
+// getRiskHeatmapSVG godoc
+// @Summary      Risk heat map image
+// @Description  Renders the risk heat map as an SVG image for management review reports: impact across, likelihood up, cells coloured by level from green (lowest) to red (highest) and labelled with their risk counts. Takes the same filters as /api/risks/heatmap.
+// @Tags         risks
+// @Produce      image/svg+xml
+// @Param        basis      query  string  false  "Ratings to place risks by (inherent|residual, default inherent)"
+// @Param        domain     query  string  false  "Domain filter (quality|environment|ohs|isms)"
+// @Param        processId  query  int     false  "Process filter"
+// @Param        process    query  string  false  "Process name filter"
+// @Param        owner      query  string  false  "Owner filter"
+// @Param        status     query  string  false  "Status filter (Open|Accepted|Mitigated)"
+// @Success      200        {file} file
+// @Failure      400        {string} string
+// @Failure      403        {string} string
+// @Failure      500        {string} string
+// @Security     BearerAuth
+// @Router       /api/risks/heatmap.svg [get]
+func (s *Server) getRiskHeatmapSVG(w http.ResponseWriter, r *http.Request) {
+	h, ok := s.riskHeatmap(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.WriteHeader(http.StatusOK)
+	writeHeatmapSVG(w, h)
+}
+
+// riskHeatmap builds the heat map for the query of r, responding with the
+// error and false if that fails.
+func (s *Server) riskHeatmap(w http.ResponseWriter, r *http.Request) (*domain.RiskHeatmap, bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	qs := r.URL.Query()
+
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return nil, false
+	}
+	processID, err := queryInt(qs, "processId")
+	if err != nil {
+		s.respondError(w, err)
+		return nil, false
+	}
+	h, err := s.riskSvc.Heatmap(r.Context(), service.RiskListFilter{
+		Domain:    dom,
+		ProcessID: processID,
+		Process:   queryString(qs, "process"),
+		Owner:     queryString(qs, "owner"),
+		Status:    queryString(qs, "status"),
+	}, qs.Get("basis"))
+	if err != nil {
+		s.respondError(w, err)
+		return nil, false
+	}
+	return h, true
+}
+
+// Heat map image layout, in pixels.
+const (
+	svgCellWidth  = 72
+	svgCellHeight = 48
+	svgLeft       = 80
+	svgTop        = 44
+	svgBottom     = 84
+	svgRight      = 20
+)
+
+// writeHeatmapSVG draws h with impact across and likelihood up.
+func writeHeatmapSVG(out io.Writer, h *domain.RiskHeatmap) {
+	width := svgLeft + h.ImpactScale*svgCellWidth + svgRight
+	height := svgTop + h.LikelihoodScale*svgCellHeight + svgBottom
+	gridBottom := svgTop + h.LikelihoodScale*svgCellHeight
+
+	colors := make(map[string]string, len(h.Levels))
+	for i, level := range h.Levels {
+		colors[level] = levelColor(i, len(h.Levels))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="26" font-size="16" font-weight="bold">Risk heat map (%s, %d risks)</text>`+"\n",
+		svgLeft, html.EscapeString(h.Basis), h.Total)
+
+	for _, c := range h.Cells {
+		x := svgLeft + (c.Impact-1)*svgCellWidth
+		y := svgTop + (h.LikelihoodScale-c.Likelihood)*svgCellHeight
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#ffffff" stroke-width="2"><title>%s: likelihood %d, impact %d, %d risks</title></rect>`+"\n",
+			x, y, svgCellWidth, svgCellHeight, colors[c.Level], html.EscapeString(c.Level), c.Likelihood, c.Impact, c.Count)
+		if c.Count > 0 {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="18" font-weight="bold" text-anchor="middle" dominant-baseline="central">%d</text>`+"\n",
+				x+svgCellWidth/2, y+svgCellHeight/2, c.Count)
+		}
+	}
+
+	for l := 1; l <= h.LikelihoodScale; l++ {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12" text-anchor="end" dominant-baseline="central">%d</text>`+"\n",
+			svgLeft-8, svgTop+(h.LikelihoodScale-l)*svgCellHeight+svgCellHeight/2, l)
+	}
+	for i := 1; i <= h.ImpactScale; i++ {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12" text-anchor="middle">%d</text>`+"\n",
+			svgLeft+(i-1)*svgCellWidth+svgCellWidth/2, gridBottom+16, i)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="13" text-anchor="middle">Impact</text>`+"\n",
+		svgLeft+h.ImpactScale*svgCellWidth/2, gridBottom+34)
+	fmt.Fprintf(&b, `<text x="24" y="%d" font-size="13" text-anchor="middle" transform="rotate(-90 24 %d)">Likelihood</text>`+"\n",
+		svgTop+h.LikelihoodScale*svgCellHeight/2, svgTop+h.LikelihoodScale*svgCellHeight/2)
+
+	x := svgLeft
+	for _, level := range h.Levels {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="14" height="14" fill="%s"/>`+"\n", x, gridBottom+52, colors[level])
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12" dominant-baseline="central">%s</text>`+"\n",
+			x+20, gridBottom+59, html.EscapeString(level))
+		x += 28 + 8*len(level)
+	}
+	b.WriteString("</svg>\n")
+	io.WriteString(out, b.String())
+}
+
+// levelColor returns the colour of the i-th of n levels, running from green
+// through amber to red.
+func levelColor(i, n int) string {
+	stops := [3][3]float64{{76, 175, 80}, {255, 193, 7}, {244, 67, 54}}
+	t := 0.0
+	if n > 1 {
+		t = float64(i) / float64(n-1)
+	}
+	from, to, f := stops[0], stops[1], t*2
+	if t > 0.5 {
+		from, to, f = stops[1], stops[2], t*2-1
+	}
+	var rgb [3]int
+	for k := range rgb {
+		rgb[k] = int(from[k] + (to[k]-from[k])*f + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Incident investigation handlers ---------
+
+// getInvestigation godoc
+// @Summary      Get incident investigation
+// @Description  Returns the incident's root-cause investigation: its 5-Whys chain and Ishikawa (fishbone) diagram. 404 until an investigation is saved.
+// @Tags         incidents
+// @Produce      json
+// @Param        id   path      int  true  "Incident ID"
+// @Success      200  {object}  domain.Investigation
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id}/investigation [get]
+func (s *Server) getInvestigation(w http.ResponseWriter, r *http.Request, id int) {
+	inv, err := s.incidentSvc.GetInvestigation(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, inv)
+}
+
+// saveInvestigation godoc
+// @Summary      Save incident investigation
+// @Description  Creates or replaces the incident's investigation. It needs whys, a fishbone diagram or both; fishbone categories are man, machine, method, material, measurement and environment, each at most once, and causes may have sub-causes. At most one why or cause may be flagged with rootCause; its answer or cause becomes the incident's rootCause. Without a flagged root cause the incident's rootCause is left unchanged.
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Incident ID"
+// @Param        request  body      SaveInvestigationRequest  true  "Investigation"
+// @Success      200      {object}  domain.Investigation
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id}/investigation [put]
+func (s *Server) saveInvestigation(w http.ResponseWriter, r *http.Request, id int) {
+	var req SaveInvestigationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	inv, err := s.incidentSvc.SaveInvestigation(r.Context(), id, service.InvestigationInput{
+		Problem:  req.Problem,
+		Whys:     req.Whys,
+		Fishbone: req.Fishbone,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, inv)
+}
+
+// deleteInvestigation godoc
+// @Summary      Delete incident investigation
+// @Description  Removes the incident's investigation. The incident keeps its rootCause.
+// @Tags         incidents
+// @Param        id   path      int     true  "Incident ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id}/investigation [delete]
+func (s *Server) deleteInvestigation(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.incidentSvc.DeleteInvestigation(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,452 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- ISMS asset inventory, controls catalogue and SoA handlers ---------
+
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listAssets(w, r)
+	case http.MethodPost:
+		s.createAsset(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleAssetByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/isms/assets/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if sub != "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getAsset(w, r, id)
+	case http.MethodPatch:
+		s.updateAsset(w, r, id)
+	case http.MethodDelete:
+		s.deleteAsset(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listAssets godoc
+// @Summary      List information assets
+// @Description  Returns the information asset inventory, filtered, sorted and paged in the database.
+// @Tags         isms
+// @Produce      json
+// @Param        type            query    string  false  "Asset type filter (Information|Software|Hardware|Service|People|Site)"
+// @Param        classification  query    string  false  "Classification filter (Public|Internal|Confidential|Restricted)"
+// @Param        owner           query    string  false  "Owner filter"
+// @Param        q               query    string  false  "Free-text search in name, description, owner and location"
+// @Param        limit           query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset          query    int     false  "Number of records to skip"
+// @Param        sort            query    string  false  "Sort order as field:asc|desc, e.g. criticality:desc"
+// @Success      200             {array}  domain.InformationAsset
+// @Header       200             {integer} X-Total-Count "Total number of matching records"
+// @Failure      400             {string} string
+// @Failure      403             {string} string
+// @Failure      500             {string} string
+// @Security     BearerAuth
+// @Router       /api/isms/assets [get]
+func (s *Server) listAssets(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	assets, total, err := s.ismsSvc.ListAssets(r.Context(), service.AssetListFilter{
+		Type:           queryString(qs, "type"),
+		Classification: queryString(qs, "classification"),
+		Owner:          queryString(qs, "owner"),
+		Search:         qs.Get("q"),
+		Page:           page,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, assets)
+}
+
+// createAsset godoc
+// @Summary      Register information asset
+// @Description  Adds an asset to the inventory. Its criticality is the highest of its confidentiality, integrity and availability ratings. Requires contributing to the Information Security domain.
+// @Tags         isms
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateAssetRequest  true  "Asset"
+// @Success      201      {object}  domain.InformationAsset
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/assets [post]
+func (s *Server) createAsset(w http.ResponseWriter, r *http.Request) {
+	var req CreateAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	a, err := s.ismsSvc.CreateAsset(r.Context(), service.CreateAssetInput{
+		Name:            req.Name,
+		Description:     req.Description,
+		Type:            req.Type,
+		Owner:           req.Owner,
+		Location:        req.Location,
+		Classification:  req.Classification,
+		Confidentiality: req.Confidentiality,
+		Integrity:       req.Integrity,
+		Availability:    req.Availability,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, a)
+}
+
+// getAsset godoc
+// @Summary      Get information asset
+// @Description  Returns an asset of the inventory. GET /api/risks?assetId={id} lists its risks.
+// @Tags         isms
+// @Produce      json
+// @Param        id   path      int  true  "Asset ID"
+// @Success      200  {object}  domain.InformationAsset
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/assets/{id} [get]
+func (s *Server) getAsset(w http.ResponseWriter, r *http.Request, id int) {
+	a, err := s.ismsSvc.GetAsset(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, a)
+}
+
+// updateAsset godoc
+// @Summary      Update information asset
+// @Description  Changes an asset. Omitted fields are left unchanged.
+// @Tags         isms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                 true  "Asset ID"
+// @Param        request  body      UpdateAssetRequest  true  "Fields to change"
+// @Success      200      {object}  domain.InformationAsset
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/assets/{id} [patch]
+func (s *Server) updateAsset(w http.ResponseWriter, r *http.Request, id int) {
+	var req UpdateAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	a, err := s.ismsSvc.UpdateAsset(r.Context(), id, service.UpdateAssetInput{
+		Name:            req.Name,
+		Description:     req.Description,
+		Type:            req.Type,
+		Owner:           req.Owner,
+		Location:        req.Location,
+		Classification:  req.Classification,
+		Confidentiality: req.Confidentiality,
+		Integrity:       req.Integrity,
+		Availability:    req.Availability,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, a)
+}
+
+// deleteAsset godoc
+// @Summary      Delete information asset
+// @Description  Removes an asset from the inventory. Assets linked to risks (including deleted ones) are refused with 409.
+// @Tags         isms
+// @Param        id   path      int     true  "Asset ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/assets/{id} [delete]
+func (s *Server) deleteAsset(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.ismsSvc.DeleteAsset(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleControls(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listControls(w, r)
+	case http.MethodPost:
+		s.createControl(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleControlByID serves /api/isms/controls/{id}. Control IDs contain
+// dots (5.15), so they are taken from the path as they are.
+func (s *Server) handleControlByID(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/isms/controls/"), "/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getControl(w, r, id)
+	case http.MethodPatch:
+		s.updateControl(w, r, id)
+	case http.MethodDelete:
+		s.deleteControl(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listControls godoc
+// @Summary      List ISMS controls
+// @Description  Returns the controls catalogue in ID order (5.9 before 5.10), filtered and paged in the database.
+// @Tags         isms
+// @Produce      json
+// @Param        theme       query    string  false  "Theme filter (Organizational|People|Physical|Technological)"
+// @Param        applicable  query    bool    false  "Only applicable (true) or excluded (false) controls"
+// @Param        status      query    string  false  "Implementation status filter"
+// @Param        q           query    string  false  "Free-text search in ID, title, description, justification and owner"
+// @Param        limit       query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset      query    int     false  "Number of records to skip"
+// @Param        sort        query    string  false  "Sort order as field:asc|desc, e.g. implementationStatus:asc"
+// @Success      200         {array}  domain.ISMSControl
+// @Header       200         {integer} X-Total-Count "Total number of matching records"
+// @Failure      400         {string} string
+// @Failure      403         {string} string
+// @Failure      500         {string} string
+// @Security     BearerAuth
+// @Router       /api/isms/controls [get]
+func (s *Server) listControls(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	applicable, err := queryOptionalBool(qs, "applicable")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	controls, total, err := s.ismsSvc.ListControls(r.Context(), service.ControlListFilter{
+		Theme:      queryString(qs, "theme"),
+		Applicable: applicable,
+		Status:     queryString(qs, "status"),
+		Search:     qs.Get("q"),
+		Page:       page,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, controls)
+}
+
+// createControl godoc
+// @Summary      Add ISMS control
+// @Description  Adds a control of the organisation's own to the catalogue. Excluding it requires a justification. Requires process_owner or ims_manager for Information Security.
+// @Tags         isms
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateControlRequest  true  "Control"
+// @Success      201      {object}  domain.ISMSControl
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      409      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/controls [post]
+func (s *Server) createControl(w http.ResponseWriter, r *http.Request) {
+	var req CreateControlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	c, err := s.ismsSvc.CreateControl(r.Context(), service.CreateControlInput{
+		ID:                   req.ID,
+		Theme:                req.Theme,
+		Title:                req.Title,
+		Description:          req.Description,
+		Applicable:           req.Applicable,
+		Justification:        req.Justification,
+		ImplementationStatus: req.ImplementationStatus,
+		Owner:                req.Owner,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, c)
+}
+
+// seedControls godoc
+// @Summary      Seed Annex A controls
+// @Description  Adds the 93 ISO 27001:2022 Annex A controls missing from the catalogue as applicable and not implemented. Controls already in the catalogue keep their decisions, so seeding again is safe. Requires the ims_manager role for all domains.
+// @Tags         isms
+// @Produce      json
+// @Success      200  {object}  SeedControlsResponse
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/controls/seed [post]
+func (s *Server) seedControls(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	added, err := s.ismsSvc.SeedAnnexA(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, SeedControlsResponse{Added: added})
+}
+
+// getControl godoc
+// @Summary      Get ISMS control
+// @Description  Returns a control of the catalogue. GET /api/risks?ismsControl={id} lists its risks.
+// @Tags         isms
+// @Produce      json
+// @Param        id   path      string  true  "Control ID, e.g. 5.15"
+// @Success      200  {object}  domain.ISMSControl
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/controls/{id} [get]
+func (s *Server) getControl(w http.ResponseWriter, r *http.Request, id string) {
+	c, err := s.ismsSvc.GetControl(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, c)
+}
+
+// updateControl godoc
+// @Summary      Update ISMS control
+// @Description  Records the applicability decision, justification, implementation status or owner of a control. The theme, title and description of Annex A controls are fixed. Requires process_owner or ims_manager for Information Security.
+// @Tags         isms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                true  "Control ID, e.g. 5.15"
+// @Param        request  body      UpdateControlRequest  true  "Fields to change"
+// @Success      200      {object}  domain.ISMSControl
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/controls/{id} [patch]
+func (s *Server) updateControl(w http.ResponseWriter, r *http.Request, id string) {
+	var req UpdateControlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	c, err := s.ismsSvc.UpdateControl(r.Context(), id, service.UpdateControlInput{
+		Theme:                req.Theme,
+		Title:                req.Title,
+		Description:          req.Description,
+		Applicable:           req.Applicable,
+		Justification:        req.Justification,
+		ImplementationStatus: req.ImplementationStatus,
+		Owner:                req.Owner,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, c)
+}
+
+// deleteControl godoc
+// @Summary      Delete ISMS control
+// @Description  Removes a control of the organisation's own. Annex A controls can't be deleted (400; mark them not applicable instead), and controls linked to risks (including deleted ones) are refused with 409.
+// @Tags         isms
+// @Param        id   path      string  true  "Control ID"
+// @Success      204
+// @Failure      400  {string}  string
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/controls/{id} [delete]
+func (s *Server) deleteControl(w http.ResponseWriter, r *http.Request, id string) {
+	if err := s.ismsSvc.DeleteControl(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getStatementOfApplicability godoc
+// @Summary      Statement of Applicability
+// @Description  Lists every control of the catalogue in ID order with its applicability, justification, implementation status and the active Information Security risks linked to it, and counts the applicable and excluded controls and the applicable ones by implementation status.
+// @Tags         isms
+// @Produce      json
+// @Success      200  {object}  domain.StatementOfApplicability
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/isms/soa [get]
+func (s *Server) getStatementOfApplicability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	soa, err := s.ismsSvc.StatementOfApplicability(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, soa)
+}
@@ -0,0 +1,144 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// --------- OHS handlers ---------
+
+// getOHSCodes godoc
+// @Summary      OHS classification codes
+// @Description  Lists the incident types, employment types, body part codes and injury nature codes incidents accept.
+// @Tags         ohs
+// @Produce      json
+// @Success      200  {object}  domain.OHSCodes
+// @Failure      403  {string}  string
+// @Security     BearerAuth
+// @Router       /api/ohs/codes [get]
+func (s *Server) getOHSCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	codes, err := s.ohsSvc.Codes(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, codes)
+}
+
+// getOHSStatistics godoc
+// @Summary      OHS statistics
+// @Description  Counts the OHS incidents that occurred in a period by type, with lost-time and recordable injuries, days lost and reportable incidents, and computes the LTIFR (per 1,000,000 hours) and TRIR (per 200,000 hours) from the recorded hours worked. The rates only count injuries in months with hours worked and are null without any. overdueReports counts reportable incidents of any period not reported by their deadline. Requires read access to OHS.
+// @Tags         ohs
+// @Produce      json
+// @Param        from  query     string  false  "First month (YYYY-MM); with to, default the last 12 months"
+// @Param        to    query     string  false  "Last month (YYYY-MM)"
+// @Success      200   {object}  domain.OHSStatistics
+// @Failure      400   {string}  string
+// @Failure      403   {string}  string
+// @Failure      500   {string}  string
+// @Security     BearerAuth
+// @Router       /api/ohs/statistics [get]
+func (s *Server) getOHSStatistics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	qs := r.URL.Query()
+	st, err := s.ohsSvc.Statistics(r.Context(), qs.Get("from"), qs.Get("to"))
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, st)
+}
+
+// listHoursWorked godoc
+// @Summary      List hours worked
+// @Description  Returns the hours worked recorded per month, oldest first.
+// @Tags         ohs
+// @Produce      json
+// @Param        from  query     string  false  "First month (YYYY-MM)"
+// @Param        to    query     string  false  "Last month (YYYY-MM)"
+// @Success      200   {array}   domain.HoursWorked
+// @Failure      400   {string}  string
+// @Failure      403   {string}  string
+// @Failure      500   {string}  string
+// @Security     BearerAuth
+// @Router       /api/ohs/hours-worked [get]
+func (s *Server) listHoursWorked(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	qs := r.URL.Query()
+	list, err := s.ohsSvc.ListHoursWorked(r.Context(), qs.Get("from"), qs.Get("to"))
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleHoursWorkedMonth(w http.ResponseWriter, r *http.Request) {
+	month := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/ohs/hours-worked/"), "/")
+	switch r.Method {
+	case http.MethodPut:
+		s.setHoursWorked(w, r, month)
+	case http.MethodDelete:
+		s.deleteHoursWorked(w, r, month)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// setHoursWorked godoc
+// @Summary      Record hours worked
+// @Description  Records the hours worked by all workers in a month, replacing an earlier figure. Requires the ims_manager role for all domains.
+// @Tags         ohs
+// @Accept       json
+// @Produce      json
+// @Param        month    path      string                 true  "Month (YYYY-MM)"
+// @Param        request  body      SetHoursWorkedRequest  true  "Hours worked"
+// @Success      200      {object}  domain.HoursWorked
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/ohs/hours-worked/{month} [put]
+func (s *Server) setHoursWorked(w http.ResponseWriter, r *http.Request, month string) {
+	var req SetHoursWorkedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	h, err := s.ohsSvc.SetHoursWorked(r.Context(), month, req.Hours)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, h)
+}
+
+// deleteHoursWorked godoc
+// @Summary      Delete hours worked
+// @Description  Removes the hours worked recorded for a month. Requires the ims_manager role for all domains.
+// @Tags         ohs
+// @Param        month  path      string  true  "Month (YYYY-MM)"
+// @Success      204
+// @Failure      403    {string}  string
+// @Failure      404    {string}  string
+// @Failure      500    {string}  string
+// @Security     BearerAuth
+// @Router       /api/ohs/hours-worked/{month} [delete]
+func (s *Server) deleteHoursWorked(w http.ResponseWriter, r *http.Request, month string) {
+	if err := s.ohsSvc.DeleteHoursWorked(r.Context(), month); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
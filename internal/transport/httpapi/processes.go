@@ -0,0 +1,241 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Process register handlers ---------
+
+func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listProcesses(w, r)
+	case http.MethodPost:
+		s.createProcess(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleProcessByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/processes/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getProcess(w, r, id)
+		case http.MethodPatch:
+			s.updateProcess(w, r, id)
+		case http.MethodDelete:
+			s.deleteProcess(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "summary":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getProcessSummary(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// listProcesses godoc
+// @Summary      List processes
+// @Description  Returns the process register, filtered, sorted and paged in the database.
+// @Tags         processes
+// @Produce      json
+// @Param        domain    query    string  false  "Only processes in this domain (quality|environment|ohs|isms)"
+// @Param        owner     query    string  false  "Owner filter"
+// @Param        parentId  query    int     false  "Only sub-processes of this process"
+// @Param        q         query    string  false  "Free-text search in name, description and owner"
+// @Param        limit     query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset    query    int     false  "Number of records to skip"
+// @Param        sort      query    string  false  "Sort order as field:asc|desc, e.g. name:asc"
+// @Success      200       {array}  domain.Process
+// @Header       200       {integer} X-Total-Count "Total number of matching records"
+// @Failure      400       {string} string
+// @Failure      403       {string} string
+// @Failure      500       {string} string
+// @Security     BearerAuth
+// @Router       /api/processes [get]
+func (s *Server) listProcesses(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	parentID, err := queryInt(qs, "parentId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	processes, total, err := s.processSvc.ListProcesses(r.Context(), service.ProcessListFilter{
+		Domain:   dom,
+		Owner:    queryString(qs, "owner"),
+		ParentID: parentID,
+		Search:   qs.Get("q"),
+		Page:     page,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, processes)
+}
+
+// createProcess godoc
+// @Summary      Register process
+// @Description  Adds a process to the process register. Names are unique regardless of letter case; surrounding and repeated spaces are removed. Requires the ims_manager role for all domains.
+// @Tags         processes
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateProcessRequest  true  "Process"
+// @Success      201      {object}  domain.Process
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      409      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/processes [post]
+func (s *Server) createProcess(w http.ResponseWriter, r *http.Request) {
+	var req CreateProcessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	p, err := s.processSvc.CreateProcess(r.Context(), service.CreateProcessInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Owner:       req.Owner,
+		ParentID:    req.ParentID,
+		Domains:     req.Domains,
+		KPIs:        req.KPIs,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, p)
+}
+
+// getProcess godoc
+// @Summary      Get process
+// @Description  Returns a process of the register.
+// @Tags         processes
+// @Produce      json
+// @Param        id   path      int  true  "Process ID"
+// @Success      200  {object}  domain.Process
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/processes/{id} [get]
+func (s *Server) getProcess(w http.ResponseWriter, r *http.Request, id int) {
+	p, err := s.processSvc.GetProcess(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, p)
+}
+
+// updateProcess godoc
+// @Summary      Update process
+// @Description  Changes a process. A new name shows on every risk and audit of the process. Requires the ims_manager role for all domains.
+// @Tags         processes
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Process ID"
+// @Param        request  body      UpdateProcessRequest  true  "Fields to change"
+// @Success      200      {object}  domain.Process
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      409      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/processes/{id} [patch]
+func (s *Server) updateProcess(w http.ResponseWriter, r *http.Request, id int) {
+	var req UpdateProcessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	p, err := s.processSvc.UpdateProcess(r.Context(), id, service.UpdateProcessInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Owner:       req.Owner,
+		ParentID:    req.ParentID,
+		Domains:     req.Domains,
+		KPIs:        req.KPIs,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, p)
+}
+
+// deleteProcess godoc
+// @Summary      Delete process
+// @Description  Removes a process from the register. Processes that risks (including deleted ones), audits, environmental aspects or sub-processes refer to are refused with 409. Requires the ims_manager role for all domains.
+// @Tags         processes
+// @Param        id   path      int     true  "Process ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/processes/{id} [delete]
+func (s *Server) deleteProcess(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.processSvc.DeleteProcess(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getProcessSummary godoc
+// @Summary      Process summary
+// @Description  Counts the risks (by status and level), incidents, audits and actions (by status) of a process in the domains the caller can read, and lists its sub-processes. Incidents count through their related risk; actions through the risk, incident or audit they were raised from.
+// @Tags         processes
+// @Produce      json
+// @Param        id   path      int  true  "Process ID"
+// @Success      200  {object}  domain.ProcessSummary
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/processes/{id}/summary [get]
+func (s *Server) getProcessSummary(w http.ResponseWriter, r *http.Request, id int) {
+	sum, err := s.processSvc.Summary(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, sum)
+}
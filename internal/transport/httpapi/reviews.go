@@ -0,0 +1,126 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Risk review handlers ---------
+
+// createRiskReview godoc
+// @Summary      Review risk
+// @Description  Records a periodic review of a risk by the caller. Ratings in the body re-score the risk; omitted ones are confirmed unchanged. The next review is scheduled from today using the reviewDays of the risk's level in the risk matrix.
+// @Tags         risks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "Risk ID"
+// @Param        request  body      CreateReviewRequest  true  "Review"
+// @Success      201      {object}  domain.RiskReview
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/reviews [post]
+func (s *Server) createRiskReview(w http.ResponseWriter, r *http.Request, id int) {
+	var req CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	rv, err := s.riskSvc.ReviewRisk(r.Context(), id, service.ReviewInput{
+		Likelihood:         req.Likelihood,
+		Impact:             req.Impact,
+		ResidualLikelihood: req.ResidualLikelihood,
+		ResidualImpact:     req.ResidualImpact,
+		Comments:           req.Comments,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, rv)
+}
+
+// listRiskReviews godoc
+// @Summary      List risk reviews
+// @Description  Returns the reviews of a risk, newest first.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int  true  "Risk ID"
+// @Success      200  {array}   domain.RiskReview
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/reviews [get]
+func (s *Server) listRiskReviews(w http.ResponseWriter, r *http.Request, id int) {
+	reviews, err := s.riskSvc.ListReviews(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, reviews)
+}
+
+// handleRiskReviewsDue godoc
+// @Summary      Risks due for review
+// @Description  Lists risks whose nextReviewAt is today or earlier, or within the given number of days, soonest first.
+// @Tags         risks
+// @Produce      json
+// @Param        within  query    int     false  "Also include risks due in the next N days (default 0)"
+// @Param        domain  query    string  false  "Domain filter (quality|environment|ohs|isms)"
+// @Param        owner   query    string  false  "Owner filter"
+// @Param        limit   query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset  query    int     false  "Number of records to skip"
+// @Param        sort    query    string  false  "Sort order as field:asc|desc (default nextReviewAt:asc)"
+// @Success      200     {array}  domain.Risk
+// @Header       200     {integer} X-Total-Count "Total number of matching records"
+// @Failure      400     {string} string
+// @Failure      403     {string} string
+// @Failure      500     {string} string
+// @Security     BearerAuth
+// @Router       /api/risks/reviews/due [get]
+func (s *Server) handleRiskReviewsDue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	qs := r.URL.Query()
+
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	within, err := queryInt(qs, "within")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	days := 0
+	if within != nil {
+		days = *within
+	}
+
+	risks, total, err := s.riskSvc.ListReviewsDue(r.Context(), days, service.RiskListFilter{
+		Domain: dom,
+		Owner:  queryString(qs, "owner"),
+		Page:   page,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, risks)
+}
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+)
+
+// --------- Risk matrix handlers ---------
+
+func (s *Server) handleRiskMatrix(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.getRiskMatrix(w, r)
+	case http.MethodPut:
+		s.updateRiskMatrix(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getRiskMatrix godoc
+// @Summary      Get risk matrix
+// @Description  Returns the likelihood and impact scales, the level bands and any per-cell overrides used to score risks and incidents. The default is a 5x5 matrix with Low (1-7), Medium (8-15) and High (16-25).
+// @Tags         risk-matrix
+// @Produce      json
+// @Success      200  {object}  domain.RiskMatrix
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risk-matrix [get]
+func (s *Server) getRiskMatrix(w http.ResponseWriter, r *http.Request) {
+	m, err := s.matrixSvc.GetMatrix(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, m)
+}
+
+// updateRiskMatrix godoc
+// @Summary      Replace risk matrix
+// @Description  Replaces the risk matrix and re-scores every active risk and incident. A record's level comes from its cell override, or else from the highest band whose minScore its likelihood x impact reaches. Refused with 400 while active records are rated outside the new scales. Requires the ims_manager role for all domains.
+// @Tags         risk-matrix
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.RiskMatrix         true  "New matrix"
+// @Success      200      {object}  RiskMatrixUpdateResponse
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/risk-matrix [put]
+func (s *Server) updateRiskMatrix(w http.ResponseWriter, r *http.Request) {
+	var m domain.RiskMatrix
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	res, err := s.matrixSvc.UpdateMatrix(r.Context(), &m)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, RiskMatrixUpdateResponse{
+		RiskMatrix:        *res.Matrix,
+		RescoredRisks:     res.RescoredRisks,
+		RescoredIncidents: res.RescoredIncidents,
+	})
+}
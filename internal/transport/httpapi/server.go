@@ -3,15 +3,19 @@ package httpapi
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/xenakil/integraflow-ims/internal/auth"
 	"github.com/xenakil/integraflow-ims/internal/domain"
 	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/scheduler"
 	"github.com/xenakil/integraflow-ims/internal/service"
 
 	// Swagger docs (generated by swag init)
@@ -19,40 +23,95 @@ import (
 )
 
 type Server struct {
+	authSvc      *service.AuthService
 	riskSvc      *service.RiskService
 	incidentSvc  *service.IncidentService
 	auditSvc     *service.AuditService
 	actionSvc    *service.ActionService
 	dashboardSvc *service.DashboardService
+	historySvc   *service.HistoryService
+	webhookSvc   *service.WebhookService
+	matrixSvc    *service.RiskMatrixService
+	processSvc   *service.ProcessService
+	attachSvc    *service.AttachmentService
+	ohsSvc       *service.OHSService
+	aspectSvc    *service.AspectService
+	ismsSvc      *service.ISMSService
+	jobs         *scheduler.Scheduler
 	mux          *http.ServeMux
 }
 
 func NewServer(
+	authSvc *service.AuthService,
 	riskSvc *service.RiskService,
 	incidentSvc *service.IncidentService,
 	auditSvc *service.AuditService,
 	actionSvc *service.ActionService,
 	dashboardSvc *service.DashboardService,
+	historySvc *service.HistoryService,
+	webhookSvc *service.WebhookService,
+	matrixSvc *service.RiskMatrixService,
+	processSvc *service.ProcessService,
+	attachSvc *service.AttachmentService,
+	ohsSvc *service.OHSService,
+	aspectSvc *service.AspectService,
+	ismsSvc *service.ISMSService,
+	jobs *scheduler.Scheduler,
 ) *Server {
 	s := &Server{
+		authSvc:      authSvc,
 		riskSvc:      riskSvc,
 		incidentSvc:  incidentSvc,
 		auditSvc:     auditSvc,
 		actionSvc:    actionSvc,
 		dashboardSvc: dashboardSvc,
+		historySvc:   historySvc,
+		webhookSvc:   webhookSvc,
+		matrixSvc:    matrixSvc,
+		processSvc:   processSvc,
+		attachSvc:    attachSvc,
+		ohsSvc:       ohsSvc,
+		aspectSvc:    aspectSvc,
+		ismsSvc:      ismsSvc,
+		jobs:         jobs,
 		mux:          http.NewServeMux(),
 	}
 	s.routes()
 	return s
 }
 
+// ServeHTTP authenticates every request except public paths and makes the
+// user available to handlers and services through the request context.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isPublicPath(r.URL.Path) {
+		u, err := s.authSvc.Authenticate(bearerToken(r))
+		if err != nil {
+			s.respondError(w, err)
+			return
+		}
+		r = r.WithContext(auth.WithUser(r.Context(), u))
+	}
 	s.mux.ServeHTTP(w, r)
 }
 
 func (s *Server) routes() {
+	s.mux.HandleFunc("/api/auth/login", s.handleLogin)
+	s.mux.HandleFunc("/api/auth/logout", s.handleLogout)
+	s.mux.HandleFunc("/api/auth/me", s.handleMe)
+	s.mux.HandleFunc("/api/auth/tokens", s.handleTokens)
+	s.mux.HandleFunc("/api/auth/tokens/", s.handleTokenByID)
+	s.mux.HandleFunc("/api/users", s.handleUsers)
+	s.mux.HandleFunc("/api/users/", s.handleUserByID)
+
 	s.mux.HandleFunc("/api/risks", s.handleRisks)
 	s.mux.HandleFunc("/api/risks/", s.handleRiskByID)
+	s.mux.HandleFunc("/api/risks/reviews/due", s.handleRiskReviewsDue)
+	s.mux.HandleFunc("/api/risks/trend", s.handleRiskTrend)
+	s.mux.HandleFunc("/api/risks/heatmap", s.getRiskHeatmap)
+	s.mux.HandleFunc("/api/risks/heatmap.svg", s.getRiskHeatmapSVG)
+
+	s.mux.HandleFunc("/api/processes", s.handleProcesses)
+	s.mux.HandleFunc("/api/processes/", s.handleProcessByID)
 
 	s.mux.HandleFunc("/api/incidents", s.handleIncidents)
 	s.mux.HandleFunc("/api/incidents/", s.handleIncidentByID)
@@ -63,16 +122,46 @@ func (s *Server) routes() {
 	s.mux.HandleFunc("/api/actions", s.handleActions)
 	s.mux.HandleFunc("/api/actions/", s.handleActionByID)
 
+	s.mux.HandleFunc("/api/attachments/", s.handleAttachmentByID)
+
+	s.mux.HandleFunc("/api/aspects", s.handleAspects)
+	s.mux.HandleFunc("/api/aspects/", s.handleAspectByID)
+
+	s.mux.HandleFunc("/api/isms/assets", s.handleAssets)
+	s.mux.HandleFunc("/api/isms/assets/", s.handleAssetByID)
+	s.mux.HandleFunc("/api/isms/controls", s.handleControls)
+	s.mux.HandleFunc("/api/isms/controls/seed", s.seedControls)
+	s.mux.HandleFunc("/api/isms/controls/", s.handleControlByID)
+	s.mux.HandleFunc("/api/isms/soa", s.getStatementOfApplicability)
+
+	s.mux.HandleFunc("/api/ohs/codes", s.getOHSCodes)
+	s.mux.HandleFunc("/api/ohs/statistics", s.getOHSStatistics)
+	s.mux.HandleFunc("/api/ohs/hours-worked", s.listHoursWorked)
+	s.mux.HandleFunc("/api/ohs/hours-worked/", s.handleHoursWorkedMonth)
+
 	s.mux.HandleFunc("/api/dashboard", s.handleDashboard)
+	s.mux.HandleFunc("/api/history/verify", s.handleVerifyHistory)
+
+	s.mux.HandleFunc("/api/webhooks", s.handleWebhooks)
+	s.mux.HandleFunc("/api/webhooks/", s.handleWebhookByID)
+
+	s.mux.HandleFunc("/api/risk-matrix", s.handleRiskMatrix)
 
 	// Swagger UI → http://localhost:8080/swagger/index.html
 	s.mux.Handle("/swagger/", httpSwagger.WrapHandler)
 
-	// Simple health check
-	s.mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("OK"))
-	})
+	s.mux.HandleFunc("/health", s.handleHealth)
+}
+
+// handleHealth godoc
+// @Summary      Health check
+// @Description  Reports that the server is up, with the interval and last run of each background job.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  HealthResponse
+// @Router       /health [get]
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, http.StatusOK, HealthResponse{Status: "OK", Jobs: s.jobs.Status()})
 }
 
 // --------- Risk handlers ---------
@@ -89,30 +178,87 @@ func (s *Server) handleRisks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleRiskByID(w http.ResponseWriter, r *http.Request) {
-	id, err := parseID(r.URL.Path, "/api/risks/")
+	id, sub, err := parseIDPath(r.URL.Path, "/api/risks/")
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodPut:
-		s.updateRiskStatus(w, r, id)
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getRisk(w, r, id)
+		case http.MethodPut:
+			s.updateRiskStatus(w, r, id)
+		case http.MethodPatch:
+			s.patchRisk(w, r, id)
+		case http.MethodDelete:
+			s.deleteRisk(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.restoreRisk(w, r, id)
+	case "attachments":
+		s.handleRecordAttachments(w, r, domain.KindRisk, id)
+	case "history":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getRiskHistory(w, r, id)
+	case "score-history":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getRiskScoreHistory(w, r, id)
+	case "treatment":
+		switch r.Method {
+		case http.MethodGet:
+			s.getRiskTreatment(w, r, id)
+		case http.MethodPut:
+			s.setRiskTreatment(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "reviews":
+		switch r.Method {
+		case http.MethodGet:
+			s.listRiskReviews(w, r, id)
+		case http.MethodPost:
+			s.createRiskReview(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "treatment/approve":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.approveRiskTreatment(w, r, id)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		http.NotFound(w, r)
 	}
 }
 
 // createRisk godoc
 // @Summary      Create a new risk
-// @Description  Creates a new IMS risk and calculates its risk score and level.
+// @Description  Creates a new IMS risk with its controls and calculates the inherent and residual risk scores and levels. Residual ratings default to the inherent ones and can't exceed them. Information Security risks can be linked to information assets and catalogue controls. The process must be registered (processId, or process with its name); unknown process names are refused with 400 and are not registered.
 // @Tags         risks
 // @Accept       json
 // @Produce      json
 // @Param        request  body      CreateRiskRequest  true  "Risk payload"
 // @Success      201      {object}  domain.Risk
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/risks [post]
 func (s *Server) createRisk(w http.ResponseWriter, r *http.Request) {
 	var req CreateRiskRequest
@@ -122,16 +268,22 @@ func (s *Server) createRisk(w http.ResponseWriter, r *http.Request) {
 	}
 
 	in := service.CreateRiskInput{
-		Title:       req.Title,
-		Process:     req.Process,
-		Domain:      req.Domain,
-		Description: req.Description,
-		Likelihood:  req.Likelihood,
-		Impact:      req.Impact,
-		Owner:       req.Owner,
+		Title:              req.Title,
+		ProcessID:          req.ProcessID,
+		Process:            req.Process,
+		Domain:             req.Domain,
+		Description:        req.Description,
+		Likelihood:         req.Likelihood,
+		Impact:             req.Impact,
+		ResidualLikelihood: req.ResidualLikelihood,
+		ResidualImpact:     req.ResidualImpact,
+		Controls:           req.Controls,
+		AssetIDs:           req.AssetIDs,
+		ISMSControls:       req.ISMSControls,
+		Owner:              req.Owner,
 	}
 
-	risk, err := s.riskSvc.CreateRisk(in)
+	risk, err := s.riskSvc.CreateRisk(r.Context(), in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -141,44 +293,107 @@ func (s *Server) createRisk(w http.ResponseWriter, r *http.Request) {
 
 // listRisks godoc
 // @Summary      List risks
-// @Description  Returns all risks, optionally filtered by IMS domain and status.
+// @Description  Returns risks filtered, sorted and paged in the database.
 // @Tags         risks
 // @Produce      json
-// @Param        domain  query    string  false  "Domain filter (quality|environment|ohs|isms)"
-// @Param        status  query    string  false  "Status filter (Open|Accepted|Mitigated)"
-// @Success      200     {array}  domain.Risk
-// @Failure      400     {string} string
-// @Failure      500     {string} string
+// @Param        domain          query    string  false  "Domain filter (quality|environment|ohs|isms)"
+// @Param        processId       query    int     false  "Process filter"
+// @Param        process         query    string  false  "Process name filter"
+// @Param        status          query    string  false  "Status filter (Open|Accepted|Mitigated)"
+// @Param        owner           query    string  false  "Owner filter"
+// @Param        level           query    string  false  "Level filter, one of the risk matrix levels (default Low|Medium|High)"
+// @Param        residualLevel   query    string  false  "Residual level filter"
+// @Param        assetId         query    int     false  "Only risks linked to this information asset"
+// @Param        ismsControl     query    string  false  "Only risks linked to this catalogue control, e.g. 5.15"
+// @Param        createdFrom     query    string  false  "Created on or after (YYYY-MM-DD)"
+// @Param        createdTo       query    string  false  "Created on or before (YYYY-MM-DD)"
+// @Param        q               query    string  false  "Free-text search in title, description, process and owner"
+// @Param        includeDeleted  query    bool    false  "Include soft-deleted risks"
+// @Param        limit           query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset          query    int     false  "Number of records to skip"
+// @Param        sort            query    string  false  "Sort order as field:asc|desc, e.g. createdAt:desc"
+// @Success      200             {array}  domain.Risk
+// @Header       200             {integer} X-Total-Count "Total number of matching records"
+// @Failure      400             {string} string
+// @Failure      403             {string} string
+// @Failure      500             {string} string
+// @Security     BearerAuth
 // @Router       /api/risks [get]
 func (s *Server) listRisks(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
-	domainStr := qs.Get("domain")
-	status := qs.Get("status")
 
-	filter := service.RiskListFilter{}
-	if domainStr != "" {
-		dom, err := domain.ParseDomain(domainStr)
-		if err != nil {
-			s.respondError(w, err)
-			return
-		}
-		filter.Domain = &dom
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
 	}
-	if status != "" {
-		filter.Status = &status
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
 	}
 
-	risks, err := s.riskSvc.ListRisks(filter)
+	processID, err := queryInt(qs, "processId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	assetID, err := queryInt(qs, "assetId")
 	if err != nil {
 		s.respondError(w, err)
 		return
 	}
+
+	filter := service.RiskListFilter{
+		Domain:         dom,
+		ProcessID:      processID,
+		Process:        queryString(qs, "process"),
+		Status:         queryString(qs, "status"),
+		Owner:          queryString(qs, "owner"),
+		Level:          queryString(qs, "level"),
+		ResidualLevel:  queryString(qs, "residualLevel"),
+		AssetID:        assetID,
+		ISMSControl:    queryString(qs, "ismsControl"),
+		Created:        repository.DateRange{From: qs.Get("createdFrom"), To: qs.Get("createdTo")},
+		Search:         qs.Get("q"),
+		IncludeDeleted: queryBool(qs, "includeDeleted"),
+		Page:           page,
+	}
+
+	risks, total, err := s.riskSvc.ListRisks(r.Context(), filter)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	s.respondJSON(w, http.StatusOK, risks)
 }
 
+// getRisk godoc
+// @Summary      Get risk
+// @Description  Returns a single risk by ID.
+// @Tags         risks
+// @Produce      json
+// @Param        id              path      int          true   "Risk ID"
+// @Param        includeDeleted  query     bool         false  "Also return the risk if it is soft-deleted"
+// @Success      200  {object}  domain.Risk
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id} [get]
+func (s *Server) getRisk(w http.ResponseWriter, r *http.Request, id int) {
+	risk, err := s.riskSvc.GetRisk(r.Context(), id, queryBool(r.URL.Query(), "includeDeleted"))
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, risk)
+}
+
 // updateRiskStatus godoc
 // @Summary      Update risk status
-// @Description  Updates the status of an existing risk.
+// @Description  Updates the status of an existing risk. Risks in an escalating level of the risk matrix (default High) can only be Accepted under an approved treatment plan with option Accept.
 // @Tags         risks
 // @Accept       json
 // @Produce      json
@@ -186,8 +401,12 @@ func (s *Server) listRisks(w http.ResponseWriter, r *http.Request) {
 // @Param        request  body      UpdateRiskStatusRequest true  "New status"
 // @Success      200      {object}  domain.Risk
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      404      {string}  string
+// @Failure      409      {object}  domain.TransitionError
+// @Failure      422      {object}  domain.TransitionError
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/risks/{id} [put]
 func (s *Server) updateRiskStatus(w http.ResponseWriter, r *http.Request, id int) {
 	var req UpdateRiskStatusRequest
@@ -196,7 +415,97 @@ func (s *Server) updateRiskStatus(w http.ResponseWriter, r *http.Request, id int
 		return
 	}
 
-	risk, err := s.riskSvc.UpdateStatus(id, req.Status)
+	risk, err := s.riskSvc.UpdateStatus(r.Context(), id, req.Status)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, risk)
+}
+
+// patchRisk godoc
+// @Summary      Patch risk
+// @Description  Updates any subset of risk fields. Inherent and residual scores and levels are recalculated from the ratings; a residual rating equal to the inherent one follows it unless given. controls, assetIds and ismsControls replace the whole list. A process name must be registered; unknown names are refused with 400.
+// @Tags         risks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Risk ID"
+// @Param        request  body      PatchRiskRequest  true  "Fields to change"
+// @Success      200      {object}  domain.Risk
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      409      {object}  domain.TransitionError
+// @Failure      422      {object}  domain.TransitionError
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id} [patch]
+func (s *Server) patchRisk(w http.ResponseWriter, r *http.Request, id int) {
+	var req PatchRiskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	in := service.UpdateRiskInput{
+		Title:              req.Title,
+		ProcessID:          req.ProcessID,
+		Process:            req.Process,
+		Domain:             req.Domain,
+		Description:        req.Description,
+		Likelihood:         req.Likelihood,
+		Impact:             req.Impact,
+		ResidualLikelihood: req.ResidualLikelihood,
+		ResidualImpact:     req.ResidualImpact,
+		Controls:           req.Controls,
+		AssetIDs:           req.AssetIDs,
+		ISMSControls:       req.ISMSControls,
+		Owner:              req.Owner,
+		Status:             req.Status,
+	}
+
+	risk, err := s.riskSvc.UpdateRisk(r.Context(), id, in)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, risk)
+}
+
+// deleteRisk godoc
+// @Summary      Delete risk
+// @Description  Soft-deletes a risk. Risks still linked to active incidents or actions are refused with 409.
+// @Tags         risks
+// @Param        id   path      int     true  "Risk ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id} [delete]
+func (s *Server) deleteRisk(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.riskSvc.DeleteRisk(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreRisk godoc
+// @Summary      Restore risk
+// @Description  Restores a soft-deleted risk.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int          true  "Risk ID"
+// @Success      200  {object}  domain.Risk
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/restore [post]
+func (s *Server) restoreRisk(w http.ResponseWriter, r *http.Request, id int) {
+	risk, err := s.riskSvc.RestoreRisk(r.Context(), id)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -204,6 +513,27 @@ func (s *Server) updateRiskStatus(w http.ResponseWriter, r *http.Request, id int
 	s.respondJSON(w, http.StatusOK, risk)
 }
 
+// getRiskHistory godoc
+// @Summary      Risk change history
+// @Description  Returns every change to a risk (including while deleted), oldest first, with actor, timestamp and field-level before/after values.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int  true  "Risk ID"
+// @Success      200  {array}   domain.HistoryEntry
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/history [get]
+func (s *Server) getRiskHistory(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := s.riskSvc.RiskHistory(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, entries)
+}
+
 // --------- Incident handlers ---------
 
 func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
@@ -218,32 +548,66 @@ func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleIncidentByID(w http.ResponseWriter, r *http.Request) {
-	id, err := parseID(r.URL.Path, "/api/incidents/")
+	id, sub, err := parseIDPath(r.URL.Path, "/api/incidents/")
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		s.getIncident(w, r, id)
-	case http.MethodPut:
-		s.updateIncident(w, r, id)
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getIncident(w, r, id)
+		case http.MethodPut:
+			s.updateIncident(w, r, id)
+		case http.MethodDelete:
+			s.deleteIncident(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.restoreIncident(w, r, id)
+	case "attachments":
+		s.handleRecordAttachments(w, r, domain.KindIncident, id)
+	case "history":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getIncidentHistory(w, r, id)
+	case "investigation":
+		switch r.Method {
+		case http.MethodGet:
+			s.getInvestigation(w, r, id)
+		case http.MethodPut:
+			s.saveInvestigation(w, r, id)
+		case http.MethodDelete:
+			s.deleteInvestigation(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		http.NotFound(w, r)
 	}
 }
 
 // createIncident godoc
 // @Summary      Create a new incident
-// @Description  Records an incident/nonconformity in the IMS.
+// @Description  Records an incident/nonconformity in the IMS. Environment incidents can be linked to an environmental aspect. Only OHS incidents carry a type and, for injuries, the injured person, body part and injury nature codes (GET /api/ohs/codes) and days lost; reportable incidents need a reporting deadline.
 // @Tags         incidents
 // @Accept       json
 // @Produce      json
 // @Param        request  body      CreateIncidentRequest  true  "Incident payload"
 // @Success      201      {object}  domain.Incident
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/incidents [post]
 func (s *Server) createIncident(w http.ResponseWriter, r *http.Request) {
 	var req CreateIncidentRequest
@@ -257,11 +621,22 @@ func (s *Server) createIncident(w http.ResponseWriter, r *http.Request) {
 		Description:   req.Description,
 		Domain:        req.Domain,
 		RelatedRiskID: req.RelatedRiskID,
+		AspectID:      req.AspectID,
 		Severity:      req.Severity,
 		Likelihood:    req.Likelihood,
+		OccurredOn:    req.OccurredOn,
+
+		Type:              req.Type,
+		InjuredPerson:     req.InjuredPerson,
+		BodyPart:          req.BodyPart,
+		InjuryNature:      req.InjuryNature,
+		DaysLost:          req.DaysLost,
+		Reportable:        req.Reportable,
+		ReportingDeadline: req.ReportingDeadline,
+		ReportedOn:        req.ReportedOn,
 	}
 
-	inc, err := s.incidentSvc.CreateIncident(in)
+	inc, err := s.incidentSvc.CreateIncident(r.Context(), in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -271,38 +646,89 @@ func (s *Server) createIncident(w http.ResponseWriter, r *http.Request) {
 
 // listIncidents godoc
 // @Summary      List incidents
-// @Description  Returns incidents, optionally filtered by domain and status.
+// @Description  Returns incidents filtered, sorted and paged in the database.
 // @Tags         incidents
 // @Produce      json
-// @Param        domain  query    string  false  "Domain filter"
-// @Param        status  query    string  false  "Status filter (Open|Investigation|Closed)"
-// @Success      200     {array}  domain.Incident
-// @Failure      400     {string} string
-// @Failure      500     {string} string
+// @Param        domain          query    string  false  "Domain filter"
+// @Param        status          query    string  false  "Status filter (Open|Investigation|Closed)"
+// @Param        level           query    string  false  "Risk level filter, one of the risk matrix levels (default Low|Medium|High)"
+// @Param        relatedRiskId   query    int     false  "Only incidents linked to this risk"
+// @Param        processId       query    int     false  "Only incidents linked to a risk of this process"
+// @Param        aspectId        query    int     false  "Only incidents linked to this environmental aspect"
+// @Param        type            query    string  false  "OHS incident type, e.g. Lost Time"
+// @Param        reportable      query    bool    false  "Only reportable (true) or non-reportable (false) incidents"
+// @Param        createdFrom     query    string  false  "Created on or after (YYYY-MM-DD)"
+// @Param        createdTo       query    string  false  "Created on or before (YYYY-MM-DD)"
+// @Param        occurredFrom    query    string  false  "Occurred on or after (YYYY-MM-DD)"
+// @Param        occurredTo      query    string  false  "Occurred on or before (YYYY-MM-DD)"
+// @Param        q               query    string  false  "Free-text search in title, description and root cause"
+// @Param        includeDeleted  query    bool    false  "Include soft-deleted incidents"
+// @Param        limit           query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset          query    int     false  "Number of records to skip"
+// @Param        sort            query    string  false  "Sort order as field:asc|desc, e.g. createdAt:desc"
+// @Success      200             {array}  domain.Incident
+// @Header       200             {integer} X-Total-Count "Total number of matching records"
+// @Failure      400             {string} string
+// @Failure      403             {string} string
+// @Failure      500             {string} string
+// @Security     BearerAuth
 // @Router       /api/incidents [get]
 func (s *Server) listIncidents(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
-	domainStr := qs.Get("domain")
-	status := qs.Get("status")
 
-	filter := service.IncidentListFilter{}
-	if domainStr != "" {
-		dom, err := domain.ParseDomain(domainStr)
-		if err != nil {
-			s.respondError(w, err)
-			return
-		}
-		filter.Domain = &dom
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
 	}
-	if status != "" {
-		filter.Status = &status
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	relatedRiskID, err := queryInt(qs, "relatedRiskId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	processID, err := queryInt(qs, "processId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	aspectID, err := queryInt(qs, "aspectId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	reportable, err := queryOptionalBool(qs, "reportable")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	filter := service.IncidentListFilter{
+		Domain:         dom,
+		ProcessID:      processID,
+		Status:         queryString(qs, "status"),
+		Level:          queryString(qs, "level"),
+		RelatedRiskID:  relatedRiskID,
+		AspectID:       aspectID,
+		Type:           queryString(qs, "type"),
+		Reportable:     reportable,
+		Created:        repository.DateRange{From: qs.Get("createdFrom"), To: qs.Get("createdTo")},
+		Occurred:       repository.DateRange{From: qs.Get("occurredFrom"), To: qs.Get("occurredTo")},
+		Search:         qs.Get("q"),
+		IncludeDeleted: queryBool(qs, "includeDeleted"),
+		Page:           page,
 	}
 
-	incs, err := s.incidentSvc.ListIncidents(filter)
+	incs, total, err := s.incidentSvc.ListIncidents(r.Context(), filter)
 	if err != nil {
 		s.respondError(w, err)
 		return
 	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	s.respondJSON(w, http.StatusOK, incs)
 }
 
@@ -311,13 +737,16 @@ func (s *Server) listIncidents(w http.ResponseWriter, r *http.Request) {
 // @Description  Returns a single incident by ID.
 // @Tags         incidents
 // @Produce      json
-// @Param        id   path      int             true  "Incident ID"
+// @Param        id              path      int             true   "Incident ID"
+// @Param        includeDeleted  query     bool            false  "Also return the incident if it is soft-deleted"
 // @Success      200  {object}  domain.Incident
+// @Failure      403  {string}  string
 // @Failure      404  {string}  string
 // @Failure      500  {string}  string
+// @Security     BearerAuth
 // @Router       /api/incidents/{id} [get]
 func (s *Server) getIncident(w http.ResponseWriter, r *http.Request, id int) {
-	inc, err := s.incidentSvc.GetIncident(id)
+	inc, err := s.incidentSvc.GetIncident(r.Context(), id, queryBool(r.URL.Query(), "includeDeleted"))
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -327,7 +756,7 @@ func (s *Server) getIncident(w http.ResponseWriter, r *http.Request, id int) {
 
 // updateIncident godoc
 // @Summary      Update incident
-// @Description  Updates root cause and/or status of an incident.
+// @Description  Updates the root cause, status, occurrence date, environmental aspect or OHS classification of an incident. Omitted fields are left unchanged.
 // @Tags         incidents
 // @Accept       json
 // @Produce      json
@@ -335,8 +764,12 @@ func (s *Server) getIncident(w http.ResponseWriter, r *http.Request, id int) {
 // @Param        request  body      UpdateIncidentRequest true  "Update payload"
 // @Success      200      {object}  domain.Incident
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      404      {string}  string
+// @Failure      409      {object}  domain.TransitionError
+// @Failure      422      {object}  domain.TransitionError
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/incidents/{id} [put]
 func (s *Server) updateIncident(w http.ResponseWriter, r *http.Request, id int) {
 	var req UpdateIncidentRequest
@@ -346,11 +779,22 @@ func (s *Server) updateIncident(w http.ResponseWriter, r *http.Request, id int)
 	}
 
 	in := service.UpdateIncidentInput{
-		RootCause: req.RootCause,
-		Status:    req.Status,
+		RootCause:  req.RootCause,
+		Status:     req.Status,
+		OccurredOn: req.OccurredOn,
+		AspectID:   req.AspectID,
+
+		Type:              req.Type,
+		InjuredPerson:     req.InjuredPerson,
+		BodyPart:          req.BodyPart,
+		InjuryNature:      req.InjuryNature,
+		DaysLost:          req.DaysLost,
+		Reportable:        req.Reportable,
+		ReportingDeadline: req.ReportingDeadline,
+		ReportedOn:        req.ReportedOn,
 	}
 
-	inc, err := s.incidentSvc.UpdateIncident(id, in)
+	inc, err := s.incidentSvc.UpdateIncident(r.Context(), id, in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -358,6 +802,68 @@ func (s *Server) updateIncident(w http.ResponseWriter, r *http.Request, id int)
 	s.respondJSON(w, http.StatusOK, inc)
 }
 
+// deleteIncident godoc
+// @Summary      Delete incident
+// @Description  Soft-deletes an incident. Incidents still linked to active actions are refused with 409.
+// @Tags         incidents
+// @Param        id   path      int     true  "Incident ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id} [delete]
+func (s *Server) deleteIncident(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.incidentSvc.DeleteIncident(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreIncident godoc
+// @Summary      Restore incident
+// @Description  Restores a soft-deleted incident.
+// @Tags         incidents
+// @Produce      json
+// @Param        id   path      int              true  "Incident ID"
+// @Success      200  {object}  domain.Incident
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id}/restore [post]
+func (s *Server) restoreIncident(w http.ResponseWriter, r *http.Request, id int) {
+	inc, err := s.incidentSvc.RestoreIncident(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, inc)
+}
+
+// getIncidentHistory godoc
+// @Summary      Incident change history
+// @Description  Returns every change to an incident (including while deleted), oldest first, with actor, timestamp and field-level before/after values.
+// @Tags         incidents
+// @Produce      json
+// @Param        id   path      int  true  "Incident ID"
+// @Success      200  {array}   domain.HistoryEntry
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/incidents/{id}/history [get]
+func (s *Server) getIncidentHistory(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := s.incidentSvc.IncidentHistory(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, entries)
+}
+
 // --------- Audit handlers ---------
 
 func (s *Server) handleAudits(w http.ResponseWriter, r *http.Request) {
@@ -372,17 +878,38 @@ func (s *Server) handleAudits(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleAuditByID(w http.ResponseWriter, r *http.Request) {
-	id, err := parseID(r.URL.Path, "/api/audits/")
+	id, sub, err := parseIDPath(r.URL.Path, "/api/audits/")
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodPut:
-		s.updateAudit(w, r, id)
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodPut:
+			s.updateAudit(w, r, id)
+		case http.MethodDelete:
+			s.deleteAudit(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.restoreAudit(w, r, id)
+	case "attachments":
+		s.handleRecordAttachments(w, r, domain.KindAudit, id)
+	case "history":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getAuditHistory(w, r, id)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		http.NotFound(w, r)
 	}
 }
 
@@ -395,7 +922,9 @@ func (s *Server) handleAuditByID(w http.ResponseWriter, r *http.Request) {
 // @Param        request  body      CreateAuditRequest  true  "Audit payload"
 // @Success      201      {object}  domain.Audit
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/audits [post]
 func (s *Server) createAudit(w http.ResponseWriter, r *http.Request) {
 	var req CreateAuditRequest
@@ -408,11 +937,12 @@ func (s *Server) createAudit(w http.ResponseWriter, r *http.Request) {
 		Title:       req.Title,
 		Scope:       req.Scope,
 		Domain:      req.Domain,
+		ProcessID:   req.ProcessID,
 		PlannedDate: req.PlannedDate,
 		Auditor:     req.Auditor,
 	}
 
-	audit, err := s.auditSvc.CreateAudit(in)
+	audit, err := s.auditSvc.CreateAudit(r.Context(), in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -422,33 +952,70 @@ func (s *Server) createAudit(w http.ResponseWriter, r *http.Request) {
 
 // listAudits godoc
 // @Summary      List audits
-// @Description  Returns internal audits, optionally filtered by status.
+// @Description  Returns internal audits filtered, sorted and paged in the database.
 // @Tags         audits
 // @Produce      json
-// @Param        status  query    string  false  "Status filter (Planned|In Progress|Completed)"
-// @Success      200     {array}  domain.Audit
-// @Failure      500     {string} string
+// @Param        domain          query    string  false  "Domain filter"
+// @Param        status          query    string  false  "Status filter (Planned|In Progress|Completed)"
+// @Param        auditor         query    string  false  "Auditor filter"
+// @Param        processId       query    int     false  "Audited process filter"
+// @Param        plannedFrom     query    string  false  "Planned on or after (YYYY-MM-DD)"
+// @Param        plannedTo       query    string  false  "Planned on or before (YYYY-MM-DD)"
+// @Param        q               query    string  false  "Free-text search in title, scope and findings"
+// @Param        includeDeleted  query    bool    false  "Include soft-deleted audits"
+// @Param        limit           query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset          query    int     false  "Number of records to skip"
+// @Param        sort            query    string  false  "Sort order as field:asc|desc, e.g. plannedDate:asc"
+// @Success      200             {array}  domain.Audit
+// @Header       200             {integer} X-Total-Count "Total number of matching records"
+// @Failure      400             {string} string
+// @Failure      403             {string} string
+// @Failure      500             {string} string
+// @Security     BearerAuth
 // @Router       /api/audits [get]
 func (s *Server) listAudits(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
-	status := qs.Get("status")
 
-	var statusPtr *string
-	if status != "" {
-		statusPtr = &status
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
 	}
 
-	audits, err := s.auditSvc.ListAudits(statusPtr)
+	processID, err := queryInt(qs, "processId")
 	if err != nil {
 		s.respondError(w, err)
 		return
 	}
+
+	filter := service.AuditListFilter{
+		Domain:         dom,
+		ProcessID:      processID,
+		Status:         queryString(qs, "status"),
+		Auditor:        queryString(qs, "auditor"),
+		Planned:        repository.DateRange{From: qs.Get("plannedFrom"), To: qs.Get("plannedTo")},
+		Search:         qs.Get("q"),
+		IncludeDeleted: queryBool(qs, "includeDeleted"),
+		Page:           page,
+	}
+
+	audits, total, err := s.auditSvc.ListAudits(r.Context(), filter)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	s.respondJSON(w, http.StatusOK, audits)
 }
 
 // updateAudit godoc
 // @Summary      Update audit
-// @Description  Updates audit status and/or findings.
+// @Description  Updates audit status and/or findings, or links the audit to a process.
 // @Tags         audits
 // @Accept       json
 // @Produce      json
@@ -456,8 +1023,12 @@ func (s *Server) listAudits(w http.ResponseWriter, r *http.Request) {
 // @Param        request  body      UpdateAuditRequest true  "Update payload"
 // @Success      200      {object}  domain.Audit
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      404      {string}  string
+// @Failure      409      {object}  domain.TransitionError
+// @Failure      422      {object}  domain.TransitionError
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/audits/{id} [put]
 func (s *Server) updateAudit(w http.ResponseWriter, r *http.Request, id int) {
 	var req UpdateAuditRequest
@@ -467,11 +1038,12 @@ func (s *Server) updateAudit(w http.ResponseWriter, r *http.Request, id int) {
 	}
 
 	in := service.UpdateAuditInput{
-		Status:   req.Status,
-		Findings: req.Findings,
+		Status:    req.Status,
+		Findings:  req.Findings,
+		ProcessID: req.ProcessID,
 	}
 
-	audit, err := s.auditSvc.UpdateAudit(id, in)
+	audit, err := s.auditSvc.UpdateAudit(r.Context(), id, in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -479,6 +1051,68 @@ func (s *Server) updateAudit(w http.ResponseWriter, r *http.Request, id int) {
 	s.respondJSON(w, http.StatusOK, audit)
 }
 
+// deleteAudit godoc
+// @Summary      Delete audit
+// @Description  Soft-deletes an audit. Audits still linked to active actions are refused with 409.
+// @Tags         audits
+// @Param        id   path      int     true  "Audit ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      409  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/audits/{id} [delete]
+func (s *Server) deleteAudit(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.auditSvc.DeleteAudit(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreAudit godoc
+// @Summary      Restore audit
+// @Description  Restores a soft-deleted audit.
+// @Tags         audits
+// @Produce      json
+// @Param        id   path      int           true  "Audit ID"
+// @Success      200  {object}  domain.Audit
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/audits/{id}/restore [post]
+func (s *Server) restoreAudit(w http.ResponseWriter, r *http.Request, id int) {
+	audit, err := s.auditSvc.RestoreAudit(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, audit)
+}
+
+// getAuditHistory godoc
+// @Summary      Audit change history
+// @Description  Returns every change to an audit (including while deleted), oldest first, with actor, timestamp and field-level before/after values.
+// @Tags         audits
+// @Produce      json
+// @Param        id   path      int  true  "Audit ID"
+// @Success      200  {array}   domain.HistoryEntry
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/audits/{id}/history [get]
+func (s *Server) getAuditHistory(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := s.auditSvc.AuditHistory(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, entries)
+}
+
 // --------- Action handlers ---------
 
 func (s *Server) handleActions(w http.ResponseWriter, r *http.Request) {
@@ -493,17 +1127,38 @@ func (s *Server) handleActions(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleActionByID(w http.ResponseWriter, r *http.Request) {
-	id, err := parseID(r.URL.Path, "/api/actions/")
+	id, sub, err := parseIDPath(r.URL.Path, "/api/actions/")
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodPut:
-		s.updateAction(w, r, id)
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodPut:
+			s.updateAction(w, r, id)
+		case http.MethodDelete:
+			s.deleteAction(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "restore":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.restoreAction(w, r, id)
+	case "attachments":
+		s.handleRecordAttachments(w, r, domain.KindAction, id)
+	case "history":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getActionHistory(w, r, id)
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		http.NotFound(w, r)
 	}
 }
 
@@ -516,7 +1171,9 @@ func (s *Server) handleActionByID(w http.ResponseWriter, r *http.Request) {
 // @Param        request  body      CreateActionRequest  true  "Action payload"
 // @Success      201      {object}  domain.Action
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/actions [post]
 func (s *Server) createAction(w http.ResponseWriter, r *http.Request) {
 	var req CreateActionRequest
@@ -534,7 +1191,7 @@ func (s *Server) createAction(w http.ResponseWriter, r *http.Request) {
 		DueDate:     req.DueDate,
 	}
 
-	act, err := s.actionSvc.CreateAction(in)
+	act, err := s.actionSvc.CreateAction(r.Context(), in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -544,38 +1201,71 @@ func (s *Server) createAction(w http.ResponseWriter, r *http.Request) {
 
 // listActions godoc
 // @Summary      List actions
-// @Description  Returns actions, optionally filtered by status or source type.
+// @Description  Returns actions filtered, sorted and paged in the database.
 // @Tags         actions
 // @Produce      json
-// @Param        status      query    string  false  "Status filter (Open|In Progress|Done|Overdue)"
-// @Param        sourceType  query    string  false  "Source type filter (Risk|Incident|Audit)"
-// @Success      200         {array}  domain.Action
-// @Failure      500         {string} string
+// @Param        status          query    string  false  "Status filter (Open|In Progress|Done|Overdue)"
+// @Param        sourceType      query    string  false  "Source type filter (Risk|Incident|Audit)"
+// @Param        sourceId        query    int     false  "Source record ID filter"
+// @Param        processId       query    int     false  "Process filter: actions from its risks and audits and from incidents linked to its risks"
+// @Param        owner           query    string  false  "Owner filter"
+// @Param        dueFrom         query    string  false  "Due on or after (YYYY-MM-DD)"
+// @Param        dueTo           query    string  false  "Due on or before (YYYY-MM-DD)"
+// @Param        q               query    string  false  "Free-text search in title and description"
+// @Param        includeDeleted  query    bool    false  "Include soft-deleted actions"
+// @Param        limit           query    int     false  "Page size (default 100, max 1000)"
+// @Param        offset          query    int     false  "Number of records to skip"
+// @Param        sort            query    string  false  "Sort order as field:asc|desc, e.g. dueDate:asc"
+// @Success      200             {array}  domain.Action
+// @Header       200             {integer} X-Total-Count "Total number of matching records"
+// @Failure      400             {string} string
+// @Failure      403             {string} string
+// @Failure      500             {string} string
+// @Security     BearerAuth
 // @Router       /api/actions [get]
 func (s *Server) listActions(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
-	status := qs.Get("status")
-	srcType := qs.Get("sourceType")
 
-	filter := service.ActionListFilter{}
-	if status != "" {
-		filter.Status = &status
+	page, err := parsePage(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
 	}
-	if srcType != "" {
-		filter.SourceType = &srcType
+	sourceID, err := queryInt(qs, "sourceId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	processID, err := queryInt(qs, "processId")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	filter := service.ActionListFilter{
+		Status:         queryString(qs, "status"),
+		SourceType:     queryString(qs, "sourceType"),
+		SourceID:       sourceID,
+		ProcessID:      processID,
+		Owner:          queryString(qs, "owner"),
+		Due:            repository.DateRange{From: qs.Get("dueFrom"), To: qs.Get("dueTo")},
+		Search:         qs.Get("q"),
+		IncludeDeleted: queryBool(qs, "includeDeleted"),
+		Page:           page,
 	}
 
-	acts, err := s.actionSvc.ListActions(filter)
+	acts, total, err := s.actionSvc.ListActions(r.Context(), filter)
 	if err != nil {
 		s.respondError(w, err)
 		return
 	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	s.respondJSON(w, http.StatusOK, acts)
 }
 
 // updateAction godoc
 // @Summary      Update action
-// @Description  Updates the status and/or due date of an action.
+// @Description  Updates the status and/or due date (YYYY-MM-DD) of an action. Moving an Overdue action's due date to today or later reopens it.
 // @Tags         actions
 // @Accept       json
 // @Produce      json
@@ -583,8 +1273,12 @@ func (s *Server) listActions(w http.ResponseWriter, r *http.Request) {
 // @Param        request  body      UpdateActionRequest true  "Update payload"
 // @Success      200      {object}  domain.Action
 // @Failure      400      {string}  string
+// @Failure      403      {string}  string
 // @Failure      404      {string}  string
+// @Failure      409      {object}  domain.TransitionError
+// @Failure      422      {object}  domain.TransitionError
 // @Failure      500      {string}  string
+// @Security     BearerAuth
 // @Router       /api/actions/{id} [put]
 func (s *Server) updateAction(w http.ResponseWriter, r *http.Request, id int) {
 	var req UpdateActionRequest
@@ -598,7 +1292,7 @@ func (s *Server) updateAction(w http.ResponseWriter, r *http.Request, id int) {
 		DueDate: req.DueDate,
 	}
 
-	act, err := s.actionSvc.UpdateAction(id, in)
+	act, err := s.actionSvc.UpdateAction(r.Context(), id, in)
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -606,6 +1300,67 @@ func (s *Server) updateAction(w http.ResponseWriter, r *http.Request, id int) {
 	s.respondJSON(w, http.StatusOK, act)
 }
 
+// deleteAction godoc
+// @Summary      Delete action
+// @Description  Soft-deletes an action.
+// @Tags         actions
+// @Param        id   path      int     true  "Action ID"
+// @Success      204
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/actions/{id} [delete]
+func (s *Server) deleteAction(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.actionSvc.DeleteAction(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreAction godoc
+// @Summary      Restore action
+// @Description  Restores a soft-deleted action.
+// @Tags         actions
+// @Produce      json
+// @Param        id   path      int            true  "Action ID"
+// @Success      200  {object}  domain.Action
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/actions/{id}/restore [post]
+func (s *Server) restoreAction(w http.ResponseWriter, r *http.Request, id int) {
+	act, err := s.actionSvc.RestoreAction(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, act)
+}
+
+// getActionHistory godoc
+// @Summary      Action change history
+// @Description  Returns every change to an action (including while deleted), oldest first, with actor, timestamp and field-level before/after values.
+// @Tags         actions
+// @Produce      json
+// @Param        id   path      int  true  "Action ID"
+// @Success      200  {array}   domain.HistoryEntry
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/actions/{id}/history [get]
+func (s *Server) getActionHistory(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := s.actionSvc.ActionHistory(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, entries)
+}
+
 // --------- Dashboard handler ---------
 
 // handleDashboard godoc
@@ -614,7 +1369,9 @@ func (s *Server) updateAction(w http.ResponseWriter, r *http.Request, id int) {
 // @Tags         dashboard
 // @Produce      json
 // @Success      200  {object}  domain.Dashboard
+// @Failure      403  {string}  string
 // @Failure      500  {string}  string
+// @Security     BearerAuth
 // @Router       /api/dashboard [get]
 func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -622,7 +1379,7 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dash, err := s.dashboardSvc.GetDashboard()
+	dash, err := s.dashboardSvc.GetDashboard(r.Context())
 	if err != nil {
 		s.respondError(w, err)
 		return
@@ -630,6 +1387,30 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusOK, dash)
 }
 
+// handleVerifyHistory godoc
+// @Summary      Verify change history
+// @Description  Walks the hash chain over all history entries and reports the first broken link. A broken chain is still a 200 response with valid=false. Requires the auditor or ims_manager role for all domains.
+// @Tags         history
+// @Produce      json
+// @Success      200  {object}  domain.HistoryVerification
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/history/verify [get]
+func (s *Server) handleVerifyHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	v, err := s.historySvc.VerifyHistory(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, v)
+}
+
 // --------- helpers ---------
 
 func (s *Server) respondJSON(w http.ResponseWriter, status int, data any) {
@@ -642,19 +1423,136 @@ func (s *Server) respondJSON(w http.ResponseWriter, status int, data any) {
 
 func (s *Server) respondError(w http.ResponseWriter, err error) {
 	log.Println("error:", err)
+
+	// Rejected status changes carry details clients can act on.
+	var te *domain.TransitionError
+	if errors.As(err, &te) {
+		status := http.StatusConflict
+		if errors.Is(err, domain.ErrGuardFailed) {
+			status = http.StatusUnprocessableEntity
+		}
+		s.respondJSON(w, status, te)
+		return
+	}
+
 	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="integraflow"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
 	case errors.Is(err, repository.ErrNotFound):
 		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrInUse),
+		errors.Is(err, repository.ErrDuplicate):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, repository.ErrTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
 	case errors.Is(err, service.ErrValidation),
-		errors.Is(err, domain.ErrInvalidDomain):
+		errors.Is(err, domain.ErrInvalidDomain),
+		errors.Is(err, domain.ErrInvalidMatrix),
+		errors.Is(err, repository.ErrInvalidQuery):
 		http.Error(w, err.Error(), http.StatusBadRequest)
 	default:
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 	}
 }
 
-func parseID(path, prefix string) (int, error) {
+// parseIDPath splits "<prefix>{id}/<sub>" into the numeric ID and the
+// remaining sub-path ("" when the path addresses the record itself).
+func parseIDPath(path, prefix string) (int, string, error) {
 	trimmed := strings.TrimPrefix(path, prefix)
 	trimmed = strings.Trim(trimmed, "/")
-	return strconv.Atoi(trimmed)
+	idPart, sub, _ := strings.Cut(trimmed, "/")
+	id, err := strconv.Atoi(idPart)
+	return id, sub, err
+}
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// parsePage reads the limit, offset and sort=field:asc|desc list parameters.
+func parsePage(qs url.Values) (repository.Page, error) {
+	page := repository.Page{Limit: defaultPageLimit}
+
+	if v := qs.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return page, fmt.Errorf("%w: limit must be between 1 and %d", service.ErrValidation, maxPageLimit)
+		}
+		page.Limit = n
+	}
+	if v := qs.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, fmt.Errorf("%w: offset must be a non-negative integer", service.ErrValidation)
+		}
+		page.Offset = n
+	}
+	if v := qs.Get("sort"); v != "" {
+		field, dir, _ := strings.Cut(v, ":")
+		page.Sort = strings.TrimSpace(field)
+		switch strings.ToLower(strings.TrimSpace(dir)) {
+		case "", "asc":
+		case "desc":
+			page.Desc = true
+		default:
+			return page, fmt.Errorf("%w: sort direction must be asc or desc", service.ErrValidation)
+		}
+	}
+	return page, nil
+}
+
+// queryString returns a pointer to a non-empty query value, or nil.
+func queryString(qs url.Values, key string) *string {
+	v := strings.TrimSpace(qs.Get(key))
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func queryInt(qs url.Values, key string) (*int, error) {
+	v := qs.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an integer", service.ErrValidation, key)
+	}
+	return &n, nil
+}
+
+func queryDomain(qs url.Values) (*domain.Domain, error) {
+	v := qs.Get("domain")
+	if v == "" {
+		return nil, nil
+	}
+	dom, err := domain.ParseDomain(v)
+	if err != nil {
+		return nil, err
+	}
+	return &dom, nil
+}
+
+func queryBool(qs url.Values, key string) bool {
+	v, _ := strconv.ParseBool(qs.Get(key))
+	return v
+}
+
+// queryOptionalBool is queryBool for filters where absent differs from
+// false.
+func queryOptionalBool(qs url.Values, key string) (*bool, error) {
+	v := qs.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be true or false", service.ErrValidation, key)
+	}
+	return &b, nil
 }
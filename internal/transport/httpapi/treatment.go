@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Risk treatment handlers ---------
+
+// getRiskTreatment godoc
+// @Summary      Get risk treatment plan
+// @Description  Returns the risk's treatment plan (null until set) with the actions raised from the risk to carry it out.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int  true  "Risk ID"
+// @Success      200  {object}  domain.TreatmentPlan
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/treatment [get]
+func (s *Server) getRiskTreatment(w http.ResponseWriter, r *http.Request, id int) {
+	plan, err := s.riskSvc.GetTreatment(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, plan)
+}
+
+// setRiskTreatment godoc
+// @Summary      Set risk treatment plan
+// @Description  Creates or replaces the risk's treatment plan. Changing the option or justification withdraws an earlier approval. Raise the plan's actions with POST /api/actions (sourceType risk); when all of them are Done, a risk treated by avoid, reduce or transfer moves to Mitigated.
+// @Tags         risks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "Risk ID"
+// @Param        request  body      SetTreatmentRequest  true  "Treatment plan"
+// @Success      200      {object}  domain.TreatmentPlan
+// @Failure      400      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/treatment [put]
+func (s *Server) setRiskTreatment(w http.ResponseWriter, r *http.Request, id int) {
+	var req SetTreatmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	plan, err := s.riskSvc.SetTreatment(r.Context(), id, service.TreatmentInput{
+		Option:        req.Option,
+		Justification: req.Justification,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, plan)
+}
+
+// approveRiskTreatment godoc
+// @Summary      Approve risk treatment plan
+// @Description  Records the caller as approver of the risk's treatment plan. Requires the process_owner or ims_manager role for the risk's domain; accepting a risk in an escalating level of the risk matrix (default High) requires the ims_manager role.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int  true  "Risk ID"
+// @Success      200  {object}  domain.TreatmentPlan
+// @Failure      400  {string}  string
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/treatment/approve [post]
+func (s *Server) approveRiskTreatment(w http.ResponseWriter, r *http.Request, id int) {
+	plan, err := s.riskSvc.ApproveTreatment(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, plan)
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import "net/http"
+
+// --------- Risk score history and trend handlers ---------
+
+// getRiskScoreHistory godoc
+// @Summary      Get risk score history
+// @Description  Returns every rating of a risk, oldest first: one point when it was created and one whenever an edit, review or risk matrix change altered its inherent or residual likelihood, impact, score or level. Also available for deleted risks.
+// @Tags         risks
+// @Produce      json
+// @Param        id   path      int  true  "Risk ID"
+// @Success      200  {array}   domain.RiskScore
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/risks/{id}/score-history [get]
+func (s *Server) getRiskScoreHistory(w http.ResponseWriter, r *http.Request, id int) {
+	scores, err := s.riskSvc.ScoreHistory(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, scores)
+}
+
+// handleRiskTrend godoc
+// @Summary      Risk trend
+// @Description  Returns the number of risks per level, overall and per domain, and their average inherent score at the end of each week (Monday to Sunday, UTC), oldest first. The current week shows the risks as they are now. Deleted risks count in the weeks before their deletion.
+// @Tags         risks
+// @Produce      json
+// @Param        weeks   query    int     false  "Number of weeks including the current one (default 12, max 104)"
+// @Param        domain  query    string  false  "Domain filter (quality|environment|ohs|isms)"
+// @Success      200     {array}  domain.RiskTrendPoint
+// @Failure      400     {string} string
+// @Failure      403     {string} string
+// @Failure      500     {string} string
+// @Security     BearerAuth
+// @Router       /api/risks/trend [get]
+func (s *Server) handleRiskTrend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	qs := r.URL.Query()
+
+	dom, err := queryDomain(qs)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	weeks, err := queryInt(qs, "weeks")
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	n := 0
+	if weeks != nil {
+		n = *weeks
+	}
+
+	trend, err := s.riskSvc.RiskTrend(r.Context(), n, dom)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, trend)
+}
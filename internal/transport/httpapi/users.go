@@ -0,0 +1,193 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- User administration handlers ---------
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listUsers(w, r)
+	case http.MethodPost:
+		s.createUser(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleUserByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/users/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getUser(w, r, id)
+		case http.MethodPatch:
+			s.updateUser(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "roles":
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.setUserRoles(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// listUsers godoc
+// @Summary      List users
+// @Description  Lists all user accounts with their roles. Requires the ims_manager role for all domains.
+// @Tags         users
+// @Produce      json
+// @Success      200  {array}   domain.User
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/users [get]
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.authSvc.ListUsers(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, users)
+}
+
+// createUser godoc
+// @Summary      Create user
+// @Description  Creates a user account with optional password and roles. Requires the ims_manager role for all domains.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateUserRequest  true  "User data"
+// @Success      201      {object}  domain.User
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      409      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/users [post]
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	u, err := s.authSvc.CreateUser(r.Context(), service.CreateUserInput{
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Password:    req.Password,
+		Roles:       req.Roles,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, u)
+}
+
+// getUser godoc
+// @Summary      Get user
+// @Description  Returns a user account with its roles. Requires the ims_manager role for all domains.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.User
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/users/{id} [get]
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, id int) {
+	u, err := s.authSvc.GetUser(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, u)
+}
+
+// updateUser godoc
+// @Summary      Update user
+// @Description  Changes a user's display name or email address, or deactivates/reactivates the account. Requires the ims_manager role for all domains.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "User ID"
+// @Param        request  body      UpdateUserRequest  true  "Fields to change"
+// @Success      200      {object}  domain.User
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/users/{id} [patch]
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, id int) {
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	u, err := s.authSvc.UpdateUser(r.Context(), id, service.UpdateUserInput{
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Active:      req.Active,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, u)
+}
+
+// setUserRoles godoc
+// @Summary      Set user roles
+// @Description  Replaces the user's role assignments. Roles: viewer, contributor, process_owner, auditor, ims_manager. A role without a domain applies to every domain. Requires the ims_manager role for all domains.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int              true  "User ID"
+// @Param        request  body      SetRolesRequest  true  "New role assignments"
+// @Success      200      {object}  domain.User
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/users/{id}/roles [put]
+func (s *Server) setUserRoles(w http.ResponseWriter, r *http.Request, id int) {
+	var req SetRolesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	u, err := s.authSvc.SetRoles(r.Context(), id, req.Roles)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, u)
+}
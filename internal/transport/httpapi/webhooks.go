@@ -0,0 +1,221 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/xenakil/integraflow-ims/internal/service"
+)
+
+// --------- Webhook handlers ---------
+
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listWebhooks(w, r)
+	case http.MethodPost:
+		s.createWebhook(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleWebhookByID(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseIDPath(r.URL.Path, "/api/webhooks/")
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch sub {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.getWebhook(w, r, id)
+		case http.MethodPatch:
+			s.updateWebhook(w, r, id)
+		case http.MethodDelete:
+			s.deleteWebhook(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "deliveries":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.listWebhookDeliveries(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// listWebhooks godoc
+// @Summary      List webhooks
+// @Description  Lists webhook subscriptions. Secrets are never returned. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {array}   domain.Webhook
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks [get]
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.webhookSvc.ListWebhooks(r.Context())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, hooks)
+}
+
+// createWebhook godoc
+// @Summary      Create webhook
+// @Description  Subscribes a URL to record events. Event types: risk.created, risk.status_changed, risk.level_changed, incident.created, incident.status_changed, audit.created, audit.status_changed, action.created, action.status_changed, or * for all. Deliveries are signed with HMAC-SHA256 in the X-IMS-Signature header. The secret is only shown in this response. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateWebhookRequest   true  "Subscription"
+// @Success      201      {object}  WebhookSecretResponse
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks [post]
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	hook, err := s.webhookSvc.CreateWebhook(r.Context(), service.CreateWebhookInput{
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+		Secret:      req.Secret,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, WebhookSecretResponse{Secret: hook.Secret, Webhook: *hook})
+}
+
+// getWebhook godoc
+// @Summary      Get webhook
+// @Description  Returns a webhook subscription. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  domain.Webhook
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{id} [get]
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request, id int) {
+	hook, err := s.webhookSvc.GetWebhook(r.Context(), id)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	s.respondJSON(w, http.StatusOK, hook)
+}
+
+// updateWebhook godoc
+// @Summary      Update webhook
+// @Description  Changes the URL, event filter or description, pauses/resumes deliveries, or rotates the secret. The new secret is only shown in this response. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Webhook ID"
+// @Param        request  body      UpdateWebhookRequest  true  "Fields to change"
+// @Success      200      {object}  WebhookSecretResponse
+// @Failure      400      {string}  string
+// @Failure      401      {string}  string
+// @Failure      403      {string}  string
+// @Failure      404      {string}  string
+// @Failure      500      {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{id} [patch]
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request, id int) {
+	var req UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, err)
+		return
+	}
+
+	hook, err := s.webhookSvc.UpdateWebhook(r.Context(), id, service.UpdateWebhookInput{
+		URL:          req.URL,
+		Events:       req.Events,
+		Description:  req.Description,
+		Active:       req.Active,
+		RotateSecret: req.RotateSecret,
+	})
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	resp := WebhookSecretResponse{Webhook: *hook}
+	if req.RotateSecret {
+		resp.Secret = hook.Secret
+	}
+	s.respondJSON(w, http.StatusOK, resp)
+}
+
+// deleteWebhook godoc
+// @Summary      Delete webhook
+// @Description  Removes a webhook subscription together with its pending deliveries and delivery log. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Param        id   path      int     true  "Webhook ID"
+// @Success      204
+// @Failure      401  {string}  string
+// @Failure      403  {string}  string
+// @Failure      404  {string}  string
+// @Failure      500  {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{id} [delete]
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.webhookSvc.DeleteWebhook(r.Context(), id); err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listWebhookDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  Returns the webhook's deliveries, newest first, each with the posted payload and the status code, response time and error of every attempt. Requires the ims_manager role for all domains.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id      path      int  true   "Webhook ID"
+// @Param        limit   query     int  false  "Page size (default 100, max 1000)"
+// @Param        offset  query     int  false  "Number of deliveries to skip"
+// @Success      200     {array}   domain.WebhookDelivery
+// @Header       200     {integer} X-Total-Count "Total number of deliveries"
+// @Failure      400     {string}  string
+// @Failure      401     {string}  string
+// @Failure      403     {string}  string
+// @Failure      404     {string}  string
+// @Failure      500     {string}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{id}/deliveries [get]
+func (s *Server) listWebhookDeliveries(w http.ResponseWriter, r *http.Request, id int) {
+	page, err := parsePage(r.URL.Query())
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	deliveries, total, err := s.webhookSvc.ListDeliveries(r.Context(), id, page)
+	if err != nil {
+		s.respondError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	s.respondJSON(w, http.StatusOK, deliveries)
+}
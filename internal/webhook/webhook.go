@@ -0,0 +1,195 @@
+// Package webhook queues record events for webhook subscriptions and
+// delivers them as signed JSON POSTs. Deliveries are persisted when the event
+// is published; Deliver, run by the scheduler, sends them and retries
+// failures with exponential backoff.
+//
+// Every request carries these headers:
+//
+//	X-IMS-Event        event type, e.g. incident.created
+//	X-IMS-Delivery     delivery ID, stable across retries
+//	X-IMS-Timestamp    Unix seconds when the attempt was signed
+//	X-IMS-Signature    sha256=<hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret>
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+)
+
+const (
+	DefaultMaxAttempts  = 8
+	DefaultRetryBackoff = 30 * time.Second
+	requestTimeout      = 15 * time.Second
+	deliverBatch        = 50
+	maxErrorBody        = 200 // Bytes of a non-2xx response kept in the attempt log
+)
+
+// Dispatcher implements service.EventPublisher for webhook subscriptions.
+type Dispatcher struct {
+	repo        repository.WebhookRepository
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// New returns a dispatcher. Non-positive maxAttempts and backoff use the
+// defaults.
+func New(repo repository.WebhookRepository, maxAttempts int, backoff time.Duration) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	return &Dispatcher{
+		repo:        repo,
+		client:      &http.Client{Timeout: requestTimeout},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// Publish queues e for every active webhook subscribed to its type. A
+// webhook gets one delivery per event ID, so publishing an event again
+// queues nothing.
+func (d *Dispatcher) Publish(ctx context.Context, e domain.Event) error {
+	if err := d.enqueue(e); err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+	return nil
+}
+
+func (d *Dispatcher) enqueue(e domain.Event) error {
+	hooks, err := d.repo.List()
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, w := range hooks {
+		if !w.Active || !w.Matches(e.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				return err
+			}
+		}
+		if err := d.repo.Enqueue(&domain.WebhookDelivery{
+			WebhookID: w.ID,
+			EventID:   e.ID,
+			EventType: e.Type,
+			Payload:   payload,
+			Status:    domain.DeliveryPending,
+			CreatedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver sends the deliveries that are due and returns how many succeeded
+// and how many attempts failed. A failed delivery is retried after the
+// backoff, doubling each time, until the maximum number of attempts.
+func (d *Dispatcher) Deliver(ctx context.Context) (delivered, failed int, err error) {
+	due, err := d.repo.Due(time.Now().UTC().Format(time.RFC3339), deliverBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	hooks := make(map[int]*domain.Webhook)
+	for _, dl := range due {
+		if ctx.Err() != nil {
+			return delivered, failed, ctx.Err()
+		}
+		w, ok := hooks[dl.WebhookID]
+		if !ok {
+			if w, err = d.repo.GetByID(dl.WebhookID); err != nil {
+				return delivered, failed, err
+			}
+			hooks[dl.WebhookID] = w
+		}
+
+		a := d.attempt(ctx, w, dl)
+		status, next := domain.DeliveryDelivered, ""
+		if a.Error != "" {
+			failed++
+			status = domain.DeliveryPending
+			if a.Attempt >= d.maxAttempts {
+				status = domain.DeliveryFailed
+			} else {
+				next = time.Now().UTC().Add(d.backoff << (a.Attempt - 1)).Format(time.RFC3339)
+			}
+			log.Printf("webhooks: delivery %d to %s (attempt %d/%d): %s", dl.ID, w.URL, a.Attempt, d.maxAttempts, a.Error)
+		} else {
+			delivered++
+		}
+		if err := d.repo.RecordAttempt(dl.ID, a, status, next); err != nil {
+			return delivered, failed, err
+		}
+	}
+	return delivered, failed, nil
+}
+
+// attempt POSTs one delivery and measures the response time. A response
+// outside 2xx counts as a failure.
+func (d *Dispatcher) attempt(ctx context.Context, w *domain.Webhook, dl *domain.WebhookDelivery) domain.WebhookAttempt {
+	start := time.Now()
+	a := domain.WebhookAttempt{Attempt: dl.Attempts + 1, AttemptedAt: start.Format(time.RFC3339)}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		a.Error = err.Error()
+		return a
+	}
+	ts := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "IntegraFlow-IMS")
+	req.Header.Set("X-IMS-Event", dl.EventType)
+	req.Header.Set("X-IMS-Delivery", strconv.Itoa(dl.ID))
+	req.Header.Set("X-IMS-Timestamp", ts)
+	req.Header.Set("X-IMS-Signature", Sign(w.Secret, ts, dl.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		a.DurationMs = time.Since(start).Milliseconds()
+		a.Error = err.Error()
+		return a
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	a.DurationMs = time.Since(start).Milliseconds()
+	a.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		a.Error = fmt.Sprintf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return a
+}
+
+// Sign returns the X-IMS-Signature value for body sent at timestamp.
+// Receivers recompute it with their copy of the secret and compare in
+// constant time.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xenakil/integraflow-ims/internal/domain"
+	"github.com/xenakil/integraflow-ims/internal/repository"
+	"github.com/xenakil/integraflow-ims/internal/repository/sqlite"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"incident.created"}`)
+	// printf '%s' '1700000000.{"event":"incident.created"}' | openssl dgst -sha256 -hmac whsec_test
+	want := "sha256=79b16c99a9ecdf8abe40575e671b36a9522efc6e4b068c6e08a39917c73213b3"
+	if got := Sign("whsec_test", "1700000000", body); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+
+	for name, got := range map[string]string{
+		"secret":    Sign("whsec_other", "1700000000", body),
+		"timestamp": Sign("whsec_test", "1700000001", body),
+		"body":      Sign("whsec_test", "1700000000", []byte(`{"event":"incident.created" }`)),
+	} {
+		if got == want {
+			t.Errorf("changing the %s does not change the signature", name)
+		}
+	}
+}
+
+// request is what the receiver stand-in saw of one POST.
+type request struct {
+	Header http.Header
+	Body   []byte
+}
+
+// receiver is a webhook endpoint stand-in that answers the first len(codes)
+// requests with those status codes and 200 after that.
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	codes    []int
+	requests []request
+}
+
+func startReceiver(t *testing.T, codes ...int) *receiver {
+	t.Helper()
+	rc := &receiver{codes: codes}
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		rc.requests = append(rc.requests, request{Header: r.Header.Clone(), Body: body})
+		code := http.StatusOK
+		if len(rc.codes) > 0 {
+			code, rc.codes = rc.codes[0], rc.codes[1:]
+		}
+		rc.mu.Unlock()
+		if code != http.StatusOK {
+			http.Error(w, "receiver unavailable", code)
+		}
+	}))
+	t.Cleanup(rc.Close)
+	return rc
+}
+
+func (rc *receiver) Requests() []request {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]request(nil), rc.requests...)
+}
+
+func openRepo(t *testing.T) (*sql.DB, *sqlite.WebhookRepository) {
+	t.Helper()
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "ims.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := sqlite.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db, sqlite.NewWebhookRepository(db)
+}
+
+func addWebhook(t *testing.T, repo *sqlite.WebhookRepository, url string, active bool, events ...string) *domain.Webhook {
+	t.Helper()
+	w := &domain.Webhook{
+		URL:       url,
+		Events:    events,
+		Secret:    "whsec_" + strconv.Itoa(len(events)) + url,
+		Active:    active,
+		CreatedAt: "2025-01-01T00:00:00Z",
+		UpdatedAt: "2025-01-01T00:00:00Z",
+	}
+	if err := repo.Create(w); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func deliveries(t *testing.T, repo *sqlite.WebhookRepository, w *domain.Webhook) []*domain.WebhookDelivery {
+	t.Helper()
+	ds, _, err := repo.Deliveries(w.ID, repository.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
+
+// makeDue moves every pending delivery's next attempt into the past, as if
+// the backoff had elapsed.
+func makeDue(t *testing.T, db *sql.DB) {
+	t.Helper()
+	if _, err := db.Exec(`UPDATE webhook_deliveries SET next_attempt_at = '2000-01-01T00:00:00Z' WHERE status = ?`, domain.DeliveryPending); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testEvent() domain.Event {
+	return domain.Event{
+		ID:         "0123456789abcdef",
+		Type:       domain.EventIncidentCreated,
+		RecordType: domain.KindIncident,
+		RecordID:   3,
+		Domain:     domain.DomainOHS,
+		Actor:      "alice",
+		OccurredAt: "2025-11-03T10:00:00Z",
+		Record:     map[string]any{"id": 3, "title": "Fall from ladder"},
+	}
+}
+
+func TestPublishQueuesSubscribedWebhooks(t *testing.T) {
+	_, repo := openRepo(t)
+	incidents := addWebhook(t, repo, "http://127.0.0.1:1/incidents", true, domain.EventIncidentCreated)
+	all := addWebhook(t, repo, "http://127.0.0.1:1/all", true, domain.WebhookAllEvents)
+	actions := addWebhook(t, repo, "http://127.0.0.1:1/actions", true, domain.EventActionCreated)
+	inactive := addWebhook(t, repo, "http://127.0.0.1:1/inactive", false, domain.WebhookAllEvents)
+
+	New(repo, 0, 0).Publish(context.Background(), testEvent())
+
+	for _, tt := range []struct {
+		hook *domain.Webhook
+		want int
+	}{{incidents, 1}, {all, 1}, {actions, 0}, {inactive, 0}} {
+		ds := deliveries(t, repo, tt.hook)
+		if len(ds) != tt.want {
+			t.Errorf("%s: %d deliveries, want %d", tt.hook.URL, len(ds), tt.want)
+			continue
+		}
+		if tt.want == 1 && (ds[0].EventID != testEvent().ID || ds[0].Status != domain.DeliveryPending) {
+			t.Errorf("%s: delivery %+v", tt.hook.URL, ds[0])
+		}
+	}
+}
+
+func TestPublishDropsRepeatedEvents(t *testing.T) {
+	_, repo := openRepo(t)
+	w := addWebhook(t, repo, "http://127.0.0.1:1/all", true, domain.WebhookAllEvents)
+	p := New(repo, 0, 0)
+
+	// The event relay publishes an event again when it could not drop it
+	// after publishing.
+	for i := 0; i < 2; i++ {
+		if err := p.Publish(context.Background(), testEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ds := deliveries(t, repo, w); len(ds) != 1 {
+		t.Errorf("%d deliveries of one event, want 1", len(ds))
+	}
+}
+
+func TestDeliverSignsRequests(t *testing.T) {
+	_, repo := openRepo(t)
+	rc := startReceiver(t)
+	w := addWebhook(t, repo, rc.URL, true, domain.WebhookAllEvents)
+	d := New(repo, 0, 0)
+	d.Publish(context.Background(), testEvent())
+
+	delivered, failed, err := d.Deliver(context.Background())
+	if err != nil || delivered != 1 || failed != 0 {
+		t.Fatalf("Deliver = %d delivered, %d failed, %v; want 1, 0, nil", delivered, failed, err)
+	}
+
+	reqs := rc.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	req := reqs[0]
+	ds := deliveries(t, repo, w)
+	for header, want := range map[string]string{
+		"Content-Type":   "application/json",
+		"X-IMS-Event":    domain.EventIncidentCreated,
+		"X-IMS-Delivery": strconv.Itoa(ds[0].ID),
+	} {
+		if got := req.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	ts := req.Header.Get("X-IMS-Timestamp")
+	if sec, err := strconv.ParseInt(ts, 10, 64); err != nil || time.Since(time.Unix(sec, 0)) > time.Minute {
+		t.Errorf("X-IMS-Timestamp = %q, want the current Unix time", ts)
+	}
+	if got, want := req.Header.Get("X-IMS-Signature"), Sign(w.Secret, ts, req.Body); got != want {
+		t.Errorf("X-IMS-Signature = %s, want %s", got, want)
+	}
+
+	var e domain.Event
+	if err := json.Unmarshal(req.Body, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != testEvent().ID || e.Type != domain.EventIncidentCreated || e.RecordID != 3 {
+		t.Errorf("posted event %+v", e)
+	}
+
+	if ds[0].Status != domain.DeliveryDelivered || len(ds[0].AttemptLog) != 1 || ds[0].AttemptLog[0].StatusCode != http.StatusOK {
+		t.Errorf("delivery %+v, want delivered after one 200 attempt", ds[0])
+	}
+}
+
+func TestDeliverBacksOffAndGivesUp(t *testing.T) {
+	db, repo := openRepo(t)
+	rc := startReceiver(t, 500, 500, 500)
+	w := addWebhook(t, repo, rc.URL, true, domain.WebhookAllEvents)
+	d := New(repo, 3, time.Hour)
+	d.Publish(context.Background(), testEvent())
+
+	for attempt, wantDelay := range []time.Duration{time.Hour, 2 * time.Hour} {
+		start := time.Now().UTC().Truncate(time.Second)
+		if delivered, failed, err := d.Deliver(context.Background()); err != nil || delivered != 0 || failed != 1 {
+			t.Fatalf("attempt %d: Deliver = %d delivered, %d failed, %v; want 0, 1, nil", attempt+1, delivered, failed, err)
+		}
+		dl := deliveries(t, repo, w)[0]
+		if dl.Status != domain.DeliveryPending || dl.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: delivery %+v, want pending", attempt+1, dl)
+		}
+		next, err := time.Parse(time.RFC3339, dl.NextAttemptAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delay := next.Sub(start); delay < wantDelay || delay > wantDelay+2*time.Second {
+			t.Errorf("attempt %d: retried after %v, want %v", attempt+1, delay, wantDelay)
+		}
+		if delivered, failed, _ := d.Deliver(context.Background()); delivered+failed != 0 {
+			t.Fatalf("attempt %d: retried before the backoff elapsed", attempt+1)
+		}
+		makeDue(t, db)
+	}
+
+	if _, failed, err := d.Deliver(context.Background()); err != nil || failed != 1 {
+		t.Fatalf("last attempt: %d failed, %v", failed, err)
+	}
+	dl := deliveries(t, repo, w)[0]
+	if dl.Status != domain.DeliveryFailed || len(dl.AttemptLog) != 3 {
+		t.Fatalf("delivery %+v, want failed after 3 attempts", dl)
+	}
+	for _, a := range dl.AttemptLog {
+		if a.StatusCode != 500 || !strings.Contains(a.Error, "receiver unavailable") {
+			t.Errorf("attempt %d logged status %d, error %q", a.Attempt, a.StatusCode, a.Error)
+		}
+	}
+	if delivered, failed, _ := d.Deliver(context.Background()); delivered+failed != 0 {
+		t.Fatal("a failed delivery was retried")
+	}
+}
+
+func TestDeliverRetriesUntilDelivered(t *testing.T) {
+	db, repo := openRepo(t)
+	rc := startReceiver(t, http.StatusBadGateway)
+	w := addWebhook(t, repo, rc.URL, true, domain.WebhookAllEvents)
+	d := New(repo, 0, time.Minute)
+	d.Publish(context.Background(), testEvent())
+
+	if _, failed, err := d.Deliver(context.Background()); err != nil || failed != 1 {
+		t.Fatalf("first Deliver: %d failed, %v; want 1", failed, err)
+	}
+	makeDue(t, db)
+	if delivered, _, err := d.Deliver(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("second Deliver: %d delivered, %v; want 1", delivered, err)
+	}
+
+	dl := deliveries(t, repo, w)[0]
+	if dl.Status != domain.DeliveryDelivered || dl.Attempts != 2 || dl.DeliveredAt == "" {
+		t.Errorf("delivery %+v, want delivered on the second attempt", dl)
+	}
+	reqs := rc.Requests()
+	if len(reqs) != 2 || reqs[0].Header.Get("X-IMS-Delivery") != reqs[1].Header.Get("X-IMS-Delivery") {
+		t.Errorf("retry did not keep the delivery ID")
+	}
+}